@@ -2,6 +2,7 @@ package cmd
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"os"
 	"strings"
@@ -20,6 +21,9 @@ func newDevCmd() *cobra.Command {
 	var variablesFile string
 	var interval string
 	var ttyFlag bool
+	var parallelism int
+	var lockTimeout time.Duration
+	var output string
 
 	devCmd := &cobra.Command{
 		Use:   "dev",
@@ -29,7 +33,7 @@ func newDevCmd() *cobra.Command {
 		jumppad dev ./
 `,
 		Args:         cobra.ArbitraryArgs,
-		RunE:         newDevCmdFunc(&variables, &variablesFile, &interval, &ttyFlag),
+		RunE:         newDevCmdFunc(&variables, &variablesFile, &interval, &ttyFlag, &parallelism, &lockTimeout, &output),
 		SilenceUsage: true,
 	}
 
@@ -37,20 +41,32 @@ func newDevCmd() *cobra.Command {
 	devCmd.Flags().StringVarP(&variablesFile, "vars-file", "", "", "Load variables from a location other than *.vars files in the blueprint folder. E.g --vars-file=./file.vars")
 	devCmd.Flags().StringVarP(&interval, "interval", "", "5s", "Interval to check for changes. E.g. --interval=5s")
 	devCmd.Flags().BoolVarP(&ttyFlag, "disable-tty", "", false, "Enable/disable output to TTY")
+	devCmd.Flags().IntVarP(&parallelism, "parallelism", "", jumppad.DefaultParallelism, "Maximum number of resources that are created at the same time, set to 1 to create resources one at a time")
+	devCmd.Flags().DurationVarP(&lockTimeout, "lock-timeout", "", 0, lockTimeoutUsage)
+	devCmd.Flags().StringVarP(&output, "output", "", outputText, outputUsage)
 
 	return devCmd
 }
 
-func newDevCmdFunc(variables *[]string, variablesFile, interval *string, ttyFlag *bool) func(cmd *cobra.Command, args []string) error {
+func newDevCmdFunc(variables *[]string, variablesFile, interval *string, ttyFlag *bool, parallelism *int, lockTimeout *time.Duration, output *string) func(cmd *cobra.Command, args []string) error {
 	return func(cmd *cobra.Command, args []string) error {
-		// create the output view
+		h, err := eventHandler(*output, cmd.OutOrStdout())
+		if err != nil {
+			return err
+		}
+
+		// create the output view, the interactive view can not be used
+		// when stdout contains the events
 		var v view.View
-		var err error
-		if *ttyFlag {
+		if *ttyFlag || h != nil {
 			v, err = view.NewLogView()
 			if err != nil {
 				return fmt.Errorf("unable to create output view: %s", err)
 			}
+
+			if h != nil {
+				v.Logger().SetOutput(redactor.Writer(cmd.ErrOrStderr()))
+			}
 		} else {
 			v, err = view.NewTTYView()
 			if err != nil {
@@ -59,11 +75,13 @@ func newDevCmdFunc(variables *[]string, variablesFile, interval *string, ttyFlag
 		}
 
 		engineClients, _ := clients.GenerateClients(v.Logger())
-		engine, err := createEngine(v.Logger(), engineClients)
+		engine, err := createEngine(v.Logger(), engineClients, jumppad.WithEventHandler(h))
 		if err != nil {
 			return fmt.Errorf("unable to create engine: %s", err)
 		}
 
+		engine.SetParallelism(*parallelism)
+
 		// create the shipyard and sub folders in the users home directory
 		utils.CreateFolders()
 
@@ -128,20 +146,41 @@ func newDevCmdFunc(variables *[]string, variablesFile, interval *string, ttyFlag
 			}
 		}
 
-		// start the
-		go doUpdates(v, engine, src, vars, *variablesFile, d)
+		// the first signal stops watching for changes once the current
+		// update has finished
+		interrupt := newInterruptHandler(v.Logger())
+		defer interrupt.Stop()
 
-		// Show the view
-		err = v.Display()
-		if err != nil {
-			return err
+		updates := make(chan error, 1)
+		go func() {
+			updates <- doUpdates(interrupt.Context(), v, engine, src, vars, *variablesFile, d, *lockTimeout)
+		}()
+
+		// Show the view, the interactive view returns when the user quits
+		display := make(chan error, 1)
+		go func() {
+			display <- v.Display()
+		}()
+
+		select {
+		case err = <-display:
+			if err != nil {
+				interrupt.Interrupt()
+				return err
+			}
+
+			cmd.Println("Waiting for resources in progress to finish, press ctrl-c to exit immediately")
+			interrupt.Interrupt()
+		case <-interrupt.Context().Done():
 		}
 
-		return nil
+		return <-updates
 	}
 }
 
-func doUpdates(v view.View, e jumppad.Engine, source string, variables map[string]string, variableFile string, interval time.Duration) {
+// doUpdates applies the configuration whenever it changes until the context
+// is cancelled, an error is only returned when an update was interrupted
+func doUpdates(ctx context.Context, v view.View, e jumppad.Engine, source string, variables map[string]string, variableFile string, interval time.Duration, lockTimeout time.Duration) error {
 	v.Logger().Debug("P_Init: Checking cmd-line parameters....................")
 	v.Logger().Debug("V_Init: Allocate screens................................")
 	v.Logger().Debug("M_LoadDefaults: Load system defaults....................")
@@ -157,7 +196,11 @@ func doUpdates(v view.View, e jumppad.Engine, source string, variables map[strin
 	_, err := config.LoadState()
 	if err != nil {
 		v.UpdateStatus("Applying initial configuration...", false)
-		_, err := e.ApplyWithVariables(context.Background(), source, variables, variableFile)
+		err := applyWithLock(ctx, v, e, source, variables, variableFile, lockTimeout)
+		if errors.Is(err, jumppad.ErrInterrupted) {
+			return err
+		}
+
 		if err != nil {
 			v.Logger().Error(err.Error())
 		}
@@ -165,7 +208,11 @@ func doUpdates(v view.View, e jumppad.Engine, source string, variables map[strin
 
 	v.UpdateStatus("Checking for changes...", false)
 	for {
-		time.Sleep(interval)
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-time.After(interval):
+		}
 
 		new, changed, removed, _, err := e.Diff(source, variables, variableFile)
 		if err != nil {
@@ -185,7 +232,11 @@ func doUpdates(v view.View, e jumppad.Engine, source string, variables map[strin
 				v.Logger().Debug("Changed", "resource", n.Metadata().ID)
 			}
 
-			_, err := e.ApplyWithVariables(context.Background(), source, variables, variableFile)
+			err := applyWithLock(ctx, v, e, source, variables, variableFile, lockTimeout)
+			if errors.Is(err, jumppad.ErrInterrupted) {
+				return err
+			}
+
 			if err != nil {
 				v.Logger().Error(err.Error())
 			}
@@ -194,3 +245,16 @@ func doUpdates(v view.View, e jumppad.Engine, source string, variables map[strin
 		}
 	}
 }
+
+// applyWithLock holds the state lock while the configuration is applied, the
+// lock is released between updates so that other commands can modify the state
+func applyWithLock(ctx context.Context, v view.View, e jumppad.Engine, source string, variables map[string]string, variableFile string, lockTimeout time.Duration) error {
+	unlock, err := lockState(lockTimeout, v.Logger())
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
+	_, err = e.ApplyWithVariables(ctx, source, variables, variableFile)
+	return err
+}
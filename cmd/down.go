@@ -1,62 +1,101 @@
 package cmd
 
 import (
-	"context"
 	"os"
-	"os/signal"
-	"syscall"
+	"time"
 
 	"github.com/jumppad-labs/jumppad/pkg/clients"
 	"github.com/jumppad-labs/jumppad/pkg/clients/connector"
 	"github.com/jumppad-labs/jumppad/pkg/clients/logger"
+	"github.com/jumppad-labs/jumppad/pkg/jumppad"
+	"github.com/jumppad-labs/jumppad/pkg/jumppad/tracing"
 	"github.com/jumppad-labs/jumppad/pkg/utils"
 	"github.com/spf13/cobra"
 )
 
 func newDestroyCmd(cc connector.Connector, l logger.Logger) *cobra.Command {
 	var force bool
+	var parallelism int
+	var lockTimeout time.Duration
+	var targets []string
+	var output string
+	var trace tracing.Options
 
 	downCmd := &cobra.Command{
-		Use:     "down",
-		Short:   "Remove all resources in the current state",
-		Long:    "Remove all resources in the current state",
-		Example: `jumppad down`,
+		Use:   "down",
+		Short: "Remove all resources in the current state",
+		Long:  "Remove all resources in the current state",
+		Example: `
+  # Remove all resources
+  jumppad down
+
+  # Remove a single container and the resources that depend on it
+  jumppad down --target resource.container.web
+	`,
 		Run: func(cmd *cobra.Command, args []string) {
+			h, err := eventHandler(output, cmd.OutOrStdout())
+			if err != nil {
+				l.Error("Unable to destroy stack", "error", err)
+				return
+			}
+
+			// stdout only contains the events
+			logger := createLogger()
+			if h != nil {
+				l.SetOutput(redactor.Writer(cmd.ErrOrStderr()))
+				logger.SetOutput(redactor.Writer(cmd.ErrOrStderr()))
+			}
+
 			engineClients, _ := clients.GenerateClients(l)
 			engineClients.ContainerTasks.SetForce(force)
 
-			engine, err := createEngine(l, engineClients)
+			tp, err := newTracerProvider(trace)
 			if err != nil {
-				l.Error("Unable to create engine", "error", err)
+				l.Error("Unable to destroy stack", "error", err)
 				return
 			}
 
-			logger := createLogger()
+			opts := []jumppad.Option{jumppad.WithEventHandler(h)}
+			if tp != nil {
+				opts = append(opts, jumppad.WithTracerProvider(tp))
+				defer shutdownTracerProvider(tp, l)
+			}
 
-			done := make(chan os.Signal, 1)
-			signal.Notify(done, syscall.SIGINT, syscall.SIGTERM)
+			engine, err := createEngine(l, engineClients, opts...)
+			if err != nil {
+				l.Error("Unable to create engine", "error", err)
+				return
+			}
 
-			ctx, cancel := context.WithCancel(context.Background())
-			defer cancel()
+			engine.SetParallelism(parallelism)
+			engine.SetTargets(targets)
+
+			interrupt := newInterruptHandler(logger)
+			defer interrupt.Stop()
 
 			cmd.Println("Destroying resources", " -- press ctrl c to cancel")
 			cmd.Println("")
 
 			logger.Debug("Destroying stack, press ctrl-c to stop", "force", force)
 
-			go func() {
-				<-done // Will block here until user hits ctrl+c
-
-				// cancel the context
-				cancel()
-			}()
+			unlock, err := lockState(lockTimeout, logger)
+			if err != nil {
+				l.Error("Unable to destroy stack", "error", err)
+				return
+			}
+			defer unlock()
 
-			err = engine.Destroy(ctx, force)
+			err = engine.Destroy(interrupt.Context(), force)
 			if err != nil {
 				l.Error("Unable to destroy stack", "error", err)
 				return
 			}
 
+			// the remaining resources are still running
+			if len(targets) > 0 {
+				return
+			}
+
 			// clean up the data folders
 			os.RemoveAll(utils.DataFolder("", os.ModePerm))
 			os.RemoveAll(utils.LibraryFolder("", os.ModePerm))
@@ -73,6 +112,12 @@ func newDestroyCmd(cc connector.Connector, l logger.Logger) *cobra.Command {
 	}
 
 	downCmd.Flags().BoolVarP(&force, "force", "", false, "When set to true Jumppad will not wait for containers to exit gracefully and will ignore errors")
+	downCmd.Flags().IntVarP(&parallelism, "parallelism", "", jumppad.DefaultParallelism, "Maximum number of resources that are destroyed at the same time, set to 1 to destroy resources one at a time")
+	downCmd.Flags().StringArrayVarP(&targets, "target", "", nil, targetUsage)
+	downCmd.Flags().StringVarP(&output, "output", "", outputText, outputUsage)
+	downCmd.Flags().StringVarP(&trace.File, "trace-file", "", "", traceFileUsage)
+	downCmd.Flags().StringVarP(&trace.Endpoint, "trace-endpoint", "", "", traceEndpointUsage)
+	downCmd.Flags().DurationVarP(&lockTimeout, "lock-timeout", "", 0, lockTimeoutUsage)
 
 	return downCmd
 }
@@ -0,0 +1,106 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/jumppad-labs/jumppad/pkg/clients/logger"
+	"github.com/jumppad-labs/jumppad/pkg/jumppad"
+	"github.com/spf13/cobra"
+)
+
+func newDriftCmd(e jumppad.Engine, l logger.Logger) *cobra.Command {
+	var repair bool
+	var jsonOutput bool
+	var lockTimeout time.Duration
+
+	driftCmd := &cobra.Command{
+		Use:   "drift",
+		Short: "Check the running resources for changes made outside of jumppad",
+		Long: `Check the running resources for changes made outside of jumppad.
+
+Each resource in the state is compared with the running resources, for example
+containers that have been stopped or removed, or detached from a network. Drifted
+resources are marked in the state and are re-created by the next 'jumppad up',
+or immediately when --repair is set.`,
+		Example: `
+  # Show the resources that have drifted
+  jumppad drift
+
+  # Re-create the resources that have drifted
+  jumppad drift --repair
+	`,
+		Args:         cobra.NoArgs,
+		SilenceUsage: true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			interrupt := newInterruptHandler(l)
+			defer interrupt.Stop()
+
+			unlock, err := lockState(lockTimeout, l)
+			if err != nil {
+				return err
+			}
+			defer unlock()
+
+			drifts, err := e.Drift(interrupt.Context(), repair)
+
+			if jsonOutput {
+				d, jerr := json.MarshalIndent(drifts, "", "  ")
+				if jerr != nil {
+					return fmt.Errorf("unable to output drift as JSON: %s", jerr)
+				}
+
+				cmd.Println(string(d))
+			} else if drifts != nil {
+				printDrift(cmd, drifts, repair)
+			}
+
+			return err
+		},
+	}
+
+	driftCmd.Flags().BoolVarP(&repair, "repair", "", false, "Re-create the resources that have drifted")
+	driftCmd.Flags().BoolVarP(&jsonOutput, "json", "", false, "Output the drifted resources as JSON")
+	driftCmd.Flags().DurationVarP(&lockTimeout, "lock-timeout", "", 0, lockTimeoutUsage)
+
+	return driftCmd
+}
+
+func printDrift(cmd *cobra.Command, drifts []*jumppad.ResourceDrift, repair bool) {
+	if len(drifts) == 0 {
+		cmd.Println("No drift detected, the running resources match the state")
+		return
+	}
+
+	for _, d := range drifts {
+		icon := yellowIcon.Render("!")
+		if repair {
+			icon = redIcon.Render("✘")
+			if d.Repaired {
+				icon = greenIcon.Render("✔")
+			}
+		}
+
+		cmd.Printf("%s %s\n", icon, whiteText.Render(d.ID))
+		for _, r := range d.Reasons {
+			cmd.Printf("    %s %s\n", grayText.Render("└─"), r)
+		}
+	}
+
+	cmd.Println()
+
+	if repair {
+		repaired := 0
+		for _, d := range drifts {
+			if d.Repaired {
+				repaired++
+			}
+		}
+
+		cmd.Printf("Drift: %d detected, %d repaired\n", len(drifts), repaired)
+		return
+	}
+
+	cmd.Printf("Drift: %d detected, run 'jumppad up' or 'jumppad drift --repair' to re-create the drifted resources\n", len(drifts))
+}
@@ -0,0 +1,28 @@
+package cmd
+
+import (
+	"fmt"
+	"io"
+
+	"github.com/jumppad-labs/jumppad/pkg/jumppad/events"
+)
+
+const (
+	outputText  = "text"
+	outputJSONL = "jsonl"
+)
+
+const outputUsage = "Output format, 'text' writes logs for people to read, 'jsonl' writes an event for each change to a resource as a line of JSON to stdout and writes the logs to stderr"
+
+// eventHandler returns the handler for the given output format, nil is
+// returned when the format does not emit events
+func eventHandler(output string, w io.Writer) (events.Handler, error) {
+	switch output {
+	case "", outputText:
+		return nil, nil
+	case outputJSONL:
+		return events.NewJSONLinesHandler(redactor.Writer(w)), nil
+	}
+
+	return nil, fmt.Errorf("invalid output '%s', valid values are '%s' and '%s'", output, outputText, outputJSONL)
+}
@@ -0,0 +1,120 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/jumppad-labs/jumppad/pkg/clients/getter"
+	"github.com/jumppad-labs/jumppad/pkg/jumppad"
+	"github.com/jumppad-labs/jumppad/pkg/utils"
+	"github.com/spf13/cobra"
+)
+
+func newGraphCmd(e jumppad.Engine, bp getter.Getter) *cobra.Command {
+	var variables []string
+	var variablesFile string
+	var format string
+	var highlight string
+
+	graphCmd := &cobra.Command{
+		Use:   "graph [file] | [directory]",
+		Short: "Output the dependency graph of the resources at the given path",
+		Long: `Output the dependency graph of the resources at the given path.
+
+The graph is written in the Graphviz DOT format by default, or as a Mermaid
+flowchart or JSON with --format. Each resource is annotated with its type, module,
+whether it is disabled and its status in the state. Edges point from a resource
+to the resource it depends on.
+
+The resources that a resource depends on and the resources that depend on it can be
+highlighted with --highlight.`,
+		Example: `
+  # Render the graph for the .hcl files in the current folder as an SVG
+  jumppad graph | dot -Tsvg > graph.svg
+
+  # Output a Mermaid flowchart highlighting the resources related to a container
+  jumppad graph --format mermaid --highlight resource.container.web ./blueprint
+	`,
+		Args:         cobra.MaximumNArgs(1),
+		SilenceUsage: true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			switch format {
+			case "dot", "mermaid", "json":
+			default:
+				return fmt.Errorf("unknown format '%s', valid formats are dot, mermaid and json", format)
+			}
+
+			// parse the vars into a map
+			vars := map[string]string{}
+			for _, v := range variables {
+				// if the variable is wrapped in single quotes remove them
+				v = strings.TrimPrefix(v, "'")
+				v = strings.TrimSuffix(v, "'")
+
+				parts := strings.Split(v, "=")
+				if len(parts) >= 2 {
+					vars[parts[0]] = strings.Join(parts[1:], "=")
+				}
+			}
+
+			// check the variables file exists
+			if variablesFile != "" {
+				if _, err := os.Stat(variablesFile); err != nil {
+					return fmt.Errorf("variables file %s, does not exist", variablesFile)
+				}
+			}
+
+			dst := "./"
+			if len(args) == 1 && args[0] != "." {
+				dst = args[0]
+			}
+
+			if !utils.IsLocalFolder(dst) && !utils.IsHCLFile(dst) {
+				// fetch the remote server from github
+				err := bp.Get(dst, utils.BlueprintLocalFolder(dst))
+				if err != nil {
+					return fmt.Errorf("unable to retrieve blueprint: %s", err)
+				}
+
+				dst = utils.BlueprintLocalFolder(dst)
+			}
+
+			g, err := e.Graph(dst, vars, variablesFile)
+			if err != nil {
+				return err
+			}
+
+			if highlight != "" {
+				err := g.Highlight(highlight)
+				if err != nil {
+					return err
+				}
+			}
+
+			switch format {
+			case "mermaid":
+				cmd.Print(g.Mermaid())
+			case "json":
+				d, err := json.MarshalIndent(g, "", "  ")
+				if err != nil {
+					return fmt.Errorf("unable to output graph as JSON: %s", err)
+				}
+
+				cmd.Println(string(d))
+			default:
+				cmd.Print(g.DOT())
+			}
+
+			return nil
+		},
+	}
+
+	graphCmd.Flags().StringSliceVarP(&variables, "var", "", nil, "Allows setting variables from the command line, variables are specified as a key and value, e.g --var key=value. Can be specified multiple times")
+	graphCmd.Flags().StringVarP(&variablesFile, "vars-file", "", "", "Load variables from a location other than *.vars files in the blueprint folder. E.g --vars-file=./file.vars")
+	graphCmd.Flags().StringVarP(&format, "format", "f", "dot", "Output format for the graph, one of dot, mermaid or json")
+	graphCmd.Flags().StringVarP(&highlight, "highlight", "", "", "Highlight the given resource, the resources it depends on and the resources that depend on it, e.g --highlight resource.container.web")
+
+	return graphCmd
+}
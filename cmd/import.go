@@ -0,0 +1,104 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/jumppad-labs/jumppad/pkg/clients/getter"
+	"github.com/jumppad-labs/jumppad/pkg/clients/logger"
+	"github.com/jumppad-labs/jumppad/pkg/jumppad"
+	"github.com/jumppad-labs/jumppad/pkg/utils"
+	"github.com/spf13/cobra"
+)
+
+func newImportCmd(e jumppad.Engine, bp getter.Getter, l logger.Logger) *cobra.Command {
+	var variables []string
+	var variablesFile string
+	var lockTimeout time.Duration
+
+	importCmd := &cobra.Command{
+		Use:   "import [resource] [id] [file] | [directory]",
+		Short: "Import an existing container or network into the state",
+		Long: `Import an existing container or network into the state.
+
+The object with the given Docker id or name is adopted as the resource in the
+configuration at the given path. The container name, image id and network addresses
+are read from Docker and the resource is added to the state as created, the next
+'jumppad up' manages the existing object instead of creating a new one.
+
+Networks are looked up by name, an imported network must have the same name and
+subnet as the network resource.
+
+Volumes can not be imported, volumes are not resources in the configuration. The
+image cache volume is reused when a volume with the same name already exists.`,
+		Example: `
+  # Import the container named postgres as the resource container.db
+  jumppad import resource.container.db postgres
+
+  # Import a network for the configuration in the folder ./blueprint
+  jumppad import resource.network.main main ./blueprint
+	`,
+		Args:         cobra.RangeArgs(2, 3),
+		SilenceUsage: true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			// parse the vars into a map
+			vars := map[string]string{}
+			for _, v := range variables {
+				// if the variable is wrapped in single quotes remove them
+				v = strings.TrimPrefix(v, "'")
+				v = strings.TrimSuffix(v, "'")
+
+				parts := strings.Split(v, "=")
+				if len(parts) >= 2 {
+					vars[parts[0]] = strings.Join(parts[1:], "=")
+				}
+			}
+
+			// check the variables file exists
+			if variablesFile != "" {
+				if _, err := os.Stat(variablesFile); err != nil {
+					return fmt.Errorf("variables file %s, does not exist", variablesFile)
+				}
+			}
+
+			dst := "./"
+			if len(args) == 3 && args[2] != "." {
+				dst = args[2]
+			}
+
+			if !utils.IsLocalFolder(dst) && !utils.IsHCLFile(dst) {
+				// fetch the remote server from github
+				err := bp.Get(dst, utils.BlueprintLocalFolder(dst))
+				if err != nil {
+					return fmt.Errorf("unable to retrieve blueprint: %s", err)
+				}
+
+				dst = utils.BlueprintLocalFolder(dst)
+			}
+
+			unlock, err := lockState(lockTimeout, l)
+			if err != nil {
+				return err
+			}
+			defer unlock()
+
+			r, err := e.Import(context.Background(), dst, vars, variablesFile, args[0], args[1])
+			if err != nil {
+				return err
+			}
+
+			cmd.Printf("%s Imported %s as %s\n", greenIcon.Render("✔"), args[1], whiteText.Render(r.Metadata().ID))
+
+			return nil
+		},
+	}
+
+	importCmd.Flags().StringSliceVarP(&variables, "var", "", nil, "Allows setting variables from the command line, variables are specified as a key and value, e.g --var key=value. Can be specified multiple times")
+	importCmd.Flags().StringVarP(&variablesFile, "vars-file", "", "", "Load variables from a location other than *.vars files in the blueprint folder. E.g --vars-file=./file.vars")
+	importCmd.Flags().DurationVarP(&lockTimeout, "lock-timeout", "", 0, lockTimeoutUsage)
+
+	return importCmd
+}
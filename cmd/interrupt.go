@@ -0,0 +1,82 @@
+package cmd
+
+import (
+	"context"
+	"os"
+	"os/signal"
+	"sync"
+	"syscall"
+
+	"github.com/jumppad-labs/jumppad/pkg/clients/logger"
+)
+
+// exit is replaced in tests
+var exit = os.Exit
+
+// interruptHandler cancels its context when the process receives SIGINT or
+// SIGTERM, this allows resources that are being created or destroyed to
+// finish or stop cleanly. A second signal exits the process immediately.
+type interruptHandler struct {
+	ctx    context.Context
+	cancel context.CancelFunc
+	sigs   chan os.Signal
+	done   chan struct{}
+	once   sync.Once
+	log    logger.Logger
+}
+
+func newInterruptHandler(l logger.Logger) *interruptHandler {
+	ctx, cancel := context.WithCancel(context.Background())
+
+	h := &interruptHandler{
+		ctx:    ctx,
+		cancel: cancel,
+		sigs:   make(chan os.Signal, 2),
+		done:   make(chan struct{}),
+		log:    l,
+	}
+
+	signal.Notify(h.sigs, syscall.SIGINT, syscall.SIGTERM)
+
+	go h.wait()
+
+	return h
+}
+
+// Context returns the context that is cancelled by the first signal
+func (h *interruptHandler) Context() context.Context {
+	return h.ctx
+}
+
+// Interrupt cancels the context in the same way as the first signal
+func (h *interruptHandler) Interrupt() {
+	h.cancel()
+}
+
+// Stop releases the signals, it must be called once the operation
+// has completed
+func (h *interruptHandler) Stop() {
+	h.once.Do(func() {
+		signal.Stop(h.sigs)
+		close(h.done)
+		h.cancel()
+	})
+}
+
+func (h *interruptHandler) wait() {
+	select {
+	case <-h.sigs:
+		h.log.Info("Interrupt received, waiting for resources in progress to finish, press ctrl-c again to exit immediately")
+		h.cancel()
+	case <-h.ctx.Done():
+	case <-h.done:
+		return
+	}
+
+	select {
+	case <-h.sigs:
+		h.log.Error("Exiting immediately, the state may not match the running resources, run 'jumppad up' to repair it")
+		exit(1)
+	case <-h.done:
+	}
+}
@@ -0,0 +1,55 @@
+package cmd
+
+import (
+	"syscall"
+	"testing"
+	"time"
+
+	"github.com/jumppad-labs/jumppad/pkg/clients/logger"
+	"github.com/stretchr/testify/require"
+)
+
+func TestInterruptHandlerCancelsContextOnFirstSignal(t *testing.T) {
+	exited := make(chan int, 1)
+	osExit := exit
+	exit = func(code int) { exited <- code }
+	t.Cleanup(func() { exit = osExit })
+
+	h := newInterruptHandler(logger.NewTestLogger(t))
+	defer h.Stop()
+
+	err := syscall.Kill(syscall.Getpid(), syscall.SIGINT)
+	require.NoError(t, err)
+
+	require.Eventually(t, func() bool { return h.Context().Err() != nil }, time.Second, 10*time.Millisecond)
+	require.Empty(t, exited)
+}
+
+func TestInterruptHandlerExitsOnSecondSignal(t *testing.T) {
+	exited := make(chan int, 1)
+	osExit := exit
+	exit = func(code int) { exited <- code }
+	t.Cleanup(func() { exit = osExit })
+
+	h := newInterruptHandler(logger.NewTestLogger(t))
+	defer h.Stop()
+
+	h.Interrupt()
+
+	err := syscall.Kill(syscall.Getpid(), syscall.SIGTERM)
+	require.NoError(t, err)
+
+	select {
+	case code := <-exited:
+		require.Equal(t, 1, code)
+	case <-time.After(time.Second):
+		require.Fail(t, "expected the process to exit")
+	}
+}
+
+func TestInterruptHandlerStopReleasesSignals(t *testing.T) {
+	h := newInterruptHandler(logger.NewTestLogger(t))
+	h.Stop()
+
+	require.Error(t, h.Context().Err())
+}
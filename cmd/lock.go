@@ -0,0 +1,27 @@
+package cmd
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/jumppad-labs/jumppad/pkg/clients/logger"
+	"github.com/jumppad-labs/jumppad/pkg/config"
+)
+
+const lockTimeoutUsage = "Duration to wait for the state lock when another jumppad process is modifying the state, e.g. --lock-timeout=30s"
+
+// lockState acquires the lock on the state and returns a function
+// that releases it
+func lockState(timeout time.Duration, l logger.Logger) (func(), error) {
+	lock, err := config.LockState(timeout)
+	if err != nil {
+		return nil, fmt.Errorf("unable to acquire state lock: %w", err)
+	}
+
+	return func() {
+		err := lock.Unlock()
+		if err != nil {
+			l.Error("Unable to release state lock", "error", err)
+		}
+	}, nil
+}
@@ -7,7 +7,9 @@ import (
 	"strings"
 
 	"github.com/hokaccha/go-prettyjson"
+	"github.com/jumppad-labs/hclconfig"
 	"github.com/jumppad-labs/hclconfig/resources"
+	"github.com/jumppad-labs/jumppad/pkg/clients/logger"
 	"github.com/jumppad-labs/jumppad/pkg/config"
 	"github.com/spf13/cobra"
 )
@@ -15,8 +17,13 @@ import (
 var outputCmd = &cobra.Command{
 	Use:   "output",
 	Short: "Show the output variables",
-	Long:  `Show the output variables`,
-	Args:  cobra.MaximumNArgs(1),
+	Long: `Show the output variables.
+
+Outputs marked with the sensitive function are redacted, the value of a
+sensitive output is only shown when the output is requested by name.`,
+	Example: `jumppad output
+jumppad output db_password`,
+	Args: cobra.MaximumNArgs(1),
 	Run: func(cmd *cobra.Command, args []string) {
 		// load the stack
 		cfg, err := config.LoadState()
@@ -38,17 +45,28 @@ var outputCmd = &cobra.Command{
 					continue
 				}
 
-				out[r.Metadata().Name] = r.(*resources.Output).Value
-
 				if len(args) > 0 && strings.EqualFold(args[0], r.Metadata().Name) {
 					d, _ := json.Marshal(r.(*resources.Output).Value)
 					fmt.Printf("%s", string(d))
 					return
 				}
+
+				out[r.Metadata().Name] = r.(*resources.Output).Value
+				if config.IsSensitive(r) {
+					out[r.Metadata().Name] = logger.Redacted
+				}
 			}
 		}
 
 		d, _ := prettyjson.Marshal(out)
-		fmt.Printf("%s", string(d))
+		fmt.Printf("%s", stateRedactor(cfg).Redact(string(d)))
 	},
 }
+
+// stateRedactor returns a redactor for the sensitive values in the state
+func stateRedactor(c *hclconfig.Config) *logger.Redactor {
+	r := logger.NewRedactor()
+	r.Add(config.SensitiveConfigValues(c)...)
+
+	return r
+}
@@ -0,0 +1,191 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/jumppad-labs/hclconfig/resources"
+	"github.com/jumppad-labs/jumppad/pkg/clients/getter"
+	"github.com/jumppad-labs/jumppad/pkg/jumppad"
+	"github.com/jumppad-labs/jumppad/pkg/utils"
+	"github.com/spf13/cobra"
+)
+
+const targetUsage = "Restrict the operation to the given resource or module, e.g. --target resource.container.web. Can be specified multiple times"
+
+func newPlanCmd(e jumppad.Engine, bp getter.Getter) *cobra.Command {
+	var variables []string
+	var variablesFile string
+	var out string
+	var targets []string
+
+	planCmd := &cobra.Command{
+		Use:   "plan [file] | [directory]",
+		Short: "Show the changes that up would make to the resources at the given path",
+		Long: `Show the changes that up would make to the resources at the given path.
+
+The plan lists every resource that would be created, refreshed, recreated or destroyed,
+the reason for the change, and the attributes that differ from the state.
+The plan can be saved to a file with --out and applied with "jumppad up --plan <file>".`,
+		Example: `
+  # Show the changes for the .hcl files in the current folder
+  jumppad plan
+
+  # Save the plan to a file and apply it
+  jumppad plan --out jumppad.plan
+  jumppad up --plan jumppad.plan
+	`,
+		Args:         cobra.ArbitraryArgs,
+		RunE:         newPlanCmdFunc(e, bp, &variables, &variablesFile, &targets, &out),
+		SilenceUsage: true,
+	}
+
+	planCmd.Flags().StringSliceVarP(&variables, "var", "", nil, "Allows setting variables from the command line, variables are specified as a key and value, e.g --var key=value. Can be specified multiple times")
+	planCmd.Flags().StringVarP(&variablesFile, "vars-file", "", "", "Load variables from a location other than *.vars files in the blueprint folder. E.g --vars-file=./file.vars")
+	planCmd.Flags().StringArrayVarP(&targets, "target", "", nil, targetUsage)
+	planCmd.Flags().StringVarP(&out, "out", "o", "", "Write the plan to the given file so that it can be applied with 'jumppad up --plan'")
+
+	return planCmd
+}
+
+func newPlanCmdFunc(e jumppad.Engine, bp getter.Getter, variables *[]string, variablesFile *string, targets *[]string, out *string) func(cmd *cobra.Command, args []string) error {
+	return func(cmd *cobra.Command, args []string) error {
+		e.SetTargets(*targets)
+
+		// create the jumppad and sub folders in the users home directory
+		utils.CreateFolders()
+
+		// parse the vars into a map
+		vars := map[string]string{}
+		for _, v := range *variables {
+			// if the variable is wrapped in single quotes remove them
+			v = strings.TrimPrefix(v, "'")
+			v = strings.TrimSuffix(v, "'")
+
+			parts := strings.Split(v, "=")
+			if len(parts) >= 2 {
+				vars[parts[0]] = strings.Join(parts[1:], "=")
+			}
+		}
+
+		// check the variables file exists
+		if variablesFile != nil && *variablesFile != "" {
+			if _, err := os.Stat(*variablesFile); err != nil {
+				return fmt.Errorf("variables file %s, does not exist", *variablesFile)
+			}
+		} else {
+			vf := ""
+			variablesFile = &vf
+		}
+
+		dst := ""
+		if len(args) == 1 {
+			dst = args[0]
+		} else {
+			dst = "./"
+		}
+
+		if dst == "." {
+			dst = "./"
+		}
+
+		if !utils.IsLocalFolder(dst) && !utils.IsHCLFile(dst) {
+			// fetch the remote server from github
+			err := bp.Get(dst, utils.BlueprintLocalFolder(dst))
+			if err != nil {
+				return fmt.Errorf("unable to retrieve blueprint: %s", err)
+			}
+
+			dst = utils.BlueprintLocalFolder(dst)
+		}
+
+		plan, err := e.Plan(dst, vars, *variablesFile)
+		if err != nil {
+			return err
+		}
+
+		printPlan(cmd, plan)
+
+		if *out != "" {
+			err := plan.Save(*out)
+			if err != nil {
+				return err
+			}
+
+			cmd.Println()
+			cmd.Printf("Plan saved to '%s', apply it with: jumppad up --plan %s\n", *out, *out)
+		}
+
+		return nil
+	}
+}
+
+func printPlan(cmd *cobra.Command, plan *jumppad.Plan) {
+	counts := map[string]int{}
+
+	cmd.Println()
+
+	for _, c := range plan.Changes {
+		if c.Action == jumppad.ActionNone {
+			continue
+		}
+
+		switch c.Type {
+		case resources.TypeModule, resources.TypeVariable, resources.TypeOutput, resources.TypeLocal:
+			continue
+		}
+
+		counts[c.Action]++
+
+		icon := ""
+		switch c.Action {
+		case jumppad.ActionCreate:
+			icon = greenIcon.Render("+")
+		case jumppad.ActionRefresh:
+			icon = yellowIcon.Render("~")
+		case jumppad.ActionRecreate:
+			icon = yellowIcon.Render("-/+")
+		case jumppad.ActionDestroy:
+			icon = redIcon.Render("-")
+		}
+
+		cmd.Printf("%s%s %s\n", icon, whiteText.Render(c.ID), grayText.Render("("+c.Action+")"))
+
+		for _, r := range c.Reasons {
+			cmd.Printf("    %s\n", grayText.Render(r))
+		}
+
+		for _, a := range c.Attributes {
+			cmd.Printf("      %s%s: %s => %s\n", yellowIcon.Render("~"), a.Path, formatPlanValue(a.Before), formatPlanValue(a.After))
+		}
+	}
+
+	if len(counts) == 0 {
+		cmd.Println("No changes, the resources are up to date")
+		return
+	}
+
+	cmd.Println()
+	cmd.Printf(
+		"Plan: %d to create, %d to refresh, %d to recreate, %d to destroy\n",
+		counts[jumppad.ActionCreate],
+		counts[jumppad.ActionRefresh],
+		counts[jumppad.ActionRecreate],
+		counts[jumppad.ActionDestroy],
+	)
+}
+
+func formatPlanValue(v any) string {
+	if v == nil {
+		return "(null)"
+	}
+
+	d, err := json.Marshal(v)
+	if err != nil {
+		return fmt.Sprintf("%v", v)
+	}
+
+	return string(d)
+}
@@ -1,6 +1,7 @@
 package cmd
 
 import (
+	"context"
 	"errors"
 	"fmt"
 	"os"
@@ -86,7 +87,7 @@ func pushK8sCluster(image string, c *k8s.Cluster, log logger.Logger, force bool)
 
 	for _, id := range ids {
 		log.Info("Pushing to container", "id", id, "image", image)
-		err = cl.ImportLocalDockerImages([]types.Image{{Name: strings.Trim(image, " ")}}, force)
+		err = cl.ImportLocalDockerImages(context.Background(), []types.Image{{Name: strings.Trim(image, " ")}}, force)
 		if err != nil {
 			return fmt.Errorf("error pushing image: %w ", err)
 		}
@@ -103,7 +104,7 @@ func pushNomadCluster(image string, c *nomad.NomadCluster, log logger.Logger, fo
 	// get the id of the cluster
 
 	log.Info("Pushing to container", "ref", c.Meta.ID, "image", image)
-	err := cl.ImportLocalDockerImages([]types.Image{{Name: strings.Trim(image, " ")}}, force)
+	err := cl.ImportLocalDockerImages(context.Background(), []types.Image{{Name: strings.Trim(image, " ")}}, force)
 	if err != nil {
 		return fmt.Errorf("error pushing image: %w ", err)
 	}
@@ -2,7 +2,9 @@ package cmd
 
 import (
 	"fmt"
+	"log"
 	"os"
+	"slices"
 	"strings"
 
 	"github.com/jumppad-labs/jumppad/cmd/changelog"
@@ -10,7 +12,9 @@ import (
 	"github.com/jumppad-labs/jumppad/pkg/clients/logger"
 	"github.com/jumppad-labs/jumppad/pkg/config"
 	"github.com/jumppad-labs/jumppad/pkg/jumppad"
+	"github.com/jumppad-labs/jumppad/pkg/utils"
 
+	"github.com/jumppad-labs/jumppad/pkg/clients/container"
 	"github.com/spf13/cobra"
 )
 
@@ -20,14 +24,23 @@ var rootCmd = &cobra.Command{
 	Long:  `Jumppad is a tool that helps you create and run development, demo, and tutorial environments`,
 }
 
+// redactor removes sensitive values from the logs and events, the engine
+// adds values to it as they are found in the configuration and state
+var redactor = logger.NewRedactor()
+
 var version string //lint:ignore U1000 set at runtime
 var date string    //lint:ignore U1000 set at runtime
 var commit string  //lint:ignore U1000 set at runtime
 
-func createEngine(l logger.Logger, c *clients.Clients) (jumppad.Engine, error) {
+func createEngine(l logger.Logger, c *clients.Clients, opts ...jumppad.Option) (jumppad.Engine, error) {
 	providers := config.NewProviders(c)
 
-	engine, err := jumppad.New(providers, l)
+	opts = append([]jumppad.Option{jumppad.WithRedactor(redactor)}, opts...)
+
+	// Set the standard writer to our logger as the DAG uses the standard library log.
+	log.SetOutput(l.StandardWriter())
+
+	engine, err := jumppad.New(providers, l, opts...)
 	if err != nil {
 		return nil, err
 	}
@@ -38,18 +51,23 @@ func createEngine(l logger.Logger, c *clients.Clients) (jumppad.Engine, error) {
 func createLogger() logger.Logger {
 	// set the log level
 	if lev := os.Getenv("LOG_LEVEL"); lev != "" {
-		return logger.NewLogger(os.Stdout, lev)
+		return logger.NewLogger(redactor.Writer(os.Stdout), lev)
 	}
 
-	return logger.NewLogger(os.Stdout, logger.LogLevelInfo)
+	return logger.NewLogger(redactor.Writer(os.Stdout), logger.LogLevelInfo)
 }
 
 // Execute the root command
 func Execute(v, c, d string) error {
 	version = v
+	config.Version = v
 	commit = c
 	date = d
 
+	// the clients are created before the flags are parsed so the flags
+	// that configure the clients are read from the arguments
+	setClientEnv(os.Args[1:])
+
 	// setup dependencies
 	l := createLogger()
 
@@ -66,7 +84,7 @@ func Execute(v, c, d string) error {
 	rootCmd.AddCommand(newDestroyCmd(engineClients.Connector, l))
 	rootCmd.AddCommand(statusCmd)
 	rootCmd.AddCommand(newPurgeCmd(engineClients.Docker, engineClients.ImageLog, l))
-	rootCmd.AddCommand(taintCmd)
+	rootCmd.AddCommand(newTaintCmd(l))
 	rootCmd.AddCommand(newVersionCmd())
 	rootCmd.AddCommand(uninstallCmd)
 	rootCmd.AddCommand(newPushCmd(engineClients.ContainerTasks, l))
@@ -88,15 +106,47 @@ func Execute(v, c, d string) error {
 
 	// add the validate command
 	rootCmd.AddCommand(newValidateCmd(engine, engineClients.Getter))
+	rootCmd.AddCommand(newPlanCmd(engine, engineClients.Getter))
+	rootCmd.AddCommand(newGraphCmd(engine, engineClients.Getter))
+	rootCmd.AddCommand(newDriftCmd(engine, l))
+	rootCmd.AddCommand(newStopCmd(engine, engineClients.ContainerTasks, l))
+	rootCmd.AddCommand(newStartCmd(engine, l))
+	rootCmd.AddCommand(newSnapshotCmd(engine, engineClients.ContainerTasks, l))
+	rootCmd.AddCommand(newImportCmd(engine, engineClients.Getter, l))
 
 	// add the fmt command
 	rootCmd.AddCommand(newFormatCmd())
 
+	// add the workspace commands
+	rootCmd.AddCommand(newWorkspaceCmd())
+
+	// add the state commands
+	rootCmd.AddCommand(newStateCmd(engine, engineClients.Docker, l))
+
+	// add the trace commands
+	rootCmd.AddCommand(newTraceCmd())
+
 	rootCmd.SilenceErrors = true
 
 	// set a pre run function to show the changelog
 	rootCmd.PersistentFlags().Bool("non-interactive", false, "Run in non-interactive mode")
+	rootCmd.PersistentFlags().String("workspace", "", fmt.Sprintf("Workspace to use instead of the selected workspace, can also be set with the %s environment variable", utils.WorkspaceEnvVar))
+	rootCmd.PersistentFlags().String("runtime", "", fmt.Sprintf("Container runtime to use, one of %s, can also be set with the %s environment variable", strings.Join(container.Runtimes, ", "), container.RuntimeEnvVar))
+	rootCmd.PersistentFlags().String("context", "", fmt.Sprintf("Docker context to use, overrides DOCKER_HOST and the docker_context setting, can also be set with the %s environment variable", utils.DockerContextEnvVar))
 	rootCmd.PersistentPreRunE = func(cmd *cobra.Command, args []string) error {
+		err := setWorkspace(cmd)
+		if err != nil {
+			return err
+		}
+
+		if rt := container.Runtime(); !slices.Contains(container.Runtimes, rt) {
+			return fmt.Errorf("unknown container runtime '%s', valid runtimes are %s", rt, strings.Join(container.Runtimes, ", "))
+		}
+
+		if _, err := utils.GetDockerEndpoint(); err != nil {
+			return err
+		}
+
 		ni, _ := cmd.Flags().GetBool("non-interactive")
 		if ni {
 			return nil
@@ -107,7 +157,7 @@ func Execute(v, c, d string) error {
 		// replace """ with ``` in changelog
 		changes = strings.ReplaceAll(changes, `"""`, "```")
 
-		err := cl.Show(changes, changesVersion, false)
+		err = cl.Show(changes, changesVersion, false)
 		if err != nil {
 			showErr(err)
 			return err
@@ -134,6 +184,79 @@ func Execute(v, c, d string) error {
 	return err
 }
 
+// setWorkspace sets the workspace from the --workspace flag and checks that
+// the current workspace exists, the workspace is set in the environment so
+// that it is also used by any child processes
+func setWorkspace(cmd *cobra.Command) error {
+	if ws, _ := cmd.Flags().GetString("workspace"); ws != "" {
+		os.Setenv(utils.WorkspaceEnvVar, ws)
+	}
+
+	ws := utils.Workspace()
+
+	err := utils.ValidateWorkspaceName(ws)
+	if err != nil {
+		return fmt.Errorf("invalid workspace '%s': %s", ws, err)
+	}
+
+	// workspace commands manage their own workspaces
+	if cmd.Parent() != nil && cmd.Parent().Name() == "workspace" {
+		return nil
+	}
+
+	if !utils.WorkspaceExists(ws) {
+		return fmt.Errorf("workspace '%s' does not exist, create it with 'jumppad workspace new %s'", ws, ws)
+	}
+
+	return nil
+}
+
+// setClientEnv sets the workspace, container runtime and Docker context from
+// the flags in args, the values are set in the environment so that they are
+// also used by any child processes
+func setClientEnv(args []string) {
+	if ws := flagFromArgs(args, "workspace"); ws != "" {
+		os.Setenv(utils.WorkspaceEnvVar, ws)
+	}
+
+	if rt := flagFromArgs(args, "runtime"); rt != "" {
+		os.Setenv(container.RuntimeEnvVar, rt)
+	}
+
+	if dc := flagFromArgs(args, "context"); dc != "" {
+		os.Setenv(utils.DockerContextEnvVar, dc)
+		return
+	}
+
+	// the context can also be set in the settings file of the workspace
+	if os.Getenv(utils.DockerContextEnvVar) == "" {
+		s, err := config.LoadSettings()
+		if err == nil && s.DockerContext != "" {
+			os.Setenv(utils.DockerContextEnvVar, s.DockerContext)
+		}
+	}
+}
+
+// flagFromArgs returns the value of the flag with the given name in args, or
+// an empty string when the flag is not set
+func flagFromArgs(args []string, name string) string {
+	for i, a := range args {
+		if a == "--" {
+			break
+		}
+
+		if v, ok := strings.CutPrefix(a, "--"+name+"="); ok {
+			return v
+		}
+
+		if a == "--"+name && i+1 < len(args) {
+			return args[i+1]
+		}
+	}
+
+	return ""
+}
+
 func showErr(err error) {
 	fmt.Println("")
 	fmt.Println(err)
@@ -0,0 +1,64 @@
+package cmd
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/jumppad-labs/jumppad/pkg/clients/container"
+	"github.com/jumppad-labs/jumppad/pkg/utils"
+	"github.com/stretchr/testify/require"
+)
+
+func setupClientEnv(t *testing.T) {
+	t.Setenv(utils.HomeEnvName(), t.TempDir())
+	t.Setenv(utils.WorkspaceEnvVar, "")
+	t.Setenv(container.RuntimeEnvVar, "")
+	t.Setenv(utils.DockerContextEnvVar, "")
+}
+
+func TestFlagFromArgsReadsBothForms(t *testing.T) {
+	require.Equal(t, "buildbox", flagFromArgs([]string{"up", "--context", "buildbox", "."}, "context"))
+	require.Equal(t, "buildbox", flagFromArgs([]string{"up", "--context=buildbox", "."}, "context"))
+	require.Equal(t, "", flagFromArgs([]string{"up", "--context"}, "context"))
+	require.Equal(t, "", flagFromArgs([]string{"exec", "--", "--context", "buildbox"}, "context"))
+}
+
+func TestSetClientEnvSetsContextFromFlag(t *testing.T) {
+	setupClientEnv(t)
+
+	setClientEnv([]string{"up", "--context", "buildbox", "--runtime=podman"})
+
+	require.Equal(t, "buildbox", os.Getenv(utils.DockerContextEnvVar))
+	require.Equal(t, "podman", os.Getenv(container.RuntimeEnvVar))
+}
+
+func TestSetClientEnvSetsContextFromSettings(t *testing.T) {
+	setupClientEnv(t)
+
+	os.MkdirAll(utils.WorkspaceHome(), 0755)
+	err := os.WriteFile(utils.SettingsPath(), []byte("jumppad {\n  docker_context = \"buildbox\"\n}\n"), 0644)
+	require.NoError(t, err)
+
+	setClientEnv([]string{"up"})
+	require.Equal(t, "buildbox", os.Getenv(utils.DockerContextEnvVar))
+
+	// the flag overrides the settings file
+	setClientEnv([]string{"up", "--context", "laptop"})
+	require.Equal(t, "laptop", os.Getenv(utils.DockerContextEnvVar))
+}
+
+func TestSetClientEnvReadsSettingsOfSelectedWorkspace(t *testing.T) {
+	setupClientEnv(t)
+
+	ws := utils.WorkspaceFolder("remote")
+	os.MkdirAll(ws, 0755)
+	err := os.WriteFile(filepath.Join(ws, "settings.hcl"), []byte("jumppad {\n  docker_context = \"buildbox\"\n}\n"), 0644)
+	require.NoError(t, err)
+
+	setClientEnv([]string{"up"})
+	require.Equal(t, "", os.Getenv(utils.DockerContextEnvVar))
+
+	setClientEnv([]string{"up", "--workspace", "remote"})
+	require.Equal(t, "buildbox", os.Getenv(utils.DockerContextEnvVar))
+}
@@ -0,0 +1,178 @@
+package cmd
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/jumppad-labs/jumppad/pkg/clients/container"
+	"github.com/jumppad-labs/jumppad/pkg/clients/logger"
+	"github.com/jumppad-labs/jumppad/pkg/config"
+	"github.com/jumppad-labs/jumppad/pkg/jumppad"
+	"github.com/jumppad-labs/jumppad/pkg/utils"
+	"github.com/spf13/cobra"
+)
+
+func newSnapshotCmd(e jumppad.Engine, ct container.ContainerTasks, l logger.Logger) *cobra.Command {
+	snapshotCmd := &cobra.Command{
+		Use:   "snapshot",
+		Short: "Save and restore snapshots of an environment",
+		Long: `Save and restore snapshots of an environment.
+
+A snapshot commits the containers of the resources in the current state to images,
+archives their volumes and records the state. Restoring a snapshot creates the
+containers from the images without running the config, resources such as exec,
+helm, terraform and random are added to the state with the values they had when
+the snapshot was created and are not run again.`,
+	}
+
+	snapshotCmd.AddCommand(newSnapshotCreateCmd(e, ct, l))
+	snapshotCmd.AddCommand(newSnapshotListCmd())
+	snapshotCmd.AddCommand(newSnapshotRestoreCmd(e, l))
+	snapshotCmd.AddCommand(newSnapshotDeleteCmd(ct))
+
+	return snapshotCmd
+}
+
+func newSnapshotCreateCmd(e jumppad.Engine, ct container.ContainerTasks, l logger.Logger) *cobra.Command {
+	var parallelism int
+	var lockTimeout time.Duration
+
+	createCmd := &cobra.Command{
+		Use:   "create [name]",
+		Short: "Create a snapshot of the resources in the current state",
+		Example: `
+  # Create a snapshot of the running environment
+  jumppad snapshot create training
+	`,
+		Args:         cobra.ExactArgs(1),
+		SilenceUsage: true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			e.SetParallelism(parallelism)
+
+			interrupt := newInterruptHandler(l)
+			defer interrupt.Stop()
+
+			unlock, err := lockState(lockTimeout, l)
+			if err != nil {
+				return err
+			}
+			defer unlock()
+
+			s, err := e.Snapshot(interrupt.Context(), args[0])
+			if err != nil {
+				// remove any images that were committed before the failure
+				if s != nil {
+					s.RemoveImages(ct)
+				}
+
+				return err
+			}
+
+			cmd.Printf("Created snapshot '%s', run 'jumppad snapshot restore %s' to restore it\n", s.Name, s.Name)
+			return nil
+		},
+	}
+
+	createCmd.Flags().IntVarP(&parallelism, "parallelism", "", jumppad.DefaultParallelism, "Maximum number of resources that are saved at the same time")
+	createCmd.Flags().DurationVarP(&lockTimeout, "lock-timeout", "", 0, lockTimeoutUsage)
+
+	return createCmd
+}
+
+func newSnapshotListCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:          "list",
+		Short:        "List the snapshots",
+		Args:         cobra.NoArgs,
+		SilenceUsage: true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			snapshots, err := config.ListSnapshots(utils.SnapshotsDir())
+			if err != nil {
+				return err
+			}
+
+			for _, s := range snapshots {
+				cmd.Printf("%-32s %s  %d containers\n", s.Name, s.Created.Format(time.RFC3339), len(s.Containers))
+			}
+
+			return nil
+		},
+	}
+}
+
+func newSnapshotRestoreCmd(e jumppad.Engine, l logger.Logger) *cobra.Command {
+	var parallelism int
+	var lockTimeout time.Duration
+
+	restoreCmd := &cobra.Command{
+		Use:   "restore [name]",
+		Short: "Create the resources in a snapshot",
+		Long: `Create the resources in a snapshot.
+
+The current state must not contain any resources, run 'jumppad down' before
+restoring a snapshot. Resources that fail to restore are marked as failed and are
+re-created by the next 'jumppad up'.`,
+		Example: `
+  # Restore the environment saved in a snapshot
+  jumppad snapshot restore training
+	`,
+		Args:         cobra.ExactArgs(1),
+		SilenceUsage: true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			e.SetParallelism(parallelism)
+
+			interrupt := newInterruptHandler(l)
+			defer interrupt.Stop()
+
+			unlock, err := lockState(lockTimeout, l)
+			if err != nil {
+				return err
+			}
+			defer unlock()
+
+			_, err = e.Restore(interrupt.Context(), args[0])
+			if err != nil {
+				return err
+			}
+
+			cmd.Printf("Restored snapshot '%s'\n", args[0])
+			return nil
+		},
+	}
+
+	restoreCmd.Flags().IntVarP(&parallelism, "parallelism", "", jumppad.DefaultParallelism, "Maximum number of resources that are restored at the same time")
+	restoreCmd.Flags().DurationVarP(&lockTimeout, "lock-timeout", "", 0, lockTimeoutUsage)
+
+	return restoreCmd
+}
+
+func newSnapshotDeleteCmd(ct container.ContainerTasks) *cobra.Command {
+	return &cobra.Command{
+		Use:          "delete [name]",
+		Short:        "Delete a snapshot and its images",
+		Example:      `jumppad snapshot delete training`,
+		Args:         cobra.ExactArgs(1),
+		SilenceUsage: true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			name := args[0]
+
+			s, _, err := config.LoadSnapshot(utils.SnapshotsDir(), name)
+			if err != nil {
+				return err
+			}
+
+			err = s.RemoveImages(ct)
+			if err != nil {
+				return fmt.Errorf("unable to remove images for snapshot '%s': %s", name, err)
+			}
+
+			err = config.RemoveSnapshot(utils.SnapshotsDir(), name)
+			if err != nil {
+				return err
+			}
+
+			cmd.Printf("Deleted snapshot '%s'\n", name)
+			return nil
+		},
+	}
+}
@@ -0,0 +1,561 @@
+package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/docker/docker/errdefs"
+	"github.com/hokaccha/go-prettyjson"
+	"github.com/jumppad-labs/hclconfig"
+	"github.com/jumppad-labs/hclconfig/resources"
+	"github.com/jumppad-labs/hclconfig/types"
+	"github.com/jumppad-labs/jumppad/pkg/clients/container"
+	"github.com/jumppad-labs/jumppad/pkg/clients/logger"
+	"github.com/jumppad-labs/jumppad/pkg/config"
+	ct "github.com/jumppad-labs/jumppad/pkg/config/resources/container"
+	"github.com/jumppad-labs/jumppad/pkg/config/resources/docs"
+	"github.com/jumppad-labs/jumppad/pkg/config/resources/k8s"
+	"github.com/jumppad-labs/jumppad/pkg/config/resources/nomad"
+	"github.com/jumppad-labs/jumppad/pkg/jumppad"
+	"github.com/jumppad-labs/jumppad/pkg/jumppad/constants"
+	"github.com/jumppad-labs/jumppad/pkg/utils"
+	"github.com/spf13/cobra"
+)
+
+func newStateCmd(e jumppad.Engine, dt container.Docker, l logger.Logger) *cobra.Command {
+	stateCmd := &cobra.Command{
+		Use:   "state",
+		Short: "Inspect and manage the state",
+		Long: `Inspect and manage the state.
+
+Every time the state changes the previous version is kept in the state history,
+the number of versions kept can be set with the state_history attribute in the
+workspace settings or the JUMPPAD_STATE_HISTORY environment variable.`,
+	}
+
+	stateCmd.AddCommand(newStateListCmd())
+	stateCmd.AddCommand(newStateShowCmd())
+	stateCmd.AddCommand(newStateRmCmd(l))
+	stateCmd.AddCommand(newStateMvCmd(dt, l))
+	stateCmd.AddCommand(newStateHistoryCmd())
+	stateCmd.AddCommand(newStateRollbackCmd(e, l))
+
+	return stateCmd
+}
+
+func newStateListCmd() *cobra.Command {
+	var resourceType string
+	var module string
+
+	listCmd := &cobra.Command{
+		Use:   "list",
+		Short: "List the resources in the state",
+		Example: `jumppad state list
+jumppad state list --type container --module consul`,
+		Args:         cobra.NoArgs,
+		SilenceUsage: true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			cfg, err := config.LoadState()
+			if err != nil {
+				return fmt.Errorf("unable to load state, do you have a running blueprint? %s", err)
+			}
+
+			ids := []string{}
+			status := map[string]string{}
+			for _, r := range cfg.Resources {
+				if resourceType != "" && r.Metadata().Type != resourceType {
+					continue
+				}
+
+				if module != "" && r.Metadata().Module != module && !strings.HasPrefix(r.Metadata().Module, module+".") {
+					continue
+				}
+
+				ids = append(ids, r.Metadata().ID)
+				status[r.Metadata().ID] = resourceStatus(r)
+			}
+
+			sort.Strings(ids)
+
+			for _, id := range ids {
+				cmd.Printf("%-10s %s\n", status[id], id)
+			}
+
+			return nil
+		},
+	}
+
+	listCmd.Flags().StringVarP(&resourceType, "type", "", "", "Only list resources of the given type")
+	listCmd.Flags().StringVarP(&module, "module", "", "", "Only list resources in the given module, including sub modules")
+
+	return listCmd
+}
+
+func newStateRmCmd(l logger.Logger) *cobra.Command {
+	var lockTimeout time.Duration
+
+	rmCmd := &cobra.Command{
+		Use:   "rm [fqrn]",
+		Short: "Remove a resource from the state without destroying it",
+		Long: `Remove a resource from the state without destroying it.
+
+The resource will no longer be managed by jumppad, running 'jumppad up' with a
+configuration that contains the resource will create it again.`,
+		Example:      `jumppad state rm resource.container.consul`,
+		Args:         cobra.ExactArgs(1),
+		SilenceUsage: true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			unlock, err := lockState(lockTimeout, l)
+			if err != nil {
+				return err
+			}
+			defer unlock()
+
+			cfg, err := config.LoadState()
+			if err != nil {
+				return fmt.Errorf("unable to load state, do you have a running blueprint? %s", err)
+			}
+
+			r, err := cfg.FindResource(args[0])
+			if err != nil {
+				return fmt.Errorf("unable to locate resource '%s' in the state", args[0])
+			}
+
+			err = cfg.RemoveResource(r)
+			if err != nil {
+				return fmt.Errorf("unable to remove resource '%s' from the state: %s", args[0], err)
+			}
+
+			err = config.SaveState(cfg)
+			if err != nil {
+				return fmt.Errorf("unable to save state: %s", err)
+			}
+
+			cmd.Printf("Removed %s from the state\n", r.Metadata().ID)
+
+			return nil
+		},
+	}
+
+	rmCmd.Flags().DurationVarP(&lockTimeout, "lock-timeout", "", 0, lockTimeoutUsage)
+
+	return rmCmd
+}
+
+func newStateMvCmd(dt container.Docker, l logger.Logger) *cobra.Command {
+	var lockTimeout time.Duration
+
+	mvCmd := &cobra.Command{
+		Use:   "mv [source] [destination]",
+		Short: "Rename a resource in the state without recreating it",
+		Long: `Rename a resource in the state without recreating it.
+
+The resource type can not be changed, references to the resource from other
+resources in the state are updated to the new name. Containers that are named
+after the resource are renamed.`,
+		Example:      `jumppad state mv resource.container.consul module.dc1.resource.container.consul`,
+		Args:         cobra.ExactArgs(2),
+		SilenceUsage: true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			unlock, err := lockState(lockTimeout, l)
+			if err != nil {
+				return err
+			}
+			defer unlock()
+
+			cfg, err := config.LoadState()
+			if err != nil {
+				return fmt.Errorf("unable to load state, do you have a running blueprint? %s", err)
+			}
+
+			r, renamed, err := moveResource(cfg, args[0], args[1])
+			if err != nil {
+				return err
+			}
+
+			done, err := renameContainers(dt, renamed, l)
+			if err != nil {
+				return err
+			}
+
+			err = config.SaveState(cfg)
+			if err != nil {
+				// the containers must keep the names in the unchanged state
+				revertContainers(dt, done, l)
+				return fmt.Errorf("unable to save state: %s", err)
+			}
+
+			cmd.Printf("Moved %s to %s\n", args[0], r.Metadata().ID)
+
+			return nil
+		},
+	}
+
+	mvCmd.Flags().DurationVarP(&lockTimeout, "lock-timeout", "", 0, lockTimeoutUsage)
+
+	return mvCmd
+}
+
+// moveResource renames the resource at source to destination and updates
+// any references to the resource from other resources in the state, the
+// container names that are derived from the resource name are returned keyed
+// by their previous name
+func moveResource(cfg *hclconfig.Config, source, destination string) (types.Resource, map[string]string, error) {
+	r, err := cfg.FindResource(source)
+	if err != nil {
+		return nil, nil, fmt.Errorf("unable to locate resource '%s' in the state", source)
+	}
+
+	dest, err := resources.ParseFQRN(destination)
+	if err != nil {
+		return nil, nil, fmt.Errorf("invalid destination '%s': %s", destination, err)
+	}
+
+	if dest.Attribute != "" {
+		return nil, nil, fmt.Errorf("invalid destination '%s', attributes can not be specified", destination)
+	}
+
+	if dest.Type != r.Metadata().Type {
+		return nil, nil, fmt.Errorf("unable to move '%s' to '%s', the resource type can not be changed", source, destination)
+	}
+
+	if _, err := cfg.FindResource(destination); err == nil {
+		return nil, nil, fmt.Errorf("unable to move '%s' to '%s', destination already exists", source, destination)
+	}
+
+	err = cfg.RemoveResource(r)
+	if err != nil {
+		return nil, nil, fmt.Errorf("unable to remove resource '%s' from the state: %s", source, err)
+	}
+
+	oldID := r.Metadata().ID
+	oldFQDN := utils.FQDN(r.Metadata().Name, r.Metadata().Module, r.Metadata().Type)
+
+	r.Metadata().Name = dest.Resource
+	r.Metadata().Module = dest.Module
+	r.Metadata().ID = dest.String()
+
+	err = cfg.AppendResource(r)
+	if err != nil {
+		return nil, nil, fmt.Errorf("unable to add resource '%s' to the state: %s", destination, err)
+	}
+
+	// update the references from other resources
+	for _, o := range cfg.Resources {
+		deps, err := renameReferences(o.GetDependencies(), o.Metadata().Module, oldID, r.Metadata().ID)
+		if err != nil {
+			return nil, nil, fmt.Errorf("unable to move '%s' to '%s', %s depends on it: %s", source, destination, o.Metadata().ID, err)
+		}
+
+		links, err := renameReferences(o.Metadata().Links, o.Metadata().Module, oldID, r.Metadata().ID)
+		if err != nil {
+			return nil, nil, fmt.Errorf("unable to move '%s' to '%s', %s references it: %s", source, destination, o.Metadata().ID, err)
+		}
+
+		o.SetDependencies(deps)
+		o.Metadata().Links = links
+	}
+
+	// computed container names end with the FQDN of the resource
+	newFQDN := utils.FQDN(r.Metadata().Name, r.Metadata().Module, r.Metadata().Type)
+	renamed := map[string]string{}
+
+	for _, n := range containerNames(r) {
+		if !strings.HasSuffix(*n, oldFQDN) {
+			continue
+		}
+
+		name := strings.TrimSuffix(*n, oldFQDN) + newFQDN
+		renamed[*n] = name
+		*n = name
+	}
+
+	return r, renamed, nil
+}
+
+// renameContainers renames the containers in renamed, which is keyed by the
+// current name of the container. Containers that do not exist are skipped,
+// when a container can not be renamed the containers that have already been
+// renamed are given their previous names. The renamed containers are returned
+// keyed by their previous name.
+func renameContainers(dt container.Docker, renamed map[string]string, l logger.Logger) (map[string]string, error) {
+	from := []string{}
+	for n := range renamed {
+		from = append(from, n)
+	}
+
+	sort.Strings(from)
+
+	done := map[string]string{}
+	for _, n := range from {
+		err := dt.ContainerRename(context.Background(), n, renamed[n])
+		if errdefs.IsNotFound(err) {
+			continue
+		}
+
+		if err != nil {
+			revertContainers(dt, done, l)
+			return nil, fmt.Errorf("unable to rename container '%s' to '%s': %s", n, renamed[n], err)
+		}
+
+		done[n] = renamed[n]
+	}
+
+	return done, nil
+}
+
+// revertContainers gives the containers in renamed their previous names,
+// failures are logged as the containers need to be renamed manually
+func revertContainers(dt container.Docker, renamed map[string]string, l logger.Logger) {
+	for from, to := range renamed {
+		err := dt.ContainerRename(context.Background(), to, from)
+		if err != nil {
+			l.Error("Unable to restore the name of the container", "container", to, "name", from, "error", err)
+		}
+	}
+}
+
+// renameReferences replaces references to the resource oldID, including
+// references to attributes of the resource, with newID. References are
+// relative to the module of the resource that contains them.
+func renameReferences(refs []string, module, oldID, newID string) ([]string, error) {
+	for i, ref := range refs {
+		fqrn, err := resources.ParseFQRN(ref)
+		if err != nil {
+			continue
+		}
+
+		abs := fqrn.AppendParentModule(module)
+		if abs.StringWithoutAttribute() != oldID {
+			continue
+		}
+
+		// references can only refer to resources in the same module or its
+		// child modules
+		dest, _ := resources.ParseFQRN(newID)
+		if module != "" && dest.Module != module && !strings.HasPrefix(dest.Module, module+".") {
+			return nil, fmt.Errorf("%s is not in the module %s", newID, module)
+		}
+
+		dest.Module = strings.TrimPrefix(strings.TrimPrefix(dest.Module, module), ".")
+		dest.Attribute = fqrn.Attribute
+		refs[i] = dest.String()
+	}
+
+	return refs, nil
+}
+
+// containerNames returns the computed names of the containers that are
+// created for the resource
+func containerNames(r types.Resource) []*string {
+	switch v := r.(type) {
+	case *ct.Container:
+		return []*string{&v.ContainerName}
+	case *ct.Sidecar:
+		return []*string{&v.ContainerName}
+	case *docs.Docs:
+		return []*string{&v.ContainerName}
+	case *k8s.Cluster:
+		return []*string{&v.ContainerName}
+	case *nomad.NomadCluster:
+		names := []*string{&v.ServerContainerName}
+		for i := range v.ClientContainerName {
+			names = append(names, &v.ClientContainerName[i])
+		}
+
+		return names
+	}
+
+	return nil
+}
+
+func resourceStatus(r types.Resource) string {
+	if r.GetDisabled() {
+		return constants.StatusDisabled
+	}
+
+	if s, ok := r.Metadata().Properties[constants.PropertyStatus].(string); ok {
+		return s
+	}
+
+	return "pending"
+}
+
+func newStateHistoryCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:          "history",
+		Short:        "List the previous versions of the state",
+		Example:      `jumppad state history`,
+		Args:         cobra.NoArgs,
+		SilenceUsage: true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			versions, err := config.StateHistory()
+			if err != nil {
+				return err
+			}
+
+			if len(versions) == 0 {
+				cmd.Println("No state history")
+				return nil
+			}
+
+			current := config.StateChecksum() != ""
+
+			cmd.Printf("%-8s %-27s %-12s %s\n", "SERIAL", "CREATED", "VERSION", "RESOURCES")
+			for i, v := range versions {
+				line := fmt.Sprintf("%-8d %-27s %-12s %d", v.Serial, v.Created.Format(time.RFC3339), v.Version, v.ResourceCount())
+				if i == 0 && current {
+					line += " " + greenIcon.Render("(current)")
+				}
+
+				cmd.Println(line)
+			}
+
+			return nil
+		},
+	}
+}
+
+func newStateShowCmd() *cobra.Command {
+	var jsonOutput bool
+
+	showCmd := &cobra.Command{
+		Use:   "show [fqrn | serial]",
+		Short: "Show a resource in the state or a version of the state from the history",
+		Long: `Show a resource in the state or a version of the state from the history.
+
+When given the fully qualified name of a resource, the resource is shown as JSON
+including any computed fields. When given a serial from 'jumppad state history'
+the resources in that version of the state are listed.`,
+		Example: `jumppad state show resource.container.consul
+jumppad state show 3`,
+		Args:         cobra.ExactArgs(1),
+		SilenceUsage: true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			serial, err := strconv.Atoi(args[0])
+			if err != nil {
+				return showStateResource(cmd, args[0])
+			}
+
+			return showStateVersion(cmd, serial, jsonOutput)
+		},
+	}
+
+	showCmd.Flags().BoolVarP(&jsonOutput, "json", "", false, "Output the version of the state as JSON")
+
+	return showCmd
+}
+
+func showStateResource(cmd *cobra.Command, fqrn string) error {
+	cfg, err := config.LoadState()
+	if err != nil {
+		return fmt.Errorf("unable to load state, do you have a running blueprint? %s", err)
+	}
+
+	r, err := cfg.FindResource(fqrn)
+	if err != nil {
+		return fmt.Errorf("unable to locate resource '%s' in the state", fqrn)
+	}
+
+	s, err := prettyjson.Marshal(r)
+	if err != nil {
+		return fmt.Errorf("unable to output resource as JSON: %s", err)
+	}
+
+	cmd.Println(stateRedactor(cfg).Redact(string(s)))
+
+	return nil
+}
+
+func showStateVersion(cmd *cobra.Command, serial int, jsonOutput bool) error {
+	sv, c, err := config.LoadStateVersion(serial)
+	if err != nil {
+		return err
+	}
+
+	if jsonOutput {
+		s, err := prettyjson.Format(sv.State)
+		if err != nil {
+			return fmt.Errorf("unable to output state as JSON: %s", err)
+		}
+
+		cmd.Println(stateRedactor(c).Redact(string(s)))
+		return nil
+	}
+
+	cmd.Printf("Serial:  %d\n", sv.Serial)
+	cmd.Printf("Created: %s\n", sv.Created.Format(time.RFC3339))
+	cmd.Printf("Version: %s\n", sv.Version)
+	cmd.Println()
+
+	ids := []string{}
+	status := map[string]string{}
+	for _, r := range c.Resources {
+		ids = append(ids, r.Metadata().ID)
+		status[r.Metadata().ID] = resourceStatus(r)
+	}
+
+	sort.Strings(ids)
+
+	for _, id := range ids {
+		cmd.Printf("%-10s %s\n", status[id], id)
+	}
+
+	return nil
+}
+
+func newStateRollbackCmd(e jumppad.Engine, l logger.Logger) *cobra.Command {
+	var lockTimeout time.Duration
+
+	rollbackCmd := &cobra.Command{
+		Use:   "rollback [serial]",
+		Short: "Restore a previous version of the state",
+		Long: `Restore a previous version of the state.
+
+Resources that are not in the previous version, or that have a different
+configuration, are destroyed and the resources from the previous version that
+are not running are created.`,
+		Example:      `jumppad state rollback 3`,
+		Args:         cobra.ExactArgs(1),
+		SilenceUsage: true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			serial, err := strconv.Atoi(args[0])
+			if err != nil {
+				return fmt.Errorf("invalid serial '%s', expected a number", args[0])
+			}
+
+			unlock, err := lockState(lockTimeout, l)
+			if err != nil {
+				return err
+			}
+			defer unlock()
+
+			_, target, err := config.LoadStateVersion(serial)
+			if err != nil {
+				if errors.Is(err, config.ErrStateVersionNotFound) {
+					return fmt.Errorf("%s, use 'jumppad state history' to list the available versions", err)
+				}
+
+				return err
+			}
+
+			_, err = e.Rollback(context.Background(), target)
+			if err != nil {
+				return fmt.Errorf("unable to roll back state: %s", err)
+			}
+
+			cmd.Printf("Rolled back state to version %d\n", serial)
+
+			return nil
+		},
+	}
+
+	rollbackCmd.Flags().DurationVarP(&lockTimeout, "lock-timeout", "", 0, lockTimeoutUsage)
+
+	return rollbackCmd
+}
@@ -0,0 +1,282 @@
+package cmd
+
+import (
+	"bytes"
+	"fmt"
+	"testing"
+
+	"github.com/docker/docker/errdefs"
+	"github.com/jumppad-labs/jumppad/pkg/clients/container/mocks"
+	"github.com/jumppad-labs/jumppad/pkg/clients/logger"
+	"github.com/jumppad-labs/jumppad/pkg/config"
+	"github.com/jumppad-labs/jumppad/pkg/config/resources/container"
+	"github.com/jumppad-labs/jumppad/pkg/config/resources/nomad"
+	"github.com/jumppad-labs/jumppad/testutils"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func runStateCmd(t *testing.T, args ...string) (string, error) {
+	return runStateCmdWithDocker(t, &mocks.Docker{}, args...)
+}
+
+func runStateCmdWithDocker(t *testing.T, md *mocks.Docker, args ...string) (string, error) {
+	c := newStateCmd(nil, md, logger.NewTestLogger(t))
+
+	out := bytes.NewBufferString("")
+	c.SetOut(out)
+	c.SetErr(out)
+	c.SetArgs(args)
+
+	err := c.Execute()
+
+	return out.String(), err
+}
+
+func TestStateListFiltersByTypeAndModule(t *testing.T) {
+	testutils.SetupState(t, stateCmdState)
+
+	out, err := runStateCmd(t, "list", "--type", "container")
+	require.NoError(t, err)
+	require.Contains(t, out, "resource.container.consul")
+	require.Contains(t, out, "module.dc1.resource.container.vault")
+	require.NotContains(t, out, "resource.network.cloud")
+
+	out, err = runStateCmd(t, "list", "--module", "dc1")
+	require.NoError(t, err)
+	require.Contains(t, out, "module.dc1.resource.container.vault")
+	require.NotContains(t, out, "resource.container.consul")
+}
+
+func TestStateShowOutputsResourceWithComputedFields(t *testing.T) {
+	testutils.SetupState(t, stateCmdState)
+
+	out, err := runStateCmd(t, "show", "resource.container.consul")
+	require.NoError(t, err)
+	require.Contains(t, out, "consul.container.local.jmpd.in")
+}
+
+func TestStateRmRemovesResourceFromState(t *testing.T) {
+	testutils.SetupState(t, stateCmdState)
+
+	_, err := runStateCmd(t, "rm", "resource.container.consul")
+	require.NoError(t, err)
+
+	c, err := config.LoadState()
+	require.NoError(t, err)
+
+	_, err = c.FindResource("resource.container.consul")
+	require.Error(t, err)
+	require.Equal(t, 3, c.ResourceCount())
+}
+
+func TestStateRmReturnsErrorWhenNotFound(t *testing.T) {
+	testutils.SetupState(t, stateCmdState)
+
+	_, err := runStateCmd(t, "rm", "resource.container.missing")
+	require.Error(t, err)
+}
+
+func TestStateMvRenamesResourceAndReferences(t *testing.T) {
+	testutils.SetupState(t, stateCmdState)
+
+	_, err := runStateCmd(t, "mv", "resource.network.cloud", "resource.network.onprem")
+	require.NoError(t, err)
+
+	c, err := config.LoadState()
+	require.NoError(t, err)
+
+	_, err = c.FindResource("resource.network.cloud")
+	require.Error(t, err)
+
+	n, err := c.FindResource("resource.network.onprem")
+	require.NoError(t, err)
+	require.Equal(t, "resource.network.onprem", n.Metadata().ID)
+
+	r, err := c.FindResource("resource.container.consul")
+	require.NoError(t, err)
+	require.Equal(t, []string{"resource.network.onprem"}, r.GetDependencies())
+	require.Equal(t, []string{"resource.network.onprem.meta.id"}, r.Metadata().Links)
+}
+
+func TestStateMvRenamesContainers(t *testing.T) {
+	testutils.SetupState(t, stateCmdState)
+
+	md := &mocks.Docker{}
+	md.On("ContainerRename", mock.Anything, mock.Anything, mock.Anything).Return(nil)
+
+	_, err := runStateCmdWithDocker(t, md, "mv", "resource.container.consul", "resource.container.server")
+	require.NoError(t, err)
+
+	md.AssertCalled(t, "ContainerRename", mock.Anything, "consul.container.local.jmpd.in", "server.container.local.jmpd.in")
+
+	c, err := config.LoadState()
+	require.NoError(t, err)
+
+	r, err := c.FindResource("resource.container.server")
+	require.NoError(t, err)
+	require.Equal(t, "server.container.local.jmpd.in", r.(*container.Container).ContainerName)
+}
+
+func TestStateMvRenamesReferencesRelativeToModule(t *testing.T) {
+	testutils.SetupState(t, stateCmdState)
+
+	_, err := runStateCmd(t, "mv", "module.dc1.resource.network.private", "module.dc1.resource.network.internal")
+	require.NoError(t, err)
+
+	c, err := config.LoadState()
+	require.NoError(t, err)
+
+	r, err := c.FindResource("module.dc1.resource.container.vault")
+	require.NoError(t, err)
+	require.Equal(t, []string{"resource.network.internal"}, r.GetDependencies())
+	require.Equal(t, []string{"resource.network.internal.meta.id"}, r.Metadata().Links)
+}
+
+func TestStateMvReturnsErrorWhenReferenceOutsideModule(t *testing.T) {
+	testutils.SetupState(t, stateCmdState)
+
+	_, err := runStateCmd(t, "mv", "module.dc1.resource.network.private", "resource.network.private")
+	require.ErrorContains(t, err, "module.dc1.resource.container.vault depends on it")
+}
+
+func TestStateMvReturnsErrorWhenTypeChanges(t *testing.T) {
+	testutils.SetupState(t, stateCmdState)
+
+	_, err := runStateCmd(t, "mv", "resource.network.cloud", "resource.container.cloud")
+	require.ErrorContains(t, err, "type can not be changed")
+}
+
+func TestStateMvReturnsErrorWhenDestinationExists(t *testing.T) {
+	testutils.SetupState(t, stateCmdState)
+
+	_, err := runStateCmd(t, "mv", "resource.container.consul", "module.dc1.resource.container.vault")
+	require.ErrorContains(t, err, "already exists")
+}
+
+func TestStateMvRestoresContainerNamesWhenRenameFails(t *testing.T) {
+	testutils.SetupState(t, stateCmdNomadState)
+
+	md := &mocks.Docker{}
+	md.On("ContainerRename", mock.Anything, "server.dev.nomad-cluster.local.jmpd.in", mock.Anything).Return(fmt.Errorf("boom"))
+	md.On("ContainerRename", mock.Anything, mock.Anything, mock.Anything).Return(nil)
+
+	_, err := runStateCmdWithDocker(t, md, "mv", "resource.nomad_cluster.dev", "resource.nomad_cluster.prod")
+	require.ErrorContains(t, err, "boom")
+
+	// the clients are renamed before the server and are given their previous names
+	md.AssertCalled(t, "ContainerRename", mock.Anything, "1.client.dev.nomad-cluster.local.jmpd.in", "1.client.prod.nomad-cluster.local.jmpd.in")
+	md.AssertCalled(t, "ContainerRename", mock.Anything, "1.client.prod.nomad-cluster.local.jmpd.in", "1.client.dev.nomad-cluster.local.jmpd.in")
+	md.AssertCalled(t, "ContainerRename", mock.Anything, "2.client.prod.nomad-cluster.local.jmpd.in", "2.client.dev.nomad-cluster.local.jmpd.in")
+
+	// the state is not changed
+	c, err := config.LoadState()
+	require.NoError(t, err)
+
+	r, err := c.FindResource("resource.nomad_cluster.dev")
+	require.NoError(t, err)
+	require.Equal(t, "server.dev.nomad-cluster.local.jmpd.in", r.(*nomad.NomadCluster).ServerContainerName)
+}
+
+func TestStateMvSkipsContainersThatDoNotExist(t *testing.T) {
+	testutils.SetupState(t, stateCmdNomadState)
+
+	md := &mocks.Docker{}
+	md.On("ContainerRename", mock.Anything, "2.client.dev.nomad-cluster.local.jmpd.in", mock.Anything).Return(errdefs.NotFound(fmt.Errorf("boom")))
+	md.On("ContainerRename", mock.Anything, mock.Anything, mock.Anything).Return(nil)
+
+	_, err := runStateCmdWithDocker(t, md, "mv", "resource.nomad_cluster.dev", "resource.nomad_cluster.prod")
+	require.NoError(t, err)
+
+	md.AssertNumberOfCalls(t, "ContainerRename", 3)
+
+	c, err := config.LoadState()
+	require.NoError(t, err)
+
+	_, err = c.FindResource("resource.nomad_cluster.prod")
+	require.NoError(t, err)
+}
+
+var stateCmdNomadState = `
+{
+  "resources": [
+  {
+      "meta": {
+        "id": "resource.nomad_cluster.dev",
+        "name": "dev",
+        "properties": {
+          "status": "created"
+        },
+        "type": "nomad_cluster"
+      },
+      "server_container_name": "server.dev.nomad-cluster.local.jmpd.in",
+      "client_container_name": [
+        "1.client.dev.nomad-cluster.local.jmpd.in",
+        "2.client.dev.nomad-cluster.local.jmpd.in"
+      ]
+  }
+  ]
+}
+`
+
+var stateCmdState = `
+{
+  "resources": [
+  {
+      "meta": {
+        "id": "resource.network.cloud",
+        "name": "cloud",
+        "properties": {
+          "status": "created"
+        },
+        "type": "network"
+      },
+      "subnet": "10.15.0.0/16"
+  },
+  {
+      "meta": {
+        "id": "resource.container.consul",
+        "name": "consul",
+        "properties": {
+          "status": "created"
+        },
+        "links": ["resource.network.cloud.meta.id"],
+        "type": "container"
+      },
+      "depends_on": ["resource.network.cloud"],
+      "container_name": "consul.container.local.jmpd.in",
+      "image": {
+        "name": "consul"
+      }
+  },
+  {
+      "meta": {
+        "id": "module.dc1.resource.network.private",
+        "name": "private",
+        "module": "dc1",
+        "properties": {
+          "status": "created"
+        },
+        "type": "network"
+      },
+      "subnet": "10.16.0.0/16"
+  },
+  {
+      "meta": {
+        "id": "module.dc1.resource.container.vault",
+        "name": "vault",
+        "module": "dc1",
+        "properties": {
+          "status": "created"
+        },
+        "links": ["resource.network.private.meta.id"],
+        "type": "container"
+      },
+      "depends_on": ["resource.network.private"],
+      "image": {
+        "name": "vault"
+      }
+  }
+  ]
+}
+`
@@ -12,6 +12,7 @@ import (
 	"github.com/jumppad-labs/jumppad/pkg/config/resources/container"
 	"github.com/jumppad-labs/jumppad/pkg/config/resources/k8s"
 	"github.com/jumppad-labs/jumppad/pkg/config/resources/nomad"
+	"github.com/jumppad-labs/jumppad/pkg/jumppad"
 	"github.com/jumppad-labs/jumppad/pkg/jumppad/constants"
 	"github.com/jumppad-labs/jumppad/pkg/utils"
 	"github.com/spf13/cobra"
@@ -59,16 +60,25 @@ var statusCmd = &cobra.Command{
 				os.Exit(1)
 			}
 
-			fmt.Println(string(s))
+			fmt.Println(stateRedactor(cfg).Redact(string(s)))
 		} else {
 			// fmt.Println()
 			// fmt.Printf("%-13s %-60s %s\n", "STATUS", "RESOURCE", "FQDN")
 
 			createdCount := 0
 			failedCount := 0
+			driftedCount := 0
+			stoppedCount := 0
 			disabledCount := 0
 			pendingCount := 0
 
+			// created resources that reference a tainted, drifted or failed
+			// resource are recreated with it by the next up
+			cascades := map[string]string{}
+			for _, c := range jumppad.PendingCascades(cfg) {
+				cascades[c.ID] = c.Cause
+			}
+
 			// sort the resources
 			resourceMap := map[string][]types.Resource{}
 
@@ -102,6 +112,12 @@ var statusCmd = &cobra.Command{
 						case constants.StatusFailed:
 							status = redIcon.Render("✘")
 							failedCount++
+						case constants.StatusDrifted:
+							status = yellowIcon.Render("~")
+							driftedCount++
+						case constants.StatusStopped:
+							status = grayIcon.Render("■")
+							stoppedCount++
 						default:
 							pendingCount++
 						}
@@ -131,6 +147,10 @@ var statusCmd = &cobra.Command{
 					default:
 						fmt.Printf("%s %s\n", status, r.Metadata().ID)
 					}
+
+					if cause, ok := cascades[r.Metadata().ID]; ok {
+						fmt.Printf("    %s %s\n", grayText.Render("└─"), yellowText.Render(fmt.Sprintf("recreated by the next up, references %s", cause)))
+					}
 				}
 			}
 
@@ -146,7 +166,7 @@ var statusCmd = &cobra.Command{
 			// fmt.Println()
 			// fmt.Println(grayIcon.Render("-") + grayText.Render("resource.container.frontend"))
 			fmt.Println()
-			fmt.Println(whiteText.Render(fmt.Sprintf("Pending: %d  Created: %d  Failed: %d  Disabled: %d", pendingCount, createdCount, failedCount, disabledCount)))
+			fmt.Println(whiteText.Render(fmt.Sprintf("Pending: %d  Created: %d  Stopped: %d  Drifted: %d  Failed: %d  Disabled: %d", pendingCount, createdCount, stoppedCount, driftedCount, failedCount, disabledCount)))
 			fmt.Println()
 		}
 	},
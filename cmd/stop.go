@@ -0,0 +1,108 @@
+package cmd
+
+import (
+	"time"
+
+	"github.com/jumppad-labs/jumppad/pkg/clients/container"
+	"github.com/jumppad-labs/jumppad/pkg/clients/logger"
+	"github.com/jumppad-labs/jumppad/pkg/jumppad"
+	"github.com/spf13/cobra"
+)
+
+func newStopCmd(e jumppad.Engine, ct container.ContainerTasks, l logger.Logger) *cobra.Command {
+	var force bool
+	var parallelism int
+	var lockTimeout time.Duration
+
+	stopCmd := &cobra.Command{
+		Use:   "stop",
+		Short: "Stop the containers of the resources in the current state",
+		Long: `Stop the containers of the resources in the current state.
+
+Containers, sidecars, cluster nodes, docs and the image cache are stopped without
+being destroyed so that they no longer use CPU and memory. Stopped resources are
+started again with 'jumppad start' or the next 'jumppad up'.`,
+		Example: `
+  # Stop all the containers
+  jumppad stop
+
+  # Start them again
+  jumppad start
+	`,
+		Args:         cobra.NoArgs,
+		SilenceUsage: true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			ct.SetForce(force)
+			e.SetParallelism(parallelism)
+
+			interrupt := newInterruptHandler(l)
+			defer interrupt.Stop()
+
+			unlock, err := lockState(lockTimeout, l)
+			if err != nil {
+				return err
+			}
+			defer unlock()
+
+			err = e.Stop(interrupt.Context())
+			if err != nil {
+				return err
+			}
+
+			cmd.Println("Resources stopped, run 'jumppad start' to start them again")
+			return nil
+		},
+	}
+
+	stopCmd.Flags().BoolVarP(&force, "force", "", false, "When set to true Jumppad will not wait for containers to exit gracefully")
+	stopCmd.Flags().IntVarP(&parallelism, "parallelism", "", jumppad.DefaultParallelism, "Maximum number of resources that are stopped at the same time")
+	stopCmd.Flags().DurationVarP(&lockTimeout, "lock-timeout", "", 0, lockTimeoutUsage)
+
+	return stopCmd
+}
+
+func newStartCmd(e jumppad.Engine, l logger.Logger) *cobra.Command {
+	var parallelism int
+	var lockTimeout time.Duration
+
+	startCmd := &cobra.Command{
+		Use:   "start",
+		Short: "Start the containers of the resources stopped by 'jumppad stop'",
+		Long: `Start the containers of the resources stopped by 'jumppad stop'.
+
+Resources are started in dependency order, containers are attached to their
+networks with the addresses they were assigned when they were created. Resources
+that fail to start are marked as failed and are re-created by the next 'jumppad up'.`,
+		Example: `
+  # Start the stopped containers
+  jumppad start
+	`,
+		Args:         cobra.NoArgs,
+		SilenceUsage: true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			e.SetParallelism(parallelism)
+
+			interrupt := newInterruptHandler(l)
+			defer interrupt.Stop()
+
+			unlock, err := lockState(lockTimeout, l)
+			if err != nil {
+				return err
+			}
+			defer unlock()
+
+			err = e.Start(interrupt.Context())
+			if err != nil {
+				return err
+			}
+
+			cmd.Println("Resources started")
+			return nil
+		},
+	}
+
+	startCmd.Flags().IntVarP(&parallelism, "parallelism", "", jumppad.DefaultParallelism, "Maximum number of resources that are started at the same time")
+	startCmd.Flags().DurationVarP(&lockTimeout, "lock-timeout", "", 0, lockTimeoutUsage)
+
+	return startCmd
+}
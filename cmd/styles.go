@@ -5,6 +5,7 @@ import "github.com/charmbracelet/lipgloss"
 // var headerText = lipgloss.NewStyle().Foreground(lipgloss.Color("7"))
 var whiteText = lipgloss.NewStyle().Foreground(lipgloss.Color("15"))
 var grayText = lipgloss.NewStyle().Foreground(lipgloss.Color("8"))
+var yellowText = lipgloss.NewStyle().Foreground(lipgloss.Color("3"))
 
 var yellowIcon = lipgloss.NewStyle().Foreground(lipgloss.Color("3")).PaddingRight(1)
 var grayIcon = lipgloss.NewStyle().Foreground(lipgloss.Color("8")).PaddingRight(1)
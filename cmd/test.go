@@ -61,6 +61,7 @@ func newTestCmd() *cobra.Command {
 	var variables []string
 	var variablesFile string
 	var tags string
+	var parallelism int
 
 	var testCmd = &cobra.Command{
 		Use:                   "test [blueprint]",
@@ -68,7 +69,7 @@ func newTestCmd() *cobra.Command {
 		Long:                  `Run functional tests for the blueprint, this command will start the jumppad blueprint `,
 		DisableFlagsInUseLine: true,
 		Args:                  cobra.ArbitraryArgs,
-		RunE:                  newTestCmdFunc(testFolder, &force, &purge, &variables, &variablesFile, &tags, &dontDestroy),
+		RunE:                  newTestCmdFunc(testFolder, &force, &purge, &variables, &variablesFile, &tags, &dontDestroy, &parallelism),
 	}
 
 	testCmd.Flags().StringVarP(&testFolder, "test-folder", "", "", "Specify the folder containing the functional tests.")
@@ -78,6 +79,7 @@ func newTestCmd() *cobra.Command {
 	testCmd.Flags().StringVarP(&variablesFile, "vars-file", "", "", "Load variables from a location other than *.vars files in the blueprint folder. E.g --vars-file=./file.vars")
 	testCmd.Flags().StringVarP(&tags, "tags", "", "", "Test tags to run e.g. @wip, @wip,@new, when not set all tests are run")
 	testCmd.Flags().BoolVarP(&dontDestroy, "dont-destroy", "", false, "When set to true, jumppad does not destroy the blueprint after executing the tests")
+	testCmd.Flags().IntVarP(&parallelism, "parallelism", "", jumppad.DefaultParallelism, "Maximum number of resources that are created at the same time, set to 1 to create resources one at a time")
 
 	return testCmd
 }
@@ -90,6 +92,7 @@ func newTestCmdFunc(
 	variablesFile *string,
 	tags *string,
 	dontDestroy *bool,
+	parallelism *int,
 ) func(cmd *cobra.Command, args []string) error {
 
 	return func(cmd *cobra.Command, args []string) error {
@@ -103,6 +106,7 @@ func newTestCmdFunc(
 			variablesFile: *variablesFile,
 			tags:          *tags,
 			dontDestroy:   dontDestroy,
+			parallelism:   parallelism,
 		}
 
 		tr.start()
@@ -128,6 +132,11 @@ type CucumberRunner struct {
 	variablesFile string
 	tags          string
 	dontDestroy   *bool
+	parallelism   *int
+
+	// interrupt stops new scenarios from starting once a signal has been
+	// received, the resources of the current scenario are still destroyed
+	interrupt *interruptHandler
 }
 
 // Initialize the functional tests
@@ -156,12 +165,15 @@ func (cr *CucumberRunner) start() {
 	opts.Paths = []string{cr.testPath}
 	opts.Tags = cr.tags
 
+	cr.interrupt = newInterruptHandler(createLogger())
+
 	status := godog.TestSuite{
 		Name:                "Blueprint test",
 		ScenarioInitializer: cr.initializeSuite,
 		Options:             opts,
 	}.Run()
 
+	cr.interrupt.Stop()
 	os.Exit(status)
 }
 
@@ -169,6 +181,10 @@ func (cr *CucumberRunner) initializeSuite(ctx *godog.ScenarioContext) {
 	sb := &strings.Builder{}
 
 	ctx.Before(func(ctx context.Context, sc *godog.Scenario) (context.Context, error) {
+		if cr.interrupt.Context().Err() != nil {
+			return ctx, fmt.Errorf("test run interrupted, skipping scenario '%s'", sc.Name)
+		}
+
 		envVars = map[string]string{}
 		commandOutput = bytes.NewBufferString("")
 		commandExitCode = 0
@@ -221,7 +237,7 @@ func (cr *CucumberRunner) initializeSuite(ctx *godog.ScenarioContext) {
 		sb := strings.Builder{}
 		l := logger.NewLogger(&sb, logger.LogLevelDebug)
 		dest := newDestroyCmd(cr.cli.Connector, l)
-		dest.SetArgs([]string{"--force"})
+		dest.SetArgs([]string{"--force", fmt.Sprintf("--parallelism=%d", *cr.parallelism)})
 
 		err = dest.Execute()
 		if err != nil {
@@ -266,6 +282,8 @@ func (cr *CucumberRunner) iRunApplyAtPath(path string) error {
 	args := []string{absPath}
 
 	noOpen := true
+	planFile := ""
+	lockTimeout := time.Duration(0)
 
 	// re-use the run command
 	rc := newRunCmdFunc(
@@ -279,6 +297,14 @@ func (cr *CucumberRunner) iRunApplyAtPath(path string) error {
 		cr.force,
 		&cr.variables,
 		&cr.variablesFile,
+		cr.parallelism,
+		nil,
+		nil,
+		nil,
+		nil,
+		nil,
+		&planFile,
+		&lockTimeout,
 		cr.l,
 	)
 
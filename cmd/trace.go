@@ -0,0 +1,115 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/jumppad-labs/jumppad/pkg/clients/logger"
+	"github.com/jumppad-labs/jumppad/pkg/jumppad/tracing"
+	"github.com/spf13/cobra"
+)
+
+const (
+	traceFileUsage     = "Write OpenTelemetry spans for the operation to the given file as JSON, the critical path can be printed with 'jumppad trace summary'"
+	traceEndpointUsage = "Send OpenTelemetry spans for the operation to an OTLP HTTP endpoint, e.g. --trace-endpoint=http://localhost:4318"
+)
+
+// newTracerProvider creates a provider for the given options, nil is
+// returned when tracing is not enabled
+func newTracerProvider(o tracing.Options) (*tracing.Provider, error) {
+	o.Version = version
+
+	tp, err := tracing.NewProvider(context.Background(), o)
+	if err != nil {
+		return nil, fmt.Errorf("unable to enable tracing: %s", err)
+	}
+
+	return tp, nil
+}
+
+// shutdownTracerProvider writes any spans that have not been exported
+func shutdownTracerProvider(tp *tracing.Provider, l logger.Logger) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	err := tp.Shutdown(ctx)
+	if err != nil {
+		l.Error("Unable to export trace", "error", err)
+	}
+}
+
+func newTraceCmd() *cobra.Command {
+	traceCmd := &cobra.Command{
+		Use:   "trace",
+		Short: "Inspect traces written with --trace-file",
+		Long:  "Inspect traces written with --trace-file",
+	}
+
+	traceCmd.AddCommand(newTraceSummaryCmd())
+
+	return traceCmd
+}
+
+func newTraceSummaryCmd() *cobra.Command {
+	summaryCmd := &cobra.Command{
+		Use:   "summary [file]",
+		Short: "Print the critical path of a trace",
+		Long: `Print the critical path of a trace.
+
+The critical path is the chain of operations that determined how long the
+operation took, reducing the time of any operation on the path reduces the
+total time. Operations that ran at the same time as the path are not shown.`,
+		Example: `jumppad up --trace-file trace.json ./
+jumppad trace summary trace.json`,
+		Args:         cobra.ExactArgs(1),
+		SilenceUsage: true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			spans, err := tracing.ReadFile(args[0])
+			if err != nil {
+				return err
+			}
+
+			path := tracing.CriticalPath(spans)
+			if len(path) == 0 {
+				return fmt.Errorf("trace file %s does not contain any spans", args[0])
+			}
+
+			root := path[0].Span
+			cmd.Printf("Critical path for trace %s, total time %s\n\n", root.TraceID, roundDuration(root.Duration()))
+			cmd.Printf("%-12s %-12s %s\n", "START", "DURATION", "OPERATION")
+
+			for _, s := range path {
+				name := s.Span.Name
+				if id := s.Span.Attributes[tracing.AttributeResourceID]; id != "" {
+					name = fmt.Sprintf("%s %s", name, id)
+				}
+
+				if s.Span.Failed {
+					name += " (failed)"
+				}
+
+				cmd.Printf(
+					"%-12s %-12s %s%s\n",
+					roundDuration(s.Span.Start.Sub(root.Start)),
+					roundDuration(s.Span.Duration()),
+					strings.Repeat("  ", s.Depth),
+					name,
+				)
+			}
+
+			return nil
+		},
+	}
+
+	return summaryCmd
+}
+
+func roundDuration(d time.Duration) time.Duration {
+	if d > time.Second {
+		return d.Round(100 * time.Millisecond)
+	}
+
+	return d.Round(time.Millisecond)
+}
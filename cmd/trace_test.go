@@ -0,0 +1,61 @@
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/jumppad-labs/jumppad/pkg/jumppad/tracing"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+)
+
+func runTraceCmd(t *testing.T, args ...string) (string, error) {
+	c := newTraceCmd()
+
+	out := bytes.NewBufferString("")
+	c.SetOut(out)
+	c.SetErr(out)
+	c.SetArgs(args)
+
+	err := c.Execute()
+
+	return out.String(), err
+}
+
+func TestTraceSummaryPrintsCriticalPath(t *testing.T) {
+	file := filepath.Join(t.TempDir(), "trace.json")
+
+	tp, err := tracing.NewProvider(context.Background(), tracing.Options{File: file})
+	require.NoError(t, err)
+
+	tr := tp.Tracer(tracing.InstrumentationName)
+	start := time.Now()
+	at := func(s int) time.Time { return start.Add(time.Duration(s) * time.Second) }
+
+	ctx, root := tr.Start(context.Background(), "jumppad.apply", trace.WithTimestamp(at(0)))
+
+	_, slow := tr.Start(ctx, "provider.create", trace.WithTimestamp(at(0)), trace.WithAttributes(attribute.String(tracing.AttributeResourceID, "resource.container.slow")))
+	slow.End(trace.WithTimestamp(at(9)))
+
+	_, fast := tr.Start(ctx, "provider.create", trace.WithTimestamp(at(0)), trace.WithAttributes(attribute.String(tracing.AttributeResourceID, "resource.container.fast")))
+	fast.End(trace.WithTimestamp(at(2)))
+
+	root.End(trace.WithTimestamp(at(10)))
+	require.NoError(t, tp.Shutdown(context.Background()))
+
+	out, err := runTraceCmd(t, "summary", file)
+	require.NoError(t, err)
+
+	require.Contains(t, out, "total time 10s")
+	require.Contains(t, out, "provider.create resource.container.slow")
+	require.NotContains(t, out, "resource.container.fast")
+}
+
+func TestTraceSummaryWithMissingFileReturnsError(t *testing.T) {
+	_, err := runTraceCmd(t, "summary", filepath.Join(t.TempDir(), "missing.json"))
+	require.Error(t, err)
+}
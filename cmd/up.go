@@ -1,18 +1,16 @@
 package cmd
 
 import (
-	"context"
 	"fmt"
 	"net/url"
 	"os"
-	"os/signal"
 	"runtime"
 	"strconv"
 	"strings"
 	"sync"
-	"syscall"
 	"time"
 
+	"github.com/jumppad-labs/hclconfig"
 	"github.com/jumppad-labs/hclconfig/resources"
 
 	"github.com/jumppad-labs/jumppad/pkg/clients/connector"
@@ -21,12 +19,14 @@ import (
 	"github.com/jumppad-labs/jumppad/pkg/clients/http"
 	"github.com/jumppad-labs/jumppad/pkg/clients/logger"
 	"github.com/jumppad-labs/jumppad/pkg/clients/system"
+	"github.com/jumppad-labs/jumppad/pkg/config"
 	"github.com/jumppad-labs/jumppad/pkg/config/resources/blueprint"
 	"github.com/jumppad-labs/jumppad/pkg/config/resources/container"
 	"github.com/jumppad-labs/jumppad/pkg/config/resources/docs"
 	"github.com/jumppad-labs/jumppad/pkg/config/resources/ingress"
 	"github.com/jumppad-labs/jumppad/pkg/config/resources/nomad"
 	"github.com/jumppad-labs/jumppad/pkg/jumppad"
+	"github.com/jumppad-labs/jumppad/pkg/jumppad/tracing"
 	"github.com/jumppad-labs/jumppad/pkg/utils"
 	"github.com/spf13/cobra"
 
@@ -38,6 +38,14 @@ func newRunCmd(e jumppad.Engine, dt cclients.ContainerTasks, bp getter.Getter, h
 	var force bool
 	var variables []string
 	var variablesFile string
+	var parallelism int
+	var planFile string
+	var lockTimeout time.Duration
+	var targets []string
+	var refresh bool
+	var atomic bool
+	var output string
+	var trace tracing.Options
 
 	runCmd := &cobra.Command{
 		Use:   "up [file] | [directory]",
@@ -52,9 +60,22 @@ func newRunCmd(e jumppad.Engine, dt cclients.ContainerTasks, bp getter.Getter, h
 
   # Create resources from a blueprint in GitHub
   jumppad up github.com/jumppad-labs/blueprints/kubernetes-vault
+
+  # Apply a plan created with 'jumppad plan --out'
+  jumppad up --plan jumppad.plan
+
+  # Create or update a single container and the resources it depends on
+  jumppad up --target resource.container.web
+
+  # Remove everything created by this run if any resource fails
+  jumppad up --atomic ./
+
+  # Record where the time is spent and print the slowest chain of operations
+  jumppad up --trace-file trace.json ./
+  jumppad trace summary trace.json
 	`,
 		Args:         cobra.ArbitraryArgs,
-		RunE:         newRunCmdFunc(e, dt, bp, hc, bc, cc, &noOpen, &force, &variables, &variablesFile, l),
+		RunE:         newRunCmdFunc(e, dt, bp, hc, bc, cc, &noOpen, &force, &variables, &variablesFile, &parallelism, &targets, &refresh, &atomic, &output, &trace, &planFile, &lockTimeout, l),
 		SilenceUsage: true,
 	}
 
@@ -62,11 +83,20 @@ func newRunCmd(e jumppad.Engine, dt cclients.ContainerTasks, bp getter.Getter, h
 	runCmd.Flags().BoolVarP(&force, "force-update", "", false, "When set to true Jumppad ignores cached images or files and will download all resources")
 	runCmd.Flags().StringSliceVarP(&variables, "var", "", nil, "Allows setting variables from the command line, variables are specified as a key and value, e.g --var key=value. Can be specified multiple times")
 	runCmd.Flags().StringVarP(&variablesFile, "vars-file", "", "", "Load variables from a location other than *.vars files in the blueprint folder. E.g --vars-file=./file.vars")
+	runCmd.Flags().IntVarP(&parallelism, "parallelism", "", jumppad.DefaultParallelism, "Maximum number of resources that are created at the same time, set to 1 to create resources one at a time")
+	runCmd.Flags().DurationVarP(&lockTimeout, "lock-timeout", "", 0, lockTimeoutUsage)
+	runCmd.Flags().StringArrayVarP(&targets, "target", "", nil, targetUsage)
+	runCmd.Flags().BoolVarP(&refresh, "refresh", "", false, "Check the running resources for drift before applying, drifted resources are re-created")
+	runCmd.Flags().BoolVarP(&atomic, "atomic", "", false, "When set to true, if any resource fails to be created or the command is cancelled all changes are rolled back to the previous state")
+	runCmd.Flags().StringVarP(&output, "output", "", outputText, outputUsage)
+	runCmd.Flags().StringVarP(&trace.File, "trace-file", "", "", traceFileUsage)
+	runCmd.Flags().StringVarP(&trace.Endpoint, "trace-endpoint", "", "", traceEndpointUsage)
+	runCmd.Flags().StringVarP(&planFile, "plan", "", "", "Apply a plan file created with 'jumppad plan --out', the plan is rejected if the state or configuration has changed since it was created")
 
 	return runCmd
 }
 
-func newRunCmdFunc(e jumppad.Engine, dt cclients.ContainerTasks, bp getter.Getter, hc http.HTTP, bc system.System, cc connector.Connector, noOpen *bool, force *bool, variables *[]string, variablesFile *string, l logger.Logger) func(cmd *cobra.Command, args []string) error {
+func newRunCmdFunc(e jumppad.Engine, dt cclients.ContainerTasks, bp getter.Getter, hc http.HTTP, bc system.System, cc connector.Connector, noOpen *bool, force *bool, variables *[]string, variablesFile *string, parallelism *int, targets *[]string, refresh *bool, atomic *bool, output *string, trace *tracing.Options, planFile *string, lockTimeout *time.Duration, l logger.Logger) func(cmd *cobra.Command, args []string) error {
 	return func(cmd *cobra.Command, args []string) error {
 		// create the shipyard and sub folders in the users home directory
 		utils.CreateFolders()
@@ -76,6 +106,42 @@ func newRunCmdFunc(e jumppad.Engine, dt cclients.ContainerTasks, bp getter.Gette
 			dt.SetForce(true)
 		}
 
+		e.SetParallelism(*parallelism)
+
+		if targets != nil {
+			e.SetTargets(*targets)
+		}
+
+		if atomic != nil {
+			e.SetAtomic(*atomic)
+		}
+
+		if output != nil {
+			h, err := eventHandler(*output, cmd.OutOrStdout())
+			if err != nil {
+				return err
+			}
+
+			// stdout only contains the events
+			if h != nil {
+				l.SetOutput(redactor.Writer(cmd.ErrOrStderr()))
+			}
+
+			e.SetEventHandler(h)
+		}
+
+		if trace != nil {
+			tp, err := newTracerProvider(*trace)
+			if err != nil {
+				return err
+			}
+
+			if tp != nil {
+				e.SetTracerProvider(tp)
+				defer shutdownTracerProvider(tp, l)
+			}
+		}
+
 		// parse the vars into a map
 		vars := map[string]string{}
 		for _, v := range *variables {
@@ -124,6 +190,27 @@ func newRunCmdFunc(e jumppad.Engine, dt cclients.ContainerTasks, bp getter.Gette
 			}
 		}
 
+		// load the plan, the configuration path and variables are read
+		// from the plan
+		var plan *jumppad.Plan
+		if planFile != nil && *planFile != "" {
+			if targets != nil && len(*targets) > 0 {
+				return fmt.Errorf("--target can not be used with --plan, the targets are read from the plan")
+			}
+
+			if refresh != nil && *refresh {
+				return fmt.Errorf("--refresh can not be used with --plan, run 'jumppad drift' before creating the plan")
+			}
+
+			var err error
+			plan, err = jumppad.LoadPlan(*planFile)
+			if err != nil {
+				return err
+			}
+
+			args = []string{plan.Path}
+		}
+
 		dst := ""
 		if len(args) == 1 {
 			dst = args[0]
@@ -162,20 +249,38 @@ func newRunCmdFunc(e jumppad.Engine, dt cclients.ContainerTasks, bp getter.Gette
 		}()
 
 		// trap ctrl c
-		done := make(chan os.Signal, 1)
-		signal.Notify(done, syscall.SIGINT, syscall.SIGTERM)
+		interrupt := newInterruptHandler(l)
+		defer interrupt.Stop()
 
-		ctx, cancel := context.WithCancel(context.Background())
-		defer cancel()
+		ctx := interrupt.Context()
 
-		go func() {
-			<-done // Will block here until user hits ctrl+c
+		unlock, err := lockState(*lockTimeout, l)
+		if err != nil {
+			return err
+		}
 
-			// cancel the context
-			cancel()
-		}()
+		// mark any resources that have drifted so they are re-created
+		if refresh != nil && *refresh {
+			drifts, err := e.Drift(ctx, false)
+			if err != nil {
+				unlock()
+				return fmt.Errorf("unable to check resources for drift: %s", err)
+			}
+
+			for _, d := range drifts {
+				l.Info("Resource has drifted and will be re-created", "ref", d.ID, "reasons", d.Reasons)
+			}
+		}
+
+		var cfg *hclconfig.Config
+		if plan != nil {
+			cfg, err = e.ApplyPlan(ctx, plan)
+		} else {
+			cfg, err = e.ApplyWithVariables(ctx, dst, vars, *variablesFile)
+		}
+
+		unlock()
 
-		config, err := e.ApplyWithVariables(ctx, dst, vars, *variablesFile)
 		if err != nil {
 			return err
 		}
@@ -186,7 +291,7 @@ func newRunCmdFunc(e jumppad.Engine, dt cclients.ContainerTasks, bp getter.Gette
 			browserList := []string{}
 			checkDuration := 30 * time.Second
 
-			for _, r := range config.Resources {
+			for _, r := range cfg.Resources {
 				switch v := r.(type) {
 				case *container.Container:
 					for _, p := range v.Ports {
@@ -244,7 +349,7 @@ func newRunCmdFunc(e jumppad.Engine, dt cclients.ContainerTasks, bp getter.Gette
 
 		// if we have a blueprint show the header
 		var b *blueprint.Blueprint
-		bps, _ := config.FindResourcesByType(blueprint.TypeBlueprint)
+		bps, _ := cfg.FindResourcesByType(blueprint.TypeBlueprint)
 		for _, bp := range bps {
 			// pick the first blueprint in the root
 			if bp.Metadata().Module == "" {
@@ -290,7 +395,13 @@ func newRunCmdFunc(e jumppad.Engine, dt cclients.ContainerTasks, bp getter.Gette
 				format := fmt.Sprintf(" * %%%ds: %%s\n", maxLen)
 
 				for _, o := range outputs {
-					fmt.Printf(format, o.Meta.Name, o.Value)
+					// sensitive outputs are shown with 'jumppad output [name]'
+					value := fmt.Sprintf("%v", o.Value)
+					if config.IsSensitive(o) {
+						value = logger.Redacted
+					}
+
+					cmd.Printf(format, o.Meta.Name, redactor.Redact(value))
 				}
 
 				cmd.Println("")
@@ -9,6 +9,7 @@ import (
 	"time"
 
 	"github.com/jumppad-labs/hclconfig"
+	"github.com/jumppad-labs/hclconfig/resources"
 	hcltypes "github.com/jumppad-labs/hclconfig/types"
 	"github.com/jumppad-labs/jumppad/pkg/clients"
 	conmock "github.com/jumppad-labs/jumppad/pkg/clients/connector/mocks"
@@ -18,11 +19,15 @@ import (
 	httpmock "github.com/jumppad-labs/jumppad/pkg/clients/http/mocks"
 	"github.com/jumppad-labs/jumppad/pkg/clients/logger"
 	systemmock "github.com/jumppad-labs/jumppad/pkg/clients/system/mocks"
+	"github.com/jumppad-labs/jumppad/pkg/config"
 	"github.com/jumppad-labs/jumppad/pkg/config/resources/blueprint"
 	"github.com/jumppad-labs/jumppad/pkg/config/resources/container"
 	"github.com/jumppad-labs/jumppad/pkg/config/resources/docs"
 	"github.com/jumppad-labs/jumppad/pkg/config/resources/ingress"
 	"github.com/jumppad-labs/jumppad/pkg/config/resources/nomad"
+	"github.com/jumppad-labs/jumppad/pkg/jumppad"
+	"github.com/jumppad-labs/jumppad/pkg/jumppad/constants"
+	"github.com/jumppad-labs/jumppad/pkg/jumppad/events"
 	enginemocks "github.com/jumppad-labs/jumppad/pkg/jumppad/mocks"
 	"github.com/jumppad-labs/jumppad/pkg/utils"
 	"github.com/jumppad-labs/jumppad/testutils"
@@ -41,6 +46,8 @@ type runMocks struct {
 }
 
 func setupRun(t *testing.T) (*cobra.Command, *runMocks) {
+	testutils.SetupState(t, "")
+
 	mockContainer := &cmock.ContainerTasks{}
 	mockContainer.On("SetForce", mock.Anything)
 
@@ -87,8 +94,15 @@ func setupRun(t *testing.T) (*cobra.Command, *runMocks) {
 	mockEngine := &enginemocks.Engine{}
 	mockEngine.On("ParseConfigWithVariables", mock.Anything, mock.Anything, mock.Anything).Return(nil)
 	mockEngine.On("ApplyWithVariables", mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(&hclconfig, nil)
+	mockEngine.On("ApplyPlan", mock.Anything, mock.Anything).Return(&hclconfig, nil)
 	mockEngine.On("GetClients", mock.Anything).Return(clients)
 	mockEngine.On("ResourceCountForType", mock.Anything).Return(0)
+	mockEngine.On("SetParallelism", mock.Anything)
+	mockEngine.On("SetTargets", mock.Anything)
+	mockEngine.On("SetEventHandler", mock.Anything)
+	mockEngine.On("SetAtomic", mock.Anything)
+	mockEngine.On("SetTracerProvider", mock.Anything)
+	mockEngine.On("Drift", mock.Anything, mock.Anything).Return(nil, nil)
 
 	bp := blueprint.Blueprint{}
 
@@ -121,6 +135,107 @@ func TestRunSetsForceOnClients(t *testing.T) {
 	rm.tasks.AssertCalled(t, "SetForce", true)
 }
 
+func TestRunSetsParallelismOnEngine(t *testing.T) {
+	rf, rm := setupRun(t)
+	rf.Flags().Set("no-browser", "true")
+	rf.Flags().Set("parallelism", "4")
+
+	err := rf.Execute()
+	require.NoError(t, err)
+
+	rm.engine.AssertCalled(t, "SetParallelism", 4)
+}
+
+func TestRunSetsTargetsOnEngine(t *testing.T) {
+	rf, rm := setupRun(t)
+	rf.Flags().Set("no-browser", "true")
+	rf.Flags().Set("target", "resource.container.web")
+	rf.Flags().Set("target", "resource.network.cloud")
+
+	err := rf.Execute()
+	require.NoError(t, err)
+
+	rm.engine.AssertCalled(t, "SetTargets", []string{"resource.container.web", "resource.network.cloud"})
+}
+
+func TestRunWithAtomicSetsAtomicOnEngine(t *testing.T) {
+	rf, rm := setupRun(t)
+	rf.Flags().Set("no-browser", "true")
+	rf.Flags().Set("atomic", "true")
+
+	err := rf.Execute()
+	require.NoError(t, err)
+
+	rm.engine.AssertCalled(t, "SetAtomic", true)
+}
+
+func TestRunWithJSONLOutputSetsEventHandler(t *testing.T) {
+	rf, rm := setupRun(t)
+	rf.Flags().Set("no-browser", "true")
+	rf.Flags().Set("output", "jsonl")
+
+	err := rf.Execute()
+	require.NoError(t, err)
+
+	rm.engine.AssertCalled(t, "SetEventHandler", mock.MatchedBy(func(h events.Handler) bool { return h != nil }))
+}
+
+func TestRunWithTraceFileSetsTracerProvider(t *testing.T) {
+	rf, rm := setupRun(t)
+	rf.Flags().Set("no-browser", "true")
+
+	file := filepath.Join(t.TempDir(), "trace.json")
+	rf.Flags().Set("trace-file", file)
+
+	err := rf.Execute()
+	require.NoError(t, err)
+
+	rm.engine.AssertCalled(t, "SetTracerProvider", mock.Anything)
+	require.FileExists(t, file)
+}
+
+func TestRunWithoutTraceFileDoesNotSetTracerProvider(t *testing.T) {
+	rf, rm := setupRun(t)
+	rf.Flags().Set("no-browser", "true")
+
+	err := rf.Execute()
+	require.NoError(t, err)
+
+	rm.engine.AssertNotCalled(t, "SetTracerProvider", mock.Anything)
+}
+
+func TestRunWithInvalidOutputReturnsError(t *testing.T) {
+	rf, rm := setupRun(t)
+	rf.Flags().Set("no-browser", "true")
+	rf.Flags().Set("output", "xml")
+
+	err := rf.Execute()
+	require.Error(t, err)
+
+	rm.engine.AssertNotCalled(t, "ApplyWithVariables", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestRunWithRefreshDetectsDrift(t *testing.T) {
+	rf, rm := setupRun(t)
+	rf.Flags().Set("no-browser", "true")
+	rf.Flags().Set("refresh", "true")
+
+	err := rf.Execute()
+	require.NoError(t, err)
+
+	rm.engine.AssertCalled(t, "Drift", mock.Anything, false)
+}
+
+func TestRunWithoutRefreshDoesNotDetectDrift(t *testing.T) {
+	rf, rm := setupRun(t)
+	rf.Flags().Set("no-browser", "true")
+
+	err := rf.Execute()
+	require.NoError(t, err)
+
+	rm.engine.AssertNotCalled(t, "Drift", mock.Anything, mock.Anything)
+}
+
 func TestRunChecksForCertBundle(t *testing.T) {
 	rf, rm := setupRun(t)
 	rf.SetArgs([]string{"/tmp"})
@@ -202,6 +317,40 @@ func TestRunSetsDestinationFromArgsWhenPresent(t *testing.T) {
 	rm.engine.AssertCalled(t, "ApplyWithVariables", mock.Anything, "/tmp", mock.Anything, mock.Anything)
 }
 
+func TestRunReturnsErrorWhenStateLocked(t *testing.T) {
+	rf, rm := setupRun(t)
+	rf.SetArgs([]string{"/tmp"})
+
+	l, err := config.LockState(0)
+	require.NoError(t, err)
+	defer l.Unlock()
+
+	err = rf.Execute()
+	require.ErrorIs(t, err, config.ErrStateLocked)
+
+	rm.engine.AssertNotCalled(t, "ApplyWithVariables", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestRunAppliesPlanFromFile(t *testing.T) {
+	rf, rm := setupRun(t)
+
+	pf := filepath.Join(t.TempDir(), "jumppad.plan")
+	p := &jumppad.Plan{Path: "/tmp", StateChecksum: "abc"}
+	err := p.Save(pf)
+	require.NoError(t, err)
+
+	rf.SetArgs([]string{"--plan", pf})
+
+	err = rf.Execute()
+	require.NoError(t, err)
+
+	ap := testutils.GetCalls(&rm.engine.Mock, "ApplyPlan")[0].Arguments[1].(*jumppad.Plan)
+	require.Equal(t, "/tmp", ap.Path)
+	require.Equal(t, "abc", ap.StateChecksum)
+
+	rm.engine.AssertNotCalled(t, "ApplyWithVariables", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+}
+
 func TestRunSetsVariablesFromFlag(t *testing.T) {
 	rf, rm := setupRun(t)
 	rf.SetArgs([]string{
@@ -215,7 +364,7 @@ func TestRunSetsVariablesFromFlag(t *testing.T) {
 	err := rf.Execute()
 	require.NoError(t, err)
 
-	args := rm.engine.Calls[0].Arguments[2]
+	args := testutils.GetCalls(&rm.engine.Mock, "ApplyWithVariables")[0].Arguments[2]
 
 	require.Equal(t, map[string]string{
 		"abc":  "1234",
@@ -351,3 +500,37 @@ func TestRunDoesNotOpensBrowserWindowWhenCheckError(t *testing.T) {
 
 	rm.system.AssertNumberOfCalls(t, "OpenBrowser", 0)
 }
+
+func TestRunRedactsSensitiveBlueprintOutputs(t *testing.T) {
+	rf, rm := setupRun(t)
+	rf.SetArgs([]string{"/tmp"})
+	rf.Flags().Set("no-browser", "true")
+
+	out := bytes.NewBuffer([]byte(""))
+	rf.SetOut(out)
+
+	bp := &blueprint.Blueprint{ResourceBase: hcltypes.ResourceBase{Meta: hcltypes.Meta{Name: "test", Type: blueprint.TypeBlueprint}}}
+
+	address := &resources.Output{ResourceBase: hcltypes.ResourceBase{Meta: hcltypes.Meta{Name: "address", Type: resources.TypeOutput}}}
+	address.Value = "10.5.0.200"
+
+	password := &resources.Output{ResourceBase: hcltypes.ResourceBase{Meta: hcltypes.Meta{
+		Name:       "password",
+		Type:       resources.TypeOutput,
+		Properties: map[string]interface{}{constants.PropertySensitive: true},
+	}}}
+	password.Value = "supersecretpassword"
+
+	c := &hclconfig.Config{Resources: []hcltypes.Resource{bp, address, password}}
+
+	testutils.RemoveOn(&rm.engine.Mock, "ApplyWithVariables")
+	rm.engine.On("ApplyWithVariables", mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(c, nil)
+	rm.engine.On("Config").Return(c)
+
+	err := rf.Execute()
+	require.NoError(t, err)
+
+	require.Contains(t, out.String(), "10.5.0.200")
+	require.Contains(t, out.String(), logger.Redacted)
+	require.NotContains(t, out.String(), "supersecretpassword")
+}
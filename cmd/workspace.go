@@ -0,0 +1,192 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/jumppad-labs/jumppad/pkg/utils"
+	"github.com/spf13/cobra"
+)
+
+func newWorkspaceCmd() *cobra.Command {
+	workspaceCmd := &cobra.Command{
+		Use:   "workspace",
+		Short: "Manage workspaces",
+		Long: `Manage workspaces.
+
+Each workspace has its own state and data folders, and the workspace name is added
+to the FQDN of the resources it creates. This allows several blueprints to run side
+by side. The current workspace can be overridden with the --workspace flag or the
+JUMPPAD_WORKSPACE environment variable.`,
+	}
+
+	workspaceCmd.AddCommand(newWorkspaceNewCmd())
+	workspaceCmd.AddCommand(newWorkspaceSelectCmd())
+	workspaceCmd.AddCommand(newWorkspaceListCmd())
+	workspaceCmd.AddCommand(newWorkspaceDeleteCmd())
+
+	return workspaceCmd
+}
+
+func newWorkspaceNewCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:          "new [name]",
+		Short:        "Create a new workspace and select it",
+		Example:      `jumppad workspace new dev`,
+		Args:         cobra.ExactArgs(1),
+		SilenceUsage: true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			name := args[0]
+
+			err := utils.ValidateWorkspaceName(name)
+			if err != nil {
+				return err
+			}
+
+			if utils.WorkspaceExists(name) {
+				return fmt.Errorf("workspace '%s' already exists", name)
+			}
+
+			err = os.MkdirAll(utils.WorkspaceFolder(name), os.ModePerm)
+			if err != nil {
+				return fmt.Errorf("unable to create workspace '%s': %s", name, err)
+			}
+
+			err = selectWorkspace(name)
+			if err != nil {
+				return err
+			}
+
+			cmd.Printf("Created and selected workspace '%s'\n", name)
+
+			return nil
+		},
+	}
+}
+
+func newWorkspaceSelectCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:          "select [name]",
+		Short:        "Select the workspace used by other commands",
+		Example:      `jumppad workspace select dev`,
+		Args:         cobra.ExactArgs(1),
+		SilenceUsage: true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			name := args[0]
+
+			err := utils.ValidateWorkspaceName(name)
+			if err != nil {
+				return err
+			}
+
+			if !utils.WorkspaceExists(name) {
+				return fmt.Errorf("workspace '%s' does not exist, create it with 'jumppad workspace new %s'", name, name)
+			}
+
+			err = selectWorkspace(name)
+			if err != nil {
+				return err
+			}
+
+			cmd.Printf("Selected workspace '%s'\n", name)
+
+			if ws := os.Getenv(utils.WorkspaceEnvVar); ws != "" && ws != name {
+				cmd.Printf("The environment variable %s is set, workspace '%s' will be used until it is unset\n", utils.WorkspaceEnvVar, ws)
+			}
+
+			return nil
+		},
+	}
+}
+
+func newWorkspaceListCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:          "list",
+		Short:        "List the workspaces, the current workspace is marked with *",
+		Args:         cobra.NoArgs,
+		SilenceUsage: true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			workspaces, err := utils.Workspaces()
+			if err != nil {
+				return err
+			}
+
+			current := utils.Workspace()
+			for _, ws := range workspaces {
+				if ws == current {
+					cmd.Printf("%s%s\n", greenIcon.Render("*"), whiteText.Render(ws))
+					continue
+				}
+
+				cmd.Printf("  %s\n", ws)
+			}
+
+			return nil
+		},
+	}
+}
+
+func newWorkspaceDeleteCmd() *cobra.Command {
+	var force bool
+
+	deleteCmd := &cobra.Command{
+		Use:          "delete [name]",
+		Short:        "Delete a workspace and its data",
+		Example:      `jumppad workspace delete dev`,
+		Args:         cobra.ExactArgs(1),
+		SilenceUsage: true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			name := args[0]
+
+			err := utils.ValidateWorkspaceName(name)
+			if err != nil {
+				return err
+			}
+
+			if name == utils.DefaultWorkspace {
+				return fmt.Errorf("the default workspace can not be deleted")
+			}
+
+			if !utils.WorkspaceExists(name) {
+				return fmt.Errorf("workspace '%s' does not exist", name)
+			}
+
+			if name == utils.Workspace() {
+				return fmt.Errorf("workspace '%s' is the current workspace, select another workspace before deleting it", name)
+			}
+
+			state := filepath.Join(utils.WorkspaceFolder(name), "state", "state.json")
+			if _, err := os.Stat(state); err == nil && !force {
+				return fmt.Errorf("workspace '%s' contains resources, run 'jumppad down --workspace %s' before deleting it, or use --force to delete the workspace without destroying the resources", name, name)
+			}
+
+			err = os.RemoveAll(utils.WorkspaceFolder(name))
+			if err != nil {
+				return fmt.Errorf("unable to delete workspace '%s': %s", name, err)
+			}
+
+			cmd.Printf("Deleted workspace '%s'\n", name)
+
+			return nil
+		},
+	}
+
+	deleteCmd.Flags().BoolVarP(&force, "force", "", false, "Delete the workspace even when it contains resources, the resources are not destroyed")
+
+	return deleteCmd
+}
+
+func selectWorkspace(name string) error {
+	err := os.MkdirAll(utils.JumppadHome(), os.ModePerm)
+	if err != nil {
+		return fmt.Errorf("unable to select workspace '%s': %s", name, err)
+	}
+
+	err = os.WriteFile(utils.WorkspaceSelectionPath(), []byte(name), 0644)
+	if err != nil {
+		return fmt.Errorf("unable to select workspace '%s': %s", name, err)
+	}
+
+	return nil
+}
@@ -0,0 +1,69 @@
+package cmd
+
+import (
+	"bytes"
+	"os"
+	"testing"
+
+	"github.com/jumppad-labs/jumppad/pkg/utils"
+	"github.com/stretchr/testify/require"
+)
+
+func runWorkspaceCmd(t *testing.T, args ...string) (string, error) {
+	c := newWorkspaceCmd()
+
+	out := bytes.NewBufferString("")
+	c.SetOut(out)
+	c.SetErr(out)
+	c.SetArgs(args)
+
+	err := c.Execute()
+
+	return out.String(), err
+}
+
+func setupWorkspaceCmd(t *testing.T) string {
+	home := t.TempDir()
+	t.Setenv(utils.HomeEnvName(), home)
+	t.Setenv(utils.WorkspaceEnvVar, "")
+
+	return home
+}
+
+func TestWorkspaceDeleteRejectsInvalidName(t *testing.T) {
+	home := setupWorkspaceCmd(t)
+	os.MkdirAll(utils.JumppadHome(), os.ModePerm)
+
+	_, err := runWorkspaceCmd(t, "delete", "..")
+	require.ErrorIs(t, err, utils.ErrInvalidWorkspaceName)
+
+	_, err = runWorkspaceCmd(t, "delete", "../..", "--force")
+	require.ErrorIs(t, err, utils.ErrInvalidWorkspaceName)
+
+	require.DirExists(t, home)
+	require.DirExists(t, utils.JumppadHome())
+}
+
+func TestWorkspaceSelectRejectsInvalidName(t *testing.T) {
+	setupWorkspaceCmd(t)
+
+	_, err := runWorkspaceCmd(t, "select", "..")
+	require.ErrorIs(t, err, utils.ErrInvalidWorkspaceName)
+	require.NoFileExists(t, utils.WorkspaceSelectionPath())
+}
+
+func TestWorkspaceDeleteRemovesWorkspace(t *testing.T) {
+	setupWorkspaceCmd(t)
+
+	_, err := runWorkspaceCmd(t, "new", "dev")
+	require.NoError(t, err)
+
+	_, err = runWorkspaceCmd(t, "select", "default")
+	require.NoError(t, err)
+
+	_, err = runWorkspaceCmd(t, "delete", "dev")
+	require.NoError(t, err)
+
+	_, err = os.Stat(utils.WorkspaceFolder("dev"))
+	require.True(t, os.IsNotExist(err))
+}
@@ -32,11 +32,21 @@ type Clients struct {
 	ImageLog       images.ImageLog
 	Connector      connector.Connector
 	TarGz          *tar.TarGz
+	// Environment is the jumppad home folder and workspace that providers
+	// create files and Docker resources in
+	Environment utils.Environment
 }
 
 // GenerateClients creates the various clients for creating and destroying resources
 func GenerateClients(l logger.Logger) (*Clients, error) {
-	dc, _ := container.NewDocker()
+	return GenerateClientsWithEnvironment(utils.Environment{}, l)
+}
+
+// GenerateClientsWithEnvironment creates the clients for creating and
+// destroying resources in the given jumppad home folder and workspace
+func GenerateClientsWithEnvironment(env utils.Environment, l logger.Logger) (*Clients, error) {
+	rt := container.Runtime()
+	dc, _ := container.NewClient(rt)
 
 	kc := k8s.NewKubernetes(60*time.Second, l)
 
@@ -52,13 +62,18 @@ func GenerateClients(l logger.Logger) (*Clients, error) {
 
 	bc := &system.SystemImpl{}
 
-	il := images.NewImageFileLog(utils.ImageCacheLog())
+	il := images.NewImageFileLog(env.ImageCacheLog())
 
 	tgz := &tar.TarGz{}
 
-	ct, _ := container.NewDockerTasks(dc, il, tgz, l)
+	ct, _ := container.NewContainerTasks(rt, dc, il, tgz, l)
+	if ct != nil {
+		ct.SetEnvironment(env)
+	}
 
 	co := connector.DefaultConnectorOptions()
+	co.LogDirectory = env.LogsDir()
+	co.CertsDir = env.CertsDir("")
 	cc := connector.NewConnector(co)
 
 	return &Clients{
@@ -75,5 +90,6 @@ func GenerateClients(l logger.Logger) (*Clients, error) {
 		ImageLog:       il,
 		Connector:      cc,
 		TarGz:          tgz,
+		Environment:    env,
 	}, nil
 }
@@ -79,6 +79,9 @@ type ConnectorOptions struct {
 	APIBind      string
 	LogLevel     string
 	PidFile      string
+	// CertsDir is the folder containing the root certificates used to
+	// secure the connection to the connector
+	CertsDir string
 }
 
 func DefaultConnectorOptions() ConnectorOptions {
@@ -90,12 +93,19 @@ func DefaultConnectorOptions() ConnectorOptions {
 	co.APIBind = ":30003"
 	co.LogLevel = "info"
 	co.PidFile = utils.GetConnectorPIDFile()
+	co.CertsDir = utils.CertsDir("")
 
 	return co
 }
 
 // NewConnector creates a new connector with the given options
 func NewConnector(opts ConnectorOptions) Connector {
+	// options that are not created with DefaultConnectorOptions use the
+	// root certificates in the jumppad home folder
+	if opts.CertsDir == "" {
+		opts.CertsDir = utils.CertsDir("")
+	}
+
 	return &ConnectorImpl{options: opts}
 }
 
@@ -324,7 +334,7 @@ func (c *ConnectorImpl) ExposeService(
 	direction string,
 ) (string, error) {
 
-	dir := utils.CertsDir("")
+	dir := c.options.CertsDir
 	cb, err := c.GetLocalCertBundle(dir)
 	if err != nil {
 		return "", fmt.Errorf("unable to find certificate at location: %s, error: %s", dir, err)
@@ -359,7 +369,7 @@ func (c *ConnectorImpl) ExposeService(
 
 // RemoveService removes a previously exposed service
 func (c *ConnectorImpl) RemoveService(id string) error {
-	cb, err := c.GetLocalCertBundle(utils.CertsDir(""))
+	cb, err := c.GetLocalCertBundle(c.options.CertsDir)
 	if err != nil {
 		return err
 	}
@@ -382,7 +392,7 @@ func (c *ConnectorImpl) RemoveService(id string) error {
 
 // ListServices lists all active services
 func (c *ConnectorImpl) ListServices() ([]*shipyard.Service, error) {
-	cb, err := c.GetLocalCertBundle(utils.CertsDir(""))
+	cb, err := c.GetLocalCertBundle(c.options.CertsDir)
 	if err != nil {
 		return nil, err
 	}
@@ -78,6 +78,16 @@ func TestConnectorSuite(t *testing.T) {
 	t.Run("Calls list", testListServicesCallsList)
 }
 
+func TestNewConnectorUsesRootCertsWhenCertsDirNotSet(t *testing.T) {
+	t.Setenv(utils.HomeEnvName(), t.TempDir())
+
+	c := NewConnector(ConnectorOptions{}).(*ConnectorImpl)
+	assert.Equal(t, utils.CertsDir(""), c.options.CertsDir)
+
+	c = NewConnector(ConnectorOptions{CertsDir: "/tmp/certs"}).(*ConnectorImpl)
+	assert.Equal(t, "/tmp/certs", c.options.CertsDir)
+}
+
 func testGenerateCreatesBundle(t *testing.T) {
 	c := NewConnector(suiteOptions)
 
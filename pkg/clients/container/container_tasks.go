@@ -4,6 +4,7 @@ import (
 	"io"
 
 	"github.com/jumppad-labs/jumppad/pkg/clients/container/types"
+	"github.com/jumppad-labs/jumppad/pkg/utils"
 )
 
 // ContainerTasks is a task oriented client which abstracts
@@ -18,6 +19,9 @@ import (
 //go:generate mockery --name ContainerTasks --filename container_tasks.go
 type ContainerTasks interface {
 	SetForce(bool)
+	// SetEnvironment sets the jumppad home folder and workspace that volume
+	// and network names are resolved in
+	SetEnvironment(utils.Environment)
 	// CreateContainer creates a new container for the given configuration
 	// if successful CreateContainer returns the ID of the created container and a nil error
 	// if not successful CreateContainer returns a blank string for the id and an error message
@@ -27,6 +31,22 @@ type ContainerTasks interface {
 	ContainerInfo(id string) (interface{}, error)
 	// RemoveContainer stops and removes a running container
 	RemoveContainer(id string, force bool) error
+	// StopContainer stops a running container without removing it, when force
+	// is set the container is killed rather than waiting for it to exit
+	StopContainer(id string, force bool) error
+	// StartContainer starts a stopped container, the container is reattached
+	// to the given networks with the IPAddress of each attachment so that it
+	// keeps the addresses it had before it was stopped
+	StartContainer(id string, networks []types.NetworkAttachment) error
+	// CommitContainer saves the filesystem of the container to an image with
+	// the given name and returns the id of the image, the contents of the
+	// volumes mounted in the container are not included in the image
+	CommitContainer(id, image string) (string, error)
+	// ExportVolumes writes the contents of the volumes mounted in the
+	// container to dst as a tar.gz archive, the entries in the archive are
+	// rooted at the path of each mount. Returns the paths of the exported
+	// volumes, when the container has no volumes nothing is written.
+	ExportVolumes(id string, dst io.Writer) ([]string, error)
 	// BuildContainer builds a container based on the given configuration
 	// If a cached image already exists Build will noop
 	// When force is specified BuildContainer will rebuild the container regardless of cached images
@@ -0,0 +1,401 @@
+package container
+
+import (
+	"bytes"
+	"encoding/base64"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	dtypes "github.com/jumppad-labs/jumppad/pkg/clients/container/types"
+	"github.com/jumppad-labs/jumppad/pkg/clients/images"
+	"github.com/jumppad-labs/jumppad/pkg/clients/logger"
+	"github.com/jumppad-labs/jumppad/pkg/clients/tar"
+	"github.com/stretchr/testify/require"
+)
+
+// the behaviour tests run against DockerTasks with a fake Docker engine and
+// PodmanTasks with a fake Podman engine
+
+func TestDockerTasksBehaviour(t *testing.T) {
+	testContainerTasksBehaviour(t, false, func(t *testing.T, c Docker) ContainerTasks {
+		dt, err := NewDockerTasks(c, testImageLog(t), &tar.TarGz{}, logger.NewTestLogger(t))
+		require.NoError(t, err)
+
+		dt.defaultWait = 1 * time.Millisecond
+		return dt
+	})
+}
+
+func TestPodmanTasksBehaviour(t *testing.T) {
+	testContainerTasksBehaviour(t, true, func(t *testing.T, c Docker) ContainerTasks {
+		pt, err := NewPodmanTasks(c, testImageLog(t), &tar.TarGz{}, logger.NewTestLogger(t))
+		require.NoError(t, err)
+
+		pt.defaultWait = 1 * time.Millisecond
+		return pt
+	})
+}
+
+func testImageLog(t *testing.T) images.ImageLog {
+	return images.NewImageFileLog(filepath.Join(t.TempDir(), "images.log"))
+}
+
+func testBehaviourContainer(name string) *dtypes.Container {
+	return &dtypes.Container{
+		Name:  name,
+		Image: &dtypes.Image{Name: "nginx:latest"},
+		Networks: []dtypes.NetworkAttachment{
+			{ID: fakeOnpremNetwork, IPAddress: "10.6.0.200", Aliases: []string{"web"}},
+		},
+	}
+}
+
+func testContainerTasksBehaviour(t *testing.T, podman bool, newTasks func(t *testing.T, c Docker) ContainerTasks) {
+	setup := func(t *testing.T) (*fakeEngine, ContainerTasks) {
+		f, c := newFakeEngine(t, podman)
+		f.addImage("nginx:latest")
+
+		return f, newTasks(t, c)
+	}
+
+	t.Run("reports the engine type", func(t *testing.T) {
+		_, ct := setup(t)
+
+		want := dtypes.EngineTypeDocker
+		if podman {
+			want = dtypes.EngineTypePodman
+		}
+
+		require.Equal(t, want, ct.EngineInfo().EngineType)
+	})
+
+	t.Run("creates a container attached only to the custom networks", func(t *testing.T) {
+		f, ct := setup(t)
+
+		id, err := ct.CreateContainer(testBehaviourContainer("web.container.local.jumppad.dev"))
+		require.NoError(t, err)
+
+		c := f.Container(id)
+		require.NotNil(t, c)
+		require.True(t, c.running)
+		require.Len(t, c.networks, 1)
+		require.Equal(t, "10.6.0.200", c.networks["onprem"].IPAddress)
+		require.Contains(t, c.networks["onprem"].Aliases, "web")
+	})
+
+	t.Run("creates a container sharing the network of another container", func(t *testing.T) {
+		f, ct := setup(t)
+
+		id, err := ct.CreateContainer(testBehaviourContainer("web.container.local.jumppad.dev"))
+		require.NoError(t, err)
+
+		sc := &dtypes.Container{
+			Name:     "sidecar.container.local.jumppad.dev",
+			Image:    &dtypes.Image{Name: "nginx:latest"},
+			Networks: []dtypes.NetworkAttachment{{ID: id, IsContainer: true}},
+		}
+
+		sid, err := ct.CreateContainer(sc)
+		require.NoError(t, err)
+
+		c := f.Container(sid)
+		require.Empty(t, c.networks)
+		require.Equal(t, "container:"+id, string(c.host.NetworkMode))
+	})
+
+	t.Run("returns an error when the network does not exist", func(t *testing.T) {
+		_, ct := setup(t)
+
+		c := testBehaviourContainer("web.container.local.jumppad.dev")
+		c.Networks[0].ID = "resource.network.missing"
+
+		_, err := ct.CreateContainer(c)
+		require.Error(t, err)
+	})
+
+	t.Run("lists the networks a container is attached to", func(t *testing.T) {
+		_, ct := setup(t)
+
+		id, err := ct.CreateContainer(testBehaviourContainer("web.container.local.jumppad.dev"))
+		require.NoError(t, err)
+
+		nets := ct.ListNetworks(id)
+		require.Equal(t, []dtypes.NetworkAttachment{{ID: fakeOnpremNetwork, Name: "onprem", IPAddress: "10.6.0.200/16"}}, nets)
+	})
+
+	t.Run("finds a network using the resource id", func(t *testing.T) {
+		_, ct := setup(t)
+
+		n, err := ct.FindNetwork(fakeOnpremNetwork)
+		require.NoError(t, err)
+		require.Equal(t, "onprem", n.Name)
+		require.Equal(t, "10.6.0.0/16", n.Subnet)
+	})
+
+	t.Run("attaches and detaches networks", func(t *testing.T) {
+		_, ct := setup(t)
+
+		c := testBehaviourContainer("web.container.local.jumppad.dev")
+		c.Networks = nil
+
+		id, err := ct.CreateContainer(c)
+		require.NoError(t, err)
+
+		err = ct.AttachNetwork("onprem", id, nil, "10.6.0.50")
+		require.NoError(t, err)
+		require.Contains(t, ct.ListNetworks(id), dtypes.NetworkAttachment{ID: fakeOnpremNetwork, Name: "onprem", IPAddress: "10.6.0.50/16"})
+
+		err = ct.DetachNetwork("onprem", id)
+		require.NoError(t, err)
+		require.NotContains(t, ct.ListNetworks(id), dtypes.NetworkAttachment{ID: fakeOnpremNetwork, Name: "onprem", IPAddress: "10.6.0.50/16"})
+	})
+
+	t.Run("finds and removes containers", func(t *testing.T) {
+		f, ct := setup(t)
+
+		id, err := ct.CreateContainer(testBehaviourContainer("web.container.local.jumppad.dev"))
+		require.NoError(t, err)
+
+		ids, err := ct.FindContainerIDs("web.container.local.jumppad.dev")
+		require.NoError(t, err)
+		require.Equal(t, []string{id}, ids)
+
+		err = ct.RemoveContainer(id, false)
+		require.NoError(t, err)
+		require.Nil(t, f.Container(id))
+
+		ids, err = ct.FindContainerIDs("web.container.local.jumppad.dev")
+		require.NoError(t, err)
+		require.Empty(t, ids)
+	})
+
+	t.Run("stops and starts a container with the recorded addresses", func(t *testing.T) {
+		f, ct := setup(t)
+
+		c := testBehaviourContainer("web.container.local.jumppad.dev")
+		c.Networks[0].IPAddress = ""
+
+		id, err := ct.CreateContainer(c)
+		require.NoError(t, err)
+
+		err = ct.StopContainer(id, false)
+		require.NoError(t, err)
+		require.False(t, f.Container(id).running)
+
+		err = ct.StartContainer(id, []dtypes.NetworkAttachment{{ID: fakeOnpremNetwork, IPAddress: "10.6.0.99", Aliases: []string{"web"}}})
+		require.NoError(t, err)
+
+		sc := f.Container(id)
+		require.True(t, sc.running)
+		require.Len(t, sc.networks, 1)
+		require.Equal(t, "10.6.0.99", sc.networks["onprem"].IPAddress)
+		require.Contains(t, sc.networks["onprem"].Aliases, "web")
+	})
+
+	t.Run("returns an error starting a container when the network does not exist", func(t *testing.T) {
+		_, ct := setup(t)
+
+		id, err := ct.CreateContainer(testBehaviourContainer("web.container.local.jumppad.dev"))
+		require.NoError(t, err)
+
+		err = ct.StopContainer(id, true)
+		require.NoError(t, err)
+
+		err = ct.StartContainer(id, []dtypes.NetworkAttachment{{ID: "resource.network.missing"}})
+		require.Error(t, err)
+	})
+
+	t.Run("executes a command and writes the output", func(t *testing.T) {
+		_, ct := setup(t)
+
+		id, err := ct.CreateContainer(testBehaviourContainer("web.container.local.jumppad.dev"))
+		require.NoError(t, err)
+
+		out := bytes.NewBufferString("")
+		code, err := ct.ExecuteCommand(id, []string{"echo", "hello"}, nil, "/", "", "", 30, out)
+		require.NoError(t, err)
+		require.Equal(t, 0, code)
+		require.Equal(t, "hello\n", out.String())
+	})
+
+	t.Run("executes a command without a writer", func(t *testing.T) {
+		_, ct := setup(t)
+
+		id, err := ct.CreateContainer(testBehaviourContainer("web.container.local.jumppad.dev"))
+		require.NoError(t, err)
+
+		code, err := ct.ExecuteCommand(id, []string{"find", "/missing"}, nil, "/", "", "", 30, nil)
+		require.Error(t, err)
+		require.Equal(t, 1, code)
+	})
+
+	t.Run("executes a script and returns the exit code", func(t *testing.T) {
+		_, ct := setup(t)
+
+		id, err := ct.CreateContainer(testBehaviourContainer("web.container.local.jumppad.dev"))
+		require.NoError(t, err)
+
+		out := bytes.NewBufferString("")
+		code, err := ct.ExecuteScript(id, "echo hello\r\nexit 3\necho unreachable", nil, "/", "", "", 30, out)
+		require.Error(t, err)
+		require.Equal(t, 3, code)
+		require.Equal(t, "hello\n", out.String())
+	})
+
+	t.Run("creates and removes volumes", func(t *testing.T) {
+		f, ct := setup(t)
+
+		name, err := ct.CreateVolume("images")
+		require.NoError(t, err)
+		require.Contains(t, f.volumes, name)
+
+		err = ct.RemoveVolume("images")
+		require.NoError(t, err)
+		require.NotContains(t, f.volumes, name)
+	})
+
+	t.Run("pulls images", func(t *testing.T) {
+		_, ct := setup(t)
+
+		err := ct.PullImage(dtypes.Image{Name: "consul:1.6.1"}, false)
+		require.NoError(t, err)
+
+		id, err := ct.FindImageInLocalRegistry(dtypes.Image{Name: "consul:1.6.1"})
+		require.NoError(t, err)
+		require.NotEmpty(t, id)
+	})
+
+	t.Run("copies files to a volume", func(t *testing.T) {
+		f, ct := setup(t)
+
+		src := filepath.Join(t.TempDir(), "config.hcl")
+		os.WriteFile(src, []byte("original"), 0644)
+
+		files, err := ct.CopyFilesToVolume("config", []string{src}, "/files/nested", false)
+		require.NoError(t, err)
+		require.Equal(t, []string{"/cache/files/nested/config.hcl"}, files)
+
+		d, ok := f.VolumeFile("config", "/files/nested/config.hcl")
+		require.True(t, ok)
+		require.Equal(t, "original", string(d))
+
+		// the helper container is removed
+		for _, c := range f.containers {
+			require.NotContains(t, c.name, "-import")
+		}
+	})
+
+	t.Run("does not replace files in a volume unless forced", func(t *testing.T) {
+		f, ct := setup(t)
+
+		src := filepath.Join(t.TempDir(), "config.hcl")
+		os.WriteFile(src, []byte("original"), 0644)
+
+		_, err := ct.CopyFilesToVolume("config", []string{src}, "/files", false)
+		require.NoError(t, err)
+
+		os.WriteFile(src, []byte("updated"), 0644)
+
+		files, err := ct.CopyFilesToVolume("config", []string{src}, "/files", false)
+		require.NoError(t, err)
+		require.Equal(t, []string{"/cache/files/config.hcl"}, files)
+
+		d, _ := f.VolumeFile("config", "/files/config.hcl")
+		require.Equal(t, "original", string(d))
+
+		_, err = ct.CopyFilesToVolume("config", []string{src}, "/files", true)
+		require.NoError(t, err)
+
+		d, _ = f.VolumeFile("config", "/files/config.hcl")
+		require.Equal(t, "updated", string(d))
+	})
+
+	t.Run("copies local images to a volume", func(t *testing.T) {
+		f, ct := setup(t)
+		f.addImage("consul:1.6.1")
+
+		files, err := ct.CopyLocalDockerImagesToVolume([]string{"consul:1.6.1"}, "images", false)
+		require.NoError(t, err)
+		require.Len(t, files, 1)
+
+		name, err := base64.StdEncoding.DecodeString(filepath.Base(files[0]))
+		require.NoError(t, err)
+
+		d, ok := f.VolumeFile("images", "/images/"+filepath.Base(files[0]))
+		require.True(t, ok)
+		require.Equal(t, "image "+string(name), string(d))
+	})
+
+	t.Run("returns an error copying images that are not in the local cache", func(t *testing.T) {
+		_, ct := setup(t)
+
+		_, err := ct.CopyLocalDockerImagesToVolume([]string{"consul:1.6.1"}, "images", false)
+		require.Error(t, err)
+	})
+
+	t.Run("commits a container to an image", func(t *testing.T) {
+		_, ct := setup(t)
+
+		id, err := ct.CreateContainer(testBehaviourContainer("web.container.local.jumppad.dev"))
+		require.NoError(t, err)
+
+		imageID, err := ct.CommitContainer(id, "jumppad.dev/snapshot/web:test")
+		require.NoError(t, err)
+		require.NotEmpty(t, imageID)
+
+		found, err := ct.FindImageInLocalRegistry(dtypes.Image{Name: "jumppad.dev/snapshot/web:test"})
+		require.NoError(t, err)
+		require.Equal(t, imageID, found)
+	})
+
+	t.Run("exports and restores the volumes of a container", func(t *testing.T) {
+		f, ct := setup(t)
+
+		c := testBehaviourContainer("web.container.local.jumppad.dev")
+		c.Volumes = []dtypes.Volume{{Source: "data", Destination: "/data", Type: "volume"}}
+
+		id, err := ct.CreateContainer(c)
+		require.NoError(t, err)
+
+		src := filepath.Join(t.TempDir(), "file")
+		os.WriteFile(src, []byte("snapshot"), 0644)
+
+		err = ct.CopyFileToContainer(id, src, "/data")
+		require.NoError(t, err)
+
+		archive := filepath.Join(t.TempDir(), "volumes.tar.gz")
+		out, _ := os.Create(archive)
+
+		paths, err := ct.ExportVolumes(id, out)
+		out.Close()
+		require.NoError(t, err)
+		require.Equal(t, []string{"/data"}, paths)
+
+		c = testBehaviourContainer("restored.container.local.jumppad.dev")
+		c.Networks[0].IPAddress = "10.6.0.201"
+		c.Volumes = []dtypes.Volume{{Source: "restored", Destination: "/data", Type: "volume"}}
+		c.VolumeArchive = archive
+
+		_, err = ct.CreateContainer(c)
+		require.NoError(t, err)
+
+		d, ok := f.VolumeFile("restored", "/file")
+		require.True(t, ok)
+		require.Equal(t, "snapshot", string(d))
+	})
+
+	t.Run("does not export volumes for a container without volumes", func(t *testing.T) {
+		_, ct := setup(t)
+
+		id, err := ct.CreateContainer(testBehaviourContainer("web.container.local.jumppad.dev"))
+		require.NoError(t, err)
+
+		out := bytes.NewBuffer(nil)
+		paths, err := ct.ExportVolumes(id, out)
+		require.NoError(t, err)
+		require.Empty(t, paths)
+		require.Zero(t, out.Len())
+	})
+}
@@ -2,7 +2,10 @@ package container
 
 import (
 	"context"
+	"fmt"
 	"io"
+	"net/http"
+	"os"
 
 	"github.com/docker/docker/api/types"
 	"github.com/docker/docker/api/types/checkpoint"
@@ -12,6 +15,8 @@ import (
 	"github.com/docker/docker/api/types/system"
 	"github.com/docker/docker/api/types/volume"
 	"github.com/docker/docker/client"
+	"github.com/docker/go-connections/tlsconfig"
+	"github.com/jumppad-labs/jumppad/pkg/utils"
 	specs "github.com/opencontainers/image-spec/specs-go/v1"
 )
 
@@ -32,6 +37,7 @@ type Docker interface {
 	ContainerStart(context.Context, string, container.StartOptions) error
 	ContainerStop(ctx context.Context, containerID string, options container.StopOptions) error
 	ContainerRemove(ctx context.Context, containerID string, options container.RemoveOptions) error
+	ContainerRename(ctx context.Context, containerID, newContainerName string) error
 	ContainerLogs(ctx context.Context, container string, options container.LogsOptions) (io.ReadCloser, error)
 	ContainerExecCreate(ctx context.Context, container string, config container.ExecOptions) (container.ExecCreateResponse, error)
 	ContainerExecStart(ctx context.Context, execID string, config container.ExecStartOptions) error
@@ -39,6 +45,9 @@ type Docker interface {
 	ContainerExecInspect(ctx context.Context, execID string) (container.ExecInspect, error)
 	ContainerExecResize(ctx context.Context, execID string, config container.ResizeOptions) error
 	ContainerInspect(ctx context.Context, containerID string) (container.InspectResponse, error)
+	ContainerCommit(ctx context.Context, containerID string, options container.CommitOptions) (container.CommitResponse, error)
+	ContainerPause(ctx context.Context, containerID string) error
+	ContainerUnpause(ctx context.Context, containerID string) error
 
 	CheckpointCreate(ctx context.Context, container string, options checkpoint.CreateOptions) error
 	CheckpointList(ctx context.Context, container string, options checkpoint.ListOptions) ([]checkpoint.Summary, error)
@@ -71,9 +80,77 @@ type Docker interface {
 	Info(ctx context.Context) (system.Info, error)
 }
 
-// NewDocker creates a new Docker client
+// NewDocker creates a new Docker client for the endpoint returned by
+// utils.GetDockerEndpoint, remote daemons can be reached with TLS or ssh
 func NewDocker() (Docker, error) {
-	cli, err := client.NewClientWithOpts(client.WithHostFromEnv(), client.WithVersion("1.41"))
+	ep, err := utils.GetDockerEndpoint()
+	if err != nil {
+		return nil, err
+	}
+
+	opts, err := clientOpts(ep)
+	if err != nil {
+		return nil, err
+	}
+
+	cli, err := client.NewClientWithOpts(append(opts, client.WithVersion("1.41"))...)
+	if err != nil {
+		return nil, err
+	}
+
+	return cli, nil
+}
+
+// clientOpts returns the options for a client that connects to the endpoint
+func clientOpts(ep *utils.DockerEndpoint) ([]client.Opt, error) {
+	switch {
+	case ep.Host == "":
+		return []client.Opt{client.WithHostFromEnv()}, nil
+
+	case ep.IsSSH():
+		dialer, err := sshDialer(ep.Host)
+		if err != nil {
+			return nil, err
+		}
+
+		// the host is not used as the connection is made by the dialer
+		return []client.Opt{client.WithHost("http://docker.example.com"), client.WithDialContext(dialer)}, nil
+
+	case ep.HasTLS():
+		tc, err := tlsconfig.Client(tlsconfig.Options{
+			CAFile:             ep.CACert,
+			CertFile:           ep.Cert,
+			KeyFile:            ep.Key,
+			InsecureSkipVerify: ep.SkipTLSVerify,
+			ExclusiveRootPools: true,
+		})
+
+		if err != nil {
+			return nil, fmt.Errorf("unable to load TLS config for Docker host %s: %s", ep.Host, err)
+		}
+
+		hc := &http.Client{Transport: &http.Transport{TLSClientConfig: tc}, CheckRedirect: client.CheckRedirect}
+
+		return []client.Opt{client.WithHTTPClient(hc), client.WithHost(ep.Host)}, nil
+	}
+
+	return []client.Opt{client.WithHost(ep.Host)}, nil
+}
+
+// NewPodman creates a client for the Docker compatible API of the Podman
+// service, the address is read from DOCKER_HOST, the selected Docker context
+// or CONTAINER_HOST, when none are set the Podman socket is used
+func NewPodman() (Docker, error) {
+	if os.Getenv("DOCKER_HOST") != "" || os.Getenv(utils.DockerContextEnvVar) != "" {
+		return NewDocker()
+	}
+
+	host := os.Getenv("CONTAINER_HOST")
+	if host == "" {
+		host, _ = podmanSocket()
+	}
+
+	cli, err := client.NewClientWithOpts(client.WithHost(host), client.WithVersion("1.41"))
 	if err != nil {
 		return nil, err
 	}
@@ -12,6 +12,7 @@ import (
 	gosignal "os/signal"
 	"path"
 	"path/filepath"
+	"sort"
 	"strconv"
 	"strings"
 	"sync"
@@ -63,6 +64,7 @@ type DockerTasks struct {
 	tg            *ctar.TarGz
 	force         bool
 	defaultWait   time.Duration
+	env           utils.Environment
 }
 
 // NewDockerTasks creates a DockerTasks with the given Docker client
@@ -105,10 +107,89 @@ func (d *DockerTasks) SetForce(force bool) {
 	d.force = force
 }
 
+// SetEnvironment sets the jumppad home folder and workspace that volume and
+// network names are resolved in
+func (d *DockerTasks) SetEnvironment(env utils.Environment) {
+	d.env = env
+}
+
 // CreateContainer creates a new Docker container for the given configuation
 func (d *DockerTasks) CreateContainer(c *dtypes.Container) (string, error) {
 	d.l.Debug("Creating Docker Container", "ref", c.Name)
 
+	dc, hc, nc, err := d.containerConfig(c)
+	if err != nil {
+		return "", err
+	}
+
+	cont, err := d.c.ContainerCreate(context.Background(), dc, hc, nc, nil, c.Name)
+	if err != nil {
+		return "", err
+	}
+
+	// first remove the container from the default network if we are adding custom networks
+	if len(c.Networks) > 0 && !hc.NetworkMode.IsContainer() {
+		d.l.Debug("Remove container from default networks", "ref", c.Name)
+
+		info, err := d.c.ContainerInspect(context.Background(), cont.ID)
+		if err != nil {
+			return "", fmt.Errorf("unable to remove container from the default network: %w", err)
+		}
+
+		// get all default attached networks, we will disconnect these later
+		defaultNets := []string{}
+		for k := range info.NetworkSettings.Networks {
+			defaultNets = append(defaultNets, k)
+		}
+
+		// attach the custom networks
+		for _, n := range c.Networks {
+			net, err := d.FindNetwork(n.ID)
+			if err != nil {
+				return "", err
+			}
+
+			err = d.AttachNetwork(net.Name, cont.ID, n.Aliases, n.IPAddress)
+
+			if err != nil {
+				// if we fail to connect to the network roll back the container
+				errRemove := d.RemoveContainer(cont.ID, false)
+				if errRemove != nil {
+					return "", fmt.Errorf("failed to attach network %s to container %s, unable to roll back container: %w", n.ID, cont.ID, err)
+				}
+
+				return "", fmt.Errorf("unable to connect container to network %s, successfully rolled back container: %w", n.ID, err)
+			}
+		}
+
+		// disconnect the default networks
+		// for podman this needs to happen after we have attached to a network or it fails silently
+		for _, n := range defaultNets {
+			d.l.Debug("Disconnecting network", "name", n, "ref", c.Name)
+
+			err := d.c.NetworkDisconnect(context.Background(), n, cont.ID, true)
+			if err != nil {
+				d.l.Warn("Unable to remove container from the network", "name", n, "ref", c.Name, "error", err)
+			}
+		}
+	}
+
+	err = d.restoreVolumes(cont.ID, c)
+	if err != nil {
+		return "", err
+	}
+
+	err = d.c.ContainerStart(context.Background(), cont.ID, container.StartOptions{})
+	if err != nil {
+		return "", err
+	}
+
+	return cont.ID, nil
+}
+
+// containerConfig creates the container, host and network configs for the
+// given container, the container is not attached to any networks
+func (d *DockerTasks) containerConfig(c *dtypes.Container) (*container.Config, *container.HostConfig, *network.NetworkingConfig, error) {
 	// ensure the image name is the full canonical image as Podman does not use the
 	// default docker.io registry
 	c.Image.Name = makeImageCanonical(c.Image.Name)
@@ -201,7 +282,46 @@ func (d *DockerTasks) CreateContainer(c *dtypes.Container) (string, error) {
 		}
 	}
 
-	// Add GPU details
+	for _, u := range c.Ulimits {
+		hc.Ulimits = append(hc.Ulimits, &container.Ulimit{Name: u.Name, Soft: u.Soft, Hard: u.Hard})
+	}
+
+	for _, dv := range c.Devices {
+		dest := dv.Destination
+		if dest == "" {
+			dest = dv.Source
+		}
+
+		perms := dv.Permissions
+		if perms == "" {
+			perms = "rwm"
+		}
+
+		hc.Devices = append(hc.Devices, container.DeviceMapping{PathOnHost: dv.Source, PathInContainer: dest, CgroupPermissions: perms})
+	}
+
+	if c.Init {
+		hc.Init = &c.Init
+	}
+
+	hc.ReadonlyRootfs = c.ReadOnly
+
+	if c.ShmSize > 0 {
+		hc.ShmSize = int64(c.ShmSize) * 1000000
+	}
+
+	if len(c.Tmpfs) > 0 {
+		hc.Tmpfs = map[string]string{}
+		for _, t := range c.Tmpfs {
+			hc.Tmpfs[t.Destination] = strings.Join(t.Options, ",")
+		}
+	}
+
+	// sort the hosts so that the config does not change between runs
+	for h, ip := range c.ExtraHosts {
+		hc.ExtraHosts = append(hc.ExtraHosts, fmt.Sprintf("%s:%s", h, ip))
+	}
+	sort.Strings(hc.ExtraHosts)
 
 	// by default the container should NOT be attached to a network
 	nc.EndpointsConfig = make(map[string]*network.EndpointSettings)
@@ -248,7 +368,7 @@ func (d *DockerTasks) CreateContainer(c *dtypes.Container) (string, error) {
 				// source does not exist, create the source as a directory
 				err := os.MkdirAll(vc.Source, os.ModePerm)
 				if err != nil {
-					return "", fmt.Errorf("source for Volume %s does not exist, error creating directory: %w", vc.Source, err)
+					return nil, nil, nil, fmt.Errorf("source for Volume %s does not exist, error creating directory: %w", vc.Source, err)
 				}
 			}
 		}
@@ -259,7 +379,7 @@ func (d *DockerTasks) CreateContainer(c *dtypes.Container) (string, error) {
 		}
 
 		if vc.SelinuxRelabel != "" && vc.BindPropagationNonRecursive {
-			return "", errors.New("cannot apply selinux relabeling and non-recursive bind mounts with docker")
+			return nil, nil, nil, errors.New("cannot apply selinux relabeling and non-recursive bind mounts with docker")
 		}
 
 		// Cannot use mounts if selinux relabeling is requested
@@ -300,7 +420,7 @@ func (d *DockerTasks) CreateContainer(c *dtypes.Container) (string, error) {
 	// create the port ranges
 	portRanges, err := createPublishedPortRanges(c.PortRanges)
 	if err != nil {
-		return "", fmt.Errorf("unable to attach to container network, invalid port range: %w", err)
+		return nil, nil, nil, fmt.Errorf("unable to attach to container network, invalid port range: %w", err)
 	}
 
 	for k, p := range portRanges.ExposedPorts {
@@ -331,7 +451,7 @@ func (d *DockerTasks) CreateContainer(c *dtypes.Container) (string, error) {
 			// Do we need to disable ipV6 networking
 			net, err := d.FindNetwork(n.ID)
 			if err != nil {
-				return "", fmt.Errorf("unable to create container network does not exist: %s", err)
+				return nil, nil, nil, fmt.Errorf("unable to create container network does not exist: %s", err)
 			}
 
 			if net.IPv6Enabled {
@@ -345,64 +465,16 @@ func (d *DockerTasks) CreateContainer(c *dtypes.Container) (string, error) {
 		hc.Sysctls = map[string]string{"net.ipv6.conf.all.disable_ipv6": "1"}
 	}
 
-	cont, err := d.c.ContainerCreate(context.Background(), dc, hc, nc, nil, c.Name)
-	if err != nil {
-		return "", err
-	}
-
-	// first remove the container from the default network if we are adding custom networks
-	if len(c.Networks) > 0 && !hc.NetworkMode.IsContainer() {
-		d.l.Debug("Remove container from default networks", "ref", c.Name)
-
-		info, err := d.c.ContainerInspect(context.Background(), cont.ID)
-		if err != nil {
-			return "", fmt.Errorf("unable to remove container from the default network: %w", err)
-		}
-
-		// get all default attached networks, we will disconnect these later
-		defaultNets := []string{}
-		for k := range info.NetworkSettings.Networks {
-			defaultNets = append(defaultNets, k)
-		}
-
-		// attach the custom networks
-		for _, n := range c.Networks {
-			net, err := d.FindNetwork(n.ID)
-			if err != nil {
-				return "", err
-			}
-
-			err = d.AttachNetwork(net.Name, cont.ID, n.Aliases, n.IPAddress)
-
-			if err != nil {
-				// if we fail to connect to the network roll back the container
-				errRemove := d.RemoveContainer(cont.ID, false)
-				if errRemove != nil {
-					return "", fmt.Errorf("failed to attach network %s to container %s, unable to roll back container: %w", n.ID, cont.ID, err)
-				}
-
-				return "", fmt.Errorf("unable to connect container to network %s, successfully rolled back container: %w", n.ID, err)
-			}
+	// sysctls set in the config override the defaults
+	for k, v := range c.Sysctls {
+		if hc.Sysctls == nil {
+			hc.Sysctls = map[string]string{}
 		}
 
-		// disconnect the default networks
-		// for podman this needs to happen after we have attached to a network or it fails silently
-		for _, n := range defaultNets {
-			d.l.Debug("Disconnecting network", "name", n, "ref", c.Name)
-
-			err := d.c.NetworkDisconnect(context.Background(), n, cont.ID, true)
-			if err != nil {
-				d.l.Warn("Unable to remove container from the network", "name", n, "ref", c.Name, "error", err)
-			}
-		}
+		hc.Sysctls[k] = v
 	}
 
-	err = d.c.ContainerStart(context.Background(), cont.ID, container.StartOptions{})
-	if err != nil {
-		return "", err
-	}
-
-	return cont.ID, nil
+	return dc, hc, nc, nil
 }
 
 // ContainerInfo returns the Docker container info
@@ -619,6 +691,171 @@ func (d *DockerTasks) RemoveContainer(id string, force bool) error {
 	return d.c.ContainerRemove(context.Background(), id, container.RemoveOptions{Force: true, RemoveVolumes: true})
 }
 
+// StopContainer stops the container and leaves it in place so that it can be
+// started again
+func (d *DockerTasks) StopContainer(id string, force bool) error {
+	timeout := 30
+	if force || d.force {
+		timeout = 0
+	}
+
+	d.l.Debug("Stopping container", "container", id, "timeout", timeout)
+
+	err := d.c.ContainerStop(context.Background(), id, container.StopOptions{Timeout: &timeout})
+	if err != nil {
+		return fmt.Errorf("unable to stop container %s: %w", id, err)
+	}
+
+	return nil
+}
+
+// StartContainer starts a stopped container, addresses that were not static
+// are released when a container stops so the networks are reconnected with
+// the addresses in the attachments before the container is started
+func (d *DockerTasks) StartContainer(id string, networks []dtypes.NetworkAttachment) error {
+	for _, n := range networks {
+		if n.IsContainer {
+			continue
+		}
+
+		net, err := d.FindNetwork(n.ID)
+		if err != nil {
+			return err
+		}
+
+		err = d.c.NetworkDisconnect(context.Background(), net.Name, id, true)
+		if err != nil {
+			d.l.Debug("Container is not attached to network", "container", id, "network", net.Name, "error", err)
+		}
+
+		err = d.AttachNetwork(net.Name, id, n.Aliases, n.IPAddress)
+		if err != nil {
+			return fmt.Errorf("unable to attach container %s to network %s: %w", id, n.ID, err)
+		}
+	}
+
+	d.l.Debug("Starting container", "container", id)
+
+	err := d.c.ContainerStart(context.Background(), id, container.StartOptions{})
+	if err != nil {
+		return fmt.Errorf("unable to start container %s: %w", id, err)
+	}
+
+	return nil
+}
+
+// CommitContainer saves the filesystem of the container to an image, the
+// container is paused while it is committed
+func (d *DockerTasks) CommitContainer(id, image string) (string, error) {
+	d.l.Debug("Committing container", "id", id, "image", image)
+
+	resp, err := d.c.ContainerCommit(context.Background(), id, container.CommitOptions{Reference: image, Pause: true})
+	if err != nil {
+		return "", fmt.Errorf("unable to commit container %s to image %s: %w", id, image, err)
+	}
+
+	return resp.ID, nil
+}
+
+// ExportVolumes archives the named and anonymous volumes mounted in the
+// container, the image cache volume shared by clusters is not exported. A
+// running container is paused so that the files do not change while they
+// are copied.
+func (d *DockerTasks) ExportVolumes(id string, dst io.Writer) ([]string, error) {
+	info, err := d.c.ContainerInspect(context.Background(), id)
+	if err != nil {
+		return nil, fmt.Errorf("unable to inspect container %s: %w", id, err)
+	}
+
+	paths := []string{}
+	for _, m := range info.Mounts {
+		if m.Type == mount.TypeVolume && m.Name != d.env.FQDNVolumeName(utils.ImageVolumeName) {
+			paths = append(paths, m.Destination)
+		}
+	}
+
+	if len(paths) == 0 {
+		return nil, nil
+	}
+
+	if info.State != nil && info.State.Running {
+		err := d.c.ContainerPause(context.Background(), id)
+		if err != nil {
+			d.l.Debug("Unable to pause container, volumes are exported while it is running", "id", id, "error", err)
+		} else {
+			defer d.c.ContainerUnpause(context.Background(), id)
+		}
+	}
+
+	streams := []ctar.Stream{}
+	for _, p := range paths {
+		d.l.Debug("Exporting volume", "id", id, "path", p)
+
+		r, _, err := d.c.CopyFromContainer(context.Background(), id, p)
+		if err != nil {
+			return nil, fmt.Errorf("unable to copy volume %s from container %s: %w", p, id, err)
+		}
+		defer r.Close()
+
+		streams = append(streams, ctar.Stream{Path: strings.TrimPrefix(path.Dir(p), "/"), Reader: r})
+	}
+
+	err = d.tg.Compress(dst, streams...)
+	if err != nil {
+		return nil, fmt.Errorf("unable to archive volumes for container %s: %w", id, err)
+	}
+
+	return paths, nil
+}
+
+// restoreVolumes copies the VolumeArchive of the container config into the
+// created container, volumes are restored before the container starts so that
+// the process in the container sees the restored files. The container is
+// removed when the archive can not be copied.
+func (d *DockerTasks) restoreVolumes(id string, c *dtypes.Container) error {
+	if c.VolumeArchive == "" {
+		return nil
+	}
+
+	d.l.Debug("Restoring volumes", "ref", c.Name, "archive", c.VolumeArchive)
+
+	err := d.copyVolumeArchive(id, c.VolumeArchive)
+	if err != nil {
+		errRemove := d.RemoveContainer(id, true)
+		if errRemove != nil {
+			return fmt.Errorf("%w, unable to roll back container: %s", err, errRemove)
+		}
+
+		return err
+	}
+
+	return nil
+}
+
+// copyVolumeArchive copies an archive created by ExportVolumes into the
+// container, the entries are extracted to the root of the container which
+// writes them to the volumes that are mounted at the same paths
+func (d *DockerTasks) copyVolumeArchive(id, archive string) error {
+	f, err := os.Open(archive)
+	if err != nil {
+		return fmt.Errorf("unable to open volume archive: %w", err)
+	}
+	defer f.Close()
+
+	r, err := d.tg.Decompress(f)
+	if err != nil {
+		return fmt.Errorf("unable to read volume archive %s: %w", archive, err)
+	}
+	defer r.Close()
+
+	err = d.c.CopyToContainer(context.Background(), id, "/", r, container.CopyToContainerOptions{})
+	if err != nil {
+		return fmt.Errorf("unable to copy volume archive %s to container: %w", archive, err)
+	}
+
+	return nil
+}
+
 func (d *DockerTasks) RemoveImage(id string) error {
 	_, err := d.c.ImageRemove(context.Background(), id, image.RemoveOptions{Force: true})
 
@@ -703,7 +940,7 @@ func (d *DockerTasks) BuildContainer(config *dtypes.Build, force bool) (string,
 // if the volume exists performs no action
 // returns the volume name and an error if unsuccessful
 func (d *DockerTasks) CreateVolume(name string) (string, error) {
-	vn := utils.FQDNVolumeName(name)
+	vn := d.env.FQDNVolumeName(name)
 
 	// By default Docker will wildcard searches, use regex to return the absolute
 	args := volume.ListOptions{Filters: filters.NewArgs()}
@@ -737,7 +974,7 @@ func (d *DockerTasks) CreateVolume(name string) (string, error) {
 
 // RemoveVolume deletes the Docker volume associated with  a cluster
 func (d *DockerTasks) RemoveVolume(name string) error {
-	vn := utils.FQDNVolumeName(name)
+	vn := d.env.FQDNVolumeName(name)
 	d.l.Debug("Deleting Volume", "ref", name, "name", vn)
 
 	return d.c.VolumeRemove(context.Background(), vn, true)
@@ -821,8 +1058,22 @@ func (d *DockerTasks) CopyLocalDockerImagesToVolume(images []string, volume stri
 	importMutex.Lock()
 	defer importMutex.Unlock()
 
-	savedImages := []string{}
+	savedImages, err := d.saveLocalImages(images)
+	if err != nil {
+		return nil, err
+	}
+
+	// clean up
+	defer removeFiles(savedImages)
+
+	// copy the images to a volume
+	return d.CopyFilesToVolume(volume, savedImages, "/images", force)
+}
 
+// saveLocalImages saves the images in the local cache to temporary files that
+// are named with the base64 encoded image name, it is the responsibility of the
+// caller to remove the files
+func (d *DockerTasks) saveLocalImages(images []string) ([]string, error) {
 	// first check that the images are in the local cache
 	for n, i := range images {
 		// first check the short tag like envoy-proxy/envoy:latest
@@ -859,22 +1110,21 @@ func (d *DockerTasks) CopyLocalDockerImagesToVolume(images []string, volume stri
 		return nil, fmt.Errorf("unable to find image '%s' in the local Docker cache, please pull the image before attempting to copy to a volume", i)
 	}
 
+	savedImages := []string{}
 	for _, i := range images {
 		compressedImageName := base64.StdEncoding.EncodeToString([]byte(i))
 
 		d.l.Debug("Copying image to container", "image", i)
 		imageFile, err := d.saveImageToTempFile(i, compressedImageName)
 		if err != nil {
+			removeFiles(savedImages)
 			return nil, err
 		}
 
-		// clean up
-		defer os.Remove(imageFile)
 		savedImages = append(savedImages, imageFile)
 	}
 
-	// copy the images to a volume
-	return d.CopyFilesToVolume(volume, savedImages, "/images", force)
+	return savedImages, nil
 }
 
 // CopyFileToVolume copies a file to a Docker volume
@@ -1351,7 +1601,8 @@ func (d *DockerTasks) DetachNetwork(network, containerID string) error {
 	return err
 }
 
-// FindNetwork returns a network using the unique resource id
+// FindNetwork returns a network in the current workspace using the unique
+// resource id
 func (d *DockerTasks) FindNetwork(id string) (dtypes.NetworkAttachment, error) {
 	nets, err := d.c.NetworkList(context.Background(), network.ListOptions{})
 	if err != nil {
@@ -1359,7 +1610,13 @@ func (d *DockerTasks) FindNetwork(id string) (dtypes.NetworkAttachment, error) {
 	}
 
 	for _, n := range nets {
-		if n.Labels["id"] == id {
+		// networks created before workspaces existed have no workspace label
+		ws := n.Labels["workspace"]
+		if ws == "" {
+			ws = utils.DefaultWorkspace
+		}
+
+		if n.Labels["id"] == id && ws == d.env.WorkspaceName() {
 			return dtypes.NetworkAttachment{
 				ID:          n.ID,
 				Name:        n.Name,
@@ -1513,6 +1770,12 @@ func (d *DockerTasks) saveImageToTempFile(image, filename string) (string, error
 	return tmpFileName, nil
 }
 
+func removeFiles(files []string) {
+	for _, f := range files {
+		os.Remove(f)
+	}
+}
+
 func copyDir(src string, dest string) error {
 
 	if dest == src {
@@ -1529,7 +1792,7 @@ func copyDir(src string, dest string) error {
 		return err
 	}
 	if !file.IsDir() {
-		return fmt.Errorf("Source " + file.Name() + " is not a directory!")
+		return fmt.Errorf("Source %s is not a directory!", file.Name())
 	}
 
 	err = os.Mkdir(dest, 0755)
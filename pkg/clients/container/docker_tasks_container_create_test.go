@@ -606,3 +606,75 @@ func TestContainerLabels(t *testing.T) {
 	assert.Contains(t, dc.Labels, "com.example.foo")
 	assert.Equal(t, "bar", dc.Labels["com.example.foo"])
 }
+
+func TestContainerConfiguresRuntimeOptions(t *testing.T) {
+	cc, md, mic := createContainerConfig()
+	cc.Init = true
+	cc.ReadOnly = true
+	cc.ShmSize = 256
+	cc.Ulimits = []dtypes.Ulimit{{Name: "nofile", Soft: 65536, Hard: 65536}}
+	cc.Tmpfs = []dtypes.Tmpfs{{Destination: "/run", Options: []string{"rw", "size=64m"}}}
+	cc.ExtraHosts = map[string]string{"db.local": "10.0.0.2", "api.local": "10.0.0.3"}
+
+	err := setupContainer(t, cc, md, mic)
+	assert.NoError(t, err)
+
+	params := testutils.GetCalls(&md.Mock, "ContainerCreate")[0].Arguments
+	hc := params[2].(*container.HostConfig)
+
+	assert.True(t, *hc.Init)
+	assert.True(t, hc.ReadonlyRootfs)
+	assert.Equal(t, int64(256000000), hc.ShmSize)
+	assert.Equal(t, []*container.Ulimit{{Name: "nofile", Soft: 65536, Hard: 65536}}, hc.Ulimits)
+	assert.Equal(t, map[string]string{"/run": "rw,size=64m"}, hc.Tmpfs)
+	assert.Equal(t, []string{"api.local:10.0.0.3", "db.local:10.0.0.2"}, hc.ExtraHosts)
+}
+
+func TestContainerDoesNotSetInitWhenFalse(t *testing.T) {
+	cc, md, mic := createContainerConfig()
+
+	err := setupContainer(t, cc, md, mic)
+	assert.NoError(t, err)
+
+	params := testutils.GetCalls(&md.Mock, "ContainerCreate")[0].Arguments
+	hc := params[2].(*container.HostConfig)
+
+	assert.Nil(t, hc.Init)
+	assert.False(t, hc.ReadonlyRootfs)
+}
+
+func TestContainerAddsDevicesWithDefaults(t *testing.T) {
+	cc, md, mic := createContainerConfig()
+	cc.Devices = []dtypes.Device{
+		{Source: "/dev/fuse"},
+		{Source: "/dev/snd", Destination: "/dev/sound", Permissions: "r"},
+	}
+
+	err := setupContainer(t, cc, md, mic)
+	assert.NoError(t, err)
+
+	params := testutils.GetCalls(&md.Mock, "ContainerCreate")[0].Arguments
+	hc := params[2].(*container.HostConfig)
+
+	assert.Equal(t, []container.DeviceMapping{
+		{PathOnHost: "/dev/fuse", PathInContainer: "/dev/fuse", CgroupPermissions: "rwm"},
+		{PathOnHost: "/dev/snd", PathInContainer: "/dev/sound", CgroupPermissions: "r"},
+	}, hc.Devices)
+}
+
+func TestContainerSysctlsOverrideDefaults(t *testing.T) {
+	cc, md, mic := createContainerConfig()
+	cc.Sysctls = map[string]string{
+		"net.ipv4.ip_forward":            "1",
+		"net.ipv6.conf.all.disable_ipv6": "0",
+	}
+
+	err := setupContainer(t, cc, md, mic)
+	assert.NoError(t, err)
+
+	params := testutils.GetCalls(&md.Mock, "ContainerCreate")[0].Arguments
+	hc := params[2].(*container.HostConfig)
+
+	assert.Equal(t, "1", hc.Sysctls["net.ipv4.ip_forward"])
+	assert.Equal(t, "0", hc.Sysctls["net.ipv6.conf.all.disable_ipv6"])
+}
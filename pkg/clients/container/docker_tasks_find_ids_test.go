@@ -6,10 +6,12 @@ import (
 
 	"github.com/docker/docker/api/types"
 	"github.com/docker/docker/api/types/container"
+	"github.com/docker/docker/api/types/network"
 	"github.com/docker/docker/api/types/system"
 	"github.com/jumppad-labs/jumppad/pkg/clients/container/mocks"
 	"github.com/jumppad-labs/jumppad/pkg/clients/logger"
 	"github.com/jumppad-labs/jumppad/pkg/clients/tar"
+	"github.com/jumppad-labs/jumppad/pkg/utils"
 	"github.com/jumppad-labs/jumppad/testutils"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
@@ -82,3 +84,35 @@ func TestFindContainerIDsReturnsNilWhenEmpty(t *testing.T) {
 	assert.NoError(t, err)
 	assert.Nil(t, ids)
 }
+
+func TestFindNetworkReturnsNetworkInCurrentWorkspace(t *testing.T) {
+	t.Setenv(utils.WorkspaceEnvVar, "dev")
+
+	md := &mocks.Docker{}
+	md.On("ServerVersion", mock.Anything).Return(types.Version{}, nil)
+	md.On("Info", mock.Anything).Return(system.Info{Driver: StorageDriverOverlay2}, nil)
+	md.On("NetworkList", mock.Anything, mock.Anything).Return(
+		[]network.Summary{
+			{
+				ID:     "default",
+				Name:   "cloud",
+				Labels: map[string]string{"id": "resource.network.cloud"},
+				IPAM:   network.IPAM{Config: []network.IPAMConfig{{Subnet: "10.5.0.0/16"}}},
+			},
+			{
+				ID:     "dev",
+				Name:   "cloud.dev",
+				Labels: map[string]string{"id": "resource.network.cloud", "workspace": "dev"},
+				IPAM:   network.IPAM{Config: []network.IPAMConfig{{Subnet: "10.6.0.0/16"}}},
+			},
+		},
+		nil,
+	)
+
+	dt, _ := NewDockerTasks(md, nil, &tar.TarGz{}, logger.NewTestLogger(t))
+
+	n, err := dt.FindNetwork("resource.network.cloud")
+	assert.NoError(t, err)
+	assert.Equal(t, "cloud.dev", n.Name)
+	assert.Equal(t, "10.6.0.0/16", n.Subnet)
+}
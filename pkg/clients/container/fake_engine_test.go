@@ -0,0 +1,915 @@
+package container
+
+import (
+	"archive/tar"
+	"bufio"
+	"bytes"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/netip"
+	"path"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/distribution/reference"
+	"github.com/docker/docker/api/types"
+	"github.com/docker/docker/api/types/container"
+	"github.com/docker/docker/api/types/filters"
+	"github.com/docker/docker/api/types/image"
+	"github.com/docker/docker/api/types/network"
+	"github.com/docker/docker/api/types/system"
+	"github.com/docker/docker/api/types/volume"
+	"github.com/docker/docker/client"
+	"github.com/docker/docker/pkg/stdcopy"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeEngine is an in memory server for the parts of the Docker Engine API
+// that are used by ContainerTasks. When podman is set the server behaves like
+// the Docker compatible API of the Podman service:
+//
+//   - the default network is called podman rather than bridge
+//   - inspecting a network does not return the attached containers
+//   - images are stored with their canonical name and the reference filter
+//     does not match short names such as alpine:latest
+type fakeEngine struct {
+	podman bool
+
+	mu         sync.Mutex
+	ids        int
+	containers map[string]*fakeContainer
+	networks   map[string]*fakeNetwork
+	volumes    map[string]fakeFS
+	images     map[string]string
+	execs      map[string]*fakeExec
+
+	// attached records the networks that each container has been attached
+	// to, including the networks that it was detached from
+	attached map[string][]string
+}
+
+// fakeFS is a file system keyed by absolute path, directories have a nil value
+type fakeFS map[string][]byte
+
+type fakeContainer struct {
+	id       string
+	name     string
+	config   *container.Config
+	host     *container.HostConfig
+	running  bool
+	networks map[string]*network.EndpointSettings
+	fs       fakeFS
+}
+
+type fakeNetwork struct {
+	id     string
+	name   string
+	labels map[string]string
+	subnet netip.Prefix
+	next   netip.Addr
+}
+
+type fakeExec struct {
+	container *fakeContainer
+	cmd       []string
+	running   bool
+	exitCode  int
+}
+
+const fakeOnpremNetwork = "resource.network.onprem"
+
+// newFakeEngine starts a fake engine and returns a client connected to it,
+// the engine has a default network and a network with the id label
+// resource.network.onprem
+func newFakeEngine(t *testing.T, podman bool) (*fakeEngine, Docker) {
+	f := &fakeEngine{
+		podman:     podman,
+		containers: map[string]*fakeContainer{},
+		networks:   map[string]*fakeNetwork{},
+		volumes:    map[string]fakeFS{},
+		images:     map[string]string{},
+		execs:      map[string]*fakeExec{},
+		attached:   map[string][]string{},
+	}
+
+	f.addNetwork(f.defaultNetwork(), nil, "172.17.0.0/16")
+	f.addNetwork("onprem", map[string]string{"id": fakeOnpremNetwork}, "10.6.0.0/16")
+
+	srv := httptest.NewServer(f.handler())
+	t.Cleanup(srv.Close)
+
+	c, err := client.NewClientWithOpts(client.WithHost("tcp://"+srv.Listener.Addr().String()), client.WithVersion(fakeAPIVersion))
+	require.NoError(t, err)
+
+	return f, c
+}
+
+const fakeAPIVersion = "1.41"
+
+func (f *fakeEngine) defaultNetwork() string {
+	if f.podman {
+		return "podman"
+	}
+
+	return "bridge"
+}
+
+func (f *fakeEngine) newID() string {
+	f.ids++
+	return fmt.Sprintf("%064d", f.ids)
+}
+
+func (f *fakeEngine) addNetwork(name string, labels map[string]string, subnet string) {
+	p := netip.MustParsePrefix(subnet)
+	id := f.newID()
+
+	// the first address is the gateway
+	f.networks[id] = &fakeNetwork{id: id, name: name, labels: labels, subnet: p, next: p.Addr().Next().Next()}
+}
+
+// addImage adds an image to the local cache
+func (f *fakeEngine) addImage(name string) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	f.images[f.imageName(name)] = "sha256:" + f.newID()
+}
+
+// imageName returns the name that an image is stored as, Docker uses the
+// short name and Podman the canonical name
+func (f *fakeEngine) imageName(name string) string {
+	ref, err := reference.ParseNormalizedNamed(name)
+	if err != nil {
+		return name
+	}
+
+	ref = reference.TagNameOnly(ref)
+	if f.podman {
+		return ref.String()
+	}
+
+	return reference.FamiliarString(ref)
+}
+
+// findImage returns the id of the image that matches the reference
+func (f *fakeEngine) findImage(name string) (string, bool) {
+	if f.podman {
+		id, ok := f.images[name]
+		return id, ok
+	}
+
+	id, ok := f.images[f.imageName(name)]
+	return id, ok
+}
+
+// Attached returns the names of the networks that the container has been
+// attached to
+func (f *fakeEngine) Attached(name string) []string {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	return f.attached[name]
+}
+
+// Container returns the container with the given id or name
+func (f *fakeEngine) Container(id string) *fakeContainer {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	return f.container(id)
+}
+
+// VolumeFile returns the contents of the file at path in the volume
+func (f *fakeEngine) VolumeFile(name, p string) ([]byte, bool) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	d, ok := f.volumes[name][p]
+	return d, ok && d != nil
+}
+
+func (f *fakeEngine) container(id string) *fakeContainer {
+	id = strings.TrimPrefix(id, "/")
+	if c, ok := f.containers[id]; ok {
+		return c
+	}
+
+	for _, c := range f.containers {
+		if c.name == id {
+			return c
+		}
+	}
+
+	return nil
+}
+
+func (f *fakeEngine) network(id string) *fakeNetwork {
+	if n, ok := f.networks[id]; ok {
+		return n
+	}
+
+	for _, n := range f.networks {
+		if n.name == id {
+			return n
+		}
+	}
+
+	return nil
+}
+
+func (f *fakeEngine) attach(c *fakeContainer, n *fakeNetwork, es *network.EndpointSettings) error {
+	if _, ok := c.networks[n.name]; ok {
+		return fmt.Errorf("container %s is already attached to network %s", c.name, n.name)
+	}
+
+	ip := n.next
+	if es != nil && es.IPAMConfig != nil && es.IPAMConfig.IPv4Address != "" {
+		a, err := netip.ParseAddr(es.IPAMConfig.IPv4Address)
+		if err != nil || !n.subnet.Contains(a) {
+			return fmt.Errorf("invalid address %s for network %s", es.IPAMConfig.IPv4Address, n.name)
+		}
+
+		ip = a
+	} else {
+		n.next = n.next.Next()
+	}
+
+	att := &network.EndpointSettings{NetworkID: n.id, IPAddress: ip.String(), IPPrefixLen: n.subnet.Bits()}
+	if es != nil {
+		att.Aliases = es.Aliases
+	}
+
+	c.networks[n.name] = att
+	f.attached[c.name] = append(f.attached[c.name], n.name)
+
+	return nil
+}
+
+// resolve returns the file system and path for the given path in the
+// container, paths in mounted volumes are resolved to the volume
+func (f *fakeEngine) resolve(c *fakeContainer, p string) (fakeFS, string) {
+	p = path.Clean("/" + p)
+
+	for _, m := range c.host.Mounts {
+		if m.Type != "volume" {
+			continue
+		}
+
+		if p == m.Target || strings.HasPrefix(p, m.Target+"/") {
+			return f.volumes[m.Source], path.Clean("/" + strings.TrimPrefix(p, m.Target))
+		}
+	}
+
+	return c.fs, p
+}
+
+func (f *fakeEngine) mkdirAll(c *fakeContainer, p string) {
+	fs, p := f.resolve(c, p)
+
+	dir := "/"
+	for _, part := range strings.Split(p, "/") {
+		dir = path.Join(dir, part)
+		if _, ok := fs[dir]; !ok {
+			fs[dir] = nil
+		}
+	}
+}
+
+// run executes the command in the container and returns the exit code, the
+// commands that the fake understands are mkdir -p, find, echo, exit and sh
+// with a script file
+func (f *fakeEngine) run(c *fakeContainer, cmd []string, stdout, stderr io.Writer) int {
+	if len(cmd) == 0 {
+		return 127
+	}
+
+	switch cmd[0] {
+	case "true":
+		return 0
+	case "echo":
+		fmt.Fprintln(stdout, strings.Join(cmd[1:], " "))
+		return 0
+	case "exit":
+		code, _ := strconv.Atoi(cmd[len(cmd)-1])
+		return code
+	case "mkdir":
+		f.mkdirAll(c, cmd[len(cmd)-1])
+		return 0
+	case "find":
+		fs, p := f.resolve(c, cmd[1])
+		if _, ok := fs[p]; !ok {
+			fmt.Fprintf(stderr, "find: %s: No such file or directory\n", cmd[1])
+			return 1
+		}
+
+		fmt.Fprintln(stdout, cmd[1])
+		return 0
+	case "sh":
+		fs, p := f.resolve(c, cmd[1])
+		script, ok := fs[p]
+		if !ok {
+			fmt.Fprintf(stderr, "sh: can't open '%s'\n", cmd[1])
+			return 2
+		}
+
+		code := 0
+		for _, l := range strings.Split(string(script), "\n") {
+			line := strings.Fields(l)
+			if len(line) == 0 {
+				continue
+			}
+
+			code = f.run(c, line, stdout, stderr)
+			if line[0] == "exit" {
+				return code
+			}
+		}
+
+		return code
+	}
+
+	fmt.Fprintf(stderr, "%s: command not found\n", cmd[0])
+	return 127
+}
+
+func (f *fakeEngine) handler() http.Handler {
+	mux := http.NewServeMux()
+	v := "/v" + fakeAPIVersion
+
+	handle := func(pattern string, h func(w http.ResponseWriter, r *http.Request)) {
+		method, p, _ := strings.Cut(pattern, " ")
+		mux.HandleFunc(method+" "+v+p, func(w http.ResponseWriter, r *http.Request) {
+			// exec start hijacks the connection and must not hold the lock
+			// while the client reads the output
+			if !strings.HasSuffix(p, "/exec/{id}/start") {
+				f.mu.Lock()
+				defer f.mu.Unlock()
+			}
+
+			h(w, r)
+		})
+	}
+
+	handle("GET /version", f.version)
+	handle("GET /info", f.info)
+	handle("GET /images/json", f.imageList)
+	handle("POST /images/create", f.imagePull)
+	handle("GET /images/get", f.imageSave)
+	handle("DELETE /images/{name...}", f.imageRemove)
+	handle("POST /containers/create", f.containerCreate)
+	handle("GET /containers/json", f.containerList)
+	handle("GET /containers/{id}/json", f.containerInspect)
+	handle("POST /containers/{id}/start", f.containerStart)
+	handle("POST /containers/{id}/stop", f.containerStop)
+	handle("POST /containers/{id}/pause", f.containerPause)
+	handle("POST /containers/{id}/unpause", f.containerPause)
+	handle("POST /commit", f.containerCommit)
+	handle("DELETE /containers/{id}", f.containerRemove)
+	handle("PUT /containers/{id}/archive", f.copyTo)
+	handle("GET /containers/{id}/archive", f.copyFrom)
+	handle("POST /containers/{id}/exec", f.execCreate)
+	handle("POST /exec/{id}/start", f.execStart)
+	handle("GET /exec/{id}/json", f.execInspect)
+	handle("GET /networks", f.networkList)
+	handle("GET /networks/{id}", f.networkInspect)
+	handle("POST /networks/{id}/connect", f.networkConnect)
+	handle("POST /networks/{id}/disconnect", f.networkDisconnect)
+	handle("GET /volumes", f.volumeList)
+	handle("POST /volumes/create", f.volumeCreate)
+	handle("DELETE /volumes/{name}", f.volumeRemove)
+
+	return mux
+}
+
+func writeJSON(w http.ResponseWriter, status int, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(v)
+}
+
+func writeError(w http.ResponseWriter, status int, format string, a ...interface{}) {
+	writeJSON(w, status, map[string]string{"message": fmt.Sprintf(format, a...)})
+}
+
+func queryFilters(r *http.Request) filters.Args {
+	args, _ := filters.FromJSON(r.URL.Query().Get("filters"))
+	return args
+}
+
+func (f *fakeEngine) version(w http.ResponseWriter, r *http.Request) {
+	name := "Engine"
+	if f.podman {
+		name = "Podman Engine"
+	}
+
+	writeJSON(w, http.StatusOK, types.Version{Components: []types.ComponentVersion{{Name: name, Version: "5.0.0"}}})
+}
+
+func (f *fakeEngine) info(w http.ResponseWriter, r *http.Request) {
+	writeJSON(w, http.StatusOK, system.Info{Driver: StorageDriverOverlay2, NCPU: 4, MemTotal: 8000000000})
+}
+
+func (f *fakeEngine) imageList(w http.ResponseWriter, r *http.Request) {
+	list := []map[string]interface{}{}
+
+	for _, ref := range queryFilters(r).Get("reference") {
+		if id, ok := f.findImage(ref); ok {
+			list = append(list, map[string]interface{}{"Id": id, "RepoTags": []string{ref}})
+		}
+	}
+
+	writeJSON(w, http.StatusOK, list)
+}
+
+func (f *fakeEngine) imagePull(w http.ResponseWriter, r *http.Request) {
+	name := r.URL.Query().Get("fromImage") + ":" + r.URL.Query().Get("tag")
+	f.images[f.imageName(name)] = "sha256:" + f.newID()
+
+	writeJSON(w, http.StatusOK, map[string]string{"status": "Downloaded newer image for " + name})
+}
+
+func (f *fakeEngine) imageSave(w http.ResponseWriter, r *http.Request) {
+	w.WriteHeader(http.StatusOK)
+	fmt.Fprintf(w, "image %s", strings.Join(r.URL.Query()["names"], ","))
+}
+
+// imageRemove removes an image using either the id or the name
+func (f *fakeEngine) imageRemove(w http.ResponseWriter, r *http.Request) {
+	name := r.PathValue("name")
+	for n, id := range f.images {
+		if id == name || n == f.imageName(name) {
+			delete(f.images, n)
+			writeJSON(w, http.StatusOK, []image.DeleteResponse{{Deleted: id}})
+			return
+		}
+	}
+
+	writeError(w, http.StatusNotFound, "No such image: %s", name)
+}
+
+func (f *fakeEngine) containerCreate(w http.ResponseWriter, r *http.Request) {
+	req := container.CreateRequest{}
+	err := json.NewDecoder(r.Body).Decode(&req)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "invalid body: %s", err)
+		return
+	}
+
+	name := r.URL.Query().Get("name")
+	if f.container(name) != nil {
+		writeError(w, http.StatusConflict, "the container name %s is already in use", name)
+		return
+	}
+
+	if _, ok := f.findImage(req.Image); !ok {
+		writeError(w, http.StatusNotFound, "No such image: %s", req.Image)
+		return
+	}
+
+	if req.HostConfig == nil {
+		req.HostConfig = &container.HostConfig{}
+	}
+
+	c := &fakeContainer{
+		id:       f.newID(),
+		name:     name,
+		config:   req.Config,
+		host:     req.HostConfig,
+		networks: map[string]*network.EndpointSettings{},
+		fs:       fakeFS{"/": nil, "/tmp": nil},
+	}
+
+	for _, m := range req.HostConfig.Mounts {
+		if _, ok := f.volumes[m.Source]; m.Type == "volume" && !ok {
+			f.volumes[m.Source] = fakeFS{"/": nil}
+		}
+
+		f.mkdirAll(c, m.Target)
+	}
+
+	endpoints := map[string]*network.EndpointSettings{}
+	if req.NetworkingConfig != nil {
+		endpoints = req.NetworkingConfig.EndpointsConfig
+	}
+
+	mode := req.HostConfig.NetworkMode
+	switch {
+	case mode.IsContainer() || mode.IsNone():
+	case len(endpoints) > 1 && !f.podman:
+		writeError(w, http.StatusBadRequest, "Container cannot be connected to network endpoints: %d", len(endpoints))
+		return
+	case len(endpoints) > 0:
+		for name, es := range endpoints {
+			n := f.network(name)
+			if n == nil {
+				writeError(w, http.StatusNotFound, "network %s not found", name)
+				return
+			}
+
+			err := f.attach(c, n, es)
+			if err != nil {
+				writeError(w, http.StatusBadRequest, "%s", err)
+				return
+			}
+		}
+	default:
+		f.attach(c, f.network(f.defaultNetwork()), nil)
+	}
+
+	f.containers[c.id] = c
+
+	writeJSON(w, http.StatusCreated, container.CreateResponse{ID: c.id})
+}
+
+func (f *fakeEngine) containerList(w http.ResponseWriter, r *http.Request) {
+	list := []container.Summary{}
+	names := queryFilters(r).Get("name")
+
+	for _, c := range f.containers {
+		match := len(names) == 0
+		for _, n := range names {
+			if ok, _ := regexp.MatchString(n, c.name); ok {
+				match = true
+			}
+		}
+
+		if match {
+			list = append(list, container.Summary{ID: c.id, Names: []string{"/" + c.name}})
+		}
+	}
+
+	writeJSON(w, http.StatusOK, list)
+}
+
+func (f *fakeEngine) containerInspect(w http.ResponseWriter, r *http.Request) {
+	c := f.container(r.PathValue("id"))
+	if c == nil {
+		writeError(w, http.StatusNotFound, "No such container: %s", r.PathValue("id"))
+		return
+	}
+
+	status := "created"
+	if c.running {
+		status = "running"
+	}
+
+	mounts := []container.MountPoint{}
+	for _, m := range c.host.Mounts {
+		mounts = append(mounts, container.MountPoint{Type: m.Type, Name: m.Source, Source: m.Source, Destination: m.Target})
+	}
+
+	writeJSON(w, http.StatusOK, container.InspectResponse{
+		ContainerJSONBase: &container.ContainerJSONBase{
+			ID:         c.id,
+			Name:       "/" + c.name,
+			Image:      c.config.Image,
+			State:      &container.State{Running: c.running, Status: status},
+			HostConfig: c.host,
+		},
+		Config:          c.config,
+		Mounts:          mounts,
+		NetworkSettings: &container.NetworkSettings{Networks: c.networks},
+	})
+}
+
+func (f *fakeEngine) containerStart(w http.ResponseWriter, r *http.Request) {
+	c := f.container(r.PathValue("id"))
+	if c == nil {
+		writeError(w, http.StatusNotFound, "No such container: %s", r.PathValue("id"))
+		return
+	}
+
+	c.running = true
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (f *fakeEngine) containerStop(w http.ResponseWriter, r *http.Request) {
+	c := f.container(r.PathValue("id"))
+	if c == nil {
+		writeError(w, http.StatusNotFound, "No such container: %s", r.PathValue("id"))
+		return
+	}
+
+	c.running = false
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// containerPause handles pause and unpause, the fake does not run processes
+// so there is nothing to pause
+func (f *fakeEngine) containerPause(w http.ResponseWriter, r *http.Request) {
+	if f.container(r.PathValue("id")) == nil {
+		writeError(w, http.StatusNotFound, "No such container: %s", r.PathValue("id"))
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (f *fakeEngine) containerCommit(w http.ResponseWriter, r *http.Request) {
+	c := f.container(r.URL.Query().Get("container"))
+	if c == nil {
+		writeError(w, http.StatusNotFound, "No such container: %s", r.URL.Query().Get("container"))
+		return
+	}
+
+	id := "sha256:" + f.newID()
+	f.images[f.imageName(r.URL.Query().Get("repo")+":"+r.URL.Query().Get("tag"))] = id
+
+	writeJSON(w, http.StatusCreated, container.CommitResponse{ID: id})
+}
+
+func (f *fakeEngine) containerRemove(w http.ResponseWriter, r *http.Request) {
+	c := f.container(r.PathValue("id"))
+	if c == nil {
+		writeError(w, http.StatusNotFound, "No such container: %s", r.PathValue("id"))
+		return
+	}
+
+	if c.running && r.URL.Query().Get("force") != "1" {
+		writeError(w, http.StatusConflict, "cannot remove container %s, container is running", c.name)
+		return
+	}
+
+	delete(f.containers, c.id)
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (f *fakeEngine) copyTo(w http.ResponseWriter, r *http.Request) {
+	c := f.container(r.PathValue("id"))
+	if c == nil {
+		writeError(w, http.StatusNotFound, "No such container: %s", r.PathValue("id"))
+		return
+	}
+
+	dst := r.URL.Query().Get("path")
+	fs, p := f.resolve(c, dst)
+	if d, ok := fs[p]; !ok || d != nil {
+		writeError(w, http.StatusNotFound, "Could not find the file %s in container %s", dst, c.name)
+		return
+	}
+
+	tr := tar.NewReader(r.Body)
+	for {
+		hdr, err := tr.Next()
+		if err == io.EOF {
+			break
+		}
+
+		if err != nil {
+			writeError(w, http.StatusBadRequest, "invalid archive: %s", err)
+			return
+		}
+
+		target := path.Join(dst, hdr.Name)
+		if hdr.Typeflag == tar.TypeDir {
+			f.mkdirAll(c, target)
+			continue
+		}
+
+		f.mkdirAll(c, path.Dir(target))
+
+		data, _ := io.ReadAll(tr)
+		fs, p := f.resolve(c, target)
+		fs[p] = append([]byte{}, data...)
+	}
+
+	w.WriteHeader(http.StatusOK)
+}
+
+func (f *fakeEngine) copyFrom(w http.ResponseWriter, r *http.Request) {
+	c := f.container(r.PathValue("id"))
+	if c == nil {
+		writeError(w, http.StatusNotFound, "No such container: %s", r.PathValue("id"))
+		return
+	}
+
+	src := r.URL.Query().Get("path")
+	fs, p := f.resolve(c, src)
+	data, ok := fs[p]
+	if !ok {
+		writeError(w, http.StatusNotFound, "Could not find the file %s in container %s", src, c.name)
+		return
+	}
+
+	// directories are copied with their contents
+	entries := []string{p}
+	if data == nil {
+		for k := range fs {
+			if k != p && strings.HasPrefix(k, strings.TrimSuffix(p, "/")+"/") {
+				entries = append(entries, k)
+			}
+		}
+
+		sort.Strings(entries)
+	}
+
+	// entries are named relative to the parent of src like the Docker engine
+	base := path.Base(path.Clean("/" + src))
+
+	buf := &bytes.Buffer{}
+	ta := tar.NewWriter(buf)
+	for _, e := range entries {
+		name := path.Join(base, strings.TrimPrefix(e, p))
+		if d := fs[e]; d == nil {
+			ta.WriteHeader(&tar.Header{Typeflag: tar.TypeDir, Name: name + "/", Mode: 0755})
+		} else {
+			ta.WriteHeader(&tar.Header{Typeflag: tar.TypeReg, Name: name, Mode: 0644, Size: int64(len(d))})
+			ta.Write(d)
+		}
+	}
+	ta.Close()
+
+	stat, _ := json.Marshal(container.PathStat{Name: base, Size: int64(len(data))})
+	w.Header().Set("X-Docker-Container-Path-Stat", base64.StdEncoding.EncodeToString(stat))
+	w.WriteHeader(http.StatusOK)
+	w.Write(buf.Bytes())
+}
+
+func (f *fakeEngine) execCreate(w http.ResponseWriter, r *http.Request) {
+	c := f.container(r.PathValue("id"))
+	if c == nil {
+		writeError(w, http.StatusNotFound, "No such container: %s", r.PathValue("id"))
+		return
+	}
+
+	if !c.running {
+		writeError(w, http.StatusConflict, "container %s is not running", c.name)
+		return
+	}
+
+	opts := container.ExecOptions{}
+	json.NewDecoder(r.Body).Decode(&opts)
+
+	id := f.newID()
+	f.execs[id] = &fakeExec{container: c, cmd: opts.Cmd, running: true}
+
+	writeJSON(w, http.StatusCreated, container.ExecCreateResponse{ID: id})
+}
+
+func (f *fakeEngine) execStart(w http.ResponseWriter, r *http.Request) {
+	f.mu.Lock()
+	e, ok := f.execs[r.PathValue("id")]
+	f.mu.Unlock()
+
+	if !ok {
+		writeError(w, http.StatusNotFound, "No such exec instance: %s", r.PathValue("id"))
+		return
+	}
+
+	conn, rw, err := w.(http.Hijacker).Hijack()
+	if err != nil {
+		return
+	}
+	defer conn.Close()
+
+	rw.WriteString("HTTP/1.1 101 UPGRADED\r\nContent-Type: application/vnd.docker.raw-stream\r\nConnection: Upgrade\r\nUpgrade: tcp\r\n\r\n")
+
+	out := bufio.NewWriter(rw)
+
+	f.mu.Lock()
+	code := f.run(e.container, e.cmd, stdcopy.NewStdWriter(out, stdcopy.Stdout), stdcopy.NewStdWriter(out, stdcopy.Stderr))
+	e.running = false
+	e.exitCode = code
+	f.mu.Unlock()
+
+	out.Flush()
+	rw.Flush()
+}
+
+func (f *fakeEngine) execInspect(w http.ResponseWriter, r *http.Request) {
+	e, ok := f.execs[r.PathValue("id")]
+	if !ok {
+		writeError(w, http.StatusNotFound, "No such exec instance: %s", r.PathValue("id"))
+		return
+	}
+
+	writeJSON(w, http.StatusOK, container.ExecInspect{ExecID: r.PathValue("id"), Running: e.running, ExitCode: e.exitCode})
+}
+
+func (f *fakeEngine) networkSummary(n *fakeNetwork) network.Summary {
+	return network.Summary{
+		ID:     n.id,
+		Name:   n.name,
+		Labels: n.labels,
+		IPAM:   network.IPAM{Config: []network.IPAMConfig{{Subnet: n.subnet.String()}}},
+	}
+}
+
+func (f *fakeEngine) networkList(w http.ResponseWriter, r *http.Request) {
+	list := []network.Summary{}
+	for _, n := range f.networks {
+		list = append(list, f.networkSummary(n))
+	}
+
+	writeJSON(w, http.StatusOK, list)
+}
+
+func (f *fakeEngine) networkInspect(w http.ResponseWriter, r *http.Request) {
+	n := f.network(r.PathValue("id"))
+	if n == nil {
+		writeError(w, http.StatusNotFound, "network %s not found", r.PathValue("id"))
+		return
+	}
+
+	s := f.networkSummary(n)
+	s.Containers = map[string]network.EndpointResource{}
+
+	if !f.podman {
+		for _, c := range f.containers {
+			if es, ok := c.networks[n.name]; ok {
+				s.Containers[c.id] = network.EndpointResource{Name: c.name, IPv4Address: fmt.Sprintf("%s/%d", es.IPAddress, es.IPPrefixLen)}
+			}
+		}
+	}
+
+	writeJSON(w, http.StatusOK, s)
+}
+
+func (f *fakeEngine) networkConnect(w http.ResponseWriter, r *http.Request) {
+	opts := network.ConnectOptions{}
+	json.NewDecoder(r.Body).Decode(&opts)
+
+	n := f.network(r.PathValue("id"))
+	c := f.container(opts.Container)
+	if n == nil || c == nil {
+		writeError(w, http.StatusNotFound, "network %s or container %s not found", r.PathValue("id"), opts.Container)
+		return
+	}
+
+	err := f.attach(c, n, opts.EndpointConfig)
+	if err != nil {
+		writeError(w, http.StatusForbidden, "%s", err)
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
+}
+
+func (f *fakeEngine) networkDisconnect(w http.ResponseWriter, r *http.Request) {
+	opts := network.DisconnectOptions{}
+	json.NewDecoder(r.Body).Decode(&opts)
+
+	n := f.network(r.PathValue("id"))
+	c := f.container(opts.Container)
+	if n == nil || c == nil {
+		writeError(w, http.StatusNotFound, "network %s or container %s not found", r.PathValue("id"), opts.Container)
+		return
+	}
+
+	if _, ok := c.networks[n.name]; !ok {
+		writeError(w, http.StatusForbidden, "container %s is not connected to network %s", c.name, n.name)
+		return
+	}
+
+	delete(c.networks, n.name)
+	w.WriteHeader(http.StatusOK)
+}
+
+func (f *fakeEngine) volumeList(w http.ResponseWriter, r *http.Request) {
+	list := volume.ListResponse{Volumes: []*volume.Volume{}}
+	names := queryFilters(r).Get("name")
+
+	for name := range f.volumes {
+		match := len(names) == 0
+		for _, n := range names {
+			if strings.Contains(name, n) {
+				match = true
+			}
+		}
+
+		if match {
+			list.Volumes = append(list.Volumes, &volume.Volume{Name: name, Driver: "local"})
+		}
+	}
+
+	writeJSON(w, http.StatusOK, list)
+}
+
+func (f *fakeEngine) volumeCreate(w http.ResponseWriter, r *http.Request) {
+	opts := volume.CreateOptions{}
+	json.NewDecoder(r.Body).Decode(&opts)
+
+	if _, ok := f.volumes[opts.Name]; !ok {
+		f.volumes[opts.Name] = fakeFS{"/": nil}
+	}
+
+	writeJSON(w, http.StatusCreated, volume.Volume{Name: opts.Name, Driver: "local"})
+}
+
+func (f *fakeEngine) volumeRemove(w http.ResponseWriter, r *http.Request) {
+	if _, ok := f.volumes[r.PathValue("name")]; !ok {
+		writeError(w, http.StatusNotFound, "no such volume: %s", r.PathValue("name"))
+		return
+	}
+
+	delete(f.volumes, r.PathValue("name"))
+	w.WriteHeader(http.StatusNoContent)
+}
@@ -6,6 +6,7 @@ import (
 	io "io"
 
 	types "github.com/jumppad-labs/jumppad/pkg/clients/container/types"
+	"github.com/jumppad-labs/jumppad/pkg/utils"
 	mock "github.com/stretchr/testify/mock"
 )
 
@@ -60,6 +61,34 @@ func (_m *ContainerTasks) BuildContainer(config *types.Build, force bool) (strin
 	return r0, r1
 }
 
+// CommitContainer provides a mock function with given fields: id, image
+func (_m *ContainerTasks) CommitContainer(id string, image string) (string, error) {
+	ret := _m.Called(id, image)
+
+	if len(ret) == 0 {
+		panic("no return value specified for CommitContainer")
+	}
+
+	var r0 string
+	var r1 error
+	if rf, ok := ret.Get(0).(func(string, string) (string, error)); ok {
+		return rf(id, image)
+	}
+	if rf, ok := ret.Get(0).(func(string, string) string); ok {
+		r0 = rf(id, image)
+	} else {
+		r0 = ret.Get(0).(string)
+	}
+
+	if rf, ok := ret.Get(1).(func(string, string) error); ok {
+		r1 = rf(id, image)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // ContainerInfo provides a mock function with given fields: id
 func (_m *ContainerTasks) ContainerInfo(id string) (interface{}, error) {
 	ret := _m.Called(id)
@@ -402,6 +431,36 @@ func (_m *ContainerTasks) ExecuteScript(id string, contents string, env []string
 	return r0, r1
 }
 
+// ExportVolumes provides a mock function with given fields: id, dst
+func (_m *ContainerTasks) ExportVolumes(id string, dst io.Writer) ([]string, error) {
+	ret := _m.Called(id, dst)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ExportVolumes")
+	}
+
+	var r0 []string
+	var r1 error
+	if rf, ok := ret.Get(0).(func(string, io.Writer) ([]string, error)); ok {
+		return rf(id, dst)
+	}
+	if rf, ok := ret.Get(0).(func(string, io.Writer) []string); ok {
+		r0 = rf(id, dst)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]string)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(string, io.Writer) error); ok {
+		r1 = rf(id, dst)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // FindContainerIDs provides a mock function with given fields: containerName
 func (_m *ContainerTasks) FindContainerIDs(containerName string) ([]string, error) {
 	ret := _m.Called(containerName)
@@ -628,11 +687,52 @@ func (_m *ContainerTasks) RemoveVolume(name string) error {
 	return r0
 }
 
+// SetEnvironment provides a mock function with given fields: _a0
+func (_m *ContainerTasks) SetEnvironment(_a0 utils.Environment) {
+	_m.Called(_a0)
+}
+
 // SetForce provides a mock function with given fields: _a0
 func (_m *ContainerTasks) SetForce(_a0 bool) {
 	_m.Called(_a0)
 }
 
+// StartContainer provides a mock function with given fields: id, networks
+func (_m *ContainerTasks) StartContainer(id string, networks []types.NetworkAttachment) error {
+	ret := _m.Called(id, networks)
+
+	if len(ret) == 0 {
+		panic("no return value specified for StartContainer")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(string, []types.NetworkAttachment) error); ok {
+		r0 = rf(id, networks)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// StopContainer provides a mock function with given fields: id, force
+func (_m *ContainerTasks) StopContainer(id string, force bool) error {
+	ret := _m.Called(id, force)
+
+	if len(ret) == 0 {
+		panic("no return value specified for StopContainer")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(string, bool) error); ok {
+		r0 = rf(id, force)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
 // TagImage provides a mock function with given fields: source, destination
 func (_m *ContainerTasks) TagImage(source string, destination string) error {
 	ret := _m.Called(source, destination)
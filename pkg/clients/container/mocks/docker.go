@@ -82,6 +82,34 @@ func (_m *Docker) CheckpointList(ctx context.Context, _a1 string, options checkp
 	return r0, r1
 }
 
+// ContainerCommit provides a mock function with given fields: ctx, containerID, options
+func (_m *Docker) ContainerCommit(ctx context.Context, containerID string, options typescontainer.CommitOptions) (common.IDResponse, error) {
+	ret := _m.Called(ctx, containerID, options)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ContainerCommit")
+	}
+
+	var r0 common.IDResponse
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, typescontainer.CommitOptions) (common.IDResponse, error)); ok {
+		return rf(ctx, containerID, options)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, typescontainer.CommitOptions) common.IDResponse); ok {
+		r0 = rf(ctx, containerID, options)
+	} else {
+		r0 = ret.Get(0).(common.IDResponse)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, typescontainer.CommitOptions) error); ok {
+		r1 = rf(ctx, containerID, options)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // ContainerCreate provides a mock function with given fields: ctx, config, hostConfig, networkingConfig, platform, containerName
 func (_m *Docker) ContainerCreate(ctx context.Context, config *typescontainer.Config, hostConfig *typescontainer.HostConfig, networkingConfig *network.NetworkingConfig, platform *v1.Platform, containerName string) (typescontainer.CreateResponse, error) {
 	ret := _m.Called(ctx, config, hostConfig, networkingConfig, platform, containerName)
@@ -318,6 +346,24 @@ func (_m *Docker) ContainerLogs(ctx context.Context, _a1 string, options typesco
 	return r0, r1
 }
 
+// ContainerPause provides a mock function with given fields: ctx, containerID
+func (_m *Docker) ContainerPause(ctx context.Context, containerID string) error {
+	ret := _m.Called(ctx, containerID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ContainerPause")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) error); ok {
+		r0 = rf(ctx, containerID)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
 // ContainerRemove provides a mock function with given fields: ctx, containerID, options
 func (_m *Docker) ContainerRemove(ctx context.Context, containerID string, options typescontainer.RemoveOptions) error {
 	ret := _m.Called(ctx, containerID, options)
@@ -336,6 +382,24 @@ func (_m *Docker) ContainerRemove(ctx context.Context, containerID string, optio
 	return r0
 }
 
+// ContainerRename provides a mock function with given fields: ctx, containerID, newContainerName
+func (_m *Docker) ContainerRename(ctx context.Context, containerID string, newContainerName string) error {
+	ret := _m.Called(ctx, containerID, newContainerName)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ContainerRename")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, string) error); ok {
+		r0 = rf(ctx, containerID, newContainerName)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
 // ContainerStart provides a mock function with given fields: _a0, _a1, _a2
 func (_m *Docker) ContainerStart(_a0 context.Context, _a1 string, _a2 typescontainer.StartOptions) error {
 	ret := _m.Called(_a0, _a1, _a2)
@@ -372,6 +436,24 @@ func (_m *Docker) ContainerStop(ctx context.Context, containerID string, options
 	return r0
 }
 
+// ContainerUnpause provides a mock function with given fields: ctx, containerID
+func (_m *Docker) ContainerUnpause(ctx context.Context, containerID string) error {
+	ret := _m.Called(ctx, containerID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ContainerUnpause")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) error); ok {
+		r0 = rf(ctx, containerID)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
 // CopyFromContainer provides a mock function with given fields: ctx, containerID, srcPath
 func (_m *Docker) CopyFromContainer(ctx context.Context, containerID string, srcPath string) (io.ReadCloser, typescontainer.PathStat, error) {
 	ret := _m.Called(ctx, containerID, srcPath)
@@ -0,0 +1,298 @@
+package container
+
+import (
+	"archive/tar"
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/docker/docker/api/types/container"
+	"github.com/docker/docker/api/types/mount"
+	"github.com/docker/docker/api/types/network"
+	dtypes "github.com/jumppad-labs/jumppad/pkg/clients/container/types"
+	"github.com/jumppad-labs/jumppad/pkg/clients/images"
+	"github.com/jumppad-labs/jumppad/pkg/clients/logger"
+	ctar "github.com/jumppad-labs/jumppad/pkg/clients/tar"
+)
+
+// PodmanTasks is a concrete implementation of ContainerTasks for the Podman
+// engine, it uses the Docker compatible API of the Podman service.
+//
+// Tasks that behave the same on Podman as on Docker, such as building and
+// pulling images, are provided by the embedded DockerTasks. Podman differs
+// when attaching containers to networks, listing the networks of a container,
+// executing commands and copying files to volumes.
+type PodmanTasks struct {
+	*DockerTasks
+}
+
+// NewPodmanTasks creates a PodmanTasks with the given client
+func NewPodmanTasks(c Docker, il images.ImageLog, tg *ctar.TarGz, l logger.Logger) (*PodmanTasks, error) {
+	dt, err := NewDockerTasks(c, il, tg, l)
+	if err != nil {
+		return nil, err
+	}
+
+	if dt.engineType != dtypes.EngineTypePodman {
+		l.Warn("Using the Podman runtime with an engine that is not Podman", "engine", dt.engineType)
+	}
+
+	return &PodmanTasks{dt}, nil
+}
+
+// CreateContainer creates a new Podman container for the given configuration
+func (p *PodmanTasks) CreateContainer(c *dtypes.Container) (string, error) {
+	p.l.Debug("Creating Podman Container", "ref", c.Name)
+
+	dc, hc, nc, err := p.containerConfig(c)
+	if err != nil {
+		return "", err
+	}
+
+	// containers created without a network are attached to the default podman
+	// network, attach the custom networks when the container is created so that
+	// the default network is never used
+	if !hc.NetworkMode.IsContainer() {
+		for _, n := range c.Networks {
+			net, err := p.FindNetwork(n.ID)
+			if err != nil {
+				return "", err
+			}
+
+			es := &network.EndpointSettings{NetworkID: net.ID}
+			if len(n.Aliases) > 0 {
+				es.Aliases = n.Aliases
+			}
+
+			if n.IPAddress != "" {
+				p.l.Debug("Assigning static ip address", "ref", c.Name, "network", net.Name, "ip_address", n.IPAddress)
+				es.IPAMConfig = &network.EndpointIPAMConfig{IPv4Address: n.IPAddress}
+			}
+
+			if hc.NetworkMode == "" {
+				hc.NetworkMode = container.NetworkMode(net.Name)
+			}
+
+			nc.EndpointsConfig[net.Name] = es
+		}
+	}
+
+	cont, err := p.c.ContainerCreate(context.Background(), dc, hc, nc, nil, c.Name)
+	if err != nil {
+		return "", err
+	}
+
+	err = p.restoreVolumes(cont.ID, c)
+	if err != nil {
+		return "", err
+	}
+
+	err = p.c.ContainerStart(context.Background(), cont.ID, container.StartOptions{})
+	if err != nil {
+		// remove the container so that it can be created again
+		errRemove := p.RemoveContainer(cont.ID, true)
+		if errRemove != nil {
+			return "", fmt.Errorf("unable to start container %s, unable to roll back container: %w", cont.ID, err)
+		}
+
+		return "", err
+	}
+
+	return cont.ID, nil
+}
+
+// ListNetworks lists the networks a container is attached to, the networks
+// are read from the container as Podman does not return the attached
+// containers when a network is inspected
+func (p *PodmanTasks) ListNetworks(id string) []dtypes.NetworkAttachment {
+	attachments := []dtypes.NetworkAttachment{}
+
+	info, err := p.c.ContainerInspect(context.Background(), id)
+	if err != nil || info.NetworkSettings == nil {
+		return attachments
+	}
+
+	names := []string{}
+	for name := range info.NetworkSettings.Networks {
+		names = append(names, name)
+	}
+
+	sort.Strings(names)
+
+	for _, name := range names {
+		n, err := p.c.NetworkInspect(context.Background(), name, network.InspectOptions{})
+		if err != nil {
+			continue
+		}
+
+		es := info.NetworkSettings.Networks[name]
+
+		att := dtypes.NetworkAttachment{}
+		att.ID = n.Labels["id"]
+		att.Name = n.Name
+
+		// use the same CIDR notation as the addresses of network containers
+		if es.IPAddress != "" {
+			att.IPAddress = fmt.Sprintf("%s/%d", es.IPAddress, es.IPPrefixLen)
+		}
+
+		attachments = append(attachments, att)
+	}
+
+	return attachments
+}
+
+// ExecuteCommand allows the execution of commands in a running Podman container,
+// see DockerTasks.ExecuteCommand.
+//
+// The exec session does not finish until the output of the attached stream
+// has been read, when writer is nil the output is read and discarded.
+func (p *PodmanTasks) ExecuteCommand(id string, command []string, env []string, workingDir string, user, group string, timeout int, writer io.Writer) (int, error) {
+	if writer == nil {
+		writer = io.Discard
+	}
+
+	return p.DockerTasks.ExecuteCommand(id, command, env, workingDir, user, group, timeout, writer)
+}
+
+// ExecuteScript allows the execution of a script in a running Podman container
+// id is the id of the container to execute the command in
+// contents is the contents of the script to execute
+// writer [optional] will be used to write any output from the command execution.
+func (p *PodmanTasks) ExecuteScript(id string, contents string, env []string, workingDir string, user, group string, timeout int, writer io.Writer) (int, error) {
+	// ensure we only have unix line ending in ths script
+	contents = strings.Replace(contents, "\r\n", "\n", -1)
+
+	err := p.CreateFileInContainer(id, contents, "script.sh", "/tmp")
+	if err != nil {
+		return defaultExitCode, fmt.Errorf("unable to create script in container: %w", err)
+	}
+
+	return p.ExecuteCommand(id, []string{"sh", "/tmp/script.sh"}, env, workingDir, user, group, timeout, writer)
+}
+
+// CopyLocalDockerImagesToVolume writes multiple images to a Podman volume as a compressed archive
+// returns the filename of the archive and an error if one occurred
+func (p *PodmanTasks) CopyLocalDockerImagesToVolume(images []string, volume string, force bool) ([]string, error) {
+	p.l.Debug("Writing images to volume", "images", images, "volume", volume)
+
+	importMutex.Lock()
+	defer importMutex.Unlock()
+
+	savedImages, err := p.saveLocalImages(images)
+	if err != nil {
+		return nil, err
+	}
+
+	defer removeFiles(savedImages)
+
+	return p.CopyFilesToVolume(volume, savedImages, "/images", force)
+}
+
+// CopyFilesToVolume copies the files to the path in a Podman volume
+// returns the names of the stored files
+//
+// The files are copied to a container that mounts the volume but is never
+// started, this does not need exec or wait for the container to be running.
+func (p *PodmanTasks) CopyFilesToVolume(volumeID string, filenames []string, path string, force bool) ([]string, error) {
+	img := makeImageCanonical("alpine:latest")
+
+	// make sure we have the alpine image needed to create the container
+	err := p.PullImage(dtypes.Image{Name: img}, false)
+	if err != nil {
+		return nil, fmt.Errorf("unable pull '%s' needed to copy files to volume: %w", img, err)
+	}
+
+	name := fmt.Sprintf("%d", time.Now().UnixNano())
+	name = fmt.Sprintf("%s-import", name[len(name)-8:])
+
+	cont, err := p.c.ContainerCreate(
+		context.Background(),
+		&container.Config{Image: img, Cmd: []string{"true"}},
+		&container.HostConfig{
+			NetworkMode: "none",
+			Mounts:      []mount.Mount{{Type: mount.TypeVolume, Source: volumeID, Target: "/cache"}},
+		},
+		nil,
+		nil,
+		name,
+	)
+
+	if err != nil {
+		return nil, fmt.Errorf("unable to create container for importing files: %w", err)
+	}
+	defer p.RemoveContainer(cont.ID, true)
+
+	// create the directory paths ensure unix paths for containers
+	destPath := filepath.ToSlash(filepath.Join("/cache", path))
+
+	dirs, err := directoryArchive(strings.TrimPrefix(destPath, "/cache"))
+	if err != nil {
+		return nil, err
+	}
+
+	err = p.c.CopyToContainer(context.Background(), cont.ID, "/cache", dirs, container.CopyToContainerOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("unable to create destination path '%s' in volume: %w", destPath, err)
+	}
+
+	// add each file individually
+	imported := []string{}
+	for _, f := range filenames {
+		destFile := fmt.Sprintf("%s/%s", destPath, filepath.Base(f))
+
+		// check if the file exists if we are not doing a forced update
+		if !p.force && !force {
+			r, _, err := p.c.CopyFromContainer(context.Background(), cont.ID, destFile)
+			if err == nil {
+				r.Close()
+
+				p.l.Debug("File already cached", "name", filepath.Base(f), "path", path)
+				imported = append(imported, destFile)
+				continue
+			}
+		}
+
+		err = p.CopyFileToContainer(cont.ID, f, destPath)
+		if err != nil {
+			return nil, fmt.Errorf("unable to copy file %s to container: %w", f, err)
+		}
+
+		imported = append(imported, destFile)
+	}
+
+	return imported, nil
+}
+
+// directoryArchive returns a tar archive that contains the directory at the
+// given path and its parents
+func directoryArchive(path string) (io.Reader, error) {
+	buf := &bytes.Buffer{}
+	ta := tar.NewWriter(buf)
+
+	dir := ""
+	for _, p := range strings.Split(path, "/") {
+		if p == "" {
+			continue
+		}
+
+		dir = dir + p + "/"
+
+		err := ta.WriteHeader(&tar.Header{Typeflag: tar.TypeDir, Name: dir, Mode: 0755})
+		if err != nil {
+			return nil, fmt.Errorf("unable to write tar header: %w", err)
+		}
+	}
+
+	err := ta.Close()
+	if err != nil {
+		return nil, fmt.Errorf("unable to create tar archive: %w", err)
+	}
+
+	return buf, nil
+}
@@ -0,0 +1,56 @@
+package container
+
+import (
+	"testing"
+	"time"
+
+	dtypes "github.com/jumppad-labs/jumppad/pkg/clients/container/types"
+	"github.com/jumppad-labs/jumppad/pkg/clients/logger"
+	"github.com/jumppad-labs/jumppad/pkg/clients/tar"
+	"github.com/stretchr/testify/require"
+)
+
+func testPodmanTasksSetup(t *testing.T) (*fakeEngine, *PodmanTasks) {
+	f, c := newFakeEngine(t, true)
+	f.addImage("nginx:latest")
+
+	pt, err := NewPodmanTasks(c, testImageLog(t), &tar.TarGz{}, logger.NewTestLogger(t))
+	require.NoError(t, err)
+
+	pt.defaultWait = 1 * time.Millisecond
+
+	return f, pt
+}
+
+func TestPodmanCreateContainerDoesNotAttachDefaultNetwork(t *testing.T) {
+	f, pt := testPodmanTasksSetup(t)
+
+	_, err := pt.CreateContainer(testBehaviourContainer("web.container.local.jumppad.dev"))
+	require.NoError(t, err)
+
+	require.Equal(t, []string{"onprem"}, f.Attached("web.container.local.jumppad.dev"))
+}
+
+func TestPodmanCreateContainerAttachesMultipleNetworks(t *testing.T) {
+	f, pt := testPodmanTasksSetup(t)
+	f.addNetwork("cloud", map[string]string{"id": "resource.network.cloud"}, "10.7.0.0/16")
+
+	c := testBehaviourContainer("web.container.local.jumppad.dev")
+	c.Networks = append(c.Networks, dtypes.NetworkAttachment{ID: "resource.network.cloud"})
+
+	id, err := pt.CreateContainer(c)
+	require.NoError(t, err)
+
+	require.Len(t, pt.ListNetworks(id), 2)
+}
+
+func TestPodmanListNetworksReadsContainerNetworks(t *testing.T) {
+	_, pt := testPodmanTasksSetup(t)
+
+	id, err := pt.CreateContainer(testBehaviourContainer("web.container.local.jumppad.dev"))
+	require.NoError(t, err)
+
+	// inspecting a Podman network does not return the attached containers
+	require.Empty(t, pt.DockerTasks.ListNetworks(id))
+	require.Len(t, pt.ListNetworks(id), 1)
+}
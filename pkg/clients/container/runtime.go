@@ -0,0 +1,123 @@
+package container
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	dtypes "github.com/jumppad-labs/jumppad/pkg/clients/container/types"
+	"github.com/jumppad-labs/jumppad/pkg/clients/images"
+	"github.com/jumppad-labs/jumppad/pkg/clients/logger"
+	ctar "github.com/jumppad-labs/jumppad/pkg/clients/tar"
+	"github.com/jumppad-labs/jumppad/pkg/utils"
+)
+
+const (
+	// RuntimeAuto selects the ContainerTasks from the engine that the client
+	// is connected to
+	RuntimeAuto   = "auto"
+	RuntimeDocker = "docker"
+	RuntimePodman = "podman"
+)
+
+// Runtimes are the valid container runtimes
+var Runtimes = []string{RuntimeAuto, RuntimeDocker, RuntimePodman}
+
+// RuntimeEnvVar is the environment variable that selects the container
+// runtime, the --runtime flag sets this so that it is also used by any child
+// processes
+const RuntimeEnvVar = "JUMPPAD_RUNTIME"
+
+// Runtime returns the container runtime set with the JUMPPAD_RUNTIME
+// environment variable, or auto when it is not set
+func Runtime() string {
+	if rt := os.Getenv(RuntimeEnvVar); rt != "" {
+		return strings.ToLower(rt)
+	}
+
+	return RuntimeAuto
+}
+
+// NewClient creates a client for the engine of the given runtime. Podman is
+// only used for the auto runtime when no Docker host or context is set and
+// the Docker socket does not exist but the Podman socket does.
+func NewClient(runtime string) (Docker, error) {
+	switch runtime {
+	case RuntimePodman:
+		return NewPodman()
+	case RuntimeAuto:
+		ep, err := utils.GetDockerEndpoint()
+		if err != nil || ep.Host != "" || fileExists(dockerSocket) {
+			return NewDocker()
+		}
+
+		if _, ok := podmanSocket(); ok {
+			return NewPodman()
+		}
+	}
+
+	return NewDocker()
+}
+
+// NewContainerTasks creates the ContainerTasks for the given runtime, for the
+// auto runtime PodmanTasks is returned when the client is connected to a
+// Podman engine and DockerTasks otherwise
+func NewContainerTasks(runtime string, c Docker, il images.ImageLog, tg *ctar.TarGz, l logger.Logger) (ContainerTasks, error) {
+	switch runtime {
+	case RuntimeDocker:
+		dt, err := NewDockerTasks(c, il, tg, l)
+		if err != nil {
+			return nil, err
+		}
+
+		return dt, nil
+	case RuntimePodman:
+		pt, err := NewPodmanTasks(c, il, tg, l)
+		if err != nil {
+			return nil, err
+		}
+
+		return pt, nil
+	case RuntimeAuto:
+		dt, err := NewDockerTasks(c, il, tg, l)
+		if err != nil {
+			return nil, err
+		}
+
+		if dt.EngineInfo().EngineType == dtypes.EngineTypePodman {
+			return &PodmanTasks{dt}, nil
+		}
+
+		return dt, nil
+	}
+
+	return nil, fmt.Errorf("unknown container runtime '%s', valid runtimes are %s", runtime, strings.Join(Runtimes, ", "))
+}
+
+const dockerSocket = "/var/run/docker.sock"
+
+// podmanSocket returns the address of the Podman socket of the current user,
+// or the system socket when the user socket does not exist. The returned
+// bool is false when neither socket exists.
+func podmanSocket() (string, bool) {
+	sockets := []string{}
+	if dir := os.Getenv("XDG_RUNTIME_DIR"); dir != "" {
+		sockets = append(sockets, filepath.Join(dir, "podman", "podman.sock"))
+	}
+
+	sockets = append(sockets, "/run/podman/podman.sock")
+
+	for _, s := range sockets {
+		if fileExists(s) {
+			return "unix://" + s, true
+		}
+	}
+
+	return "unix://" + sockets[0], false
+}
+
+func fileExists(path string) bool {
+	_, err := os.Stat(path)
+	return err == nil
+}
@@ -0,0 +1,65 @@
+package container
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/jumppad-labs/jumppad/pkg/clients/logger"
+	"github.com/jumppad-labs/jumppad/pkg/clients/tar"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRuntimeDefaultsToAuto(t *testing.T) {
+	t.Setenv(RuntimeEnvVar, "")
+	require.Equal(t, RuntimeAuto, Runtime())
+
+	t.Setenv(RuntimeEnvVar, "Podman")
+	require.Equal(t, RuntimePodman, Runtime())
+}
+
+func TestNewContainerTasksAutoSelectsEngine(t *testing.T) {
+	_, dc := newFakeEngine(t, false)
+	ct, err := NewContainerTasks(RuntimeAuto, dc, nil, &tar.TarGz{}, logger.NewTestLogger(t))
+	require.NoError(t, err)
+	require.IsType(t, &DockerTasks{}, ct)
+
+	_, pc := newFakeEngine(t, true)
+	ct, err = NewContainerTasks(RuntimeAuto, pc, nil, &tar.TarGz{}, logger.NewTestLogger(t))
+	require.NoError(t, err)
+	require.IsType(t, &PodmanTasks{}, ct)
+}
+
+func TestNewContainerTasksUsesSelectedRuntime(t *testing.T) {
+	_, dc := newFakeEngine(t, false)
+
+	ct, err := NewContainerTasks(RuntimePodman, dc, nil, &tar.TarGz{}, logger.NewTestLogger(t))
+	require.NoError(t, err)
+	require.IsType(t, &PodmanTasks{}, ct)
+
+	_, pc := newFakeEngine(t, true)
+
+	ct, err = NewContainerTasks(RuntimeDocker, pc, nil, &tar.TarGz{}, logger.NewTestLogger(t))
+	require.NoError(t, err)
+	require.IsType(t, &DockerTasks{}, ct)
+}
+
+func TestNewContainerTasksReturnsErrorForUnknownRuntime(t *testing.T) {
+	_, dc := newFakeEngine(t, false)
+
+	ct, err := NewContainerTasks("containerd", dc, nil, &tar.TarGz{}, logger.NewTestLogger(t))
+	require.Error(t, err)
+	require.Nil(t, ct)
+}
+
+func TestPodmanSocketUsesUserSocket(t *testing.T) {
+	dir := t.TempDir()
+	t.Setenv("XDG_RUNTIME_DIR", dir)
+
+	os.MkdirAll(filepath.Join(dir, "podman"), os.ModePerm)
+	os.WriteFile(filepath.Join(dir, "podman", "podman.sock"), nil, 0600)
+
+	s, ok := podmanSocket()
+	require.True(t, ok)
+	require.Equal(t, "unix://"+filepath.Join(dir, "podman", "podman.sock"), s)
+}
@@ -0,0 +1,150 @@
+package container
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/jumppad-labs/jumppad/pkg/clients/container/types"
+	"github.com/jumppad-labs/jumppad/pkg/utils"
+)
+
+// Snapshot records the images and volume archives that are created when a
+// snapshot of an environment is taken, containers are recreated from the
+// snapshot rather than from the images in the config.
+type Snapshot struct {
+	Name    string    `json:"name"`
+	Created time.Time `json:"created"`
+
+	// Containers is keyed on the name of the container
+	Containers map[string]SnapshotContainer `json:"containers"`
+
+	// Dir is the folder where the snapshot is stored
+	Dir string `json:"-"`
+
+	mu sync.Mutex
+}
+
+// SnapshotContainer is a container that has been saved to a snapshot
+type SnapshotContainer struct {
+	// Image is the name of the image the container was committed to
+	Image string `json:"image"`
+	// Volumes is set when the volumes of the container have been archived
+	Volumes bool `json:"volumes,omitempty"`
+}
+
+// NewSnapshot creates a new snapshot with the given name stored in the
+// folder dir
+func NewSnapshot(name, dir string) *Snapshot {
+	return &Snapshot{
+		Name:       name,
+		Created:    time.Now(),
+		Containers: map[string]SnapshotContainer{},
+		Dir:        dir,
+	}
+}
+
+// ImageName returns the name of the image for the given container
+func (s *Snapshot) ImageName(container string) string {
+	return fmt.Sprintf("%s/%s:%s", utils.SnapshotImagePrefix, container, s.Name)
+}
+
+// VolumeArchive returns the path of the volume archive for the given container
+func (s *Snapshot) VolumeArchive(container string) string {
+	return filepath.Join(s.Dir, "volumes", container+".tar.gz")
+}
+
+// Save commits the container with the given name to an image and archives
+// the contents of its volumes, Save is safe to call concurrently.
+func (s *Snapshot) Save(ct ContainerTasks, name string) error {
+	ids, err := ct.FindContainerIDs(name)
+	if err != nil {
+		return err
+	}
+
+	if len(ids) == 0 {
+		return fmt.Errorf("unable to find container %s", name)
+	}
+
+	image := s.ImageName(name)
+	_, err = ct.CommitContainer(ids[0], image)
+	if err != nil {
+		return err
+	}
+
+	archive := s.VolumeArchive(name)
+	err = os.MkdirAll(filepath.Dir(archive), os.ModePerm)
+	if err != nil {
+		return fmt.Errorf("unable to create volumes folder: %w", err)
+	}
+
+	f, err := os.Create(archive)
+	if err != nil {
+		return fmt.Errorf("unable to create volume archive: %w", err)
+	}
+
+	paths, err := ct.ExportVolumes(ids[0], f)
+	f.Close()
+
+	if err != nil || len(paths) == 0 {
+		os.Remove(archive)
+	}
+
+	if err != nil {
+		return err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.Containers[name] = SnapshotContainer{Image: image, Volumes: len(paths) > 0}
+
+	return nil
+}
+
+// Restore updates the container config to use the image and volume archive
+// saved for the container, returns an error if the container is not in the
+// snapshot.
+func (s *Snapshot) Restore(c *types.Container) error {
+	s.mu.Lock()
+	sc, ok := s.Containers[c.Name]
+	s.mu.Unlock()
+
+	if !ok {
+		return fmt.Errorf("container %s is not in the snapshot %s", c.Name, s.Name)
+	}
+
+	c.Image = &types.Image{Name: sc.Image}
+
+	if sc.Volumes {
+		c.VolumeArchive = s.VolumeArchive(c.Name)
+	}
+
+	return nil
+}
+
+// RemoveImages removes the images created for the snapshot
+func (s *Snapshot) RemoveImages(ct ContainerTasks) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for _, sc := range s.Containers {
+		id, err := ct.FindImageInLocalRegistry(types.Image{Name: sc.Image})
+		if err != nil {
+			return err
+		}
+
+		if id == "" {
+			continue
+		}
+
+		err = ct.RemoveImage(id)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
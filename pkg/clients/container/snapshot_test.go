@@ -0,0 +1,100 @@
+package container
+
+import (
+	"path/filepath"
+	"testing"
+
+	dtypes "github.com/jumppad-labs/jumppad/pkg/clients/container/types"
+	"github.com/jumppad-labs/jumppad/pkg/clients/logger"
+	"github.com/jumppad-labs/jumppad/pkg/clients/tar"
+	"github.com/stretchr/testify/require"
+)
+
+func setupSnapshotTests(t *testing.T) (*fakeEngine, *DockerTasks, *Snapshot) {
+	f, c := newFakeEngine(t, false)
+	f.addImage("nginx:latest")
+
+	dt, err := NewDockerTasks(c, testImageLog(t), &tar.TarGz{}, logger.NewTestLogger(t))
+	require.NoError(t, err)
+
+	return f, dt, NewSnapshot("test", t.TempDir())
+}
+
+func TestSnapshotSavesContainerWithVolumes(t *testing.T) {
+	_, dt, s := setupSnapshotTests(t)
+
+	c := testBehaviourContainer("web.container.local.jumppad.dev")
+	c.Volumes = []dtypes.Volume{{Source: "data", Destination: "/data", Type: "volume"}}
+
+	_, err := dt.CreateContainer(c)
+	require.NoError(t, err)
+
+	err = s.Save(dt, "web.container.local.jumppad.dev")
+	require.NoError(t, err)
+
+	sc := s.Containers["web.container.local.jumppad.dev"]
+	require.Equal(t, "jumppad.dev/snapshot/web.container.local.jumppad.dev:test", sc.Image)
+	require.True(t, sc.Volumes)
+	require.FileExists(t, s.VolumeArchive("web.container.local.jumppad.dev"))
+
+	id, err := dt.FindImageInLocalRegistry(dtypes.Image{Name: sc.Image})
+	require.NoError(t, err)
+	require.NotEmpty(t, id)
+}
+
+func TestSnapshotSavesContainerWithoutVolumes(t *testing.T) {
+	_, dt, s := setupSnapshotTests(t)
+
+	_, err := dt.CreateContainer(testBehaviourContainer("web.container.local.jumppad.dev"))
+	require.NoError(t, err)
+
+	err = s.Save(dt, "web.container.local.jumppad.dev")
+	require.NoError(t, err)
+
+	require.False(t, s.Containers["web.container.local.jumppad.dev"].Volumes)
+	require.NoFileExists(t, s.VolumeArchive("web.container.local.jumppad.dev"))
+}
+
+func TestSnapshotSaveReturnsErrorWhenContainerNotExists(t *testing.T) {
+	_, dt, s := setupSnapshotTests(t)
+
+	err := s.Save(dt, "web.container.local.jumppad.dev")
+	require.Error(t, err)
+	require.Empty(t, s.Containers)
+}
+
+func TestSnapshotRestoreSetsImageAndVolumeArchive(t *testing.T) {
+	s := NewSnapshot("test", t.TempDir())
+	s.Containers["web"] = SnapshotContainer{Image: s.ImageName("web"), Volumes: true}
+
+	c := &dtypes.Container{Name: "web", Image: &dtypes.Image{Name: "nginx:latest"}}
+	err := s.Restore(c)
+	require.NoError(t, err)
+
+	require.Equal(t, "jumppad.dev/snapshot/web:test", c.Image.Name)
+	require.Equal(t, filepath.Join(s.Dir, "volumes", "web.tar.gz"), c.VolumeArchive)
+}
+
+func TestSnapshotRestoreReturnsErrorWhenContainerNotInSnapshot(t *testing.T) {
+	s := NewSnapshot("test", t.TempDir())
+
+	err := s.Restore(&dtypes.Container{Name: "web"})
+	require.Error(t, err)
+}
+
+func TestSnapshotRemovesImages(t *testing.T) {
+	_, dt, s := setupSnapshotTests(t)
+
+	_, err := dt.CreateContainer(testBehaviourContainer("web.container.local.jumppad.dev"))
+	require.NoError(t, err)
+
+	err = s.Save(dt, "web.container.local.jumppad.dev")
+	require.NoError(t, err)
+
+	err = s.RemoveImages(dt)
+	require.NoError(t, err)
+
+	id, err := dt.FindImageInLocalRegistry(dtypes.Image{Name: s.ImageName("web.container.local.jumppad.dev")})
+	require.NoError(t, err)
+	require.Empty(t, id)
+}
@@ -0,0 +1,173 @@
+package container
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"net"
+	"net/url"
+	"os/exec"
+	"strings"
+	"sync"
+	"time"
+)
+
+// sshDialer returns a dialer that connects to the Docker daemon on a remote
+// host by running docker system dial-stdio with the ssh command, this is the
+// same as the Docker CLI so the user's ssh config and agent are used
+func sshDialer(host string) (func(ctx context.Context, network, addr string) (net.Conn, error), error) {
+	u, err := url.Parse(host)
+	if err != nil {
+		return nil, fmt.Errorf("invalid ssh host '%s': %s", host, err)
+	}
+
+	if u.Scheme != "ssh" || u.Hostname() == "" {
+		return nil, fmt.Errorf("invalid ssh host '%s', the host must be in the format ssh://[user@]host[:port]", host)
+	}
+
+	if u.Path != "" && u.Path != "/" {
+		return nil, fmt.Errorf("invalid ssh host '%s', a path can not be set", host)
+	}
+
+	args := []string{}
+	if u.User != nil {
+		args = append(args, "-l", u.User.Username())
+	}
+
+	if u.Port() != "" {
+		args = append(args, "-p", u.Port())
+	}
+
+	args = append(args, "--", u.Hostname(), "docker", "system", "dial-stdio")
+
+	return func(ctx context.Context, network, addr string) (net.Conn, error) {
+		// the connection outlives the context of the request that opened it
+		return newCommandConn(exec.Command("ssh", args...))
+	}, nil
+}
+
+// commandConn is a net.Conn that writes to the stdin and reads from the
+// stdout of a command
+type commandConn struct {
+	cmd    *exec.Cmd
+	stdin  io.WriteCloser
+	stdout io.ReadCloser
+	stderr *lockedBuffer
+
+	// read is set once data has been read from the command
+	read      bool
+	closeOnce sync.Once
+	waitOnce  sync.Once
+}
+
+func newCommandConn(cmd *exec.Cmd) (*commandConn, error) {
+	c := &commandConn{cmd: cmd, stderr: &lockedBuffer{}}
+	cmd.Stderr = c.stderr
+
+	var err error
+	c.stdin, err = cmd.StdinPipe()
+	if err != nil {
+		return nil, err
+	}
+
+	c.stdout, err = cmd.StdoutPipe()
+	if err != nil {
+		return nil, err
+	}
+
+	err = cmd.Start()
+	if err != nil {
+		return nil, fmt.Errorf("unable to run '%s': %s", strings.Join(cmd.Args, " "), err)
+	}
+
+	return c, nil
+}
+
+func (c *commandConn) Read(p []byte) (int, error) {
+	n, err := c.stdout.Read(p)
+	if n > 0 {
+		c.read = true
+	}
+
+	// the command exited before the connection was established, return
+	// the error written by ssh once it has been copied from the command
+	if err == io.EOF && !c.read {
+		c.wait()
+
+		if msg := c.stderr.String(); msg != "" {
+			return 0, fmt.Errorf("connection closed by '%s': %s", strings.Join(c.cmd.Args, " "), strings.TrimSpace(msg))
+		}
+	}
+
+	return n, err
+}
+
+func (c *commandConn) Write(p []byte) (int, error) {
+	return c.stdin.Write(p)
+}
+
+// CloseWrite closes stdin so that the remote end receives EOF, this is used
+// when the connection is hijacked
+func (c *commandConn) CloseWrite() error {
+	return c.stdin.Close()
+}
+
+func (c *commandConn) Close() error {
+	c.closeOnce.Do(func() {
+		c.stdin.Close()
+		c.stdout.Close()
+
+		if c.cmd.Process != nil {
+			c.cmd.Process.Kill()
+			c.wait()
+		}
+	})
+
+	return nil
+}
+
+func (c *commandConn) wait() {
+	c.waitOnce.Do(func() {
+		c.cmd.Wait()
+	})
+}
+
+func (c *commandConn) LocalAddr() net.Addr {
+	return dummyAddr{}
+}
+
+func (c *commandConn) RemoteAddr() net.Addr {
+	return dummyAddr{}
+}
+
+// deadlines are not supported by the pipes of a command
+func (c *commandConn) SetDeadline(t time.Time) error      { return nil }
+func (c *commandConn) SetReadDeadline(t time.Time) error  { return nil }
+func (c *commandConn) SetWriteDeadline(t time.Time) error { return nil }
+
+type dummyAddr struct{}
+
+func (dummyAddr) Network() string { return "dummy" }
+func (dummyAddr) String() string  { return "dummy" }
+
+// lockedBuffer collects the stderr of a command, it is written to by the
+// command and read by the connection
+type lockedBuffer struct {
+	mu  sync.Mutex
+	buf bytes.Buffer
+}
+
+func (b *lockedBuffer) Write(p []byte) (int, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	return b.buf.Write(p)
+}
+
+func (b *lockedBuffer) String() string {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	return b.buf.String()
+}
@@ -0,0 +1,75 @@
+package container
+
+import (
+	"context"
+	"io"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+// setupFakeSSH puts an ssh command on the path that records its arguments
+// and echoes stdin, the returned file contains the arguments
+func setupFakeSSH(t *testing.T, script string) string {
+	if runtime.GOOS == "windows" {
+		t.Skip("fake ssh command requires a shell")
+	}
+
+	dir := t.TempDir()
+	args := filepath.Join(dir, "args")
+
+	err := os.WriteFile(filepath.Join(dir, "ssh"), []byte("#!/bin/sh\necho \"$@\" > "+args+"\n"+script+"\n"), 0755)
+	require.NoError(t, err)
+
+	t.Setenv("PATH", dir+string(os.PathListSeparator)+os.Getenv("PATH"))
+
+	return args
+}
+
+func TestSSHDialerRunsDialStdio(t *testing.T) {
+	args := setupFakeSSH(t, "exec cat")
+
+	dial, err := sshDialer("ssh://dev@buildbox:2222")
+	require.NoError(t, err)
+
+	conn, err := dial(context.Background(), "tcp", "docker.example.com:80")
+	require.NoError(t, err)
+	defer conn.Close()
+
+	_, err = conn.Write([]byte("ping"))
+	require.NoError(t, err)
+
+	out := make([]byte, 4)
+	_, err = io.ReadFull(conn, out)
+	require.NoError(t, err)
+	require.Equal(t, "ping", string(out))
+
+	d, _ := os.ReadFile(args)
+	require.Equal(t, "-l dev -p 2222 -- buildbox docker system dial-stdio", strings.TrimSpace(string(d)))
+}
+
+func TestSSHDialerReturnsSSHError(t *testing.T) {
+	setupFakeSSH(t, "echo 'Permission denied (publickey)' >&2\nexit 255")
+
+	dial, err := sshDialer("ssh://buildbox")
+	require.NoError(t, err)
+
+	conn, err := dial(context.Background(), "tcp", "docker.example.com:80")
+	require.NoError(t, err)
+	defer conn.Close()
+
+	_, err = io.ReadAll(conn)
+	require.ErrorContains(t, err, "Permission denied (publickey)")
+}
+
+func TestSSHDialerReturnsErrorForInvalidHost(t *testing.T) {
+	_, err := sshDialer("tcp://buildbox:2375")
+	require.Error(t, err)
+
+	_, err = sshDialer("ssh://buildbox/var/run/docker.sock")
+	require.Error(t, err)
+}
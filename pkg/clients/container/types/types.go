@@ -21,6 +21,21 @@ type Container struct {
 
 	// User block for mapping the user id and group id inside the container
 	RunAs *User
+
+	// runtime options
+	Init       bool              // run an init process as PID 1
+	ReadOnly   bool              // mount the root filesystem as read only
+	ShmSize    int               // size of /dev/shm in MB
+	Sysctls    map[string]string // namespaced kernel parameters
+	ExtraHosts map[string]string // hostname to IP address mappings added to /etc/hosts
+	Tmpfs      []Tmpfs
+	Ulimits    []Ulimit
+	Devices    []Device
+
+	// VolumeArchive is the path of an archive created by ExportVolumes, the
+	// archive is copied into the container before it is started to restore
+	// the contents of its volumes
+	VolumeArchive string
 }
 
 type User struct {
@@ -69,6 +84,26 @@ type Volume struct {
 	SelinuxRelabel              string
 }
 
+// Ulimit sets the soft and hard limits for a resource such as nofile
+type Ulimit struct {
+	Name string
+	Soft int64
+	Hard int64
+}
+
+// Device is a host device that is added to the container
+type Device struct {
+	Source      string
+	Destination string
+	Permissions string
+}
+
+// Tmpfs is a tmpfs filesystem mounted in the container
+type Tmpfs struct {
+	Destination string
+	Options     []string
+}
+
 // Port is a port mapping
 type Port struct {
 	Local         string
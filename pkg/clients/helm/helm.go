@@ -1,6 +1,7 @@
 package helm
 
 import (
+	"errors"
 	"fmt"
 	"os"
 	"path"
@@ -17,8 +18,12 @@ import (
 	"helm.sh/helm/v3/pkg/getter"
 	"helm.sh/helm/v3/pkg/kube"
 	"helm.sh/helm/v3/pkg/repo"
+	"helm.sh/helm/v3/pkg/storage/driver"
 )
 
+// ErrReleaseNotFound is returned when a Helm release does not exist
+var ErrReleaseNotFound = errors.New("release not found")
+
 var helmLock sync.Mutex
 var helmStorage = &repo.File{}
 
@@ -35,6 +40,10 @@ type Helm interface {
 	// Destroy the given chart
 	Destroy(kubeConfig, name, namespace string) error
 
+	// ReleaseStatus returns the status of the release, ErrReleaseNotFound is
+	// returned when the release does not exist
+	ReleaseStatus(kubeConfig, name, namespace string) (string, error)
+
 	//UpsertChartRepository configures the remote chart repository
 	UpsertChartRepository(name, url string) error
 }
@@ -211,6 +220,29 @@ func (h *HelmImpl) Destroy(kubeConfig, name, namespace string) error {
 	return nil
 }
 
+func (h *HelmImpl) ReleaseStatus(kubeConfig, name, namespace string) (string, error) {
+	s := kube.GetConfig(kubeConfig, "default", namespace)
+	cfg := &action.Configuration{}
+	err := cfg.Init(s, namespace, "", func(format string, v ...interface{}) {
+		h.log.Debug("Helm debug message", "message", fmt.Sprintf(format, v...))
+	})
+	if err != nil {
+		return "", fmt.Errorf("unable to initialize configuration: %w", err)
+	}
+
+	client := action.NewStatus(cfg)
+	rel, err := client.Run(name)
+	if err != nil {
+		if errors.Is(err, driver.ErrReleaseNotFound) {
+			return "", ErrReleaseNotFound
+		}
+
+		return "", fmt.Errorf("unable to get status for release %s: %w", name, err)
+	}
+
+	return rel.Info.Status.String(), nil
+}
+
 func (h *HelmImpl) UpsertChartRepository(name, url string) error {
 	r := repo.Entry{
 		Name:                  name,
@@ -10,6 +10,8 @@ import (
 	"time"
 
 	"github.com/jumppad-labs/jumppad/pkg/clients/logger"
+	"github.com/jumppad-labs/jumppad/pkg/jumppad/tracing"
+	"go.opentelemetry.io/otel/attribute"
 	"helm.sh/helm/v3/pkg/kube"
 	v1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
@@ -160,7 +162,10 @@ func (k *KubernetesImpl) Delete(files []string) error {
 // and running.
 // selectors are checked sequentially
 // pods = ["component=server,app=consul", "component=client,app=consul"]
-func (k *KubernetesImpl) HealthCheckPods(ctx context.Context, selectors []string, timeout time.Duration) error {
+func (k *KubernetesImpl) HealthCheckPods(ctx context.Context, selectors []string, timeout time.Duration) (err error) {
+	ctx, span := tracing.Start(ctx, "HealthCheckPods", attribute.StringSlice("k8s.selectors", selectors))
+	defer func() { tracing.End(span, err) }()
+
 	// check all pods are running
 	for _, s := range selectors {
 		k.l.Debug("Health checking pods", "selector", s)
@@ -0,0 +1,138 @@
+package logger
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// Redacted replaces sensitive values in output
+const Redacted = "(sensitive)"
+
+// minRedactLength is the length below which values are not redacted,
+// replacing very short values would mangle unrelated output
+const minRedactLength = 4
+
+// Redactor replaces known sensitive values in text, values are added as
+// they become known, i.e. when a password is generated. A nil Redactor
+// does not redact anything.
+type Redactor struct {
+	mu     sync.RWMutex
+	values map[string]bool
+	sorted []string
+}
+
+// NewRedactor creates a Redactor without any values
+func NewRedactor() *Redactor {
+	return &Redactor{values: map[string]bool{}}
+}
+
+// Add adds values that should be redacted, the JSON encoded forms of the
+// values are also redacted so that values with quotes or new lines are
+// removed from JSON output
+func (r *Redactor) Add(values ...string) {
+	if r == nil {
+		return
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	added := false
+	for _, v := range values {
+		if len(v) < minRedactLength {
+			continue
+		}
+
+		for _, f := range encodings(v) {
+			if !r.values[f] {
+				r.values[f] = true
+				added = true
+			}
+		}
+	}
+
+	if !added {
+		return
+	}
+
+	// replace the longest values first so that a value which contains
+	// another value is fully redacted
+	r.sorted = make([]string, 0, len(r.values))
+	for v := range r.values {
+		r.sorted = append(r.sorted, v)
+	}
+
+	sort.Slice(r.sorted, func(i, j int) bool {
+		if len(r.sorted[i]) == len(r.sorted[j]) {
+			return r.sorted[i] < r.sorted[j]
+		}
+
+		return len(r.sorted[i]) > len(r.sorted[j])
+	})
+}
+
+// Redact returns s with any sensitive values replaced
+func (r *Redactor) Redact(s string) string {
+	if r == nil {
+		return s
+	}
+
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	for _, v := range r.sorted {
+		s = strings.ReplaceAll(s, v, Redacted)
+	}
+
+	return s
+}
+
+// Writer returns a writer that redacts anything written to it before
+// writing to w. Values are only redacted when they are contained in a
+// single call to Write, loggers write each line with a single call.
+func (r *Redactor) Writer(w io.Writer) io.Writer {
+	if r == nil {
+		return w
+	}
+
+	return &redactWriter{r, w}
+}
+
+type redactWriter struct {
+	r *Redactor
+	w io.Writer
+}
+
+func (rw *redactWriter) Write(p []byte) (int, error) {
+	_, err := io.WriteString(rw.w, rw.r.Redact(string(p)))
+	if err != nil {
+		return 0, err
+	}
+
+	// report the length of the original data so callers do not treat the
+	// replaced values as a short write
+	return len(p), nil
+}
+
+// encodings returns v and the forms it takes inside JSON strings
+func encodings(v string) []string {
+	forms := []string{v}
+
+	if d, err := json.Marshal(v); err == nil {
+		forms = append(forms, string(d[1:len(d)-1]))
+	}
+
+	b := bytes.NewBuffer(nil)
+	enc := json.NewEncoder(b)
+	enc.SetEscapeHTML(false)
+	if err := enc.Encode(v); err == nil {
+		d := bytes.TrimSpace(b.Bytes())
+		forms = append(forms, string(d[1:len(d)-1]))
+	}
+
+	return forms
+}
@@ -0,0 +1,54 @@
+package logger
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestRedactReplacesValues(t *testing.T) {
+	r := NewRedactor()
+	r.Add("secret", "secret-password")
+
+	out := r.Redact("password=secret-password user=secret")
+	require.Equal(t, "password=(sensitive) user=(sensitive)", out)
+}
+
+func TestRedactReplacesJSONEncodedValues(t *testing.T) {
+	r := NewRedactor()
+	r.Add("line one\n\"line <two>\"")
+
+	d, err := json.Marshal(map[string]string{"key": "line one\n\"line <two>\""})
+	require.NoError(t, err)
+
+	require.Equal(t, `{"key":"(sensitive)"}`, r.Redact(string(d)))
+}
+
+func TestRedactIgnoresShortValues(t *testing.T) {
+	r := NewRedactor()
+	r.Add("abc", "")
+
+	require.Equal(t, "abc", r.Redact("abc"))
+}
+
+func TestRedactWithNilRedactorReturnsInput(t *testing.T) {
+	var r *Redactor
+	r.Add("secret")
+
+	require.Equal(t, "secret", r.Redact("secret"))
+}
+
+func TestRedactWriterRedactsOutput(t *testing.T) {
+	r := NewRedactor()
+	b := bytes.NewBuffer(nil)
+
+	l := NewLogger(r.Writer(b), LogLevelInfo)
+	r.Add("secret-password")
+
+	l.Info("Created resource", "password", "secret-password")
+
+	require.Contains(t, b.String(), "password=(sensitive)")
+	require.NotContains(t, b.String(), "secret-password")
+}
@@ -192,7 +192,7 @@ https://jumppad.dev/docs/introduction/installation for other options.
 `
 
 func (b *SystemImpl) checkDocker() error {
-	d, err := container.NewDocker()
+	d, err := container.NewClient(container.Runtime())
 	if err != nil {
 		return err
 	}
@@ -213,7 +213,7 @@ func (b *SystemImpl) checkDocker() error {
 }
 
 func (b *SystemImpl) checkPodman() error {
-	d, err := container.NewDocker()
+	d, err := container.NewClient(container.Runtime())
 	if err != nil {
 		return err
 	}
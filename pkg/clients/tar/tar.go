@@ -6,6 +6,7 @@ import (
 	"fmt"
 	"io"
 	"os"
+	"path"
 	"path/filepath"
 	"strings"
 
@@ -149,6 +150,63 @@ func (tg *TarGz) Create(buf io.Writer, options *TarGzOptions, src []string, igno
 	return nil
 }
 
+// Stream is a tar stream such as the archive returned when copying files
+// from a container, the entries are added to an archive in the folder Path
+type Stream struct {
+	Path   string
+	Reader io.Reader
+}
+
+// Compress writes the entries of the tar streams to dst as a single tar.gz
+// archive. Unlike Create the headers of the entries are not changed, this
+// keeps the ownership, permissions and links of the files.
+func (tg *TarGz) Compress(dst io.Writer, streams ...Stream) error {
+	zw := gzip.NewWriter(dst)
+	tw := tar.NewWriter(zw)
+
+	for _, s := range streams {
+		tr := tar.NewReader(s.Reader)
+
+		for {
+			header, err := tr.Next()
+			if err == io.EOF {
+				break
+			}
+
+			if err != nil {
+				return fmt.Errorf("unable to read tar stream for %s: %s", s.Path, err)
+			}
+
+			header.Name = path.Join(s.Path, header.Name)
+
+			// hard links are relative to the root of the archive
+			if header.Typeflag == tar.TypeLink {
+				header.Linkname = path.Join(s.Path, header.Linkname)
+			}
+
+			if err := tw.WriteHeader(header); err != nil {
+				return err
+			}
+
+			if _, err := io.Copy(tw, tr); err != nil {
+				return err
+			}
+		}
+	}
+
+	if err := tw.Close(); err != nil {
+		return err
+	}
+
+	return zw.Close()
+}
+
+// Decompress returns a tar stream containing the entries of the tar.gz
+// archive src
+func (tg *TarGz) Decompress(src io.Reader) (io.ReadCloser, error) {
+	return gzip.NewReader(src)
+}
+
 func (tg *TarGz) Extract(src io.Reader, gziped bool, dst string) error {
 	var zr io.Reader = src
 	var err error
@@ -1,7 +1,9 @@
 package tar
 
 import (
+	"archive/tar"
 	"bytes"
+	"io"
 	"os"
 	"path/filepath"
 	"testing"
@@ -195,3 +197,56 @@ func TestTarDirStrippingFolders(t *testing.T) {
 	require.FileExists(t, filepath.Join(out, "/test2.txt"))
 	require.FileExists(t, filepath.Join(out, "/test3.txt"))
 }
+
+func TestCompressCombinesStreamsInFolders(t *testing.T) {
+	stream := func(files map[string]string) *bytes.Buffer {
+		buf := bytes.NewBuffer(nil)
+		tw := tar.NewWriter(buf)
+		for n, c := range files {
+			tw.WriteHeader(&tar.Header{Typeflag: tar.TypeReg, Name: n, Mode: 0600, Uid: 999, Gid: 999, Size: int64(len(c))})
+			tw.Write([]byte(c))
+		}
+		tw.Close()
+
+		return buf
+	}
+
+	buf := bytes.NewBuffer(nil)
+
+	tg := &TarGz{}
+	err := tg.Compress(buf,
+		Stream{Path: "var/lib", Reader: stream(map[string]string{"data/test1.txt": "test1"})},
+		Stream{Path: "", Reader: stream(map[string]string{"cache/test2.txt": "test2"})},
+	)
+	require.NoError(t, err)
+
+	r, err := tg.Decompress(buf)
+	require.NoError(t, err)
+	defer r.Close()
+
+	headers := map[string]*tar.Header{}
+	tr := tar.NewReader(r)
+	for {
+		h, err := tr.Next()
+		if err == io.EOF {
+			break
+		}
+
+		require.NoError(t, err)
+		headers[h.Name] = h
+	}
+
+	require.Len(t, headers, 2)
+	require.Contains(t, headers, "var/lib/data/test1.txt")
+	require.Contains(t, headers, "cache/test2.txt")
+
+	// ownership and permissions are kept
+	require.Equal(t, 999, headers["var/lib/data/test1.txt"].Uid)
+	require.Equal(t, int64(0600), headers["var/lib/data/test1.txt"].Mode)
+}
+
+func TestCompressReturnsErrorForInvalidStream(t *testing.T) {
+	tg := &TarGz{}
+	err := tg.Compress(bytes.NewBuffer(nil), Stream{Path: "data", Reader: bytes.NewBufferString("not a tar stream")})
+	require.Error(t, err)
+}
@@ -9,8 +9,14 @@ import (
 	"github.com/jumppad-labs/jumppad/pkg/utils"
 )
 
-func customHCLFuncJumppad() (string, error) {
-	return utils.JumppadHome(), nil
+// homeFunctions resolves the folders returned by the jumppad and data
+// functions in the jumppad home folder and workspace of the environment
+type homeFunctions struct {
+	env utils.Environment
+}
+
+func (h homeFunctions) customHCLFuncJumppad() (string, error) {
+	return h.env.JumppadHome(), nil
 }
 
 // returns the docker host ip address
@@ -22,7 +28,13 @@ func customHCLFuncDockerHost() (string, error) {
 	return utils.GetDockerHost(), nil
 }
 
-func customHCLFuncDataFolderWithPermissions(name string, permissions int) (string, error) {
+// customHCLFuncSensitive returns the value unchanged, variables and outputs
+// that wrap their value with sensitive are redacted from output
+func customHCLFuncSensitive(value string) (string, error) {
+	return value, nil
+}
+
+func (h homeFunctions) customHCLFuncDataFolderWithPermissions(name string, permissions int) (string, error) {
 	if permissions < 0 || permissions > 777 {
 		return "", fmt.Errorf("permissions must be a three digit number less than 777")
 	}
@@ -32,12 +44,16 @@ func customHCLFuncDataFolderWithPermissions(name string, permissions int) (strin
 	oInt, _ := strconv.ParseInt(strInt, 8, 32)
 
 	perms := os.FileMode(uint32(oInt))
-	return utils.DataFolder(name, perms), nil
+	return h.dataFolder(name, perms), nil
 }
 
-func customHCLFuncDataFolder(name string) (string, error) {
+func (h homeFunctions) customHCLFuncDataFolder(name string) (string, error) {
 	perms := os.FileMode(0775)
-	return utils.DataFolder(name, perms), nil
+	return h.dataFolder(name, perms), nil
+}
+
+func (h homeFunctions) dataFolder(name string, perms os.FileMode) string {
+	return h.env.DataFolder(name, perms)
 }
 
 func customHCLFuncSystem(property string) (string, error) {
@@ -5,6 +5,7 @@ import (
 	"path/filepath"
 	"testing"
 
+	"github.com/jumppad-labs/jumppad/pkg/utils"
 	"github.com/stretchr/testify/require"
 )
 
@@ -22,3 +23,26 @@ func TestExistsTrue(t *testing.T) {
 	require.NoError(t, err)
 	require.Equal(t, true, exists)
 }
+
+func TestDataFolderUsesHome(t *testing.T) {
+	home := t.TempDir()
+	h := homeFunctions{env: utils.Environment{Home: home, Workspace: utils.DefaultWorkspace}}
+
+	d, err := h.customHCLFuncDataFolder("test")
+	require.NoError(t, err)
+	require.Equal(t, filepath.Join(home, "data", "test"), d)
+	require.DirExists(t, d)
+
+	j, err := h.customHCLFuncJumppad()
+	require.NoError(t, err)
+	require.Equal(t, home, j)
+}
+
+func TestDataFolderUsesWorkspaceInHome(t *testing.T) {
+	home := t.TempDir()
+	h := homeFunctions{env: utils.Environment{Home: home, Workspace: "dev"}}
+
+	d, err := h.customHCLFuncDataFolder("test")
+	require.NoError(t, err)
+	require.Equal(t, filepath.Join(home, "workspaces", "dev", "data", "test"), d)
+}
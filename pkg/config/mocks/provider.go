@@ -5,6 +5,7 @@ package mocks
 import (
 	context "context"
 
+	container "github.com/jumppad-labs/jumppad/pkg/clients/container"
 	sdk "github.com/jumppad-labs/plugin-sdk"
 	mock "github.com/stretchr/testify/mock"
 
@@ -68,6 +69,46 @@ func (_m *Provider) Destroy(ctx context.Context, force bool) error {
 	return r0
 }
 
+// Drifted provides a mock function with given fields: ctx
+func (_m *Provider) Drifted(ctx context.Context) ([]string, error) {
+	ret := _m.Called(ctx)
+
+	var r0 []string
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context) ([]string, error)); ok {
+		return rf(ctx)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context) []string); ok {
+		r0 = rf(ctx)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]string)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context) error); ok {
+		r1 = rf(ctx)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Import provides a mock function with given fields: ctx, id
+func (_m *Provider) Import(ctx context.Context, id string) error {
+	ret := _m.Called(ctx, id)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) error); ok {
+		r0 = rf(ctx, id)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
 // Init provides a mock function with given fields: cfg, log
 func (_m *Provider) Init(cfg types.Resource, log sdk.Logger) error {
 	ret := _m.Called(cfg, log)
@@ -122,6 +163,62 @@ func (_m *Provider) Refresh(ctx context.Context) error {
 	return r0
 }
 
+// Restore provides a mock function with given fields: ctx, s
+func (_m *Provider) Restore(ctx context.Context, s *container.Snapshot) error {
+	ret := _m.Called(ctx, s)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, *container.Snapshot) error); ok {
+		r0 = rf(ctx, s)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// Snapshot provides a mock function with given fields: ctx, s
+func (_m *Provider) Snapshot(ctx context.Context, s *container.Snapshot) error {
+	ret := _m.Called(ctx, s)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, *container.Snapshot) error); ok {
+		r0 = rf(ctx, s)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// Start provides a mock function with given fields: ctx
+func (_m *Provider) Start(ctx context.Context) error {
+	ret := _m.Called(ctx)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context) error); ok {
+		r0 = rf(ctx)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// Stop provides a mock function with given fields: ctx
+func (_m *Provider) Stop(ctx context.Context) error {
+	ret := _m.Called(ctx)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context) error); ok {
+		r0 = rf(ctx)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
 type mockConstructorTestingTNewProvider interface {
 	mock.TestingT
 	Cleanup(func())
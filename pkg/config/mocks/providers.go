@@ -14,6 +14,10 @@ type Providers struct {
 	mock.Mock
 	Providers  []*Provider
 	returnVals map[string]error
+
+	// Drift is returned by the Drifted method of the provider for the
+	// resource with the given name
+	Drift map[string][]string
 }
 
 func NewProviders(returnVals map[string]error) *Providers {
@@ -38,6 +42,12 @@ func (_m *Providers) GetProvider(c types.Resource) sdk.Provider {
 	m.On("Destroy", mock.Anything, mock.Anything).Return(val)
 	m.On("Refresh", mock.Anything).Return(val)
 	m.On("Changed").Return(false, val)
+	m.On("Drifted", mock.Anything).Return(_m.Drift[c.Metadata().Name], val)
+	m.On("Import", mock.Anything, mock.Anything).Return(val)
+	m.On("Stop", mock.Anything).Return(val)
+	m.On("Start", mock.Anything).Return(val)
+	m.On("Snapshot", mock.Anything, mock.Anything).Return(val)
+	m.On("Restore", mock.Anything, mock.Anything).Return(val)
 	m.On("Init", mock.Anything, mock.Anything).Return(nil)
 
 	m.Init(c, nil)
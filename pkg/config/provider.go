@@ -1,10 +1,13 @@
 package config
 
 import (
+	"context"
 	"reflect"
 
 	"github.com/jumppad-labs/hclconfig/types"
 	"github.com/jumppad-labs/jumppad/pkg/clients"
+	"github.com/jumppad-labs/jumppad/pkg/clients/container"
+	"github.com/jumppad-labs/jumppad/pkg/clients/logger"
 	sdk "github.com/jumppad-labs/plugin-sdk"
 )
 
@@ -15,6 +18,57 @@ type Provider interface {
 	sdk.Provider
 }
 
+// DriftDetector is implemented by providers that can compare a created
+// resource with the resources that are actually running
+type DriftDetector interface {
+	// Drifted returns a description of each difference between the resource
+	// and the running resources, an empty slice means there is no drift
+	Drifted(ctx context.Context) ([]string, error)
+}
+
+// Importer is implemented by providers that can adopt existing objects,
+// such as containers created outside of jumppad, as the resource
+type Importer interface {
+	// Import inspects the object with the given id and sets the computed
+	// fields of the resource from it
+	Import(ctx context.Context, id string) error
+}
+
+// Stopper is implemented by providers of resources that run in containers,
+// the containers can be stopped to free CPU and memory and started again
+// without re-creating the resource
+type Stopper interface {
+	// Stop stops the containers for the resource without removing them
+	Stop(ctx context.Context) error
+
+	// Start starts the stopped containers for the resource
+	Start(ctx context.Context) error
+}
+
+// Snapshotter is implemented by providers of resources that can be saved to
+// a snapshot and recreated from it, resources that do not implement the
+// interface are restored to the state without being created again
+type Snapshotter interface {
+	// Snapshot saves the containers for the resource to the snapshot
+	Snapshot(ctx context.Context, s *container.Snapshot) error
+
+	// Restore creates the resource from the containers saved to the snapshot
+	Restore(ctx context.Context, s *container.Snapshot) error
+}
+
+// ClientsInitializer is implemented by providers that can be initialized
+// with clients that were created by the caller rather than generated from
+// the environment
+type ClientsInitializer interface {
+	// InitWithClients initializes the provider for the resource using the
+	// given clients
+	InitWithClients(cfg types.Resource, l sdk.Logger, c *clients.Clients) error
+}
+
+// ClientsFactory creates the clients used to initialize a provider, the
+// factory is called once for every provider
+type ClientsFactory func(l logger.Logger) (*clients.Clients, error)
+
 // ConfigWrapper allows the provider config to be deserialized to a type
 type ConfigWrapper struct {
 	Type  string
@@ -27,10 +81,18 @@ type Providers interface {
 
 type ProvidersImpl struct {
 	clients *clients.Clients
+	factory ClientsFactory
 }
 
 func NewProviders(c *clients.Clients) Providers {
-	return &ProvidersImpl{c}
+	return &ProvidersImpl{clients: c}
+}
+
+// NewProvidersWithFactory creates Providers that initialize each provider
+// with the clients returned by the factory, providers that do not implement
+// ClientsInitializer generate their own clients
+func NewProvidersWithFactory(l logger.Logger, f ClientsFactory) Providers {
+	return &ProvidersImpl{clients: &clients.Clients{Logger: l}, factory: f}
 }
 
 func (p *ProvidersImpl) GetProvider(r types.Resource) sdk.Provider {
@@ -39,6 +101,18 @@ func (p *ProvidersImpl) GetProvider(r types.Resource) sdk.Provider {
 		ptr := reflect.New(reflect.TypeOf(t).Elem())
 
 		prov := ptr.Interface().(Provider)
+
+		if ci, ok := prov.(ClientsInitializer); ok && p.factory != nil {
+			c, err := p.factory(p.clients.Logger)
+			if err != nil {
+				p.clients.Logger.Error("Unable to create clients for provider", "ref", r.Metadata().ID, "error", err)
+				return nil
+			}
+
+			ci.InitWithClients(r, p.clients.Logger, c)
+			return prov
+		}
+
 		prov.Init(r, p.clients.Logger)
 
 		return prov
@@ -1,6 +1,9 @@
 package blueprint
 
-import "github.com/jumppad-labs/hclconfig/types"
+import (
+	"github.com/jumppad-labs/hclconfig/types"
+	"github.com/jumppad-labs/jumppad/pkg/config/resources/lifecycle"
+)
 
 // TypeContainer is the resource string for a Container resource
 const TypeBlueprint string = "blueprint"
@@ -9,6 +12,8 @@ const TypeBlueprint string = "blueprint"
 type Blueprint struct {
 	types.ResourceBase `hcl:",remain"`
 
+	Lifecycle *lifecycle.Lifecycle `hcl:"lifecycle,block" json:"lifecycle,omitempty"`
+
 	Title        string   `hcl:"title,optional" json:"title,omitempty"`
 	Organization string   `hcl:"organization,optional" json:"organization,omitempty"`
 	Author       string   `hcl:"author,optional" json:"author,omitempty"`
@@ -21,16 +21,20 @@ type Provider struct {
 
 // NewBuild creates a null noop provider
 func (b *Provider) Init(cfg htypes.Resource, l sdk.Logger) error {
-	c, ok := cfg.(*Build)
-	if !ok {
-		return fmt.Errorf("unable to initialize Build provider, resource is not of type Build")
-	}
-
 	cli, err := clients.GenerateClients(l)
 	if err != nil {
 		return err
 	}
 
+	return b.InitWithClients(cfg, l, cli)
+}
+
+func (b *Provider) InitWithClients(cfg htypes.Resource, l sdk.Logger, cli *clients.Clients) error {
+	c, ok := cfg.(*Build)
+	if !ok {
+		return fmt.Errorf("unable to initialize Build provider, resource is not of type Build")
+	}
+
 	b.config = c
 	b.client = cli.ContainerTasks
 	b.log = l
@@ -6,8 +6,8 @@ import (
 	"path"
 
 	"github.com/jumppad-labs/hclconfig/types"
-	"github.com/jumppad-labs/jumppad/pkg/config"
 	"github.com/jumppad-labs/jumppad/pkg/config/resources/container"
+	"github.com/jumppad-labs/jumppad/pkg/config/resources/lifecycle"
 	"github.com/jumppad-labs/jumppad/pkg/utils"
 )
 
@@ -18,6 +18,8 @@ type Build struct {
 	// embedded type holding name, etc
 	types.ResourceBase `hcl:",remain"`
 
+	Lifecycle *lifecycle.Lifecycle `hcl:"lifecycle,block" json:"lifecycle,omitempty"`
+
 	Container BuildContainer `hcl:"container,block" json:"container"`
 
 	// Outputs allow files or directories to be copied from the container
@@ -62,18 +64,13 @@ func (b *Build) Process() error {
 		}
 	}
 
-	cfg, err := config.LoadState()
-	if err == nil {
-		// try and find the resource in the state
-		r, _ := cfg.FindResource(b.Meta.ID)
-		if r != nil {
-			kstate := r.(*Build)
-			b.Image = kstate.Image
+	return nil
+}
 
-			// add the build checksum
-			b.BuildChecksum = kstate.BuildChecksum
-		}
-	}
+func (b *Build) RestoreState(r types.Resource) {
+	kstate := r.(*Build)
+	b.Image = kstate.Image
 
-	return nil
+	// add the build checksum
+	b.BuildChecksum = kstate.BuildChecksum
 }
@@ -14,6 +14,7 @@ import (
 	"github.com/jumppad-labs/jumppad/pkg/clients/container"
 	"github.com/jumppad-labs/jumppad/pkg/clients/container/types"
 	"github.com/jumppad-labs/jumppad/pkg/clients/logger"
+	"github.com/jumppad-labs/jumppad/pkg/jumppad/tracing"
 	"github.com/jumppad-labs/jumppad/pkg/utils"
 	sdk "github.com/jumppad-labs/plugin-sdk"
 )
@@ -25,22 +26,28 @@ type Provider struct {
 	config *ImageCache
 	client container.ContainerTasks
 	log    logger.Logger
+	env    utils.Environment
 }
 
 func (p *Provider) Init(cfg htypes.Resource, l sdk.Logger) error {
-	c, ok := cfg.(*ImageCache)
-	if !ok {
-		return fmt.Errorf("unable to initialize ImageCache provider, resource is not of type ImageCache")
-	}
-
 	cli, err := clients.GenerateClients(l)
 	if err != nil {
 		return err
 	}
 
+	return p.InitWithClients(cfg, l, cli)
+}
+
+func (p *Provider) InitWithClients(cfg htypes.Resource, l sdk.Logger, cli *clients.Clients) error {
+	c, ok := cfg.(*ImageCache)
+	if !ok {
+		return fmt.Errorf("unable to initialize ImageCache provider, resource is not of type ImageCache")
+	}
+
 	p.config = c
 	p.client = cli.ContainerTasks
 	p.log = l
+	p.env = cli.Environment
 
 	return nil
 }
@@ -77,7 +84,7 @@ func (p *Provider) Create(ctx context.Context) error {
 	}
 
 	if len(ids) == 0 {
-		_, err := p.createImageCache(registries, authRegistries)
+		_, err := p.createImageCache(ctx, registries, authRegistries)
 		if err != nil {
 			return err
 		}
@@ -132,8 +139,85 @@ func (p *Provider) Refresh(ctx context.Context) error {
 	return p.reConfigureNetworks(dependentNetworks)
 }
 
+// Stop stops the cache container without removing it
+func (p *Provider) Stop(ctx context.Context) error {
+	if ctx.Err() != nil {
+		p.log.Debug("Context cancelled, skipping stop", "ref", p.config.Meta.ID)
+		return nil
+	}
+
+	p.log.Info("Stop ImageCache", "ref", p.config.Meta.ID)
+
+	ids, err := p.Lookup()
+	if err != nil {
+		return err
+	}
+
+	for _, id := range ids {
+		err := p.client.StopContainer(id, false)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// Start starts the stopped cache container, clusters reach the cache by
+// name so the networks are not reattached with fixed addresses
+func (p *Provider) Start(ctx context.Context) error {
+	if ctx.Err() != nil {
+		p.log.Debug("Context cancelled, skipping start", "ref", p.config.Meta.ID)
+		return nil
+	}
+
+	p.log.Info("Start ImageCache", "ref", p.config.Meta.ID)
+
+	ids, err := p.Lookup()
+	if err != nil {
+		return err
+	}
+
+	if len(ids) == 0 {
+		return fmt.Errorf("image cache container does not exist")
+	}
+
+	for _, id := range ids {
+		err := p.client.StartContainer(id, nil)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// Snapshot does nothing, the image cache is shared by all environments
+func (p *Provider) Snapshot(ctx context.Context, s *container.Snapshot) error {
+	return nil
+}
+
+// Restore creates the image cache when it is not already running
+func (p *Provider) Restore(ctx context.Context, s *container.Snapshot) error {
+	return p.Create(ctx)
+}
+
 func (p *Provider) Lookup() ([]string, error) {
-	return p.client.FindContainerIDs(utils.FQDN(p.config.Meta.Name, p.config.Meta.Module, p.config.Meta.Type))
+	return p.client.FindContainerIDs(p.env.FQDN(p.config.Meta.Name, p.config.Meta.Module, p.config.Meta.Type))
+}
+
+// Drifted checks that the image cache container exists
+func (p *Provider) Drifted(ctx context.Context) ([]string, error) {
+	ids, err := p.Lookup()
+	if err != nil {
+		return nil, err
+	}
+
+	if len(ids) == 0 {
+		return []string{"image cache container does not exist"}, nil
+	}
+
+	return nil, nil
 }
 
 func (p *Provider) Changed() (bool, error) {
@@ -142,8 +226,8 @@ func (p *Provider) Changed() (bool, error) {
 	return false, nil
 }
 
-func (p *Provider) createImageCache(registries []string, authRegistries []string) (string, error) {
-	fqdn := utils.FQDN(p.config.Meta.Name, p.config.Meta.Module, p.config.Meta.Type)
+func (p *Provider) createImageCache(ctx context.Context, registries []string, authRegistries []string) (string, error) {
+	fqdn := p.env.FQDN(p.config.Meta.Name, p.config.Meta.Module, p.config.Meta.Type)
 
 	// Create the volume to store the cache
 	// if this volume exists it will not be recreated
@@ -153,8 +237,8 @@ func (p *Provider) createImageCache(registries []string, authRegistries []string
 	}
 
 	// copy the ca and key
-	cert := filepath.Join(utils.CertsDir(""), "root.cert")
-	key := filepath.Join(utils.CertsDir(""), "root.key")
+	cert := filepath.Join(p.env.CertsDir(""), "root.cert")
+	key := filepath.Join(p.env.CertsDir(""), "root.key")
 
 	_, err = p.client.CopyFilesToVolume(volID, []string{cert, key}, "/ca", true)
 	if err != nil {
@@ -162,7 +246,9 @@ func (p *Provider) createImageCache(registries []string, authRegistries []string
 	}
 
 	// pull the container image
-	err = p.client.PullImage(types.Image{Name: cacheImage}, false)
+	err = tracing.Trace(ctx, "PullImage", func() error {
+		return p.client.PullImage(types.Image{Name: cacheImage}, false)
+	}, tracing.Images(cacheImage))
 	if err != nil {
 		return "", err
 	}
@@ -174,7 +260,7 @@ func (p *Provider) createImageCache(registries []string, authRegistries []string
 
 	cc.Volumes = []types.Volume{
 		{
-			Source:      utils.FQDNVolumeName("images"),
+			Source:      p.env.FQDNVolumeName("images"),
 			Destination: "/cache",
 			Type:        "volume",
 		},
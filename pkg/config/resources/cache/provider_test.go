@@ -38,7 +38,7 @@ func setupImageCacheTests() (*ImageCache, *cmocks.ContainerTasks) {
 func TestImageCacheCreateDoesNotCreateContainerWhenExists(t *testing.T) {
 	cc, md := setupImageCacheTests()
 
-	c := Provider{cc, md, logger.NewTestLogger(t)}
+	c := Provider{cc, md, logger.NewTestLogger(t), utils.Environment{}}
 	err := c.Create(context.Background())
 	require.NoError(t, err)
 
@@ -51,7 +51,7 @@ func TestImageCacheCreateDoesNotCreateContainerWhenExists(t *testing.T) {
 func TestImageCacheCreateCreatesVolume(t *testing.T) {
 	cc, md := setupImageCacheTests()
 
-	c := Provider{cc, md, logger.NewTestLogger(t)}
+	c := Provider{cc, md, logger.NewTestLogger(t), utils.Environment{}}
 	err := c.Create(context.Background())
 	require.NoError(t, err)
 
@@ -61,7 +61,7 @@ func TestImageCacheCreateCreatesVolume(t *testing.T) {
 func TestImageCachePullsImage(t *testing.T) {
 	cc, md := setupImageCacheTests()
 
-	c := Provider{cc, md, logger.NewTestLogger(t)}
+	c := Provider{cc, md, logger.NewTestLogger(t), utils.Environment{}}
 	err := c.Create(context.Background())
 	require.NoError(t, err)
 
@@ -71,7 +71,7 @@ func TestImageCachePullsImage(t *testing.T) {
 func TestImageCacheCreateAddsVolumes(t *testing.T) {
 	cc, md := setupImageCacheTests()
 
-	c := Provider{cc, md, logger.NewTestLogger(t)}
+	c := Provider{cc, md, logger.NewTestLogger(t), utils.Environment{}}
 	err := c.Create(context.Background())
 	require.NoError(t, err)
 
@@ -89,7 +89,7 @@ func TestImageCacheCreateAddsVolumes(t *testing.T) {
 func TestImageCacheCreateAddsEnvironmentVariables(t *testing.T) {
 	cc, md := setupImageCacheTests()
 
-	c := Provider{cc, md, logger.NewTestLogger(t)}
+	c := Provider{cc, md, logger.NewTestLogger(t), utils.Environment{}}
 	err := c.Create(context.Background())
 	require.NoError(t, err)
 
@@ -124,7 +124,7 @@ func TestImageCacheCreateAddsUnauthenticatedRegistries(t *testing.T) {
 		},
 	}
 
-	c := Provider{cc, md, logger.NewTestLogger(t)}
+	c := Provider{cc, md, logger.NewTestLogger(t), utils.Environment{}}
 	err := c.Create(context.Background())
 	require.NoError(t, err)
 
@@ -155,7 +155,7 @@ func TestImageCacheCreateAddsAuthenticatedRegistries(t *testing.T) {
 		},
 	}
 
-	c := Provider{cc, md, logger.NewTestLogger(t)}
+	c := Provider{cc, md, logger.NewTestLogger(t), utils.Environment{}}
 	err := c.Create(context.Background())
 	require.NoError(t, err)
 
@@ -169,7 +169,7 @@ func TestImageCacheCreateAddsAuthenticatedRegistries(t *testing.T) {
 func TestImageCacheCreateCopiesCerts(t *testing.T) {
 	cc, md := setupImageCacheTests()
 
-	c := Provider{cc, md, logger.NewTestLogger(t)}
+	c := Provider{cc, md, logger.NewTestLogger(t), utils.Environment{}}
 	err := c.Create(context.Background())
 	require.NoError(t, err)
 
@@ -205,7 +205,7 @@ func TestImageCacheAttachesAndDetatchesNetworks(t *testing.T) {
 	md.On("FindNetwork", "resource.network.one").Once().Return(ctypes.NetworkAttachment{Name: "one"}, nil)
 	md.On("FindNetwork", "resource.network.two").Once().Return(ctypes.NetworkAttachment{Name: "two"}, nil)
 
-	c := Provider{cc, md, logger.NewTestLogger(t)}
+	c := Provider{cc, md, logger.NewTestLogger(t), utils.Environment{}}
 	err := c.Create(context.Background())
 	require.NoError(t, err)
 
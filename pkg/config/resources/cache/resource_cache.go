@@ -3,6 +3,7 @@ package cache
 import (
 	"github.com/jumppad-labs/hclconfig/types"
 	ctypes "github.com/jumppad-labs/jumppad/pkg/config/resources/container"
+	"github.com/jumppad-labs/jumppad/pkg/config/resources/lifecycle"
 )
 
 // TypeImageCache is the resource string for a ImageCache resource
@@ -13,6 +14,8 @@ type ImageCache struct {
 	// embedded type holding name, etc
 	types.ResourceBase `hcl:",remain"`
 
+	Lifecycle *lifecycle.Lifecycle `hcl:"lifecycle,block" json:"lifecycle,omitempty"`
+
 	Registries []Registry `hcl:"registry,block" json:"registries,omitempty"`
 
 	Networks ctypes.NetworkAttachments `hcl:"network,block" json:"networks,omitempty"` // Attach to the correct network // only when Image is specified
@@ -1,6 +1,9 @@
 package cache
 
-import "github.com/jumppad-labs/hclconfig/types"
+import (
+	"github.com/jumppad-labs/hclconfig/types"
+	"github.com/jumppad-labs/jumppad/pkg/config/resources/lifecycle"
+)
 
 const TypeRegistry string = "container_registry"
 
@@ -9,6 +12,8 @@ type Registry struct {
 	// embedded type holding name, etc
 	types.ResourceBase `hcl:",remain"`
 
+	Lifecycle *lifecycle.Lifecycle `hcl:"lifecycle,block" json:"lifecycle,omitempty"`
+
 	Hostname string        `hcl:"hostname" json:"hostname"`         // Hostname of the registry
 	Auth     *RegistryAuth `hcl:"auth,block" json:"auth,omitempty"` // auth to authenticate against registry
 }
@@ -17,5 +22,5 @@ type Registry struct {
 type RegistryAuth struct {
 	Hostname string `hcl:"hostname,optional" json:"hostname,omitempty"` // Hostname for authentication, can be different from registry hostname
 	Username string `hcl:"username" json:"username"`                    // Username for authentication
-	Password string `hcl:"password" json:"password"`                    // Password for authentication
+	Password string `hcl:"password" json:"password" sensitive:"true"`   // Password for authentication
 }
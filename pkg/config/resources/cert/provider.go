@@ -14,6 +14,7 @@ import (
 
 	"github.com/jumppad-labs/connector/crypto"
 	htypes "github.com/jumppad-labs/hclconfig/types"
+	"github.com/jumppad-labs/jumppad/pkg/clients/container"
 	"github.com/jumppad-labs/jumppad/pkg/clients/logger"
 	sdk "github.com/jumppad-labs/plugin-sdk"
 	"github.com/sethvargo/go-retry"
@@ -147,6 +148,24 @@ func (p *CAProvider) Destroy(ctx context.Context, force bool) error {
 	return destroy(p.config.Meta.Module, p.config.Meta.Name, p.config.Output, p.log)
 }
 
+// Snapshot does nothing, the certificates are stored in the state
+func (p *CAProvider) Snapshot(ctx context.Context, s *container.Snapshot) error {
+	return nil
+}
+
+// Restore writes the CA from the state rather than generating a new CA so
+// that certificates issued by the CA are still trusted
+func (p *CAProvider) Restore(ctx context.Context, s *container.Snapshot) error {
+	if ctx.Err() != nil {
+		p.log.Debug("Context cancelled, skipping restore", "ref", p.config.Meta.ID)
+		return nil
+	}
+
+	p.log.Info("Restore CA Certificate", "ref", p.config.Meta.ID)
+
+	return restoreFiles(p.config.PrivateKey, p.config.PublicKeyPEM, p.config.PublicKeySSH, p.config.Cert)
+}
+
 func (p *CAProvider) Lookup() ([]string, error) {
 	return nil, nil
 }
@@ -289,6 +308,23 @@ func (p *LeafProvider) Refresh(ctx context.Context) error {
 	return nil
 }
 
+// Snapshot does nothing, the certificates are stored in the state
+func (p *LeafProvider) Snapshot(ctx context.Context, s *container.Snapshot) error {
+	return nil
+}
+
+// Restore writes the certificate and keys from the state
+func (p *LeafProvider) Restore(ctx context.Context, s *container.Snapshot) error {
+	if ctx.Err() != nil {
+		p.log.Debug("Context cancelled, skipping restore", "ref", p.config.Meta.Name)
+		return nil
+	}
+
+	p.log.Info("Restore Leaf Certificate", "ref", p.config.Meta.Name)
+
+	return restoreFiles(p.config.PrivateKey, p.config.PublicKeyPEM, p.config.PublicKeySSH, p.config.Cert)
+}
+
 func (p *LeafProvider) Lookup() ([]string, error) {
 	return nil, nil
 }
@@ -299,6 +335,42 @@ func (p *LeafProvider) Changed() (bool, error) {
 	return false, nil
 }
 
+// restoreFiles writes the contents of the files recorded in the state, the
+// keys and certificate are written with the same permissions as when they
+// were generated so that the private key is only readable by the owner
+func restoreFiles(key, pub, ssh, cert File) error {
+	files := []struct {
+		file File
+		mode os.FileMode
+	}{
+		{key, 0400},
+		{pub, 0400},
+		{ssh, os.ModePerm},
+		{cert, 0400},
+	}
+
+	for _, f := range files {
+		if f.file.Path == "" {
+			return fmt.Errorf("certificate file %s is not in the state", f.file.Filename)
+		}
+
+		err := os.MkdirAll(f.file.Directory, os.ModePerm)
+		if err != nil {
+			return err
+		}
+
+		// read only files can not be overwritten
+		os.Remove(f.file.Path)
+
+		err = os.WriteFile(f.file.Path, []byte(f.file.Contents), f.mode)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
 func destroy(module, name, output string, log logger.Logger) error {
 	keyFile := path.Join(output, fmt.Sprintf("%s.key", name))
 	pubkeyFile := path.Join(output, fmt.Sprintf("%s.pub", name))
@@ -3,6 +3,7 @@ package cert
 import (
 	"context"
 	"fmt"
+	"os"
 	"path"
 	"testing"
 
@@ -75,7 +76,10 @@ func TestGeneratesValidLeaf(t *testing.T) {
 	require.NoError(t, err)
 
 	require.FileExists(t, path.Join(c.Output, fmt.Sprintf("%s-leaf.cert", c.Meta.Name)))
-	require.FileExists(t, path.Join(c.Output, fmt.Sprintf("%s-leaf.key", c.Meta.Name)))
+
+	fi, err := os.Stat(path.Join(c.Output, fmt.Sprintf("%s-leaf.key", c.Meta.Name)))
+	require.NoError(t, err)
+	require.Equal(t, os.FileMode(0400), fi.Mode().Perm())
 	require.FileExists(t, path.Join(c.Output, fmt.Sprintf("%s-leaf.pub", c.Meta.Name)))
 	require.FileExists(t, path.Join(c.Output, fmt.Sprintf("%s-leaf.ssh", c.Meta.Name)))
 }
@@ -94,3 +98,52 @@ func TestDestroyCleansUpLeaf(t *testing.T) {
 	require.NoFileExists(t, path.Join(c.Output, fmt.Sprintf("%s-leaf.pub", c.Meta.Name)))
 	require.NoFileExists(t, path.Join(c.Output, fmt.Sprintf("%s-leaf.ssh", c.Meta.Name)))
 }
+
+func TestRestoreWritesCAFromState(t *testing.T) {
+	c, p := setupCACert(t)
+
+	err := p.Create(context.Background())
+	require.NoError(t, err)
+
+	cert := c.Cert.Contents
+
+	err = p.Destroy(context.Background(), false)
+	require.NoError(t, err)
+
+	err = p.Restore(context.Background(), nil)
+	require.NoError(t, err)
+
+	d, err := os.ReadFile(path.Join(c.Output, fmt.Sprintf("%s.cert", c.Meta.Name)))
+	require.NoError(t, err)
+	require.Equal(t, cert, string(d))
+
+	fi, err := os.Stat(path.Join(c.Output, fmt.Sprintf("%s.key", c.Meta.Name)))
+	require.NoError(t, err)
+	require.Equal(t, os.FileMode(0400), fi.Mode().Perm())
+}
+
+func TestRestoreReturnsErrorWhenCANotInState(t *testing.T) {
+	_, p := setupCACert(t)
+
+	err := p.Restore(context.Background(), nil)
+	require.Error(t, err)
+}
+
+func TestRestoreWritesLeafFromState(t *testing.T) {
+	c, p := setupLeafCert(t)
+
+	err := p.Create(context.Background())
+	require.NoError(t, err)
+
+	err = p.Destroy(context.Background(), false)
+	require.NoError(t, err)
+
+	err = p.Restore(context.Background(), nil)
+	require.NoError(t, err)
+
+	require.FileExists(t, path.Join(c.Output, fmt.Sprintf("%s-leaf.cert", c.Meta.Name)))
+
+	fi, err := os.Stat(path.Join(c.Output, fmt.Sprintf("%s-leaf.key", c.Meta.Name)))
+	require.NoError(t, err)
+	require.Equal(t, os.FileMode(0400), fi.Mode().Perm())
+}
@@ -2,7 +2,7 @@ package cert
 
 import (
 	"github.com/jumppad-labs/hclconfig/types"
-	"github.com/jumppad-labs/jumppad/pkg/config"
+	"github.com/jumppad-labs/jumppad/pkg/config/resources/lifecycle"
 	"github.com/jumppad-labs/jumppad/pkg/utils"
 )
 
@@ -13,13 +13,15 @@ const TypeCertificateCA string = "certificate_ca"
 type CertificateCA struct {
 	types.ResourceBase `hcl:",remain"`
 
+	Lifecycle *lifecycle.Lifecycle `hcl:"lifecycle,block" json:"lifecycle,omitempty"`
+
 	// Output directory to write the certificate and key too
 	Output string `hcl:"output" json:"output"`
 
 	// output parameters
 
 	// Key is the value related to the certificate key
-	PrivateKey File `hcl:"private_key,optional" json:"private_key"`
+	PrivateKey File `hcl:"private_key,optional" json:"private_key" sensitive:"Contents"`
 
 	// Key is the value related to the certificate key
 	PublicKeyPEM File `hcl:"public_key_pem,optional" json:"public_key_pem"`
@@ -36,24 +38,17 @@ func (c *CertificateCA) Process() error {
 	c.PublicKeyPEM = File{}
 	c.Cert = File{}
 
-	// do we have an existing resource in the state?
-	// if so we need to set any computed resources for dependents
-	cfg, err := config.LoadState()
-	if err == nil {
-		// try and find the resource in the state
-		r, _ := cfg.FindResource(c.Meta.ID)
-		if r != nil {
-			kstate := r.(*CertificateCA)
-			c.PrivateKey = kstate.PrivateKey
-			c.PublicKeySSH = kstate.PublicKeySSH
-			c.PublicKeyPEM = kstate.PublicKeyPEM
-			c.Cert = kstate.Cert
-		}
-	}
-
 	return nil
 }
 
+func (c *CertificateCA) RestoreState(r types.Resource) {
+	kstate := r.(*CertificateCA)
+	c.PrivateKey = kstate.PrivateKey
+	c.PublicKeySSH = kstate.PublicKeySSH
+	c.PublicKeyPEM = kstate.PublicKeyPEM
+	c.Cert = kstate.Cert
+}
+
 // TypeCertificateCA is the resource string for a self-signed CA
 const TypeCertificateLeaf string = "certificate_leaf"
 
@@ -61,6 +56,8 @@ const TypeCertificateLeaf string = "certificate_leaf"
 type CertificateLeaf struct {
 	types.ResourceBase `hcl:",remain"`
 
+	Lifecycle *lifecycle.Lifecycle `hcl:"lifecycle,block" json:"lifecycle,omitempty"`
+
 	CAKey  string `hcl:"ca_key" json:"ca_key"`   // Path to the primary key for the root CA
 	CACert string `hcl:"ca_cert" json:"ca_cert"` // Path to the root CA
 
@@ -72,7 +69,7 @@ type CertificateLeaf struct {
 	// output parameters
 
 	// Key is the value related to the certificate key
-	PrivateKey File `hcl:"private_key,optional" json:"private_key"`
+	PrivateKey File `hcl:"private_key,optional" json:"private_key" sensitive:"Contents"`
 
 	// Key is the value related to the certificate key
 	PublicKeyPEM File `hcl:"public_key_pem,optional" json:"public_key_pem"`
@@ -91,24 +88,17 @@ func (c *CertificateLeaf) Process() error {
 	c.PublicKeyPEM = File{}
 	c.Cert = File{}
 
-	// do we have an existing resource in the state?
-	// if so we need to set any computed resources for dependents
-	cfg, err := config.LoadState()
-	if err == nil {
-		// try and find the resource in the state
-		r, _ := cfg.FindResource(c.Meta.ID)
-		if r != nil {
-			kstate := r.(*CertificateLeaf)
-			c.PrivateKey = kstate.PrivateKey
-			c.PublicKeySSH = kstate.PublicKeySSH
-			c.PublicKeyPEM = kstate.PublicKeyPEM
-			c.Cert = kstate.Cert
-		}
-	}
-
 	return nil
 }
 
+func (c *CertificateLeaf) RestoreState(r types.Resource) {
+	kstate := r.(*CertificateLeaf)
+	c.PrivateKey = kstate.PrivateKey
+	c.PublicKeySSH = kstate.PublicKeySSH
+	c.PublicKeyPEM = kstate.PublicKeyPEM
+	c.Cert = kstate.Cert
+}
+
 type File struct {
 	Filename  string `hcl:"filename,optional" json:"filename"`
 	Directory string `hcl:"directory,optional" json:"directory"`
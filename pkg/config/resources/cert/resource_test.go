@@ -71,6 +71,10 @@ func TestCertCALoadsValuesFromState(t *testing.T) {
 	err := ca.Process()
 	require.NoError(t, err)
 
+	sf, err := config.LoadState()
+	require.NoError(t, err)
+	config.RestoreState(ca, sf)
+
 	require.Equal(t, "private.key", ca.PrivateKey.Filename)
 	require.Equal(t, "public.key", ca.PublicKeyPEM.Filename)
 	require.Equal(t, "public.ssh", ca.PublicKeySSH.Filename)
@@ -136,6 +140,10 @@ func TestCertLeafLoadsValuesFromState(t *testing.T) {
 	err := ca.Process()
 	require.NoError(t, err)
 
+	sf, err := config.LoadState()
+	require.NoError(t, err)
+	config.RestoreState(ca, sf)
+
 	require.Equal(t, "private.key", ca.PrivateKey.Filename)
 	require.Equal(t, "cert.pem", ca.Cert.Filename)
 }
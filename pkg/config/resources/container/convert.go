@@ -1,6 +1,10 @@
 package container
 
-import "github.com/jumppad-labs/jumppad/pkg/clients/container/types"
+import (
+	"strings"
+
+	"github.com/jumppad-labs/jumppad/pkg/clients/container/types"
+)
 
 func (i Image) ToClientImage() types.Image {
 	return types.Image{
@@ -38,6 +42,31 @@ func (n NetworkAttachments) ToClientNetworkAttachments() []types.NetworkAttachme
 	return nets
 }
 
+// ToStartNetworkAttachments returns the client network attachments used to
+// start a stopped container, each network is attached with the address that
+// was assigned when the container was created
+func (n NetworkAttachments) ToStartNetworkAttachments() []types.NetworkAttachment {
+	nets := []types.NetworkAttachment{}
+	for _, net := range n {
+		addr := net.AssignedAddress
+		if addr == "" {
+			addr = net.IPAddress
+		}
+
+		// clusters record the address with the netmask
+		addr, _, _ = strings.Cut(addr, "/")
+
+		nets = append(nets, types.NetworkAttachment{
+			ID:        net.ID,
+			Name:      net.Name,
+			IPAddress: addr,
+			Aliases:   net.Aliases,
+		})
+	}
+
+	return nets
+}
+
 func (v Volume) ToClientVolume() types.Volume {
 	return types.Volume{
 		Source:                      v.Source,
@@ -6,15 +6,20 @@ import (
 	"fmt"
 	"os"
 	"path"
+	"slices"
 	"strings"
 	"time"
 
+	dcontainer "github.com/docker/docker/api/types/container"
+	"github.com/jumppad-labs/hclconfig/resources"
 	htypes "github.com/jumppad-labs/hclconfig/types"
 	"github.com/jumppad-labs/jumppad/pkg/clients"
 	"github.com/jumppad-labs/jumppad/pkg/clients/container"
 	"github.com/jumppad-labs/jumppad/pkg/clients/container/types"
 	"github.com/jumppad-labs/jumppad/pkg/clients/http"
 	"github.com/jumppad-labs/jumppad/pkg/clients/logger"
+	"github.com/jumppad-labs/jumppad/pkg/jumppad/events"
+	"github.com/jumppad-labs/jumppad/pkg/jumppad/tracing"
 	"github.com/jumppad-labs/jumppad/pkg/utils"
 	sdk "github.com/jumppad-labs/plugin-sdk"
 )
@@ -26,6 +31,10 @@ type Provider struct {
 	client     container.ContainerTasks
 	httpClient http.HTTP
 	log        logger.Logger
+	env        utils.Environment
+
+	// snapshot is set when the container is restored from a snapshot
+	snapshot *container.Snapshot
 }
 
 func (p *Provider) Init(cfg htypes.Resource, l sdk.Logger) error {
@@ -34,9 +43,14 @@ func (p *Provider) Init(cfg htypes.Resource, l sdk.Logger) error {
 		return err
 	}
 
+	return p.InitWithClients(cfg, l, cli)
+}
+
+func (p *Provider) InitWithClients(cfg htypes.Resource, l sdk.Logger, cli *clients.Clients) error {
 	p.client = cli.ContainerTasks
 	p.httpClient = cli.HTTP
 	p.log = l
+	p.env = cli.Environment
 
 	cs, sok := cfg.(*Sidecar)
 	if sok {
@@ -55,6 +69,13 @@ func (p *Provider) Init(cfg htypes.Resource, l sdk.Logger) error {
 		co.Privileged = cs.Privileged
 		co.Resources = cs.Resources
 		co.MaxRestartCount = cs.MaxRestartCount
+		co.Init = cs.Init
+		co.ReadOnly = cs.ReadOnly
+		co.ShmSize = cs.ShmSize
+		co.Sysctls = cs.Sysctls
+		co.Tmpfs = cs.Tmpfs
+		co.Ulimits = cs.Ulimits
+		co.Devices = cs.Devices
 
 		p.sidecar = cs
 		p.config = co
@@ -133,6 +154,184 @@ func (c *Provider) Destroy(ctx context.Context, force bool) error {
 	return c.internalDestroy(ctx, force)
 }
 
+// Drifted checks that the container exists, is running and is attached to
+// the networks defined in the config
+func (c *Provider) Drifted(ctx context.Context) ([]string, error) {
+	ids, err := c.Lookup()
+	if err != nil {
+		return nil, err
+	}
+
+	if len(ids) == 0 {
+		return []string{fmt.Sprintf("container %s does not exist", c.config.ContainerName)}, nil
+	}
+
+	drift := []string{}
+
+	info, err := c.client.ContainerInfo(ids[0])
+	if err != nil {
+		return nil, err
+	}
+
+	if ci, ok := info.(dcontainer.InspectResponse); ok && ci.ContainerJSONBase != nil && ci.State != nil && !ci.State.Running {
+		drift = append(drift, fmt.Sprintf("container %s is not running, status: %s", c.config.ContainerName, ci.State.Status))
+	}
+
+	// sidecars share the network of the target container
+	if c.sidecar != nil {
+		return drift, nil
+	}
+
+	attached := c.client.ListNetworks(ids[0])
+
+	for _, n := range c.config.Networks {
+		if !slices.ContainsFunc(attached, func(a types.NetworkAttachment) bool { return isAttachment(a, n) }) {
+			drift = append(drift, fmt.Sprintf("container %s is not attached to network %s", c.config.ContainerName, n.ID))
+		}
+	}
+
+	return drift, nil
+}
+
+// Stop stops the container without removing it
+func (c *Provider) Stop(ctx context.Context) error {
+	if ctx.Err() != nil {
+		c.log.Debug("Context cancelled, skipping container stop", "ref", c.config.Meta.ID)
+		return nil
+	}
+
+	c.log.Info("Stop Container", "ref", c.config.Meta.ID)
+
+	ids, err := c.Lookup()
+	if err != nil {
+		return err
+	}
+
+	for _, id := range ids {
+		err := c.client.StopContainer(id, false)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// Start starts the stopped container with the addresses it was assigned when
+// it was created, once started the health checks must pass
+func (c *Provider) Start(ctx context.Context) error {
+	if ctx.Err() != nil {
+		c.log.Debug("Context cancelled, skipping container start", "ref", c.config.Meta.ID)
+		return nil
+	}
+
+	c.log.Info("Start Container", "ref", c.config.Meta.ID)
+
+	ids, err := c.Lookup()
+	if err != nil {
+		return err
+	}
+
+	if len(ids) == 0 {
+		return fmt.Errorf("container %s does not exist", c.config.ContainerName)
+	}
+
+	// sidecars share the network of the target container
+	nets := []types.NetworkAttachment{}
+	if c.sidecar == nil {
+		nets = NetworkAttachments(c.config.Networks).ToStartNetworkAttachments()
+	}
+
+	for _, id := range ids {
+		err := c.client.StartContainer(id, nets)
+		if err != nil {
+			return err
+		}
+	}
+
+	return c.runHealthChecks(ctx, ids[0])
+}
+
+// Snapshot commits the container to an image and archives its volumes
+func (c *Provider) Snapshot(ctx context.Context, s *container.Snapshot) error {
+	if ctx.Err() != nil {
+		c.log.Debug("Context cancelled, skipping container snapshot", "ref", c.config.Meta.ID)
+		return nil
+	}
+
+	c.log.Info("Snapshot Container", "ref", c.config.Meta.ID)
+
+	return s.Save(c.client, c.config.ContainerName)
+}
+
+// Restore creates the container from the image and volumes saved to the
+// snapshot, the container is attached to the networks with the addresses
+// that it was assigned when the snapshot was taken
+func (c *Provider) Restore(ctx context.Context, s *container.Snapshot) error {
+	if ctx.Err() != nil {
+		c.log.Debug("Context cancelled, skipping container restore", "ref", c.config.Meta.ID)
+		return nil
+	}
+
+	c.log.Info("Restore Container", "ref", c.config.Meta.ID)
+
+	c.snapshot = s
+
+	err := c.internalCreate(ctx, c.sidecar != nil)
+	if err != nil {
+		return err
+	}
+
+	if c.sidecar != nil {
+		c.sidecar.ContainerName = c.config.ContainerName
+	}
+
+	return nil
+}
+
+// Import adopts an existing Docker container, the name, image id and the
+// addresses of the configured networks are read from the running container
+func (c *Provider) Import(ctx context.Context, id string) error {
+	info, err := c.client.ContainerInfo(id)
+	if err != nil {
+		return err
+	}
+
+	ci, ok := info.(dcontainer.InspectResponse)
+	if !ok || ci.ContainerJSONBase == nil {
+		return fmt.Errorf("unable to read information about container %s", id)
+	}
+
+	c.log.Info("Importing Container", "ref", c.config.Meta.ID, "container", ci.Name)
+
+	c.config.ContainerName = strings.TrimPrefix(ci.Name, "/")
+	c.config.Image.ID = ci.Image
+
+	if c.sidecar != nil {
+		c.sidecar.ContainerName = c.config.ContainerName
+		c.sidecar.Image.ID = c.config.Image.ID
+	} else {
+		c.setAssignedAddresses(ci.ID)
+
+		for _, n := range c.config.Networks {
+			if n.AssignedAddress == "" {
+				c.log.Warn("Imported container is not attached to network", "ref", c.config.Meta.ID, "network", n.ID)
+			}
+		}
+	}
+
+	changed, err := c.Changed()
+	if err != nil {
+		return err
+	}
+
+	if changed {
+		c.log.Warn("Image of imported container does not match the configuration, the container will be re-created by the next apply", "ref", c.config.Meta.ID, "image", c.config.Image.Name)
+	}
+
+	return nil
+}
+
 func (c *Provider) Changed() (bool, error) {
 	// has the image id changed
 	id, err := c.client.FindImageInLocalRegistry(types.Image{Name: c.config.Image.Name})
@@ -153,7 +352,7 @@ func (c *Provider) Changed() (bool, error) {
 
 func (c *Provider) internalCreate(ctx context.Context, sidecar bool) error {
 	// set the fqdn
-	fqdn := utils.FQDN(c.config.Meta.Name, c.config.Meta.Module, c.config.Meta.Type)
+	fqdn := c.env.FQDN(c.config.Meta.Name, c.config.Meta.Module, c.config.Meta.Type)
 	c.config.ContainerName = fqdn
 
 	// pull any images needed for this container
@@ -163,22 +362,28 @@ func (c *Provider) internalCreate(ctx context.Context, sidecar bool) error {
 		Password: c.config.Image.Password,
 	}
 
-	err := c.client.PullImage(img, false)
-	if err != nil {
-		c.log.Error("Error pulling container image", "ref", c.config.Meta.ID, "image", c.config.Image.Name)
+	// containers restored from a snapshot use the committed image, the image
+	// id is kept from the state
+	if c.snapshot == nil {
+		err := tracing.Trace(ctx, "PullImage", func() error {
+			return c.client.PullImage(img, false)
+		}, tracing.Images(img.Name))
+		if err != nil {
+			c.log.Error("Error pulling container image", "ref", c.config.Meta.ID, "image", c.config.Image.Name)
 
-		return err
-	}
+			return err
+		}
 
-	// update the image ID
-	id, err := c.client.FindImageInLocalRegistry(img)
-	if err != nil {
-		c.log.Error("Unable to lookup image in local registry", "ref", c.config.Meta.ID, "error", err)
-		return err
-	}
+		// update the image ID
+		id, err := c.client.FindImageInLocalRegistry(img)
+		if err != nil {
+			c.log.Error("Unable to lookup image in local registry", "ref", c.config.Meta.ID, "error", err)
+			return err
+		}
 
-	// id should never be blank here as we have pulled the image
-	c.config.Image.ID = id
+		// id should never be blank here as we have pulled the image
+		c.config.Image.ID = id
+	}
 
 	new := types.Container{
 		Name:            fqdn,
@@ -190,13 +395,23 @@ func (c *Provider) internalCreate(ctx context.Context, sidecar bool) error {
 		DNS:             c.config.DNS,
 		Privileged:      c.config.Privileged,
 		MaxRestartCount: c.config.MaxRestartCount,
+		Init:            c.config.Init,
+		ReadOnly:        c.config.ReadOnly,
+		ShmSize:         c.config.ShmSize,
+		Sysctls:         c.config.Sysctls,
+		ExtraHosts:      c.config.ExtraHosts,
 	}
 
 	for _, v := range c.config.Networks {
+		ip := v.IPAddress
+		if c.snapshot != nil && v.AssignedAddress != "" {
+			ip = v.AssignedAddress
+		}
+
 		new.Networks = append(new.Networks, types.NetworkAttachment{
 			ID:          v.ID,
 			Name:        v.Name,
-			IPAddress:   v.IPAddress,
+			IPAddress:   ip,
 			Aliases:     v.Aliases,
 			IsContainer: sidecar,
 		})
@@ -232,6 +447,29 @@ func (c *Provider) internalCreate(ctx context.Context, sidecar bool) error {
 		})
 	}
 
+	for _, t := range c.config.Tmpfs {
+		new.Tmpfs = append(new.Tmpfs, types.Tmpfs{
+			Destination: t.Destination,
+			Options:     t.Options,
+		})
+	}
+
+	for _, u := range c.config.Ulimits {
+		new.Ulimits = append(new.Ulimits, types.Ulimit{
+			Name: u.Name,
+			Soft: u.Soft,
+			Hard: u.Hard,
+		})
+	}
+
+	for _, d := range c.config.Devices {
+		new.Devices = append(new.Devices, types.Device{
+			Source:      d.Source,
+			Destination: d.Destination,
+			Permissions: d.Permissions,
+		})
+	}
+
 	if c.config.Capabilities != nil {
 		new.Capabilities = &types.Capabilities{
 			Add:  c.config.Capabilities.Add,
@@ -261,27 +499,27 @@ func (c *Provider) internalCreate(ctx context.Context, sidecar bool) error {
 		}
 	}
 
-	id, err = c.client.CreateContainer(&new)
+	if c.snapshot != nil {
+		err := c.snapshot.Restore(&new)
+		if err != nil {
+			return err
+		}
+	}
+
+	id, err := c.client.CreateContainer(&new)
 	if err != nil {
 		c.log.Error("Unable to create container", "ref", c.config.Meta.ID, "error", err)
 		return err
 	}
 
 	// get the assigned ip addresses for the container
-	dc := c.client.ListNetworks(id)
-	for _, n := range dc {
-		for i, net := range c.config.Networks {
-			if net.ID == n.ID {
-				// remove the netmask
-				ip, _, _ := strings.Cut(n.IPAddress, "/")
+	c.setAssignedAddresses(id)
 
-				// set the assigned address and name
-				c.config.Networks[i].AssignedAddress = ip
-				c.config.Networks[i].Name = n.Name
-			}
-		}
-	}
+	return c.runHealthChecks(ctx, id)
+}
 
+// runHealthChecks waits for the health checks defined in the config to pass
+func (c *Provider) runHealthChecks(ctx context.Context, id string) error {
 	if c.config.HealthCheck == nil {
 		return nil
 	}
@@ -297,6 +535,8 @@ func (c *Provider) internalCreate(ctx context.Context, sidecar bool) error {
 
 	// execute tcp health checks
 	for _, hc := range c.config.HealthCheck.TCP {
+		c.healthCheckEvent(ctx, fmt.Sprintf("waiting for TCP health check %s", hc.Address))
+
 		err := c.httpClient.HealthCheckTCP(
 			hc.Address,
 			timeout,
@@ -305,10 +545,14 @@ func (c *Provider) internalCreate(ctx context.Context, sidecar bool) error {
 		if err != nil {
 			return err
 		}
+
+		c.healthCheckEvent(ctx, fmt.Sprintf("TCP health check %s passed", hc.Address))
 	}
 
 	// execute http health checks
 	for _, hc := range c.config.HealthCheck.HTTP {
+		c.healthCheckEvent(ctx, fmt.Sprintf("waiting for HTTP health check %s", hc.Address))
+
 		err := c.httpClient.HealthCheckHTTP(
 			hc.Address,
 			hc.Method,
@@ -321,22 +565,65 @@ func (c *Provider) internalCreate(ctx context.Context, sidecar bool) error {
 		if err != nil {
 			return err
 		}
+
+		c.healthCheckEvent(ctx, fmt.Sprintf("HTTP health check %s passed", hc.Address))
 	}
 
 	for _, hc := range c.config.HealthCheck.Exec {
+		c.healthCheckEvent(ctx, "waiting for Exec health check")
+
 		err := c.runExecHealthCheck(ctx, id, hc.Command, hc.Script, hc.ExitCode, timeout)
 		if err != nil {
 			return err
 		}
+
+		c.healthCheckEvent(ctx, "Exec health check passed")
 	}
 
 	return nil
 }
 
+// setAssignedAddresses sets the address and name of each configured network
+// from the networks that the container with the given id is attached to
+func (c *Provider) setAssignedAddresses(id string) {
+	for _, n := range c.client.ListNetworks(id) {
+		for i, net := range c.config.Networks {
+			if isAttachment(n, net) {
+				// remove the netmask
+				ip, _, _ := strings.Cut(n.IPAddress, "/")
+
+				// set the assigned address and name
+				c.config.Networks[i].AssignedAddress = ip
+				c.config.Networks[i].Name = n.Name
+			}
+		}
+	}
+}
+
+// isAttachment returns true when the container network n is the network of
+// the attachment, networks that were not created by jumppad have no id label
+// and are matched with the name of the network resource
+func isAttachment(n types.NetworkAttachment, attachment NetworkAttachment) bool {
+	if n.ID != "" {
+		return n.ID == attachment.ID
+	}
+
+	fqrn, err := resources.ParseFQRN(attachment.ID)
+	if err != nil {
+		return false
+	}
+
+	return fqrn.Resource != "" && fqrn.Resource == n.Name
+}
+
+func (c *Provider) healthCheckEvent(ctx context.Context, message string) {
+	events.HealthCheck(ctx, c.config.Meta.ID, c.config.Meta.Type, message)
+}
+
 func (c *Provider) runExecHealthCheck(ctx context.Context, id string, command []string, script string, exitCode int, timeout time.Duration) error {
 	if len(script) > 0 {
 		// write the script to a temp file
-		dir, err := os.MkdirTemp(utils.JumppadTemp(), "script*")
+		dir, err := os.MkdirTemp(c.env.JumppadTemp(), "script*")
 		if err != nil {
 			return fmt.Errorf("unable to create temporary directory for script: %s", err)
 		}
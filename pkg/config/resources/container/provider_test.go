@@ -6,12 +6,16 @@ import (
 	"testing"
 	"time"
 
+	dcontainer "github.com/docker/docker/api/types/container"
 	"github.com/jumppad-labs/hclconfig/types"
+	"github.com/jumppad-labs/jumppad/pkg/clients"
+	"github.com/jumppad-labs/jumppad/pkg/clients/container"
 	"github.com/jumppad-labs/jumppad/pkg/clients/container/mocks"
 	ctypes "github.com/jumppad-labs/jumppad/pkg/clients/container/types"
 	hmocks "github.com/jumppad-labs/jumppad/pkg/clients/http/mocks"
 	"github.com/jumppad-labs/jumppad/pkg/clients/logger"
 	"github.com/jumppad-labs/jumppad/pkg/config/resources/healthcheck"
+	"github.com/jumppad-labs/jumppad/pkg/utils"
 	"github.com/jumppad-labs/jumppad/testutils"
 	"github.com/stretchr/testify/mock"
 	assert "github.com/stretchr/testify/require"
@@ -45,7 +49,7 @@ func setupContainerTests(t *testing.T) (*Container, *mocks.ContainerTasks, *hmoc
 func TestContainerCreatesSuccessfully(t *testing.T) {
 	cc, md, hc := setupContainerTests(t)
 
-	c := Provider{cc, nil, md, hc, logger.NewTestLogger(t)}
+	c := Provider{config: cc, client: md, httpClient: hc, log: logger.NewTestLogger(t)}
 
 	err := c.Create(context.Background())
 	assert.NoError(t, err)
@@ -106,6 +110,63 @@ func TestContainerSidecarCreatesContainerSuccessfully(t *testing.T) {
 	assert.Equal(t, cs.MaxRestartCount, ac.MaxRestartCount)
 }
 
+func TestContainerCreatesWithRuntimeOptions(t *testing.T) {
+	cc, md, hc := setupContainerTests(t)
+	cc.Init = true
+	cc.ReadOnly = true
+	cc.ShmSize = 256
+	cc.Sysctls = map[string]string{"net.ipv4.ip_forward": "1"}
+	cc.ExtraHosts = map[string]string{"db.local": "10.0.0.2"}
+	cc.Tmpfs = []Tmpfs{{Destination: "/run", Options: []string{"size=64m"}}}
+	cc.Ulimits = []Ulimit{{Name: "nofile", Soft: 1024, Hard: 65536}}
+	cc.Devices = []Device{{Source: "/dev/fuse"}}
+
+	c := Provider{config: cc, client: md, httpClient: hc, log: logger.NewTestLogger(t)}
+
+	err := c.Create(context.Background())
+	assert.NoError(t, err)
+
+	ac := testutils.GetCalls(&md.Mock, "CreateContainer")[0].Arguments[0].(*ctypes.Container)
+
+	assert.True(t, ac.Init)
+	assert.True(t, ac.ReadOnly)
+	assert.Equal(t, 256, ac.ShmSize)
+	assert.Equal(t, cc.Sysctls, ac.Sysctls)
+	assert.Equal(t, cc.ExtraHosts, ac.ExtraHosts)
+	assert.Equal(t, []ctypes.Tmpfs{{Destination: "/run", Options: []string{"size=64m"}}}, ac.Tmpfs)
+	assert.Equal(t, []ctypes.Ulimit{{Name: "nofile", Soft: 1024, Hard: 65536}}, ac.Ulimits)
+	assert.Equal(t, []ctypes.Device{{Source: "/dev/fuse"}}, ac.Devices)
+}
+
+func TestContainerSidecarInitCopiesRuntimeOptions(t *testing.T) {
+	c, md, hc := setupContainerTests(t)
+
+	cs := &Sidecar{ResourceBase: types.ResourceBase{
+		Meta: types.Meta{Name: "tests", Type: TypeSidecar},
+	}}
+
+	cs.Target = *c
+	cs.Init = true
+	cs.ReadOnly = true
+	cs.ShmSize = 128
+	cs.Sysctls = map[string]string{"net.core.somaxconn": "1024"}
+	cs.Tmpfs = []Tmpfs{{Destination: "/tmp"}}
+	cs.Ulimits = []Ulimit{{Name: "nproc", Soft: 512, Hard: 512}}
+	cs.Devices = []Device{{Source: "/dev/fuse"}}
+
+	p := Provider{}
+	err := p.InitWithClients(cs, logger.NewTestLogger(t), &clients.Clients{ContainerTasks: md, HTTP: hc})
+	assert.NoError(t, err)
+
+	assert.Equal(t, cs.Init, p.config.Init)
+	assert.Equal(t, cs.ReadOnly, p.config.ReadOnly)
+	assert.Equal(t, cs.ShmSize, p.config.ShmSize)
+	assert.Equal(t, cs.Sysctls, p.config.Sysctls)
+	assert.Equal(t, cs.Tmpfs, p.config.Tmpfs)
+	assert.Equal(t, cs.Ulimits, p.config.Ulimits)
+	assert.Equal(t, cs.Devices, p.config.Devices)
+}
+
 func TestContainerRunsHTTPChecks(t *testing.T) {
 	cc, md, hc := setupContainerTests(t)
 	cc.HealthCheck = &healthcheck.HealthCheckContainer{
@@ -240,6 +301,112 @@ func TestContainerDoesNotDestroysWhenLookupError(t *testing.T) {
 	md.AssertNotCalled(t, "RemoveContainer")
 }
 
+func TestContainerStopsContainer(t *testing.T) {
+	cc, md, hc := setupContainerTests(t)
+	p := Provider{config: cc, client: md, httpClient: hc, log: logger.NewTestLogger(t)}
+
+	md.On("FindContainerIDs", cc.ContainerName).Return([]string{"abc"}, nil)
+	md.On("StopContainer", "abc", false).Return(nil)
+
+	err := p.Stop(context.Background())
+	assert.NoError(t, err)
+	md.AssertCalled(t, "StopContainer", "abc", false)
+}
+
+func TestContainerStartsContainerWithAssignedAddresses(t *testing.T) {
+	cc, md, hc := setupContainerTests(t)
+	cc.Networks = []NetworkAttachment{
+		{ID: "resource.network.cloud", AssignedAddress: "10.6.0.5"},
+		{ID: "resource.network.main", IPAddress: "10.7.0.3"},
+	}
+	p := Provider{config: cc, client: md, httpClient: hc, log: logger.NewTestLogger(t)}
+
+	md.On("FindContainerIDs", cc.ContainerName).Return([]string{"abc"}, nil)
+	md.On("StartContainer", "abc", mock.Anything).Return(nil)
+
+	err := p.Start(context.Background())
+	assert.NoError(t, err)
+
+	md.AssertCalled(t, "StartContainer", "abc", []ctypes.NetworkAttachment{
+		{ID: "resource.network.cloud", IPAddress: "10.6.0.5"},
+		{ID: "resource.network.main", IPAddress: "10.7.0.3"},
+	})
+}
+
+func TestContainerStartReturnsErrorWhenNotExists(t *testing.T) {
+	cc, md, hc := setupContainerTests(t)
+	p := Provider{config: cc, client: md, httpClient: hc, log: logger.NewTestLogger(t)}
+
+	md.On("FindContainerIDs", cc.ContainerName).Return(nil, nil)
+
+	err := p.Start(context.Background())
+	assert.Error(t, err)
+	md.AssertNotCalled(t, "StartContainer", mock.Anything, mock.Anything)
+}
+
+func TestContainerStartsSidecarWithoutNetworks(t *testing.T) {
+	cc, md, hc := setupContainerTests(t)
+	cc.Networks = []NetworkAttachment{{ID: "target", AssignedAddress: "10.6.0.5"}}
+	p := Provider{config: cc, sidecar: &Sidecar{}, client: md, httpClient: hc, log: logger.NewTestLogger(t)}
+
+	md.On("FindContainerIDs", cc.ContainerName).Return([]string{"abc"}, nil)
+	md.On("StartContainer", "abc", mock.Anything).Return(nil)
+
+	err := p.Start(context.Background())
+	assert.NoError(t, err)
+	md.AssertCalled(t, "StartContainer", "abc", []ctypes.NetworkAttachment{})
+}
+
+func TestContainerSnapshotSavesContainer(t *testing.T) {
+	cc, md, hc := setupContainerTests(t)
+	cc.ContainerName = "tests.container.local.jmpd.in"
+	p := Provider{config: cc, client: md, httpClient: hc, log: logger.NewTestLogger(t)}
+
+	md.On("FindContainerIDs", cc.ContainerName).Return([]string{"abc"}, nil)
+	md.On("CommitContainer", "abc", mock.Anything).Return("sha256:123", nil)
+	md.On("ExportVolumes", "abc", mock.Anything).Return([]string{"/data"}, nil)
+
+	s := container.NewSnapshot("test", t.TempDir())
+
+	err := p.Snapshot(context.Background(), s)
+	assert.NoError(t, err)
+
+	md.AssertCalled(t, "CommitContainer", "abc", "jumppad.dev/snapshot/tests.container.local.jmpd.in:test")
+	assert.Equal(t, container.SnapshotContainer{Image: s.ImageName(cc.ContainerName), Volumes: true}, s.Containers[cc.ContainerName])
+}
+
+func TestContainerRestoresFromSnapshotWithAssignedAddresses(t *testing.T) {
+	cc, md, hc := setupContainerTests(t)
+	cc.Image.ID = "myimage"
+	cc.Networks = []NetworkAttachment{{ID: "resource.network.cloud", AssignedAddress: "10.6.0.5"}}
+	p := Provider{config: cc, client: md, httpClient: hc, log: logger.NewTestLogger(t)}
+
+	name := utils.FQDN("tests", "", TypeContainer)
+	s := container.NewSnapshot("test", t.TempDir())
+	s.Containers[name] = container.SnapshotContainer{Image: s.ImageName(name), Volumes: true}
+
+	err := p.Restore(context.Background(), s)
+	assert.NoError(t, err)
+
+	md.AssertNotCalled(t, "PullImage", mock.Anything, mock.Anything)
+
+	params := testutils.GetCalls(&md.Mock, "CreateContainer")[0].Arguments[0].(*ctypes.Container)
+	assert.Equal(t, s.ImageName(name), params.Image.Name)
+	assert.Equal(t, s.VolumeArchive(name), params.VolumeArchive)
+	assert.Equal(t, "10.6.0.5", params.Networks[0].IPAddress)
+	assert.Equal(t, "myimage", cc.Image.ID)
+}
+
+func TestContainerRestoreReturnsErrorWhenNotInSnapshot(t *testing.T) {
+	cc, md, hc := setupContainerTests(t)
+	p := Provider{config: cc, client: md, httpClient: hc, log: logger.NewTestLogger(t)}
+
+	err := p.Restore(context.Background(), container.NewSnapshot("test", t.TempDir()))
+	assert.Error(t, err)
+
+	md.AssertNotCalled(t, "CreateContainer", mock.Anything)
+}
+
 func TestContainerLooksupIDs(t *testing.T) {
 	cc, md, hc := setupContainerTests(t)
 	cc.Networks = []NetworkAttachment{NetworkAttachment{Name: "cloud"}}
@@ -275,3 +442,42 @@ func TestContainerAddsResources(t *testing.T) {
 	assert.Equal(t, "nvidia", ac.Resources.GPU.Driver)
 	assert.Equal(t, []string{"1"}, ac.Resources.GPU.DeviceIDs)
 }
+
+func TestContainerImportSetsComputedFields(t *testing.T) {
+	cc, md, hc := setupContainerTests(t)
+	cc.Networks = []NetworkAttachment{{ID: "resource.network.main"}, {ID: "resource.network.cloud"}}
+
+	md.On("ContainerInfo", "postgres").Return(dcontainer.InspectResponse{
+		ContainerJSONBase: &dcontainer.ContainerJSONBase{ID: "abc", Name: "/postgres", Image: "myimage"},
+	}, nil)
+
+	// networks created outside of jumppad do not have the id label
+	testutils.RemoveOn(&md.Mock, "ListNetworks")
+	md.On("ListNetworks", "abc").Return([]ctypes.NetworkAttachment{
+		{Name: "main", IPAddress: "10.0.0.2/16"},
+		{ID: "resource.network.cloud", Name: "cloud", IPAddress: "10.5.0.3/16"},
+	})
+
+	p := Provider{config: cc, client: md, httpClient: hc, log: logger.NewTestLogger(t)}
+
+	err := p.Import(context.Background(), "postgres")
+	assert.NoError(t, err)
+
+	assert.Equal(t, "postgres", cc.ContainerName)
+	assert.Equal(t, "myimage", cc.Image.ID)
+	assert.Equal(t, "10.0.0.2", cc.Networks[0].AssignedAddress)
+	assert.Equal(t, "main", cc.Networks[0].Name)
+	assert.Equal(t, "10.5.0.3", cc.Networks[1].AssignedAddress)
+
+	md.AssertNotCalled(t, "CreateContainer", mock.Anything)
+}
+
+func TestContainerImportReturnsErrorWhenNotFound(t *testing.T) {
+	cc, md, hc := setupContainerTests(t)
+	md.On("ContainerInfo", "postgres").Return(nil, fmt.Errorf("boom"))
+
+	p := Provider{config: cc, client: md, httpClient: hc, log: logger.NewTestLogger(t)}
+
+	err := p.Import(context.Background(), "postgres")
+	assert.Error(t, err)
+}
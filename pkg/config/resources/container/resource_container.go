@@ -4,8 +4,8 @@ import (
 	"strings"
 
 	"github.com/jumppad-labs/hclconfig/types"
-	"github.com/jumppad-labs/jumppad/pkg/config"
 	"github.com/jumppad-labs/jumppad/pkg/config/resources/healthcheck"
+	"github.com/jumppad-labs/jumppad/pkg/config/resources/lifecycle"
 	"github.com/jumppad-labs/jumppad/pkg/utils"
 )
 
@@ -17,6 +17,8 @@ type Container struct {
 	// embedded type holding name, etc
 	types.ResourceBase `hcl:",remain"`
 
+	Lifecycle *lifecycle.Lifecycle `hcl:"lifecycle,block" json:"lifecycle,omitempty"`
+
 	Networks        []NetworkAttachment `hcl:"network,block" json:"networks,omitempty"`           // Attach to the correct network // only when Image is specified
 	Image           Image               `hcl:"image,block" json:"image"`                          // Image to use for the container
 	Entrypoint      []string            `hcl:"entrypoint,optional" json:"entrypoint,omitempty"`   // Entrypoint to use when starting the container
@@ -40,6 +42,16 @@ type Container struct {
 	// User block for mapping the user id and group id inside the container
 	RunAs *User `hcl:"run_as,block" json:"run_as,omitempty"`
 
+	// runtime options
+	Init       bool              `hcl:"init,optional" json:"init,omitempty"`               // Run an init process as PID 1 that reaps zombie processes
+	ReadOnly   bool              `hcl:"read_only,optional" json:"read_only,omitempty"`     // Mount the root filesystem of the container as read only
+	ShmSize    int               `hcl:"shm_size,optional" json:"shm_size,omitempty"`       // Size of /dev/shm in MB
+	Sysctls    map[string]string `hcl:"sysctls,optional" json:"sysctls,omitempty"`         // Namespaced kernel parameters to set in the container
+	ExtraHosts map[string]string `hcl:"extra_hosts,optional" json:"extra_hosts,omitempty"` // Hostname to IP address mappings added to /etc/hosts
+	Tmpfs      []Tmpfs           `hcl:"tmpfs,block" json:"tmpfs,omitempty"`                // tmpfs filesystems to mount in the container
+	Ulimits    []Ulimit          `hcl:"ulimit,block" json:"ulimits,omitempty"`             // Resource limits such as the number of open files
+	Devices    []Device          `hcl:"device,block" json:"devices,omitempty"`             // Host devices to add to the container
+
 	// Output parameters
 
 	// ContainerName is the fully qualified domain name for the container, this can be used
@@ -118,31 +130,26 @@ func (c *Container) Process() error {
 		}
 	}
 
-	// do we have an existing resource in the state?
-	// if so we need to set any computed resources for dependents
-	cfg, err := config.LoadState()
-	if err == nil {
-		// try and find the resource in the state
-		r, _ := cfg.FindResource(c.Meta.ID)
-		if r != nil {
-			kstate := r.(*Container)
-			c.ContainerName = kstate.ContainerName
-
-			// add the image id from state
-			c.Image.ID = kstate.Image.ID
-
-			// add the network addresses
-			for _, a := range kstate.Networks {
-				for i, m := range c.Networks {
-					if m.ID == a.ID {
-						c.Networks[i].AssignedAddress = a.AssignedAddress
-						c.Networks[i].Name = a.Name
-						break
-					}
-				}
+	defaultUlimits(c.Ulimits)
+
+	return validateRuntimeOptions(c.ShmSize, c.Sysctls, c.ExtraHosts, c.Tmpfs, c.Ulimits, c.Devices)
+}
+
+func (c *Container) RestoreState(r types.Resource) {
+	kstate := r.(*Container)
+	c.ContainerName = kstate.ContainerName
+
+	// add the image id from state
+	c.Image.ID = kstate.Image.ID
+
+	// add the network addresses
+	for _, a := range kstate.Networks {
+		for i, m := range c.Networks {
+			if m.ID == a.ID {
+				c.Networks[i].AssignedAddress = a.AssignedAddress
+				c.Networks[i].Name = a.Name
+				break
 			}
 		}
 	}
-
-	return nil
 }
@@ -7,7 +7,7 @@ type Image struct {
 	// Username is the Docker registry user to use for private repositories
 	Username string `hcl:"username,optional" json:"username,omitempty"`
 	// Password is the Docker registry password to use for private repositories
-	Password string `hcl:"password,optional" json:"password,omitempty"`
+	Password string `hcl:"password,optional" json:"password,omitempty" sensitive:"true"`
 
 	// output
 
@@ -0,0 +1,126 @@
+package container
+
+import (
+	"fmt"
+	"net"
+	"path"
+	"slices"
+	"strings"
+)
+
+// Ulimit sets the soft and hard limits for a resource in the container,
+// a value of -1 means unlimited
+type Ulimit struct {
+	Name string `hcl:"name" json:"name"`                    // name of the limit i.e. nofile, nproc, memlock
+	Soft int64  `hcl:"soft" json:"soft"`                    // soft limit
+	Hard int64  `hcl:"hard,optional" json:"hard,omitempty"` // hard limit, defaults to the soft limit
+}
+
+// Device adds a device from the host to the container
+type Device struct {
+	Source      string `hcl:"source" json:"source"`                              // path of the device on the host i.e. /dev/fuse
+	Destination string `hcl:"destination,optional" json:"destination,omitempty"` // path of the device in the container, defaults to the source
+	Permissions string `hcl:"permissions,optional" json:"permissions,omitempty"` // cgroup permissions for the device [r, w, m], defaults to rwm
+}
+
+// Tmpfs mounts a tmpfs filesystem in the container
+type Tmpfs struct {
+	Destination string   `hcl:"destination" json:"destination"`            // path to mount the filesystem
+	Options     []string `hcl:"options,optional" json:"options,omitempty"` // mount options i.e. size=64m, mode=1777
+}
+
+var ulimitNames = []string{
+	"core", "cpu", "data", "fsize", "locks", "memlock", "msgqueue", "nice",
+	"nofile", "nproc", "rss", "rtprio", "rttime", "sigpending", "stack",
+}
+
+// sysctls that are not namespaced affect the host and are rejected by Docker
+var sysctlPrefixes = []string{"net.", "fs.mqueue.", "kernel.msg", "kernel.sem", "kernel.shm"}
+
+// validateRuntimeOptions checks the runtime options shared by containers and
+// sidecars, errors are returned before the container is created so that they
+// are reported with the location of the resource
+func validateRuntimeOptions(shmSize int, sysctls, extraHosts map[string]string, tmpfs []Tmpfs, ulimits []Ulimit, devices []Device) error {
+	if shmSize < 0 {
+		return fmt.Errorf("shm_size must not be negative")
+	}
+
+	for k := range sysctls {
+		if !hasPrefix(k, sysctlPrefixes) {
+			return fmt.Errorf("sysctl %s can not be set, only namespaced sysctls net.*, fs.mqueue.*, kernel.msg*, kernel.sem and kernel.shm* are supported", k)
+		}
+	}
+
+	for h, ip := range extraHosts {
+		if h == "" || strings.ContainsAny(h, ": ") {
+			return fmt.Errorf("extra_hosts contains the invalid hostname '%s'", h)
+		}
+
+		if ip != "host-gateway" && net.ParseIP(ip) == nil {
+			return fmt.Errorf("extra_hosts address %s for %s must be an IP address or host-gateway", ip, h)
+		}
+	}
+
+	mounted := map[string]bool{}
+	for _, t := range tmpfs {
+		if !path.IsAbs(t.Destination) {
+			return fmt.Errorf("tmpfs destination %s must be an absolute path", t.Destination)
+		}
+
+		if mounted[t.Destination] {
+			return fmt.Errorf("tmpfs destination %s is defined more than once", t.Destination)
+		}
+
+		mounted[t.Destination] = true
+	}
+
+	for _, u := range ulimits {
+		if !slices.Contains(ulimitNames, u.Name) {
+			return fmt.Errorf("ulimit %s is not a valid limit, valid limits are %s", u.Name, strings.Join(ulimitNames, ", "))
+		}
+
+		if u.Soft < -1 || u.Hard < -1 {
+			return fmt.Errorf("ulimit %s must be greater than or equal to -1", u.Name)
+		}
+
+		if u.Hard != -1 && (u.Soft == -1 || u.Soft > u.Hard) {
+			return fmt.Errorf("ulimit %s soft limit %d must not be greater than the hard limit %d", u.Name, u.Soft, u.Hard)
+		}
+	}
+
+	for _, d := range devices {
+		if !path.IsAbs(d.Source) {
+			return fmt.Errorf("device source %s must be an absolute path", d.Source)
+		}
+
+		if d.Destination != "" && !path.IsAbs(d.Destination) {
+			return fmt.Errorf("device destination %s must be an absolute path", d.Destination)
+		}
+
+		if strings.Trim(d.Permissions, "rwm") != "" {
+			return fmt.Errorf("device %s has invalid permissions %s, permissions must be a combination of r, w and m", d.Source, d.Permissions)
+		}
+	}
+
+	return nil
+}
+
+// defaultUlimits sets the hard limit of any ulimits that only specify a soft
+// limit
+func defaultUlimits(ulimits []Ulimit) {
+	for i, u := range ulimits {
+		if u.Hard == 0 {
+			ulimits[i].Hard = u.Soft
+		}
+	}
+}
+
+func hasPrefix(s string, prefixes []string) bool {
+	for _, p := range prefixes {
+		if strings.HasPrefix(s, p) {
+			return true
+		}
+	}
+
+	return false
+}
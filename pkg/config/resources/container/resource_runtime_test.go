@@ -0,0 +1,103 @@
+package container
+
+import (
+	"testing"
+
+	"github.com/jumppad-labs/hclconfig/types"
+	"github.com/stretchr/testify/require"
+)
+
+func TestContainerProcessValidatesRuntimeOptions(t *testing.T) {
+	tt := map[string]struct {
+		container Container
+		err       string
+	}{
+		"valid": {
+			container: Container{
+				ShmSize:    256,
+				Sysctls:    map[string]string{"net.ipv4.ip_forward": "1", "kernel.shmmax": "1024"},
+				ExtraHosts: map[string]string{"db.local": "10.0.0.2", "host.local": "host-gateway"},
+				Tmpfs:      []Tmpfs{{Destination: "/run"}},
+				Ulimits:    []Ulimit{{Name: "nofile", Soft: 1024, Hard: 65536}, {Name: "memlock", Soft: -1, Hard: -1}},
+				Devices:    []Device{{Source: "/dev/fuse", Permissions: "rw"}},
+			},
+		},
+		"negative shm_size": {
+			container: Container{ShmSize: -1},
+			err:       "shm_size",
+		},
+		"sysctl not namespaced": {
+			container: Container{Sysctls: map[string]string{"vm.max_map_count": "262144"}},
+			err:       "sysctl vm.max_map_count",
+		},
+		"extra_hosts invalid address": {
+			container: Container{ExtraHosts: map[string]string{"db.local": "nope"}},
+			err:       "extra_hosts address nope",
+		},
+		"extra_hosts invalid hostname": {
+			container: Container{ExtraHosts: map[string]string{"db:local": "10.0.0.2"}},
+			err:       "invalid hostname",
+		},
+		"tmpfs relative destination": {
+			container: Container{Tmpfs: []Tmpfs{{Destination: "run"}}},
+			err:       "absolute path",
+		},
+		"tmpfs duplicate destination": {
+			container: Container{Tmpfs: []Tmpfs{{Destination: "/run"}, {Destination: "/run"}}},
+			err:       "more than once",
+		},
+		"ulimit unknown name": {
+			container: Container{Ulimits: []Ulimit{{Name: "files", Soft: 10}}},
+			err:       "ulimit files is not a valid limit",
+		},
+		"ulimit soft greater than hard": {
+			container: Container{Ulimits: []Ulimit{{Name: "nofile", Soft: 100, Hard: 10}}},
+			err:       "soft limit 100",
+		},
+		"device relative source": {
+			container: Container{Devices: []Device{{Source: "dev/fuse"}}},
+			err:       "device source",
+		},
+		"device invalid permissions": {
+			container: Container{Devices: []Device{{Source: "/dev/fuse", Permissions: "rx"}}},
+			err:       "invalid permissions",
+		},
+	}
+
+	for name, tc := range tt {
+		t.Run(name, func(t *testing.T) {
+			c := tc.container
+			c.ResourceBase = types.ResourceBase{Meta: types.Meta{File: "./"}}
+
+			err := c.Process()
+			if tc.err == "" {
+				require.NoError(t, err)
+				return
+			}
+
+			require.ErrorContains(t, err, tc.err)
+		})
+	}
+}
+
+func TestContainerProcessDefaultsUlimitHardToSoft(t *testing.T) {
+	c := &Container{
+		ResourceBase: types.ResourceBase{Meta: types.Meta{File: "./"}},
+		Ulimits:      []Ulimit{{Name: "nofile", Soft: 65536}},
+	}
+
+	err := c.Process()
+	require.NoError(t, err)
+
+	require.Equal(t, int64(65536), c.Ulimits[0].Hard)
+}
+
+func TestSidecarProcessValidatesRuntimeOptions(t *testing.T) {
+	c := &Sidecar{
+		ResourceBase: types.ResourceBase{Meta: types.Meta{File: "./"}},
+		Sysctls:      map[string]string{"vm.swappiness": "10"},
+	}
+
+	err := c.Process()
+	require.ErrorContains(t, err, "sysctl vm.swappiness")
+}
@@ -2,8 +2,8 @@ package container
 
 import (
 	"github.com/jumppad-labs/hclconfig/types"
-	"github.com/jumppad-labs/jumppad/pkg/config"
 	"github.com/jumppad-labs/jumppad/pkg/config/resources/healthcheck"
+	"github.com/jumppad-labs/jumppad/pkg/config/resources/lifecycle"
 	"github.com/jumppad-labs/jumppad/pkg/utils"
 )
 
@@ -15,6 +15,8 @@ type Sidecar struct {
 	// embedded type holding name, etc
 	types.ResourceBase `hcl:",remain"`
 
+	Lifecycle *lifecycle.Lifecycle `hcl:"lifecycle,block" json:"lifecycle,omitempty"`
+
 	Target Container `hcl:"target" json:"target"`
 
 	Image       Image             `hcl:"image,block" json:"image"`                          // image to use for the container
@@ -34,6 +36,16 @@ type Sidecar struct {
 
 	MaxRestartCount int `hcl:"max_restart_count,optional" json:"max_restart_count,omitempty"`
 
+	// runtime options, extra_hosts can not be set as the sidecar shares the
+	// network of the target container
+	Init     bool              `hcl:"init,optional" json:"init,omitempty"`           // run an init process as PID 1 that reaps zombie processes
+	ReadOnly bool              `hcl:"read_only,optional" json:"read_only,omitempty"` // mount the root filesystem of the container as read only
+	ShmSize  int               `hcl:"shm_size,optional" json:"shm_size,omitempty"`   // size of /dev/shm in MB
+	Sysctls  map[string]string `hcl:"sysctls,optional" json:"sysctls,omitempty"`     // namespaced kernel parameters to set in the container
+	Tmpfs    []Tmpfs           `hcl:"tmpfs,block" json:"tmpfs,omitempty"`            // tmpfs filesystems to mount in the container
+	Ulimits  []Ulimit          `hcl:"ulimit,block" json:"ulimits,omitempty"`         // resource limits such as the number of open files
+	Devices  []Device          `hcl:"device,block" json:"devices,omitempty"`         // host devices to add to the container
+
 	// Output parameters
 
 	// ContainerName is the fully qualified domain name for the container the sidecar is linked to, this can be used
@@ -50,20 +62,15 @@ func (c *Sidecar) Process() error {
 		}
 	}
 
-	// do we have an existing resource in the state?
-	// if so we need to set any computed resources for dependents
-	cfg, err := config.LoadState()
-	if err == nil {
-		// try and find the resource in the state
-		r, _ := cfg.FindResource(c.Meta.ID)
-		if r != nil {
-			kstate := r.(*Sidecar)
-			c.ContainerName = kstate.ContainerName
-
-			// add the image id from state
-			c.Image.ID = kstate.Image.ID
-		}
-	}
+	defaultUlimits(c.Ulimits)
+
+	return validateRuntimeOptions(c.ShmSize, c.Sysctls, nil, c.Tmpfs, c.Ulimits, c.Devices)
+}
+
+func (c *Sidecar) RestoreState(r types.Resource) {
+	kstate := r.(*Sidecar)
+	c.ContainerName = kstate.ContainerName
 
-	return nil
+	// add the image id from state
+	c.Image.ID = kstate.Image.ID
 }
@@ -61,5 +61,9 @@ func TestSidecarLoadsValuesFromState(t *testing.T) {
 	err := docs.Process()
 	require.NoError(t, err)
 
+	sf, err := config.LoadState()
+	require.NoError(t, err)
+	config.RestoreState(docs, sf)
+
 	require.Equal(t, "fqdn.mine", docs.ContainerName)
 }
@@ -11,6 +11,7 @@ import (
 
 	htypes "github.com/jumppad-labs/hclconfig/types"
 	"github.com/jumppad-labs/jumppad/pkg/clients"
+	"github.com/jumppad-labs/jumppad/pkg/clients/container"
 	"github.com/jumppad-labs/jumppad/pkg/clients/getter"
 	"github.com/jumppad-labs/jumppad/pkg/utils"
 	sdk "github.com/jumppad-labs/plugin-sdk"
@@ -21,22 +22,28 @@ type Provider struct {
 	log    sdk.Logger
 	config *Copy
 	getter getter.Getter
+	env    utils.Environment
 }
 
 func (p *Provider) Init(cfg htypes.Resource, l sdk.Logger) error {
-	c, ok := cfg.(*Copy)
-	if !ok {
-		return fmt.Errorf("unable to initialize Copy provider, resource is not an instance of Copy")
-	}
-
 	cli, err := clients.GenerateClients(l)
 	if err != nil {
 		return err
 	}
 
+	return p.InitWithClients(cfg, l, cli)
+}
+
+func (p *Provider) InitWithClients(cfg htypes.Resource, l sdk.Logger, cli *clients.Clients) error {
+	c, ok := cfg.(*Copy)
+	if !ok {
+		return fmt.Errorf("unable to initialize Copy provider, resource is not an instance of Copy")
+	}
+
 	p.getter = cli.Getter
 	p.config = c
 	p.log = l
+	p.env = cli.Environment
 
 	return nil
 }
@@ -55,7 +62,7 @@ func (p *Provider) Create(ctx context.Context) error {
 	_, err := os.Stat(srcPath)
 
 	if err != nil {
-		tempPath := filepath.Join(utils.JumppadTemp(), "copy", p.config.Meta.ID)
+		tempPath := filepath.Join(p.env.JumppadTemp(), "copy", p.config.Meta.ID)
 
 		defer func() {
 			// clean up temporary files
@@ -158,6 +165,16 @@ func (p *Provider) Destroy(ctx context.Context, force bool) error {
 	return nil
 }
 
+// Snapshot does nothing, the files are copied again when restored
+func (p *Provider) Snapshot(ctx context.Context, s *container.Snapshot) error {
+	return nil
+}
+
+// Restore copies the files to the destination
+func (p *Provider) Restore(ctx context.Context, s *container.Snapshot) error {
+	return p.Create(ctx)
+}
+
 func (p *Provider) Lookup() ([]string, error) {
 	return nil, nil
 }
@@ -9,6 +9,7 @@ import (
 	"github.com/jumppad-labs/hclconfig/types"
 	"github.com/jumppad-labs/jumppad/pkg/clients/getter"
 	"github.com/jumppad-labs/jumppad/pkg/clients/logger"
+	"github.com/jumppad-labs/jumppad/pkg/utils"
 	"github.com/stretchr/testify/require"
 )
 
@@ -29,7 +30,7 @@ func setupCopy(t *testing.T) (*Copy, *Provider) {
 	cc.Source = inDir
 	cc.Destination = outDir
 
-	p := &Provider{logger.NewTestLogger(t), cc, getter.NewGetter(true)}
+	p := &Provider{logger.NewTestLogger(t), cc, getter.NewGetter(true), utils.Environment{}}
 
 	return cc, p
 }
@@ -4,7 +4,7 @@ import (
 	"os"
 
 	"github.com/jumppad-labs/hclconfig/types"
-	"github.com/jumppad-labs/jumppad/pkg/config"
+	"github.com/jumppad-labs/jumppad/pkg/config/resources/lifecycle"
 	"github.com/jumppad-labs/jumppad/pkg/utils"
 )
 
@@ -17,6 +17,8 @@ type Copy struct {
 	// embedded type holding name, etc
 	types.ResourceBase `hcl:",remain"`
 
+	Lifecycle *lifecycle.Lifecycle `hcl:"lifecycle,block" json:"lifecycle,omitempty"`
+
 	Depends []string `hcl:"depends_on,optional" json:"depends,omitempty"`
 
 	Source      string `hcl:"source" json:"source"`                              // Source file, folder, url, git repo, etc
@@ -36,15 +38,10 @@ func (t *Copy) Process() error {
 
 	t.Destination = utils.EnsureAbsolute(t.Destination, t.Meta.File)
 
-	cfg, err := config.LoadState()
-	if err == nil {
-		// try and find the resource in the state
-		r, _ := cfg.FindResource(t.Meta.ID)
-		if r != nil {
-			kstate := r.(*Copy)
-			t.CopiedFiles = kstate.CopiedFiles
-		}
-	}
-
 	return nil
 }
+
+func (t *Copy) RestoreState(r types.Resource) {
+	kstate := r.(*Copy)
+	t.CopiedFiles = kstate.CopiedFiles
+}
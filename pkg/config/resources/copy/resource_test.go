@@ -75,5 +75,9 @@ func TestCopySetsOutputsFromState(t *testing.T) {
 
 	c.Process()
 
+	sf, err := config.LoadState()
+	require.NoError(t, err)
+	config.RestoreState(c, sf)
+
 	require.Equal(t, []string{"a", "b"}, c.CopiedFiles)
 }
@@ -13,6 +13,7 @@ import (
 	"github.com/jumppad-labs/jumppad/pkg/clients"
 	"github.com/jumppad-labs/jumppad/pkg/clients/container"
 	"github.com/jumppad-labs/jumppad/pkg/clients/container/types"
+	"github.com/jumppad-labs/jumppad/pkg/jumppad/tracing"
 	"github.com/jumppad-labs/jumppad/pkg/utils"
 	sdk "github.com/jumppad-labs/plugin-sdk"
 	"github.com/mohae/deepcopy"
@@ -83,22 +84,28 @@ type DocsProvider struct {
 	config *Docs
 	client container.ContainerTasks
 	log    sdk.Logger
+	env    utils.Environment
 }
 
 func (p *DocsProvider) Init(cfg htypes.Resource, l sdk.Logger) error {
-	c, ok := cfg.(*Docs)
-	if !ok {
-		return fmt.Errorf("unable to initialize Docs provider, resource is not of type Docs")
-	}
-
 	cli, err := clients.GenerateClients(l)
 	if err != nil {
 		return err
 	}
 
+	return p.InitWithClients(cfg, l, cli)
+}
+
+func (p *DocsProvider) InitWithClients(cfg htypes.Resource, l sdk.Logger, cli *clients.Clients) error {
+	c, ok := cfg.(*Docs)
+	if !ok {
+		return fmt.Errorf("unable to initialize Docs provider, resource is not of type Docs")
+	}
+
 	p.config = c
 	p.client = cli.ContainerTasks
 	p.log = l
+	p.env = cli.Environment
 
 	return nil
 }
@@ -117,7 +124,7 @@ func (p *DocsProvider) Create(ctx context.Context) error {
 	}
 
 	// write the content
-	return p.createDocsContainer()
+	return p.createDocsContainer(ctx)
 }
 
 // Destroy the documentation container
@@ -143,12 +150,74 @@ func (p *DocsProvider) Destroy(ctx context.Context, force bool) error {
 	}
 
 	// remove the cached files
-	contentPath := utils.LibraryFolder("", 0775)
+	contentPath := p.env.LibraryFolder("", 0775)
 	os.RemoveAll(contentPath)
 
 	return nil
 }
 
+// Stop stops the documentation container without removing it
+func (p *DocsProvider) Stop(ctx context.Context) error {
+	if ctx.Err() != nil {
+		p.log.Debug("Context is cancelled, skipping stop", "ref", p.config.Meta.ID)
+		return nil
+	}
+
+	p.log.Info("Stop Documentation", "ref", p.config.Meta.ID)
+
+	ids, err := p.Lookup()
+	if err != nil {
+		return err
+	}
+
+	for _, id := range ids {
+		err := p.client.StopContainer(id, false)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// Start starts the stopped documentation container
+func (p *DocsProvider) Start(ctx context.Context) error {
+	if ctx.Err() != nil {
+		p.log.Debug("Context is cancelled, skipping start", "ref", p.config.Meta.ID)
+		return nil
+	}
+
+	p.log.Info("Start Documentation", "ref", p.config.Meta.ID)
+
+	ids, err := p.Lookup()
+	if err != nil {
+		return err
+	}
+
+	if len(ids) == 0 {
+		return fmt.Errorf("documentation container %s does not exist", p.config.ContainerName)
+	}
+
+	for _, id := range ids {
+		err := p.client.StartContainer(id, p.config.Networks.ToStartNetworkAttachments())
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// Snapshot does nothing, the docs content is generated from the config
+func (p *DocsProvider) Snapshot(ctx context.Context, s *container.Snapshot) error {
+	return nil
+}
+
+// Restore creates the docs container and generates the content again
+func (p *DocsProvider) Restore(ctx context.Context, s *container.Snapshot) error {
+	return p.Create(ctx)
+}
+
 // Lookup the ID of the documentation container
 func (p *DocsProvider) Lookup() ([]string, error) {
 	return p.client.FindContainerIDs(p.config.ContainerName)
@@ -218,9 +287,9 @@ func (p *DocsProvider) generateContentChecksum() (string, error) {
 	return cs, nil
 }
 
-func (p *DocsProvider) createDocsContainer() error {
+func (p *DocsProvider) createDocsContainer(ctx context.Context) error {
 	// set the FQDN
-	fqdn := utils.FQDN(p.config.Meta.Name, p.config.Meta.Module, p.config.Meta.Type)
+	fqdn := p.env.FQDN(p.config.Meta.Name, p.config.Meta.Module, p.config.Meta.Type)
 	p.config.ContainerName = fqdn
 
 	// create the container config
@@ -242,7 +311,9 @@ func (p *DocsProvider) createDocsContainer() error {
 	}
 
 	// pull the docker image
-	err := p.client.PullImage(*cc.Image, false)
+	err := tracing.Trace(ctx, "PullImage", func() error {
+		return p.client.PullImage(*cc.Image, false)
+	}, tracing.Images(cc.Image.Name))
 	if err != nil {
 		return err
 	}
@@ -265,7 +336,7 @@ func (p *DocsProvider) createDocsContainer() error {
 	}
 
 	// ~/.jumppad/library/content
-	contentPath := utils.LibraryFolder("content", 0775)
+	contentPath := p.env.LibraryFolder("content", 0775)
 
 	// mount the content
 	cc.Volumes = append(
@@ -277,7 +348,7 @@ func (p *DocsProvider) createDocsContainer() error {
 	)
 
 	// ~/.jumppad/library/config
-	configPath := utils.LibraryFolder("config", 0775)
+	configPath := p.env.LibraryFolder("config", 0775)
 
 	cc.Volumes = append(
 		cc.Volumes,
@@ -318,7 +389,7 @@ func (p *DocsProvider) generateDocs() error {
 	p.log.Info("Refresh Docs", "ref", p.config.Meta.ID)
 
 	// refresh content on disk
-	configPath := utils.LibraryFolder("config", 0775)
+	configPath := p.env.LibraryFolder("config", 0775)
 
 	// jumppad.config.js
 
@@ -343,7 +414,7 @@ func (p *DocsProvider) generateDocs() error {
 	}
 
 	// /content
-	contentPath := utils.LibraryFolder("content", 0775)
+	contentPath := p.env.LibraryFolder("content", 0775)
 
 	for _, book := range p.config.Content {
 		bookPath := filepath.Join(contentPath, book.Meta.Name)
@@ -2,6 +2,7 @@ package docs
 
 import (
 	"github.com/jumppad-labs/hclconfig/types"
+	"github.com/jumppad-labs/jumppad/pkg/config/resources/lifecycle"
 )
 
 const TypeBook string = "book"
@@ -9,6 +10,8 @@ const TypeBook string = "book"
 type Book struct {
 	types.ResourceBase `hcl:",remain"`
 
+	Lifecycle *lifecycle.Lifecycle `hcl:"lifecycle,block" json:"lifecycle,omitempty"`
+
 	Title    string    `hcl:"title" json:"title"`
 	Chapters []Chapter `hcl:"chapters" json:"chapters"`
 }
@@ -2,6 +2,7 @@ package docs
 
 import (
 	"github.com/jumppad-labs/hclconfig/types"
+	"github.com/jumppad-labs/jumppad/pkg/config/resources/lifecycle"
 )
 
 const TypeChapter string = "chapter"
@@ -9,6 +10,8 @@ const TypeChapter string = "chapter"
 type Chapter struct {
 	types.ResourceBase `hcl:",remain"`
 
+	Lifecycle *lifecycle.Lifecycle `hcl:"lifecycle,block" json:"lifecycle,omitempty"`
+
 	Prerequisites []string `hcl:"prerequisites,optional" json:"prerequisites"`
 
 	Title string          `hcl:"title,optional" json:"title,omitempty"`
@@ -2,8 +2,8 @@ package docs
 
 import (
 	"github.com/jumppad-labs/hclconfig/types"
-	"github.com/jumppad-labs/jumppad/pkg/config"
 	ctypes "github.com/jumppad-labs/jumppad/pkg/config/resources/container"
+	"github.com/jumppad-labs/jumppad/pkg/config/resources/lifecycle"
 	"github.com/jumppad-labs/jumppad/pkg/utils"
 )
 
@@ -15,6 +15,8 @@ const TypeDocs string = "docs"
 type Docs struct {
 	types.ResourceBase `hcl:",remain"`
 
+	Lifecycle *lifecycle.Lifecycle `hcl:"lifecycle,block" json:"lifecycle,omitempty"`
+
 	Networks ctypes.NetworkAttachments `hcl:"network,block" json:"networks,omitempty"` // Attach to the correct network // only when Image is specified
 
 	Image *ctypes.Image `hcl:"image,block" json:"image,omitempty"` // image to use for the container
@@ -54,18 +56,11 @@ func (d *Docs) Process() error {
 		d.Assets = utils.EnsureAbsolute(d.Assets, d.Meta.File)
 	}
 
-	// do we have an existing resource in the state?
-	// if so we need to set any computed resources for dependents
-	cfg, err := config.LoadState()
-	if err == nil {
-		// try and find the resource in the state
-		r, _ := cfg.FindResource(d.Meta.ID)
-		if r != nil {
-			kstate := r.(*Docs)
-			d.ContainerName = kstate.ContainerName
-			d.ContentChecksum = kstate.ContentChecksum
-		}
-	}
-
 	return nil
 }
+
+func (d *Docs) RestoreState(r types.Resource) {
+	kstate := r.(*Docs)
+	d.ContainerName = kstate.ContainerName
+	d.ContentChecksum = kstate.ContentChecksum
+}
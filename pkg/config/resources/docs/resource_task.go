@@ -2,6 +2,7 @@ package docs
 
 import (
 	"github.com/jumppad-labs/hclconfig/types"
+	"github.com/jumppad-labs/jumppad/pkg/config/resources/lifecycle"
 )
 
 const TypeTask string = "task"
@@ -9,6 +10,8 @@ const TypeTask string = "task"
 type Task struct {
 	types.ResourceBase `hcl:",remain"`
 
+	Lifecycle *lifecycle.Lifecycle `hcl:"lifecycle,block" json:"lifecycle,omitempty"`
+
 	Prerequisites []string    `hcl:"prerequisites,optional" json:"prerequisites"`
 	Config        *Config     `hcl:"config,block" json:"config,omitempty"`
 	Conditions    []Condition `hcl:"condition,block" json:"conditions"`
@@ -50,5 +50,9 @@ func TestDocsLoadsValuesFromState(t *testing.T) {
 	err := docs.Process()
 	require.NoError(t, err)
 
+	sf, err := config.LoadState()
+	require.NoError(t, err)
+	config.RestoreState(docs, sf)
+
 	require.Equal(t, "fqdn.mine", docs.ContainerName)
 }
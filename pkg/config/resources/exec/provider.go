@@ -16,6 +16,7 @@ import (
 	contClient "github.com/jumppad-labs/jumppad/pkg/clients/container"
 	"github.com/jumppad-labs/jumppad/pkg/clients/container/types"
 	"github.com/jumppad-labs/jumppad/pkg/clients/logger"
+	"github.com/jumppad-labs/jumppad/pkg/jumppad/tracing"
 	"github.com/jumppad-labs/jumppad/pkg/utils"
 	sdk "github.com/jumppad-labs/plugin-sdk"
 )
@@ -30,24 +31,30 @@ type Provider struct {
 	container contClient.ContainerTasks
 	command   cmdClient.Command
 	log       logger.Logger
+	env       utils.Environment
 }
 
 // Intit creates a new Exec provider
 func (p *Provider) Init(cfg htypes.Resource, l sdk.Logger) error {
-	c, ok := cfg.(*Exec)
-	if !ok {
-		return fmt.Errorf("unable to initialize provider, resource is not of type Exec")
-	}
-
 	cli, err := clients.GenerateClients(l)
 	if err != nil {
 		return err
 	}
 
+	return p.InitWithClients(cfg, l, cli)
+}
+
+func (p *Provider) InitWithClients(cfg htypes.Resource, l sdk.Logger, cli *clients.Clients) error {
+	c, ok := cfg.(*Exec)
+	if !ok {
+		return fmt.Errorf("unable to initialize provider, resource is not of type Exec")
+	}
+
 	p.config = c
 	p.command = cli.Command
 	p.container = cli.ContainerTasks
 	p.log = l
+	p.env = cli.Environment
 
 	return nil
 }
@@ -60,7 +67,7 @@ func (p *Provider) Create(ctx context.Context) error {
 
 	p.log.Info("Executing script", "ref", p.config.Meta.ID, "script", p.config.Script)
 
-	outPath := fmt.Sprintf("%s/%s.out", utils.JumppadTemp(), p.config.Meta.ID)
+	outPath := fmt.Sprintf("%s/%s.out", p.env.JumppadTemp(), p.config.Meta.ID)
 
 	if _, err := os.Stat(outPath); err != nil {
 		err := os.WriteFile(outPath, []byte{}, 0755)
@@ -75,7 +82,7 @@ func (p *Provider) Create(ctx context.Context) error {
 	// check if we have a target or image specified
 	if p.config.Image != nil || p.config.Target != nil {
 		// remote exec
-		err := p.createRemoteExec(outPath)
+		err := p.createRemoteExec(ctx, outPath)
 		if err != nil {
 			return fmt.Errorf("unable to create remote exec: %w", err)
 		}
@@ -159,12 +166,12 @@ func (p *Provider) Changed() (bool, error) {
 	return false, nil
 }
 
-func (p *Provider) createRemoteExec(outputPath string) error {
+func (p *Provider) createRemoteExec(ctx context.Context, outputPath string) error {
 	// execution target id
 	targetID := ""
 	if p.config.Target == nil {
 		// Not using existing target create new container
-		id, err := p.createRemoteExecContainer()
+		id, err := p.createRemoteExecContainer(ctx)
 		if err != nil {
 			return fmt.Errorf("unable to create container for exec.%s: %w", p.config.Meta.Name, err)
 		}
@@ -226,9 +233,9 @@ func (p *Provider) createRemoteExec(outputPath string) error {
 	return nil
 }
 
-func (p *Provider) createRemoteExecContainer() (string, error) {
+func (p *Provider) createRemoteExecContainer(ctx context.Context) (string, error) {
 	// generate the ID for the new container based on the clock time and a string
-	fqdn := utils.FQDN(p.config.Meta.Name, p.config.Meta.Module, p.config.Meta.Type)
+	fqdn := p.env.FQDN(p.config.Meta.Name, p.config.Meta.Module, p.config.Meta.Type)
 
 	new := types.Container{
 		Name:        fqdn,
@@ -261,7 +268,9 @@ func (p *Provider) createRemoteExecContainer() (string, error) {
 	new.Command = []string{"/bin/sh"} // ensure container does not immediately exit
 
 	// pull any images needed for this container
-	err := p.container.PullImage(*new.Image, false)
+	err := tracing.Trace(ctx, "PullImage", func() error {
+		return p.container.PullImage(*new.Image, false)
+	}, tracing.Images(new.Image.Name))
 	if err != nil {
 		p.log.Error("Unable to pull container image", "ref", p.config.Meta.ID, "image", new.Image.Name)
 
@@ -286,7 +295,7 @@ func (p *Provider) createLocalExec(outputPath string) (int, error) {
 	}
 
 	// create a temporary file for the script
-	scriptPath := filepath.Join(utils.JumppadTemp(), fmt.Sprintf("exec_%s.sh", p.config.Meta.Name))
+	scriptPath := filepath.Join(p.env.JumppadTemp(), fmt.Sprintf("exec_%s.sh", p.config.Meta.Name))
 	err := os.WriteFile(scriptPath, []byte(contents), 0755)
 	if err != nil {
 		return 0, fmt.Errorf("unable to write script to file: %s", err)
@@ -300,7 +309,7 @@ func (p *Provider) createLocalExec(outputPath string) (int, error) {
 	}
 
 	// create the folders for logs and pids
-	logPath := filepath.Join(utils.LogsDir(), fmt.Sprintf("exec_%s.log", p.config.Meta.Name))
+	logPath := filepath.Join(p.env.LogsDir(), fmt.Sprintf("exec_%s.log", p.config.Meta.Name))
 
 	// do we have a duration to parse
 	var d time.Duration
@@ -337,7 +346,7 @@ func (p *Provider) createLocalExec(outputPath string) (int, error) {
 }
 
 func (p *Provider) generateOutput() error {
-	outPath := fmt.Sprintf("%s/%s.out", utils.JumppadTemp(), p.config.Meta.ID)
+	outPath := fmt.Sprintf("%s/%s.out", p.env.JumppadTemp(), p.config.Meta.ID)
 
 	// parse any output from the script
 	if _, err := os.Stat(outPath); err != nil {
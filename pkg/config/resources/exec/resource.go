@@ -5,8 +5,8 @@ import (
 	"strings"
 
 	"github.com/jumppad-labs/hclconfig/types"
-	"github.com/jumppad-labs/jumppad/pkg/config"
 	ctypes "github.com/jumppad-labs/jumppad/pkg/config/resources/container"
+	"github.com/jumppad-labs/jumppad/pkg/config/resources/lifecycle"
 	"github.com/jumppad-labs/jumppad/pkg/utils"
 )
 
@@ -18,6 +18,8 @@ type Exec struct {
 	// embedded type holding name, etc
 	types.ResourceBase `hcl:",remain"`
 
+	Lifecycle *lifecycle.Lifecycle `hcl:"lifecycle,block" json:"lifecycle,omitempty"`
+
 	Script           string            `hcl:"script" json:"script"`                                          // script to execute
 	WorkingDirectory string            `hcl:"working_directory,optional" json:"working_directory,omitempty"` // Working directory to execute commands
 	Daemon           bool              `hcl:"daemon,optional" json:"daemon,omitempty"`                       // Should the process run as a daemon
@@ -63,20 +65,12 @@ func (e *Exec) Process() error {
 
 	e.Checksum = cs
 
-	// do we have an existing resource in the state?
-	// if so we need to set any computed resources for dependents
-	cfg, err := config.LoadState()
-	if err == nil {
-		// try and find the resource in the state
-		r, _ := cfg.FindResource(e.Meta.ID)
-
-		if r != nil {
-			kstate := r.(*Exec)
-			e.PID = kstate.PID
-			e.ExitCode = kstate.ExitCode
-			e.Output = kstate.Output
-		}
-	}
-
 	return nil
 }
+
+func (e *Exec) RestoreState(r types.Resource) {
+	kstate := r.(*Exec)
+	e.PID = kstate.PID
+	e.ExitCode = kstate.ExitCode
+	e.Output = kstate.Output
+}
@@ -41,6 +41,10 @@ func TestExecSetsOutputsFromState(t *testing.T) {
 
 	c.Process()
 
+	sf, err := config.LoadState()
+	require.NoError(t, err)
+	config.RestoreState(c, sf)
+
 	require.Equal(t, 42, c.PID)
 }
 
@@ -2,6 +2,7 @@ package helm
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"time"
 
@@ -11,6 +12,7 @@ import (
 	"github.com/jumppad-labs/jumppad/pkg/clients/helm"
 	"github.com/jumppad-labs/jumppad/pkg/clients/k8s"
 	"github.com/jumppad-labs/jumppad/pkg/clients/logger"
+	"github.com/jumppad-labs/jumppad/pkg/jumppad/events"
 	"github.com/jumppad-labs/jumppad/pkg/utils"
 	sdk "github.com/jumppad-labs/plugin-sdk"
 )
@@ -26,6 +28,15 @@ type Provider struct {
 }
 
 func (p *Provider) Init(cfg htypes.Resource, l sdk.Logger) error {
+	cli, err := clients.GenerateClients(l)
+	if err != nil {
+		return err
+	}
+
+	return p.InitWithClients(cfg, l, cli)
+}
+
+func (p *Provider) InitWithClients(cfg htypes.Resource, l sdk.Logger, cli *clients.Clients) error {
 	h, ok := cfg.(*Helm)
 
 	if !ok {
@@ -34,11 +45,6 @@ func (p *Provider) Init(cfg htypes.Resource, l sdk.Logger) error {
 
 	p.config = h
 
-	cli, err := clients.GenerateClients(l)
-	if err != nil {
-		return err
-	}
-
 	p.config = h
 	p.kubeClient = cli.Kubernetes
 	p.helmClient = cli.Helm
@@ -166,10 +172,14 @@ func (p *Provider) Create(ctx context.Context) error {
 			return fmt.Errorf("unable to parse health check duration: %w", err)
 		}
 
+		events.HealthCheck(ctx, p.config.Meta.ID, p.config.Meta.Type, fmt.Sprintf("waiting for pods %v", p.config.HealthCheck.Pods))
+
 		err = p.kubeClient.HealthCheckPods(ctx, p.config.HealthCheck.Pods, to)
 		if err != nil {
 			return fmt.Errorf("health check failed after helm chart setup: %w", err)
 		}
+
+		events.HealthCheck(ctx, p.config.Meta.ID, p.config.Meta.Type, "pods are running")
 	}
 
 	return nil
@@ -218,6 +228,31 @@ func (p *Provider) Refresh(ctx context.Context) error {
 	return nil
 }
 
+// Drifted checks that the Helm release exists and is deployed
+func (p *Provider) Drifted(ctx context.Context) ([]string, error) {
+	namespace := p.config.Namespace
+	if namespace == "" {
+		namespace = "default"
+	}
+
+	name, _ := utils.ReplaceNonURIChars(p.config.Meta.Name)
+
+	status, err := p.helmClient.ReleaseStatus(p.config.Cluster.KubeConfig.ConfigPath, name, namespace)
+	if errors.Is(err, helm.ErrReleaseNotFound) {
+		return []string{fmt.Sprintf("release %s does not exist in namespace %s", name, namespace)}, nil
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	if status != "deployed" {
+		return []string{fmt.Sprintf("release %s has status %s", name, status)}, nil
+	}
+
+	return nil, nil
+}
+
 func (p *Provider) Changed() (bool, error) {
 	p.log.Debug("Checking changes", "ref", p.config.Meta.Name)
 
@@ -2,7 +2,7 @@ package http
 
 import (
 	"github.com/jumppad-labs/hclconfig/types"
-	"github.com/jumppad-labs/jumppad/pkg/config"
+	"github.com/jumppad-labs/jumppad/pkg/config/resources/lifecycle"
 )
 
 const TypeHTTP string = "http"
@@ -10,6 +10,8 @@ const TypeHTTP string = "http"
 type HTTP struct {
 	types.ResourceBase `hcl:",remain"`
 
+	Lifecycle *lifecycle.Lifecycle `hcl:"lifecycle,block" json:"lifecycle,omitempty"`
+
 	Method string `hcl:"method" json:"method"`
 	URL    string `hcl:"url" json:"url"`
 
@@ -22,17 +24,8 @@ type HTTP struct {
 	Body   string `hcl:"body,optional" json:"body"`
 }
 
-func (t *HTTP) Process() error {
-	cfg, err := config.LoadState()
-	if err == nil {
-		// try and find the resource in the state
-		r, _ := cfg.FindResource(t.Meta.ID)
-		if r != nil {
-			state := r.(*HTTP)
-			t.Status = state.Status
-			t.Body = state.Body
-		}
-	}
-
-	return nil
+func (t *HTTP) RestoreState(r types.Resource) {
+	state := r.(*HTTP)
+	t.Status = state.Status
+	t.Body = state.Body
 }
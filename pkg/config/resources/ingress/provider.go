@@ -27,16 +27,20 @@ type Provider struct {
 }
 
 func (p *Provider) Init(cfg htypes.Resource, l sdk.Logger) error {
-	c, ok := cfg.(*Ingress)
-	if !ok {
-		return fmt.Errorf("unable to initialize Ingress provider, resource is not of type Ingress")
-	}
-
 	cli, err := clients.GenerateClients(l)
 	if err != nil {
 		return err
 	}
 
+	return p.InitWithClients(cfg, l, cli)
+}
+
+func (p *Provider) InitWithClients(cfg htypes.Resource, l sdk.Logger, cli *clients.Clients) error {
+	c, ok := cfg.(*Ingress)
+	if !ok {
+		return fmt.Errorf("unable to initialize Ingress provider, resource is not of type Ingress")
+	}
+
 	p.config = c
 	p.client = cli.ContainerTasks
 	p.connector = cli.Connector
@@ -79,6 +83,16 @@ func (p *Provider) Destroy(ctx context.Context, force bool) error {
 	return nil
 }
 
+// Snapshot does nothing, the ingress is a service in the connector
+func (p *Provider) Snapshot(ctx context.Context, s *container.Snapshot) error {
+	return nil
+}
+
+// Restore exposes the service again with the connector
+func (p *Provider) Restore(ctx context.Context, s *container.Snapshot) error {
+	return p.Create(ctx)
+}
+
 // Lookup satisfies the interface method but is not implemented by LocalExec
 func (p *Provider) Lookup() ([]string, error) {
 	p.log.Debug("Lookup Ingress", "ref", p.config.Meta.ID, "id", p.config.IngressID)
@@ -164,7 +178,9 @@ func (p *Provider) exposeLocal() error {
 		return fmt.Errorf("unable to expose remote service on cluster :%w", err)
 	}
 
-	addr := fmt.Sprintf("%s:%d", utils.GetDockerIP(), p.config.Port)
+	// the port is opened by the local connector not the Docker daemon
+	localIP, _ := utils.GetLocalIPAndHostname()
+	addr := fmt.Sprintf("%s:%d", localIP, p.config.Port)
 	p.log.Debug("Successfully exposed service", "id", id, "dest", remoteAddr, "addr", addr)
 
 	p.config.IngressID = id
@@ -239,7 +255,9 @@ func (p *Provider) exposeRemote() error {
 		return fmt.Errorf("unable to expose remote service on cluster :%w", err)
 	}
 
-	addr := fmt.Sprintf("%s:%d", utils.GetDockerIP(), p.config.Port)
+	// the port is opened by the local connector not the Docker daemon
+	localIP, _ := utils.GetLocalIPAndHostname()
+	addr := fmt.Sprintf("%s:%d", localIP, p.config.Port)
 	p.log.Debug("Successfully exposed service", "id", id, "dest", destAddr, "addr", addr)
 
 	p.config.IngressID = id
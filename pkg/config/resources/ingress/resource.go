@@ -4,7 +4,7 @@ import (
 	"fmt"
 
 	"github.com/jumppad-labs/hclconfig/types"
-	"github.com/jumppad-labs/jumppad/pkg/config"
+	"github.com/jumppad-labs/jumppad/pkg/config/resources/lifecycle"
 	"github.com/jumppad-labs/jumppad/pkg/utils"
 )
 
@@ -15,6 +15,8 @@ const TypeIngress string = "ingress"
 type Ingress struct {
 	types.ResourceBase `hcl:",remain"`
 
+	Lifecycle *lifecycle.Lifecycle `hcl:"lifecycle,block" json:"lifecycle,omitempty"`
+
 	// local port to expose the service on
 	Port int `hcl:"port" json:"port"`
 
@@ -84,19 +86,12 @@ func (i *Ingress) Process() error {
 
 	i.Target.Config["service"] = sn
 
-	// do we have an existing resource in the state?
-	// if so we need to set any computed resources for dependents
-	c, err := config.LoadState()
-	if err == nil {
-		// try and find the resource in the state
-		r, _ := c.FindResource(i.Meta.ID)
-		if r != nil {
-			kstate := r.(*Ingress)
-			i.IngressID = kstate.IngressID
-			i.LocalAddress = kstate.LocalAddress
-			i.RemoteAddress = kstate.RemoteAddress
-		}
-	}
-
 	return nil
 }
+
+func (i *Ingress) RestoreState(r types.Resource) {
+	kstate := r.(*Ingress)
+	i.IngressID = kstate.IngressID
+	i.LocalAddress = kstate.LocalAddress
+	i.RemoteAddress = kstate.RemoteAddress
+}
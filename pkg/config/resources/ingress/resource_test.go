@@ -40,6 +40,10 @@ func TestIngressSetsOutputsFromState(t *testing.T) {
 
 	c.Process()
 
+	sf, err := config.LoadState()
+	require.NoError(t, err)
+	config.RestoreState(c, sf)
+
 	require.Equal(t, "42", c.IngressID)
 	require.Equal(t, "127.0.0.1", c.LocalAddress)
 }
@@ -23,8 +23,11 @@ import (
 	"github.com/jumppad-labs/jumppad/pkg/clients/http"
 	"github.com/jumppad-labs/jumppad/pkg/clients/k8s"
 	"github.com/jumppad-labs/jumppad/pkg/clients/logger"
+	"github.com/jumppad-labs/jumppad/pkg/config/resources/container"
+	"github.com/jumppad-labs/jumppad/pkg/jumppad/tracing"
 	"github.com/jumppad-labs/jumppad/pkg/utils"
 	sdk "github.com/jumppad-labs/plugin-sdk"
+	"go.opentelemetry.io/otel/attribute"
 	"gopkg.in/yaml.v3"
 )
 
@@ -44,25 +47,31 @@ type ClusterProvider struct {
 	httpClient http.HTTP
 	connector  connector.Connector
 	log        logger.Logger
+	env        utils.Environment
 }
 
 func (p *ClusterProvider) Init(cfg htypes.Resource, l sdk.Logger) error {
-	c, ok := cfg.(*Cluster)
-	if !ok {
-		return fmt.Errorf("unable to initialize Kubernetes cluster provider, resource is not of type K8sCluster")
-	}
-
 	cli, err := clients.GenerateClients(l)
 	if err != nil {
 		return err
 	}
 
+	return p.InitWithClients(cfg, l, cli)
+}
+
+func (p *ClusterProvider) InitWithClients(cfg htypes.Resource, l sdk.Logger, cli *clients.Clients) error {
+	c, ok := cfg.(*Cluster)
+	if !ok {
+		return fmt.Errorf("unable to initialize Kubernetes cluster provider, resource is not of type K8sCluster")
+	}
+
 	p.config = c
 	p.client = cli.ContainerTasks
 	p.kubeClient = cli.Kubernetes
 	p.httpClient = cli.HTTP
 	p.connector = cli.Connector
 	p.log = l
+	p.env = cli.Environment
 
 	return nil
 }
@@ -74,7 +83,9 @@ func (p *ClusterProvider) Create(ctx context.Context) error {
 		return nil
 	}
 
-	return p.createK3s(ctx)
+	p.log.Info("Creating Cluster", "ref", p.config.Meta.ID)
+
+	return p.createK3s(ctx, nil)
 }
 
 // Destroy implements interface method to destroy a cluster
@@ -89,7 +100,7 @@ func (p *ClusterProvider) Destroy(ctx context.Context, force bool) error {
 
 // Lookup the a clusters current state
 func (p *ClusterProvider) Lookup() ([]string, error) {
-	return p.client.FindContainerIDs(utils.FQDN(fmt.Sprintf("server.%s", p.config.Meta.Name), p.config.Meta.Module, p.config.Meta.Type))
+	return p.client.FindContainerIDs(p.env.FQDN(fmt.Sprintf("server.%s", p.config.Meta.Name), p.config.Meta.Module, p.config.Meta.Type))
 }
 
 func (p *ClusterProvider) Refresh(ctx context.Context) error {
@@ -107,15 +118,119 @@ func (p *ClusterProvider) Refresh(ctx context.Context) error {
 
 	if len(ci) > 0 {
 		p.log.Info("Copied images changed, pushing new copy to the cluster", "ref", p.config.Meta.ID)
-		err := p.ImportLocalDockerImages(ci, false)
+		err := p.ImportLocalDockerImages(ctx, ci, false)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// Drifted checks that the server container for the cluster exists
+func (p *ClusterProvider) Drifted(ctx context.Context) ([]string, error) {
+	ids, err := p.Lookup()
+	if err != nil {
+		return nil, err
+	}
+
+	if len(ids) == 0 {
+		return []string{fmt.Sprintf("server container for cluster %s does not exist", p.config.Meta.Name)}, nil
+	}
+
+	return nil, nil
+}
+
+// Stop stops the server container for the cluster without removing it
+func (p *ClusterProvider) Stop(ctx context.Context) error {
+	if ctx.Err() != nil {
+		p.log.Debug("Skipping stop, context cancelled", "ref", p.config.Meta.ID)
+		return nil
+	}
+
+	p.log.Info("Stop Cluster", "ref", p.config.Meta.ID)
+
+	ids, err := p.Lookup()
+	if err != nil {
+		return err
+	}
+
+	for _, id := range ids {
+		err := p.client.StopContainer(id, false)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// Start starts the stopped server container and waits for the default pods
+// to be running
+func (p *ClusterProvider) Start(ctx context.Context) error {
+	if ctx.Err() != nil {
+		p.log.Debug("Skipping start, context cancelled", "ref", p.config.Meta.ID)
+		return nil
+	}
+
+	p.log.Info("Start Cluster", "ref", p.config.Meta.ID)
+
+	ids, err := p.Lookup()
+	if err != nil {
+		return err
+	}
+
+	if len(ids) == 0 {
+		return fmt.Errorf("server container for cluster %s does not exist", p.config.Meta.Name)
+	}
+
+	nets := container.NetworkAttachments(p.config.Networks).ToStartNetworkAttachments()
+	for _, id := range ids {
+		err := p.client.StartContainer(id, nets)
 		if err != nil {
 			return err
 		}
 	}
 
+	p.kubeClient, err = p.kubeClient.SetConfig(p.config.KubeConfig.ConfigPath)
+	if err != nil {
+		return err
+	}
+
+	err = p.kubeClient.HealthCheckPods(ctx, []string{"app=local-path-provisioner", "k8s-app=kube-dns"}, startTimeout)
+	if err != nil {
+		return fmt.Errorf("timeout waiting for Kubernetes default pods: %w", err)
+	}
+
 	return nil
 }
 
+// Snapshot commits the server container for the cluster to an image, the
+// images imported to the cluster are part of the server container
+func (p *ClusterProvider) Snapshot(ctx context.Context, s *cclient.Snapshot) error {
+	if ctx.Err() != nil {
+		p.log.Debug("Skipping snapshot, context cancelled", "ref", p.config.Meta.ID)
+		return nil
+	}
+
+	p.log.Info("Snapshot Cluster", "ref", p.config.Meta.ID)
+
+	return s.Save(p.client, p.config.ContainerName)
+}
+
+// Restore creates the cluster from the server container saved to the
+// snapshot, the copied images are not imported again
+func (p *ClusterProvider) Restore(ctx context.Context, s *cclient.Snapshot) error {
+	if ctx.Err() != nil {
+		p.log.Debug("Skipping restore, context cancelled", "ref", p.config.Meta.ID)
+		return nil
+	}
+
+	p.log.Info("Restore Cluster", "ref", p.config.Meta.ID)
+
+	return p.createK3s(ctx, s)
+}
+
 func (p *ClusterProvider) Changed() (bool, error) {
 	p.log.Debug("Checking changes Leaf Certificate", "ref", p.config.Meta.Name)
 
@@ -133,7 +248,10 @@ func (p *ClusterProvider) Changed() (bool, error) {
 }
 
 // ImportLocalDockerImages fetches Docker images stored on the local client and imports them into the cluster
-func (p *ClusterProvider) ImportLocalDockerImages(images []ctypes.Image, force bool) error {
+func (p *ClusterProvider) ImportLocalDockerImages(ctx context.Context, images []ctypes.Image, force bool) (err error) {
+	ctx, span := tracing.Start(ctx, "ImportLocalDockerImages", attribute.String(tracing.AttributeResourceID, p.config.Meta.ID))
+	defer func() { tracing.End(span, err) }()
+
 	id, err := p.Lookup()
 	if err != nil {
 		return err
@@ -147,7 +265,9 @@ func (p *ClusterProvider) ImportLocalDockerImages(images []ctypes.Image, force b
 			continue
 		}
 
-		err := p.client.PullImage(i, false)
+		err := tracing.Trace(ctx, "PullImage", func() error {
+			return p.client.PullImage(i, false)
+		}, tracing.Images(i.Name))
 		if err != nil {
 			return err
 		}
@@ -156,8 +276,12 @@ func (p *ClusterProvider) ImportLocalDockerImages(images []ctypes.Image, force b
 	}
 
 	// import to volume
-	vn := utils.FQDNVolumeName(utils.ImageVolumeName)
-	imagesFile, err := p.client.CopyLocalDockerImagesToVolume(imgs, vn, force)
+	vn := p.env.FQDNVolumeName(utils.ImageVolumeName)
+	var imagesFile []string
+	err = tracing.Trace(ctx, "CopyLocalDockerImagesToVolume", func() (err error) {
+		imagesFile, err = p.client.CopyLocalDockerImagesToVolume(imgs, vn, force)
+		return err
+	}, tracing.Images(imgs...))
 	if err != nil {
 		return err
 	}
@@ -244,9 +368,9 @@ func (p *ClusterProvider) getChangedImages() ([]ctypes.Image, error) {
 	return changed, nil
 }
 
-func (p *ClusterProvider) createK3s(ctx context.Context) error {
-	p.log.Info("Creating Cluster", "ref", p.config.Meta.ID)
-
+// createK3s creates the server container for the cluster, when snapshot is
+// not nil the container is created from the snapshot
+func (p *ClusterProvider) createK3s(ctx context.Context, snapshot *cclient.Snapshot) error {
 	// check the cluster does not already exist
 	ids, err := p.Lookup()
 	if err != nil {
@@ -258,10 +382,14 @@ func (p *ClusterProvider) createK3s(ctx context.Context) error {
 	}
 
 	img := ctypes.Image{Name: p.config.Image.Name, Username: p.config.Image.Username, Password: p.config.Image.Password}
-	// pull the container image
-	err = p.client.PullImage(img, false)
-	if err != nil {
-		return err
+	// pull the container image, a restored cluster uses the committed image
+	if snapshot == nil {
+		err = tracing.Trace(ctx, "PullImage", func() error {
+			return p.client.PullImage(img, false)
+		}, tracing.Images(img.Name))
+		if err != nil {
+			return err
+		}
 	}
 
 	// create the volume for the cluster
@@ -272,7 +400,7 @@ func (p *ClusterProvider) createK3s(ctx context.Context) error {
 
 	// create the server
 	name := fmt.Sprintf("server.%s", p.config.Meta.Name)
-	fqrn := utils.FQDN(name, p.config.Meta.Module, p.config.Meta.Type)
+	fqrn := p.env.FQDN(name, p.config.Meta.Module, p.config.Meta.Type)
 
 	cc := &ctypes.Container{}
 	cc.Name = fqrn
@@ -289,6 +417,11 @@ func (p *ClusterProvider) createK3s(ctx context.Context) error {
 		})
 	}
 
+	// the node keeps the addresses it had when the snapshot was taken
+	if snapshot != nil {
+		cc.Networks = container.NetworkAttachments(p.config.Networks).ToStartNetworkAttachments()
+	}
+
 	// set the volume mount for the images
 	cc.Volumes = []ctypes.Volume{
 		{
@@ -352,13 +485,13 @@ func (p *ClusterProvider) createK3s(ctx context.Context) error {
 
 	if sv.Check(v) {
 		// load the CA from a file
-		ca, err := os.ReadFile(filepath.Join(utils.CertsDir(""), "/root.cert"))
+		ca, err := os.ReadFile(filepath.Join(p.env.CertsDir(""), "/root.cert"))
 		if err != nil {
 			return fmt.Errorf("unable to read root CA for proxy: %s", err)
 		}
 
-		cc.Environment["CONTAINERD_HTTP_PROXY"] = utils.ImageCacheAddress()
-		cc.Environment["CONTAINERD_HTTPS_PROXY"] = utils.ImageCacheAddress()
+		cc.Environment["CONTAINERD_HTTP_PROXY"] = p.env.ImageCacheAddress()
+		cc.Environment["CONTAINERD_HTTPS_PROXY"] = p.env.ImageCacheAddress()
 		cc.Environment["PROXY_CA"] = string(ca)
 
 		// add the no-proxy overrides
@@ -374,8 +507,11 @@ func (p *ClusterProvider) createK3s(ctx context.Context) error {
 		cc.Environment[k] = v
 	}
 
-	// set the Connector server port to a random number
-	p.config.ConnectorPort = rand.Intn(utils.MaxRandomPort-utils.MinRandomPort) + utils.MinRandomPort
+	// set the Connector server port to a random number, a restored cluster
+	// keeps the port that the deployed connector is listening on
+	if snapshot == nil || p.config.ConnectorPort == 0 {
+		p.config.ConnectorPort = rand.Intn(utils.MaxRandomPort-utils.MinRandomPort) + utils.MinRandomPort
+	}
 
 	// determine the snapshotter, if a storage driver other than overlay is used then
 	// snapshotter must be set to native or the container will not start
@@ -405,7 +541,7 @@ func (p *ClusterProvider) createK3s(ctx context.Context) error {
 	}
 
 	// create the server address
-	FQDN := fmt.Sprintf("server.%s", utils.FQDN(p.config.Meta.Name, p.config.Meta.Module, p.config.Meta.Type))
+	FQDN := fmt.Sprintf("server.%s", p.env.FQDN(p.config.Meta.Name, p.config.Meta.Module, p.config.Meta.Type))
 	p.config.ContainerName = FQDN
 
 	// Set the default startup args
@@ -476,6 +612,13 @@ func (p *ClusterProvider) createK3s(ctx context.Context) error {
 
 	cc.Command = args
 
+	if snapshot != nil {
+		err := snapshot.Restore(cc)
+		if err != nil {
+			return err
+		}
+	}
+
 	id, err := p.client.CreateContainer(cc)
 	if err != nil {
 		return err
@@ -560,8 +703,9 @@ func (p *ClusterProvider) createK3s(ctx context.Context) error {
 	}
 
 	// import the images to the servers container d instance
-	// importing images means that k3s does not need to pull from a remote docker hub
-	if len(p.config.CopyImages) > 0 {
+	// importing images means that k3s does not need to pull from a remote docker hub,
+	// restored clusters already contain the images
+	if len(p.config.CopyImages) > 0 && snapshot == nil {
 		imgs := []ctypes.Image{}
 		for _, i := range p.config.CopyImages {
 			imgs = append(imgs, ctypes.Image{
@@ -572,7 +716,7 @@ func (p *ClusterProvider) createK3s(ctx context.Context) error {
 
 		}
 
-		err := p.ImportLocalDockerImages(imgs, false)
+		err := p.ImportLocalDockerImages(ctx, imgs, false)
 		if err != nil {
 			return fmt.Errorf("unable to importing Docker images: %w", err)
 		}
@@ -622,7 +766,7 @@ func (p *ClusterProvider) waitForStart(ctx context.Context, id string) error {
 
 func (p *ClusterProvider) copyKubeConfig(id string) (string, error) {
 	// create destination kubeconfig file paths
-	_, kubePath, _ := utils.CreateKubeConfigPath(p.config.Meta.ID)
+	_, kubePath, _ := p.env.CreateKubeConfigPath(p.config.Meta.ID)
 
 	// get kubeconfig file from container and read contents
 	err := p.client.CopyFromContainer(id, "/output/kubeconfig.yaml", kubePath)
@@ -635,7 +779,7 @@ func (p *ClusterProvider) copyKubeConfig(id string) (string, error) {
 
 func (p *ClusterProvider) createLocalKubeConfig(kubeconfig string) (string, error) {
 	ip := utils.GetDockerIP()
-	_, kubePath, _ := utils.CreateKubeConfigPath(p.config.Meta.ID)
+	_, kubePath, _ := p.env.CreateKubeConfigPath(p.config.Meta.ID)
 
 	err := p.changeServerAddressInK8sConfig(
 		fmt.Sprintf("https://%s", ip),
@@ -685,7 +829,7 @@ func (p *ClusterProvider) changeServerAddressInK8sConfig(addr, origFile, newFile
 // once it has started
 func (p *ClusterProvider) deployConnector(ctx context.Context, grpcPort, httpPort int) error {
 	// generate the certificates for the service
-	cb, err := p.connector.GetLocalCertBundle(utils.CertsDir(""))
+	cb, err := p.connector.GetLocalCertBundle(p.env.CertsDir(""))
 	if err != nil {
 		return fmt.Errorf("unable to fetch root certificates for ingress: %s", err)
 	}
@@ -700,7 +844,7 @@ func (p *ClusterProvider) deployConnector(ctx context.Context, grpcPort, httpPor
 			fmt.Sprintf("%s:%d", utils.GetDockerIP(), grpcPort),
 		},
 		[]string{utils.GetDockerIP()},
-		utils.CertsDir(p.config.Meta.Name),
+		p.env.CertsDir(p.config.Meta.Name),
 	)
 
 	if err != nil {
@@ -781,7 +925,7 @@ func (p *ClusterProvider) destroyK3s(force bool) error {
 		}
 	}
 
-	configDir, _, _ := utils.CreateKubeConfigPath(p.config.Meta.ID)
+	configDir, _, _ := p.env.CreateKubeConfigPath(p.config.Meta.ID)
 	os.RemoveAll(configDir)
 
 	return nil
@@ -789,7 +933,7 @@ func (p *ClusterProvider) destroyK3s(force bool) error {
 
 // createRegistriesConfig creates the k3s mirrors config for the cluster
 func (p *ClusterProvider) createRegistriesConfig() (string, error) {
-	dir, _, _ := utils.CreateKubeConfigPath(p.config.Meta.ID)
+	dir, _, _ := p.env.CreateKubeConfigPath(p.config.Meta.ID)
 	daemonConfigPath := path.Join(dir, "registries.yaml")
 
 	// remove any existing files, fail silently
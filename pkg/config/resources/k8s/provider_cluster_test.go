@@ -14,6 +14,7 @@ import (
 	htypes "github.com/jumppad-labs/hclconfig/types"
 	conmocks "github.com/jumppad-labs/jumppad/pkg/clients/connector/mocks"
 	contypes "github.com/jumppad-labs/jumppad/pkg/clients/connector/types"
+	cclient "github.com/jumppad-labs/jumppad/pkg/clients/container"
 	cmocks "github.com/jumppad-labs/jumppad/pkg/clients/container/mocks"
 	ctypes "github.com/jumppad-labs/jumppad/pkg/clients/container/types"
 	"github.com/jumppad-labs/jumppad/pkg/clients/k8s"
@@ -134,7 +135,7 @@ func TestClusterK3ErrorsWhenUnableToLookupIDs(t *testing.T) {
 	md.On("FindContainerIDs", mock.Anything, mock.Anything).Return(nil, fmt.Errorf("boom"))
 
 	mk := &k8s.MockKubernetes{}
-	p := ClusterProvider{clusterConfig, md, mk, nil, nil, logger.NewTestLogger(t)}
+	p := ClusterProvider{clusterConfig, md, mk, nil, nil, logger.NewTestLogger(t), utils.Environment{}}
 
 	err := p.Create(context.Background())
 	assert.Error(t, err)
@@ -144,7 +145,7 @@ func TestClusterK3SetsEnvironment(t *testing.T) {
 	cc, md, mk, mc := setupClusterMocks(t)
 	cc.Image = nil
 
-	p := ClusterProvider{clusterConfig, md, mk, nil, mc, logger.NewTestLogger(t)}
+	p := ClusterProvider{clusterConfig, md, mk, nil, mc, logger.NewTestLogger(t), utils.Environment{}}
 
 	err := p.Create(context.Background())
 	assert.NoError(t, err)
@@ -162,7 +163,7 @@ func TestClusterK3DoesNotSetProxyEnvironmentWithWrongVersion(t *testing.T) {
 	cc, md, mk, mc := setupClusterMocks(t)
 	cc.Image = &container.Image{Name: "jumppad.dev/k3s:v1.12.1"}
 
-	p := ClusterProvider{cc, md, mk, nil, mc, logger.NewTestLogger(t)}
+	p := ClusterProvider{cc, md, mk, nil, mc, logger.NewTestLogger(t), utils.Environment{}}
 
 	err := p.Create(context.Background())
 	assert.NoError(t, err)
@@ -176,7 +177,7 @@ func TestClusterK3NoProxyIsSet(t *testing.T) {
 	cc, md, mk, mc := setupClusterMocks(t)
 	cc.Config = &ClusterConfig{DockerConfig: &DockerConfig{NoProxy: []string{"test.com", "test2.com"}}}
 
-	p := ClusterProvider{cc, md, mk, nil, mc, logger.NewTestLogger(t)}
+	p := ClusterProvider{cc, md, mk, nil, mc, logger.NewTestLogger(t), utils.Environment{}}
 
 	err := p.Create(context.Background())
 	assert.NoError(t, err)
@@ -190,7 +191,7 @@ func TestClusterK3ErrorsWhenClusterExists(t *testing.T) {
 	md.On("FindContainerIDs", utils.FQDN("server."+clusterConfig.Meta.Name, "", TypeK8sCluster)).Return([]string{"abc"}, nil)
 
 	mk := &k8s.MockKubernetes{}
-	p := ClusterProvider{clusterConfig, md, mk, nil, nil, logger.NewTestLogger(t)}
+	p := ClusterProvider{clusterConfig, md, mk, nil, nil, logger.NewTestLogger(t), utils.Environment{}}
 
 	err := p.Create(context.Background())
 	assert.Error(t, err)
@@ -198,7 +199,7 @@ func TestClusterK3ErrorsWhenClusterExists(t *testing.T) {
 
 func TestClusterK3PullsImage(t *testing.T) {
 	cc, md, mk, mc := setupClusterMocks(t)
-	p := ClusterProvider{cc, md, mk, nil, mc, logger.NewTestLogger(t)}
+	p := ClusterProvider{cc, md, mk, nil, mc, logger.NewTestLogger(t), utils.Environment{}}
 
 	err := p.Create(context.Background())
 	assert.NoError(t, err)
@@ -208,7 +209,7 @@ func TestClusterK3PullsImage(t *testing.T) {
 func TestClusterK3CreatesNewVolume(t *testing.T) {
 	cc, md, mk, mc := setupClusterMocks(t)
 
-	p := ClusterProvider{cc, md, mk, nil, mc, logger.NewTestLogger(t)}
+	p := ClusterProvider{cc, md, mk, nil, mc, logger.NewTestLogger(t), utils.Environment{}}
 
 	err := p.Create(context.Background())
 	assert.NoError(t, err)
@@ -221,7 +222,7 @@ func TestClusterK3FailsWhenUnableToCreatesANewVolume(t *testing.T) {
 	testutils.RemoveOn(&md.Mock, "CreateVolume")
 	md.On("CreateVolume", mock.Anything, mock.Anything).Return("", fmt.Errorf("boom"))
 
-	p := ClusterProvider{cc, md, mk, nil, mc, logger.NewTestLogger(t)}
+	p := ClusterProvider{cc, md, mk, nil, mc, logger.NewTestLogger(t), utils.Environment{}}
 
 	err := p.Create(context.Background())
 	assert.Error(t, err)
@@ -231,7 +232,7 @@ func TestClusterK3FailsWhenUnableToCreatesANewVolume(t *testing.T) {
 func TestClusterK3CreatesAServer(t *testing.T) {
 	cc, md, mk, mc := setupClusterMocks(t)
 
-	p := ClusterProvider{cc, md, mk, nil, mc, logger.NewTestLogger(t)}
+	p := ClusterProvider{cc, md, mk, nil, mc, logger.NewTestLogger(t), utils.Environment{}}
 
 	err := p.Create(context.Background())
 	assert.NoError(t, err)
@@ -285,7 +286,7 @@ func TestClusterK3CreatesAServerWithAdditionalPorts(t *testing.T) {
 	cc.Ports = []container.Port{{Local: "8080", Remote: "8080", Host: "8080"}}
 	cc.PortRanges = []container.PortRange{{Range: "8000-9000", EnableHost: true}}
 
-	p := ClusterProvider{cc, md, mk, nil, mc, logger.NewTestLogger(t)}
+	p := ClusterProvider{cc, md, mk, nil, mc, logger.NewTestLogger(t), utils.Environment{}}
 
 	err := p.Create(context.Background())
 	assert.NoError(t, err)
@@ -310,7 +311,7 @@ func TestClusterK3sErrorsIfServerNOTStart(t *testing.T) {
 		nil,
 	)
 
-	p := ClusterProvider{cc, md, mk, nil, mc, logger.NewTestLogger(t)}
+	p := ClusterProvider{cc, md, mk, nil, mc, logger.NewTestLogger(t), utils.Environment{}}
 	startTimeout = 10 * time.Millisecond // reset the startTimeout, do not want to wait 120s
 
 	err := p.Create(context.Background())
@@ -321,7 +322,7 @@ func TestClusterK3sDownloadsConfig(t *testing.T) {
 	cc, md, mk, mc := setupClusterMocks(t)
 	_, kubePath, _ := utils.CreateKubeConfigPath(cc.Meta.ID)
 
-	p := ClusterProvider{cc, md, mk, nil, mc, logger.NewTestLogger(t)}
+	p := ClusterProvider{cc, md, mk, nil, mc, logger.NewTestLogger(t), utils.Environment{}}
 
 	err := p.Create(context.Background())
 	assert.NoError(t, err)
@@ -338,7 +339,7 @@ func TestClusterK3sRaisesErrorWhenUnableToDownloadConfig(t *testing.T) {
 	testutils.RemoveOn(&md.Mock, "CopyFromContainer")
 	md.On("CopyFromContainer", mock.Anything, mock.Anything, mock.Anything).Return(fmt.Errorf("boom"))
 
-	p := ClusterProvider{cc, md, mk, nil, mc, logger.NewTestLogger(t)}
+	p := ClusterProvider{cc, md, mk, nil, mc, logger.NewTestLogger(t), utils.Environment{}}
 
 	err := p.Create(context.Background())
 	assert.Error(t, err)
@@ -354,7 +355,7 @@ func TestClusterK3sSetsServerInConfig(t *testing.T) {
 
 	cc, md, mk, mc := setupClusterMocks(t)
 
-	p := ClusterProvider{cc, md, mk, nil, mc, logger.NewTestLogger(t)}
+	p := ClusterProvider{cc, md, mk, nil, mc, logger.NewTestLogger(t), utils.Environment{}}
 
 	err := p.Create(context.Background())
 	assert.NoError(t, err)
@@ -376,7 +377,7 @@ func TestClusterK3sSetsServerInConfig(t *testing.T) {
 func TestCreateSetsKubeConfig(t *testing.T) {
 	cc, md, mk, mc := setupClusterMocks(t)
 
-	p := ClusterProvider{cc, md, mk, nil, mc, logger.NewTestLogger(t)}
+	p := ClusterProvider{cc, md, mk, nil, mc, logger.NewTestLogger(t), utils.Environment{}}
 
 	err := p.Create(context.Background())
 	assert.NoError(t, err)
@@ -390,7 +391,7 @@ func TestCreateSetsKubeConfig(t *testing.T) {
 func TestClusterK3sCreatesKubeClient(t *testing.T) {
 	cc, md, mk, mc := setupClusterMocks(t)
 
-	p := ClusterProvider{cc, md, mk, nil, mc, logger.NewTestLogger(t)}
+	p := ClusterProvider{cc, md, mk, nil, mc, logger.NewTestLogger(t), utils.Environment{}}
 
 	err := p.Create(context.Background())
 	assert.NoError(t, err)
@@ -403,7 +404,7 @@ func TestClusterK3sErrorsWhenFailedToCreateKubeClient(t *testing.T) {
 	testutils.RemoveOn(&mk.Mock, "SetConfig")
 	mk.Mock.On("SetConfig", mock.Anything).Return(fmt.Errorf("boom"))
 
-	p := ClusterProvider{cc, md, mk, nil, mc, logger.NewTestLogger(t)}
+	p := ClusterProvider{cc, md, mk, nil, mc, logger.NewTestLogger(t), utils.Environment{}}
 
 	err := p.Create(context.Background())
 	assert.Error(t, err)
@@ -412,7 +413,7 @@ func TestClusterK3sErrorsWhenFailedToCreateKubeClient(t *testing.T) {
 func TestClusterK3sWaitsForPods(t *testing.T) {
 	cc, md, mk, mc := setupClusterMocks(t)
 
-	p := ClusterProvider{cc, md, mk, nil, mc, logger.NewTestLogger(t)}
+	p := ClusterProvider{cc, md, mk, nil, mc, logger.NewTestLogger(t), utils.Environment{}}
 
 	err := p.Create(context.Background())
 	assert.NoError(t, err)
@@ -425,7 +426,7 @@ func TestClusterK3sErrorsWhenWaitsForPodsFail(t *testing.T) {
 	testutils.RemoveOn(&mk.Mock, "HealthCheckPods")
 	mk.On("HealthCheckPods", mock.Anything, mock.Anything, mock.Anything).Return(fmt.Errorf("boom"))
 
-	p := ClusterProvider{cc, md, mk, nil, mc, logger.NewTestLogger(t)}
+	p := ClusterProvider{cc, md, mk, nil, mc, logger.NewTestLogger(t), utils.Environment{}}
 
 	err := p.Create(context.Background())
 	assert.Error(t, err)
@@ -435,7 +436,7 @@ func TestClusterK3sStreamsLogsWhenRunning(t *testing.T) {
 	cc, md, mk, mc := setupClusterMocks(t)
 
 	mk.On("GetPodLogs", mock.Anything, mock.Anything).Return(fmt.Errorf("boom"))
-	p := ClusterProvider{cc, md, mk, nil, mc, logger.NewTestLogger(t)}
+	p := ClusterProvider{cc, md, mk, nil, mc, logger.NewTestLogger(t), utils.Environment{}}
 
 	err := p.Create(context.Background())
 	assert.NoError(t, err)
@@ -455,7 +456,7 @@ func TestClusterK3sImportDockerImagesDoesNothingWhenEmpty(t *testing.T) {
 	md.On("ExecuteCommand", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(0, nil)
 	md.On("FindImageInLocalRegistry", mock.Anything).Return("abc123", nil)
 
-	p := ClusterProvider{cc, md, mk, nil, mc, logger.NewTestLogger(t)}
+	p := ClusterProvider{cc, md, mk, nil, mc, logger.NewTestLogger(t), utils.Environment{}}
 
 	err := p.Create(context.Background())
 	assert.NoError(t, err)
@@ -482,7 +483,7 @@ func TestClusterK3sImportDockerImagesPullsImages(t *testing.T) {
 	md.On("ExecuteCommand", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(0, nil)
 	md.On("FindImageInLocalRegistry", mock.Anything).Return("abc123", nil)
 
-	p := ClusterProvider{cc, md, mk, nil, mc, logger.NewTestLogger(t)}
+	p := ClusterProvider{cc, md, mk, nil, mc, logger.NewTestLogger(t), utils.Environment{}}
 
 	err := p.Create(context.Background())
 	assert.NoError(t, err)
@@ -500,7 +501,7 @@ func TestClusterK3sImportDockerCopiesImages(t *testing.T) {
 	md.On("ExecuteCommand", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(0, nil)
 	md.On("FindImageInLocalRegistry", mock.Anything).Return("abc123", nil)
 
-	p := ClusterProvider{cc, md, mk, nil, mc, logger.NewTestLogger(t)}
+	p := ClusterProvider{cc, md, mk, nil, mc, logger.NewTestLogger(t), utils.Environment{}}
 
 	err := p.Create(context.Background())
 	assert.NoError(t, err)
@@ -518,7 +519,7 @@ func TestClusterK3sImportDockerCopyImageFailReturnsError(t *testing.T) {
 	testutils.RemoveOn(&md.Mock, "CopyLocalDockerImagesToVolume")
 	md.On("CopyLocalDockerImagesToVolume", mock.Anything, mock.Anything, mock.Anything).Return([]string{}, fmt.Errorf("boom"))
 
-	p := ClusterProvider{cc, md, mk, nil, mc, logger.NewTestLogger(t)}
+	p := ClusterProvider{cc, md, mk, nil, mc, logger.NewTestLogger(t), utils.Environment{}}
 
 	err := p.Create(context.Background())
 	assert.Error(t, err)
@@ -533,7 +534,7 @@ func TestClusterK3sImportDockerRunsExecCommand(t *testing.T) {
 	md.On("ExecuteCommand", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(0, nil)
 	md.On("FindImageInLocalRegistry", mock.Anything).Return("abc123", nil)
 
-	p := ClusterProvider{cc, md, mk, nil, mc, logger.NewTestLogger(t)}
+	p := ClusterProvider{cc, md, mk, nil, mc, logger.NewTestLogger(t), utils.Environment{}}
 	err := p.Create(context.Background())
 
 	assert.NoError(t, err)
@@ -549,7 +550,7 @@ func TestClusterK3sImportDockerExecFailReturnsError(t *testing.T) {
 	md.On("ExecuteCommand", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(1, fmt.Errorf("boom"))
 	md.On("FindImageInLocalRegistry", mock.Anything).Return("abc123", nil)
 
-	p := ClusterProvider{cc, md, mk, nil, mc, logger.NewTestLogger(t)}
+	p := ClusterProvider{cc, md, mk, nil, mc, logger.NewTestLogger(t), utils.Environment{}}
 
 	err := p.Create(context.Background())
 	assert.Error(t, err)
@@ -558,7 +559,7 @@ func TestClusterK3sImportDockerExecFailReturnsError(t *testing.T) {
 func TestClusterK3sGeneratesCertsForConnector(t *testing.T) {
 	cc, md, mk, mc := setupClusterMocks(t)
 
-	p := ClusterProvider{cc, md, mk, nil, mc, logger.NewTestLogger(t)}
+	p := ClusterProvider{cc, md, mk, nil, mc, logger.NewTestLogger(t), utils.Environment{}}
 
 	err := p.Create(context.Background())
 	assert.NoError(t, err)
@@ -577,7 +578,7 @@ func TestClusterK3sGeneratesCertsForConnector(t *testing.T) {
 func TestClusterK3sGeneratesCertsForDeployment(t *testing.T) {
 	cc, md, mk, mc := setupClusterMocks(t)
 
-	p := ClusterProvider{cc, md, mk, nil, mc, logger.NewTestLogger(t)}
+	p := ClusterProvider{cc, md, mk, nil, mc, logger.NewTestLogger(t), utils.Environment{}}
 
 	err := p.Create(context.Background())
 	assert.NoError(t, err)
@@ -591,7 +592,7 @@ func TestClusterK3sGeneratesCertsForDeployment(t *testing.T) {
 func TestClusterK3sDeploysConnector(t *testing.T) {
 	cc, md, mk, mc := setupClusterMocks(t)
 
-	p := ClusterProvider{cc, md, mk, nil, mc, logger.NewTestLogger(t)}
+	p := ClusterProvider{cc, md, mk, nil, mc, logger.NewTestLogger(t), utils.Environment{}}
 
 	err := p.Create(context.Background())
 	assert.NoError(t, err)
@@ -616,7 +617,7 @@ func TestClusterK3sDeploysConnector(t *testing.T) {
 func TestClusterK3sWaitsForConnectorStart(t *testing.T) {
 	cc, md, mk, mc := setupClusterMocks(t)
 
-	p := ClusterProvider{cc, md, mk, nil, mc, logger.NewTestLogger(t)}
+	p := ClusterProvider{cc, md, mk, nil, mc, logger.NewTestLogger(t), utils.Environment{}}
 
 	err := p.Create(context.Background())
 	assert.NoError(t, err)
@@ -625,10 +626,53 @@ func TestClusterK3sWaitsForConnectorStart(t *testing.T) {
 }
 
 // Destroy Tests
+func TestClusterK3sSnapshotSavesServerContainer(t *testing.T) {
+	cc := deepcopy.Copy(clusterConfig).(*Cluster)
+	cc.ContainerName = "server.test.k8s-cluster.local.jmpd.in"
+
+	md := &cmocks.ContainerTasks{}
+	md.On("FindContainerIDs", cc.ContainerName).Return([]string{"abc"}, nil)
+	md.On("CommitContainer", "abc", mock.Anything).Return("sha256:123", nil)
+	md.On("ExportVolumes", "abc", mock.Anything).Return(nil, nil)
+
+	p := ClusterProvider{cc, md, nil, nil, nil, logger.NewTestLogger(t), utils.Environment{}}
+	s := cclient.NewSnapshot("test", t.TempDir())
+
+	err := p.Snapshot(context.Background(), s)
+	assert.NoError(t, err)
+
+	md.AssertCalled(t, "CommitContainer", "abc", s.ImageName(cc.ContainerName))
+	assert.Contains(t, s.Containers, cc.ContainerName)
+}
+
+func TestClusterK3sRestoresFromSnapshot(t *testing.T) {
+	cc, md, mk, mc := setupClusterMocks(t)
+	cc.ConnectorPort = 31000
+	cc.CopyImages = append(cc.CopyImages, container.Image{Name: "test:123"})
+	cc.Networks = []container.NetworkAttachment{{ID: "cloud", AssignedAddress: "10.6.0.5"}}
+
+	name := fmt.Sprintf("server.%s", utils.FQDN(cc.Meta.Name, cc.Meta.Module, cc.Meta.Type))
+	s := cclient.NewSnapshot("test", t.TempDir())
+	s.Containers[name] = cclient.SnapshotContainer{Image: s.ImageName(name)}
+
+	p := ClusterProvider{cc, md, mk, nil, mc, logger.NewTestLogger(t), utils.Environment{}}
+
+	err := p.Restore(context.Background(), s)
+	assert.NoError(t, err)
+
+	md.AssertNotCalled(t, "PullImage", mock.Anything, mock.Anything)
+	md.AssertNotCalled(t, "CopyLocalDockerImagesToVolume", mock.Anything, mock.Anything, mock.Anything)
+
+	params := testutils.GetCalls(&md.Mock, "CreateContainer")[0].Arguments[0].(*ctypes.Container)
+	assert.Equal(t, s.ImageName(name), params.Image.Name)
+	assert.Equal(t, "10.6.0.5", params.Networks[0].IPAddress)
+	assert.Equal(t, 31000, cc.ConnectorPort)
+}
+
 func TestClusterK3sDestroyGetsIDr(t *testing.T) {
 	cc, md, mk, mc := setupClusterMocks(t)
 
-	p := ClusterProvider{cc, md, mk, nil, mc, logger.NewTestLogger(t)}
+	p := ClusterProvider{cc, md, mk, nil, mc, logger.NewTestLogger(t), utils.Environment{}}
 
 	err := p.Destroy(context.Background(), false)
 	assert.NoError(t, err)
@@ -640,7 +684,7 @@ func TestClusterK3sDestroyWithFindIDErrorReturnsError(t *testing.T) {
 	testutils.RemoveOn(&md.Mock, "FindContainerIDs")
 	md.On("FindContainerIDs", mock.Anything, mock.Anything).Return(nil, fmt.Errorf("boom"))
 
-	p := ClusterProvider{cc, md, mk, nil, mc, logger.NewTestLogger(t)}
+	p := ClusterProvider{cc, md, mk, nil, mc, logger.NewTestLogger(t), utils.Environment{}}
 
 	err := p.Destroy(context.Background(), false)
 	assert.Error(t, err)
@@ -651,7 +695,7 @@ func TestClusterK3sDestroyWithNoIDReturns(t *testing.T) {
 	testutils.RemoveOn(&md.Mock, "FindContainerIDs")
 	md.On("FindContainerIDs", mock.Anything, mock.Anything).Return(nil, nil)
 
-	p := ClusterProvider{cc, md, mk, nil, mc, logger.NewTestLogger(t)}
+	p := ClusterProvider{cc, md, mk, nil, mc, logger.NewTestLogger(t), utils.Environment{}}
 
 	err := p.Destroy(context.Background(), false)
 	assert.NoError(t, err)
@@ -663,7 +707,7 @@ func TestClusterK3sDestroyRemovesContainer(t *testing.T) {
 	testutils.RemoveOn(&md.Mock, "FindContainerIDs")
 	md.On("FindContainerIDs", mock.Anything, mock.Anything).Return([]string{"found"}, nil)
 
-	p := ClusterProvider{cc, md, mk, nil, mc, logger.NewTestLogger(t)}
+	p := ClusterProvider{cc, md, mk, nil, mc, logger.NewTestLogger(t), utils.Environment{}}
 
 	err := p.Destroy(context.Background(), false)
 	assert.NoError(t, err)
@@ -677,7 +721,7 @@ func TestClusterK3sDestroyRemovesConfig(t *testing.T) {
 
 	dir, _, _ := utils.CreateKubeConfigPath(cc.Meta.Name)
 
-	p := ClusterProvider{cc, md, mk, nil, mc, logger.NewTestLogger(t)}
+	p := ClusterProvider{cc, md, mk, nil, mc, logger.NewTestLogger(t), utils.Environment{}}
 
 	err := p.Destroy(context.Background(), false)
 	assert.NoError(t, err)
@@ -692,7 +736,7 @@ func TestLookupReturnsIDs(t *testing.T) {
 	testutils.RemoveOn(&md.Mock, "FindContainerIDs")
 	md.On("FindContainerIDs", mock.Anything, mock.Anything).Return([]string{"found"}, nil)
 
-	p := ClusterProvider{cc, md, mk, nil, mc, logger.NewTestLogger(t)}
+	p := ClusterProvider{cc, md, mk, nil, mc, logger.NewTestLogger(t), utils.Environment{}}
 
 	ids, err := p.Lookup()
 
@@ -9,6 +9,7 @@ import (
 	htypes "github.com/jumppad-labs/hclconfig/types"
 	"github.com/jumppad-labs/jumppad/pkg/clients"
 	"github.com/jumppad-labs/jumppad/pkg/clients/k8s"
+	"github.com/jumppad-labs/jumppad/pkg/jumppad/events"
 	"github.com/jumppad-labs/jumppad/pkg/utils"
 	sdk "github.com/jumppad-labs/plugin-sdk"
 )
@@ -22,16 +23,20 @@ type ConfigProvider struct {
 }
 
 func (p *ConfigProvider) Init(cfg htypes.Resource, l sdk.Logger) error {
-	c, ok := cfg.(*Config)
-	if !ok {
-		return fmt.Errorf("unable to initialize Config provider, resource is not of type K8sConfig")
-	}
-
 	cli, err := clients.GenerateClients(l)
 	if err != nil {
 		return err
 	}
 
+	return p.InitWithClients(cfg, l, cli)
+}
+
+func (p *ConfigProvider) InitWithClients(cfg htypes.Resource, l sdk.Logger, cli *clients.Clients) error {
+	c, ok := cfg.(*Config)
+	if !ok {
+		return fmt.Errorf("unable to initialize Config provider, resource is not of type K8sConfig")
+	}
+
 	p.config = c
 	p.client = cli.Kubernetes
 	p.log = l
@@ -68,10 +73,14 @@ func (p *ConfigProvider) create(ctx context.Context) error {
 			return fmt.Errorf("unable to parse healthcheck duration: %w", err)
 		}
 
+		events.HealthCheck(ctx, p.config.Meta.ID, p.config.Meta.Type, fmt.Sprintf("waiting for pods %v", p.config.HealthCheck.Pods))
+
 		err = p.client.HealthCheckPods(ctx, p.config.HealthCheck.Pods, to)
 		if err != nil {
 			return fmt.Errorf("healthcheck failed after helm chart setup: %w", err)
 		}
+
+		events.HealthCheck(ctx, p.config.Meta.ID, p.config.Meta.Type, "pods are running")
 	}
 
 	// set the checksums
@@ -4,8 +4,8 @@ import (
 	"fmt"
 
 	"github.com/jumppad-labs/hclconfig/types"
-	"github.com/jumppad-labs/jumppad/pkg/config"
 	"github.com/jumppad-labs/jumppad/pkg/config/resources/container"
+	"github.com/jumppad-labs/jumppad/pkg/config/resources/lifecycle"
 	"github.com/jumppad-labs/jumppad/pkg/utils"
 )
 
@@ -18,6 +18,8 @@ type Cluster struct {
 	// embedded type holding name, etc.
 	types.ResourceBase `hcl:",remain"`
 
+	Lifecycle *lifecycle.Lifecycle `hcl:"lifecycle,block" json:"lifecycle,omitempty"`
+
 	Networks []container.NetworkAttachment `hcl:"network,block" json:"networks,omitempty"` // Attach to the correct network // only when Image is specified
 
 	Image   *container.Image   `hcl:"image,block" json:"images,omitempty"` // optional image to use when creating the cluster
@@ -109,46 +111,39 @@ func (k *Cluster) Process() error {
 		}
 	}
 
-	// do we have an existing resource in the state?
-	// if so we need to set any computed resources for dependents
-	c, err := config.LoadState()
-	if err == nil {
-		// try and find the resource in the state
-		r, _ := c.FindResource(k.Meta.ID)
-		if r != nil {
-			kstate := r.(*Cluster)
-			k.KubeConfig = kstate.KubeConfig
-			k.ContainerName = kstate.ContainerName
-			k.APIPort = kstate.APIPort
-			k.ConnectorPort = kstate.ConnectorPort
-			k.ExternalIP = kstate.ExternalIP
-			k.KubeConfig = kstate.KubeConfig
-			k.Resources = kstate.Resources
-
-			// add the network addresses
-			for _, a := range kstate.Networks {
-				for i, m := range k.Networks {
-					if m.ID == a.ID {
-						k.Networks[i].IPAddress = a.IPAddress
-						k.Networks[i].Name = a.Name
-						break
-					}
-				}
-			}
+	return nil
+}
 
-			// add the image id from state
-			for x, img := range k.CopyImages {
-				for _, sImg := range kstate.CopyImages {
-					if img.Name == sImg.Name && img.Username == sImg.Username {
-						k.CopyImages[x].ID = sImg.ID
-					}
-				}
+func (k *Cluster) RestoreState(r types.Resource) {
+	kstate := r.(*Cluster)
+	k.KubeConfig = kstate.KubeConfig
+	k.ContainerName = kstate.ContainerName
+	k.APIPort = kstate.APIPort
+	k.ConnectorPort = kstate.ConnectorPort
+	k.ExternalIP = kstate.ExternalIP
+	k.KubeConfig = kstate.KubeConfig
+	k.Resources = kstate.Resources
+
+	// add the network addresses
+	for _, a := range kstate.Networks {
+		for i, m := range k.Networks {
+			if m.ID == a.ID {
+				k.Networks[i].IPAddress = a.IPAddress
+				k.Networks[i].Name = a.Name
+				break
 			}
+		}
+	}
 
-			// the network name is set
-			copy(k.Networks, kstate.Networks)
+	// add the image id from state
+	for x, img := range k.CopyImages {
+		for _, sImg := range kstate.CopyImages {
+			if img.Name == sImg.Name && img.Username == sImg.Username {
+				k.CopyImages[x].ID = sImg.ID
+			}
 		}
 	}
 
-	return nil
+	// the network name is set
+	copy(k.Networks, kstate.Networks)
 }
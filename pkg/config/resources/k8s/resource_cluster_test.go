@@ -72,6 +72,10 @@ func TestK8sClusterSetsOutputsFromState(t *testing.T) {
 
 	c.Process()
 
+	sf, err := config.LoadState()
+	require.NoError(t, err)
+	config.RestoreState(c, sf)
+
 	// check the output parameters
 	require.Equal(t, "127.0.0.1", c.ExternalIP)
 	require.Equal(t, 123, c.APIPort)
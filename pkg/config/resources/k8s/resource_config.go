@@ -2,8 +2,8 @@ package k8s
 
 import (
 	"github.com/jumppad-labs/hclconfig/types"
-	"github.com/jumppad-labs/jumppad/pkg/config"
 	"github.com/jumppad-labs/jumppad/pkg/config/resources/healthcheck"
+	"github.com/jumppad-labs/jumppad/pkg/config/resources/lifecycle"
 	"github.com/jumppad-labs/jumppad/pkg/utils"
 )
 
@@ -15,6 +15,8 @@ const TypeKubernetesConfig string = "kubernetes_config"
 type Config struct {
 	types.ResourceBase `hcl:",remain"`
 
+	Lifecycle *lifecycle.Lifecycle `hcl:"lifecycle,block" json:"lifecycle,omitempty"`
+
 	Cluster Cluster `hcl:"cluster" json:"cluster"`
 
 	// Path of a file or directory of Kubernetes config files to apply
@@ -38,15 +40,10 @@ func (k *Config) Process() error {
 		k.Paths[i] = utils.EnsureAbsolute(p, k.Meta.File)
 	}
 
-	cfg, err := config.LoadState()
-	if err == nil {
-		// try and find the resource in the state
-		r, _ := cfg.FindResource(k.Meta.ID)
-		if r != nil {
-			state := r.(*Config)
-			k.JobChecksums = state.JobChecksums
-		}
-	}
-
 	return nil
 }
+
+func (k *Config) RestoreState(r types.Resource) {
+	state := r.(*Config)
+	k.JobChecksums = state.JobChecksums
+}
@@ -0,0 +1,154 @@
+package lifecycle
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+
+	"github.com/jumppad-labs/hclconfig"
+	"github.com/jumppad-labs/hclconfig/types"
+)
+
+// IgnoreAll is the ignore_changes value that ignores every attribute
+const IgnoreAll = "all"
+
+// Checksum returns the checksum of the resource without the attributes and
+// blocks in ignore. Like the checksum calculated by the parser it is
+// generated from the resolved values of the resource, changes to variables
+// or referenced resources that set attributes which are not ignored change
+// the checksum.
+func Checksum(r types.Resource, ignore []string) (string, error) {
+	for _, i := range ignore {
+		if i == IgnoreAll {
+			return hclconfig.HashString(""), nil
+		}
+	}
+
+	m := r.Metadata()
+
+	// the checksums and properties are set by the engine and are not part
+	// of the configuration, dependencies are sorted as they change
+	// depending on the order the graph is processed
+	cs := m.Checksum
+	props := m.Properties
+	m.Checksum = types.Checksum{}
+	m.Properties = nil
+
+	sort.Strings(r.GetDependencies())
+	sort.Strings(m.Links)
+
+	d, err := json.Marshal(r)
+
+	m.Checksum = cs
+	m.Properties = props
+
+	if err != nil {
+		return "", fmt.Errorf("unable to generate checksum for %s: %s", m.ID, err)
+	}
+
+	var values any
+	err = json.Unmarshal(d, &values)
+	if err != nil {
+		return "", fmt.Errorf("unable to generate checksum for %s: %s", m.ID, err)
+	}
+
+	for _, i := range ignore {
+		removePath(values, jsonPath(reflect.TypeOf(r), strings.Split(i, ".")))
+	}
+
+	// maps are marshaled with sorted keys so the checksum is stable
+	d, err = json.Marshal(values)
+	if err != nil {
+		return "", fmt.Errorf("unable to generate checksum for %s: %s", m.ID, err)
+	}
+
+	return hclconfig.HashString(string(d)), nil
+}
+
+// jsonPath converts the path of an attribute or block in the configuration
+// to the path of the value in the JSON of the resource. The names of blocks
+// often differ from their JSON keys e.g. port and ports, elements of the
+// path that can not be found in the type are returned unchanged.
+func jsonPath(t reflect.Type, path []string) []string {
+	keys := []string{}
+
+	for i, p := range path {
+		f, ok := fieldByHCLName(elemType(t), p)
+		if !ok {
+			return append(keys, path[i:]...)
+		}
+
+		keys = append(keys, jsonName(f))
+		t = f.Type
+	}
+
+	return keys
+}
+
+// fieldByHCLName returns the field of the struct t, or of the structs
+// embedded in t, with the given hcl tag name
+func fieldByHCLName(t reflect.Type, name string) (reflect.StructField, bool) {
+	if t.Kind() != reflect.Struct {
+		return reflect.StructField{}, false
+	}
+
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		tag := strings.Split(f.Tag.Get("hcl"), ",")[0]
+
+		if f.Anonymous && tag == "" {
+			if ef, ok := fieldByHCLName(elemType(f.Type), name); ok {
+				return ef, true
+			}
+
+			continue
+		}
+
+		if tag == name {
+			return f, true
+		}
+	}
+
+	return reflect.StructField{}, false
+}
+
+// elemType returns the type of the values held by pointers, slices and maps
+func elemType(t reflect.Type) reflect.Type {
+	for t.Kind() == reflect.Pointer || t.Kind() == reflect.Slice || t.Kind() == reflect.Array || t.Kind() == reflect.Map {
+		t = t.Elem()
+	}
+
+	return t
+}
+
+// jsonName returns the key used for the field when it is marshaled to JSON
+func jsonName(f reflect.StructField) string {
+	if n := strings.Split(f.Tag.Get("json"), ",")[0]; n != "" {
+		return n
+	}
+
+	return f.Name
+}
+
+// removePath removes the value at path from the unmarshaled JSON v, blocks
+// that can be set more than once are lists and the path is removed from
+// every element
+func removePath(v any, path []string) {
+	switch vt := v.(type) {
+	case []any:
+		for _, e := range vt {
+			removePath(e, path)
+		}
+	case map[string]any:
+		if len(path) == 1 {
+			delete(vt, path[0])
+			return
+		}
+
+		if c, ok := vt[path[0]]; ok {
+			removePath(c, path[1:])
+		}
+	}
+}
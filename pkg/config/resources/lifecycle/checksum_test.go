@@ -0,0 +1,98 @@
+package lifecycle
+
+import (
+	"testing"
+
+	"github.com/jumppad-labs/hclconfig/types"
+	"github.com/stretchr/testify/require"
+)
+
+type checksumImage struct {
+	Name string `hcl:"name" json:"name"`
+}
+
+type checksumContainer struct {
+	types.ResourceBase `hcl:",remain"`
+
+	Images      []checksumImage   `hcl:"image,block" json:"images"`
+	Environment map[string]string `hcl:"environment,optional" json:"environment,omitempty"`
+	Command     []string          `hcl:"command,optional" json:"command,omitempty"`
+
+	Lifecycle *Lifecycle `hcl:"lifecycle,block" json:"lifecycle,omitempty"`
+}
+
+func checksumResource(image, version, command string) *checksumContainer {
+	return &checksumContainer{
+		ResourceBase: types.ResourceBase{
+			Meta: types.Meta{ID: "resource.container.app", Name: "app", Type: "container"},
+		},
+		Images:      []checksumImage{{Name: image}},
+		Environment: map[string]string{"VERSION": version},
+		Command:     []string{command},
+	}
+}
+
+func TestChecksumIgnoresAttributes(t *testing.T) {
+	a, err := Checksum(checksumResource("app:1", "1", "run"), []string{"environment"})
+	require.NoError(t, err)
+
+	b, err := Checksum(checksumResource("app:1", "2", "run"), []string{"environment"})
+	require.NoError(t, err)
+	require.Equal(t, a, b)
+
+	c, err := Checksum(checksumResource("app:1", "2", "start"), []string{"environment"})
+	require.NoError(t, err)
+	require.NotEqual(t, a, c)
+}
+
+func TestChecksumIgnoresNestedAttributesInBlocks(t *testing.T) {
+	a, err := Checksum(checksumResource("app:1", "1", "run"), []string{"image.name"})
+	require.NoError(t, err)
+
+	b, err := Checksum(checksumResource("app:2", "1", "run"), []string{"image.name"})
+	require.NoError(t, err)
+	require.Equal(t, a, b)
+
+	c, err := Checksum(checksumResource("app:2", "2", "run"), []string{"image.name"})
+	require.NoError(t, err)
+	require.NotEqual(t, a, c)
+}
+
+func TestChecksumIgnoresAll(t *testing.T) {
+	a, err := Checksum(checksumResource("app:1", "1", "run"), []string{IgnoreAll})
+	require.NoError(t, err)
+
+	b, err := Checksum(checksumResource("app:2", "2", "start"), []string{IgnoreAll})
+	require.NoError(t, err)
+	require.Equal(t, a, b)
+}
+
+func TestChecksumIsDifferentForModuleInstances(t *testing.T) {
+	a, err := Checksum(checksumResource("app:1", "1", "run"), []string{"environment"})
+	require.NoError(t, err)
+
+	r := checksumResource("app:1", "1", "run")
+	r.Meta.ID = "module.dc1.resource.container.app"
+	r.Meta.Module = "dc1"
+
+	b, err := Checksum(r, []string{"environment"})
+	require.NoError(t, err)
+	require.NotEqual(t, a, b)
+}
+
+func TestChecksumIgnoresEngineMetadata(t *testing.T) {
+	a, err := Checksum(checksumResource("app:1", "1", "run"), []string{"environment"})
+	require.NoError(t, err)
+
+	r := checksumResource("app:1", "1", "run")
+	r.Meta.Checksum = types.Checksum{Parsed: "abc", Processed: "123"}
+	r.Meta.Properties = map[string]any{"status": "created"}
+
+	b, err := Checksum(r, []string{"environment"})
+	require.NoError(t, err)
+	require.Equal(t, a, b)
+
+	// the metadata of the resource is not changed
+	require.Equal(t, "abc", r.Meta.Checksum.Parsed)
+	require.Equal(t, "created", r.Meta.Properties["status"])
+}
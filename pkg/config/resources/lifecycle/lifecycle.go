@@ -0,0 +1,146 @@
+package lifecycle
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+	"time"
+
+	"github.com/jumppad-labs/hclconfig/types"
+)
+
+// DefaultRetryBackoff is the time between attempts when retries are set
+// without a retry_backoff
+const DefaultRetryBackoff = 5 * time.Second
+
+// Lifecycle is an internal block that can be added to any resource to
+// control how the engine creates and destroys it
+//
+//	lifecycle {
+//	  retries         = 3
+//	  retry_backoff   = "10s"
+//	  create_timeout  = "5m"
+//	  destroy_timeout = "2m"
+//
+//	  prevent_destroy = true
+//	  ignore_changes  = ["environment", "image.name"]
+//	}
+type Lifecycle struct {
+	// Retries is the number of times a failed create, refresh or destroy
+	// is attempted again
+	Retries int `hcl:"retries,optional" json:"retries,omitempty"`
+
+	// RetryBackoff expressed as a go duration i.e 10s
+	RetryBackoff string `hcl:"retry_backoff,optional" json:"retry_backoff,omitempty"`
+
+	// CreateTimeout is the maximum time for each attempt to create or
+	// refresh the resource expressed as a go duration i.e 5m
+	CreateTimeout string `hcl:"create_timeout,optional" json:"create_timeout,omitempty"`
+
+	// DestroyTimeout is the maximum time for each attempt to destroy the
+	// resource expressed as a go duration i.e 2m
+	DestroyTimeout string `hcl:"destroy_timeout,optional" json:"destroy_timeout,omitempty"`
+
+	// PreventDestroy causes any operation that would destroy or re-create
+	// the resource to fail
+	PreventDestroy bool `hcl:"prevent_destroy,optional" json:"prevent_destroy,omitempty"`
+
+	// IgnoreChanges are the attributes and blocks that are not compared
+	// when checking if the configuration of the resource has changed.
+	// Nested attributes are separated with a dot i.e. image.name, the
+	// value all ignores changes to every attribute.
+	IgnoreChanges []string `hcl:"ignore_changes,optional" json:"ignore_changes,omitempty"`
+}
+
+// Settings are the parsed values of a Lifecycle block, a zero timeout
+// means the operation has no deadline
+type Settings struct {
+	Retries        int
+	RetryBackoff   time.Duration
+	CreateTimeout  time.Duration
+	DestroyTimeout time.Duration
+	PreventDestroy bool
+	IgnoreChanges  []string
+}
+
+// Parse validates the block and returns the settings
+func (l *Lifecycle) Parse() (*Settings, error) {
+	s := &Settings{RetryBackoff: DefaultRetryBackoff}
+	if l == nil {
+		return s, nil
+	}
+
+	if l.Retries < 0 {
+		return nil, fmt.Errorf("retries must be zero or greater, got %d", l.Retries)
+	}
+
+	s.Retries = l.Retries
+	s.PreventDestroy = l.PreventDestroy
+
+	for _, ic := range l.IgnoreChanges {
+		if strings.TrimSpace(ic) == "" {
+			return nil, fmt.Errorf("ignore_changes must not contain empty values")
+		}
+	}
+
+	s.IgnoreChanges = l.IgnoreChanges
+
+	var err error
+	if l.RetryBackoff != "" {
+		s.RetryBackoff, err = parseDuration("retry_backoff", l.RetryBackoff)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if l.CreateTimeout != "" {
+		s.CreateTimeout, err = parseDuration("create_timeout", l.CreateTimeout)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if l.DestroyTimeout != "" {
+		s.DestroyTimeout, err = parseDuration("destroy_timeout", l.DestroyTimeout)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return s, nil
+}
+
+// FromResource returns the lifecycle block of the given resource, nil is
+// returned when the resource does not have a block or the resource type
+// does not support lifecycle
+func FromResource(r types.Resource) *Lifecycle {
+	v := reflect.ValueOf(r)
+	if v.Kind() == reflect.Ptr {
+		v = v.Elem()
+	}
+
+	if v.Kind() != reflect.Struct {
+		return nil
+	}
+
+	f := v.FieldByName("Lifecycle")
+	if !f.IsValid() {
+		return nil
+	}
+
+	l, _ := f.Interface().(*Lifecycle)
+	return l
+}
+
+func parseDuration(name, value string) (time.Duration, error) {
+	d, err := time.ParseDuration(value)
+	if err != nil {
+		return 0, fmt.Errorf("unable to parse %s, please specify as a go duration i.e 30s, 1m: %s", name, err)
+	}
+
+	if d < 0 {
+		return 0, fmt.Errorf("%s must not be negative, got %s", name, value)
+	}
+
+	return d, nil
+}
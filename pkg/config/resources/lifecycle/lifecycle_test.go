@@ -0,0 +1,63 @@
+package lifecycle
+
+import (
+	"testing"
+	"time"
+
+	"github.com/jumppad-labs/hclconfig/types"
+	"github.com/stretchr/testify/require"
+)
+
+type testResource struct {
+	types.ResourceBase `hcl:",remain"`
+
+	Lifecycle *Lifecycle `hcl:"lifecycle,block" json:"lifecycle,omitempty"`
+}
+
+func TestParseNilReturnsDefaults(t *testing.T) {
+	var l *Lifecycle
+
+	s, err := l.Parse()
+	require.NoError(t, err)
+	require.Equal(t, 0, s.Retries)
+	require.Equal(t, DefaultRetryBackoff, s.RetryBackoff)
+	require.Zero(t, s.CreateTimeout)
+	require.Zero(t, s.DestroyTimeout)
+}
+
+func TestParseReturnsDurations(t *testing.T) {
+	l := &Lifecycle{Retries: 3, RetryBackoff: "10s", CreateTimeout: "5m", DestroyTimeout: "2m", PreventDestroy: true, IgnoreChanges: []string{"image"}}
+
+	s, err := l.Parse()
+	require.NoError(t, err)
+	require.Equal(t, 3, s.Retries)
+	require.Equal(t, 10*time.Second, s.RetryBackoff)
+	require.Equal(t, 5*time.Minute, s.CreateTimeout)
+	require.Equal(t, 2*time.Minute, s.DestroyTimeout)
+	require.True(t, s.PreventDestroy)
+	require.Equal(t, []string{"image"}, s.IgnoreChanges)
+}
+
+func TestParseInvalidValuesReturnsError(t *testing.T) {
+	_, err := (&Lifecycle{Retries: -1}).Parse()
+	require.Error(t, err)
+
+	_, err = (&Lifecycle{DestroyTimeout: "-1s"}).Parse()
+	require.ErrorContains(t, err, "destroy_timeout")
+
+	_, err = (&Lifecycle{RetryBackoff: "abc"}).Parse()
+	require.ErrorContains(t, err, "retry_backoff")
+}
+
+func TestFromResourceReturnsBlock(t *testing.T) {
+	l := &Lifecycle{Retries: 1}
+
+	require.Equal(t, l, FromResource(&testResource{Lifecycle: l}))
+	require.Nil(t, FromResource(&testResource{}))
+	require.Nil(t, FromResource(&types.ResourceBase{}))
+}
+
+func TestParseEmptyIgnoreChangesReturnsError(t *testing.T) {
+	_, err := (&Lifecycle{IgnoreChanges: []string{"environment", " "}}).Parse()
+	require.ErrorContains(t, err, "ignore_changes")
+}
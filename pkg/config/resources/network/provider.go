@@ -4,12 +4,14 @@ import (
 	"context"
 	"fmt"
 	"net"
+	"regexp"
 
 	"github.com/docker/docker/api/types/filters"
 	"github.com/docker/docker/api/types/network"
 	htypes "github.com/jumppad-labs/hclconfig/types"
 	"github.com/jumppad-labs/jumppad/pkg/clients"
 	"github.com/jumppad-labs/jumppad/pkg/clients/container"
+	"github.com/jumppad-labs/jumppad/pkg/utils"
 	sdk "github.com/jumppad-labs/plugin-sdk"
 )
 
@@ -20,22 +22,28 @@ type Provider struct {
 	config *Network
 	client container.Docker
 	log    sdk.Logger
+	env    utils.Environment
 }
 
 func (p *Provider) Init(cfg htypes.Resource, l sdk.Logger) error {
-	c, ok := cfg.(*Network)
-	if !ok {
-		return fmt.Errorf("unable to initialize Network provider, resource is not of type Network")
-	}
-
 	cli, err := clients.GenerateClients(l)
 	if err != nil {
 		return err
 	}
 
+	return p.InitWithClients(cfg, l, cli)
+}
+
+func (p *Provider) InitWithClients(cfg htypes.Resource, l sdk.Logger, cli *clients.Clients) error {
+	c, ok := cfg.(*Network)
+	if !ok {
+		return fmt.Errorf("unable to initialize Network provider, resource is not of type Network")
+	}
+
 	p.config = c
 	p.client = cli.Docker
 	p.log = l
+	p.env = cli.Environment
 
 	return nil
 }
@@ -75,8 +83,8 @@ func (p *Provider) Create(ctx context.Context) error {
 
 	// is the network name and subnet equal to one which already exists
 	for _, ne := range nets {
-		if ne.Name == p.config.Meta.Name {
-			return fmt.Errorf("a Network already exists with the name: %s ref:%s", p.config.Meta.Name, p.config.Meta.ID)
+		if ne.Name == p.env.NetworkName(p.config.Meta.Name) {
+			return fmt.Errorf("a Network already exists with the name: %s ref:%s", ne.Name, p.config.Meta.ID)
 		}
 	}
 
@@ -126,15 +134,26 @@ func (p *Provider) Destroy(ctx context.Context, force bool) error {
 	}
 
 	if len(ids) == 1 {
-		return p.client.NetworkRemove(context.Background(), p.config.Meta.Name)
+		return p.client.NetworkRemove(context.Background(), p.env.NetworkName(p.config.Meta.Name))
 	}
 
 	return nil
 }
 
+// Snapshot does nothing, networks do not have any state to save
+func (p *Provider) Snapshot(ctx context.Context, s *container.Snapshot) error {
+	return nil
+}
+
+// Restore creates the network, the subnet is taken from the config so that
+// containers are restored with the same addresses
+func (p *Provider) Restore(ctx context.Context, s *container.Snapshot) error {
+	return p.Create(ctx)
+}
+
 // Lookup the ID for a network
 func (p *Provider) Lookup() ([]string, error) {
-	nets, err := p.getNetworks(p.config.Meta.Name)
+	nets, err := p.getNetworks(p.env.NetworkName(p.config.Meta.Name))
 
 	if err != nil {
 		return nil, err
@@ -159,6 +178,44 @@ func (p *Provider) Refresh(ctx context.Context) error {
 	return nil
 }
 
+// Drifted checks that the Docker network exists
+func (p *Provider) Drifted(ctx context.Context) ([]string, error) {
+	ids, err := p.Lookup()
+	if err != nil {
+		return nil, err
+	}
+
+	if len(ids) == 0 {
+		return []string{fmt.Sprintf("network %s does not exist", p.env.NetworkName(p.config.Meta.Name))}, nil
+	}
+
+	return nil, nil
+}
+
+// Import adopts an existing Docker network, networks are looked up by the
+// name of the resource in the current workspace so the network must have the
+// same name and subnet
+func (p *Provider) Import(ctx context.Context, id string) error {
+	n, err := p.client.NetworkInspect(ctx, id, network.InspectOptions{})
+	if err != nil {
+		return fmt.Errorf("unable to find network %s: %w", id, err)
+	}
+
+	p.log.Info("Importing Network", "ref", p.config.Meta.ID, "network", n.Name)
+
+	if name := p.env.NetworkName(p.config.Meta.Name); n.Name != name {
+		return fmt.Errorf("unable to import network %s as %s, the network must be named %s", n.Name, p.config.Meta.ID, name)
+	}
+
+	for _, ci := range n.IPAM.Config {
+		if ci.Subnet == p.config.Subnet {
+			return nil
+		}
+	}
+
+	return fmt.Errorf("unable to import network %s as %s, the network does not have the subnet %s", n.Name, p.config.Meta.ID, p.config.Subnet)
+}
+
 func (p *Provider) Changed() (bool, error) {
 	p.log.Debug("Checking changes", "ref", p.config.Meta.ID)
 
@@ -181,18 +238,25 @@ func (p *Provider) createWithDriver(driver string) error {
 		Labels: map[string]string{
 			"created_by": "jumppad",
 			"id":         p.config.Meta.ID,
+			"workspace":  p.env.WorkspaceName(),
 		},
 		Attachable: true,
 	}
 
-	_, err := p.client.NetworkCreate(context.Background(), p.config.Meta.Name, opts)
+	_, err := p.client.NetworkCreate(context.Background(), p.env.NetworkName(p.config.Meta.Name), opts)
 
 	return err
 }
 
 func (p *Provider) getNetworks(name string) ([]network.Summary, error) {
 	args := filters.NewArgs()
-	args.Add("name", name)
+
+	// the name filter matches partial names, anchor it so that networks in
+	// other workspaces are not returned
+	if name != "" {
+		args.Add("name", "^"+regexp.QuoteMeta(name)+"$")
+	}
+
 	return p.client.NetworkList(context.Background(), network.ListOptions{Filters: args})
 }
 
@@ -9,6 +9,7 @@ import (
 	"github.com/jumppad-labs/hclconfig/types"
 	"github.com/jumppad-labs/jumppad/pkg/clients/container/mocks"
 	"github.com/jumppad-labs/jumppad/pkg/clients/logger"
+	"github.com/jumppad-labs/jumppad/pkg/utils"
 	"github.com/jumppad-labs/jumppad/testutils"
 	"github.com/stretchr/testify/mock"
 	assert "github.com/stretchr/testify/require"
@@ -95,6 +96,57 @@ func TestNetworkCreatesCorrectly(t *testing.T) {
 	assert.Equal(t, c.Subnet, nco.IPAM.Config[0].Subnet)
 }
 
+func TestNetworkCreatesWithWorkspaceName(t *testing.T) {
+	t.Setenv(utils.WorkspaceEnvVar, "dev")
+
+	c := &Network{
+		ResourceBase: types.ResourceBase{Meta: types.Meta{Name: "testnetwork", ID: "resource.network.testnetwork"}},
+	}
+	c.Subnet = "10.1.2.0/24"
+
+	md, p := setupNetworkTests(t, c)
+	testutils.RemoveOn(&md.Mock, "NetworkList")
+	md.On("NetworkList", mock.Anything, mock.Anything).Return([]network.Summary{
+		{
+			ID:   "default",
+			Name: "testnetwork",
+			IPAM: network.IPAM{
+				Config: []network.IPAMConfig{{Subnet: "10.1.3.0/24"}},
+			},
+		},
+	}, nil)
+
+	err := p.Create(context.Background())
+	assert.NoError(t, err)
+
+	params := md.Calls[1].Arguments
+	nco := params[2].(network.CreateOptions)
+
+	assert.Equal(t, "testnetwork.dev", params[1].(string))
+	assert.Equal(t, "dev", nco.Labels["workspace"])
+}
+
+func TestNetworkDestroyRemovesWorkspaceNetwork(t *testing.T) {
+	t.Setenv(utils.WorkspaceEnvVar, "dev")
+
+	c := &Network{
+		ResourceBase: types.ResourceBase{Meta: types.Meta{Name: "testnetwork"}},
+	}
+
+	md, p := setupNetworkTests(t, c)
+	testutils.RemoveOn(&md.Mock, "NetworkList")
+	md.On("NetworkList", mock.Anything, mock.Anything).Return([]network.Summary{{ID: "testnet", Name: "testnetwork.dev"}}, nil)
+	md.On("NetworkRemove", mock.Anything, mock.Anything).Return(nil)
+
+	err := p.Destroy(context.Background(), false)
+	assert.NoError(t, err)
+
+	md.AssertCalled(t, "NetworkRemove", mock.Anything, "testnetwork.dev")
+
+	opts := md.Calls[0].Arguments[1].(network.ListOptions)
+	assert.Equal(t, []string{`^testnetwork\.dev$`}, opts.Filters.Get("name"))
+}
+
 func TestNetworkCreatesNatWhenNoBridge(t *testing.T) {
 	c := &Network{
 		ResourceBase: types.ResourceBase{Meta: types.Meta{Name: "testnetwork"}},
@@ -181,3 +233,62 @@ func TestCreateWithOverlappingSubnetReturnsError(t *testing.T) {
 	err := p.Create(context.Background())
 	assert.Error(t, err)
 }
+
+func TestNetworkImportWithMatchingNetwork(t *testing.T) {
+	c := &Network{
+		ResourceBase: types.ResourceBase{Meta: types.Meta{Name: "testnetwork"}},
+	}
+	c.Subnet = "10.1.2.0/24"
+
+	md, p := setupNetworkTests(t, c)
+	md.On("NetworkInspect", mock.Anything, "abc", mock.Anything).Return(network.Summary{
+		ID:   "abc",
+		Name: "testnetwork",
+		IPAM: network.IPAM{
+			Config: []network.IPAMConfig{{Subnet: "10.1.2.0/24"}},
+		},
+	}, nil)
+
+	err := p.Import(context.Background(), "abc")
+	assert.NoError(t, err)
+
+	md.AssertNotCalled(t, "NetworkCreate", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestNetworkImportWithDifferentNameReturnsError(t *testing.T) {
+	c := &Network{
+		ResourceBase: types.ResourceBase{Meta: types.Meta{Name: "testnetwork"}},
+	}
+	c.Subnet = "10.1.2.0/24"
+
+	md, p := setupNetworkTests(t, c)
+	md.On("NetworkInspect", mock.Anything, "abc", mock.Anything).Return(network.Summary{
+		ID:   "abc",
+		Name: "other",
+		IPAM: network.IPAM{
+			Config: []network.IPAMConfig{{Subnet: "10.1.2.0/24"}},
+		},
+	}, nil)
+
+	err := p.Import(context.Background(), "abc")
+	assert.ErrorContains(t, err, "must be named testnetwork")
+}
+
+func TestNetworkImportWithDifferentSubnetReturnsError(t *testing.T) {
+	c := &Network{
+		ResourceBase: types.ResourceBase{Meta: types.Meta{Name: "testnetwork"}},
+	}
+	c.Subnet = "10.1.2.0/24"
+
+	md, p := setupNetworkTests(t, c)
+	md.On("NetworkInspect", mock.Anything, "abc", mock.Anything).Return(network.Summary{
+		ID:   "abc",
+		Name: "testnetwork",
+		IPAM: network.IPAM{
+			Config: []network.IPAMConfig{{Subnet: "10.8.2.0/24"}},
+		},
+	}, nil)
+
+	err := p.Import(context.Background(), "abc")
+	assert.ErrorContains(t, err, "subnet 10.1.2.0/24")
+}
@@ -2,6 +2,7 @@ package network
 
 import (
 	"github.com/jumppad-labs/hclconfig/types"
+	"github.com/jumppad-labs/jumppad/pkg/config/resources/lifecycle"
 )
 
 // TypeNetwork is the string resource type for Network resources
@@ -12,6 +13,8 @@ type Network struct {
 	// embedded type holding name, etc
 	types.ResourceBase `hcl:",remain"`
 
+	Lifecycle *lifecycle.Lifecycle `hcl:"lifecycle,block" json:"lifecycle,omitempty"`
+
 	Subnet     string `hcl:"subnet" json:"subnet"`
 	EnableIPv6 bool   `hcl:"enable_ipv6,optional" json:"enable_ipv6"`
 }
@@ -10,6 +10,7 @@ import (
 	"path"
 	"path/filepath"
 	"runtime"
+	"slices"
 	"strings"
 	"sync"
 	"time"
@@ -22,8 +23,10 @@ import (
 	ctypes "github.com/jumppad-labs/jumppad/pkg/clients/container/types"
 	"github.com/jumppad-labs/jumppad/pkg/clients/logger"
 	"github.com/jumppad-labs/jumppad/pkg/clients/nomad"
+	"github.com/jumppad-labs/jumppad/pkg/jumppad/tracing"
 	"github.com/jumppad-labs/jumppad/pkg/utils"
 	sdk "github.com/jumppad-labs/plugin-sdk"
+	"go.opentelemetry.io/otel/attribute"
 )
 
 var _ sdk.Provider = &ClusterProvider{}
@@ -35,6 +38,7 @@ type ClusterProvider struct {
 	nomadClient nomad.Nomad
 	connector   connector.Connector
 	log         logger.Logger
+	env         utils.Environment
 }
 
 var startTimeout = (300 * time.Second)
@@ -45,6 +49,10 @@ func (p *ClusterProvider) Init(cfg htypes.Resource, l sdk.Logger) error {
 		return err
 	}
 
+	return p.InitWithClients(cfg, l, cli)
+}
+
+func (p *ClusterProvider) InitWithClients(cfg htypes.Resource, l sdk.Logger, cli *clients.Clients) error {
 	c, ok := cfg.(*NomadCluster)
 	if !ok {
 		return fmt.Errorf("unable to initialize NomadCluster provider, resource is not of type NomadCluster")
@@ -55,6 +63,7 @@ func (p *ClusterProvider) Init(cfg htypes.Resource, l sdk.Logger) error {
 	p.nomadClient = cli.Nomad
 	p.connector = cli.Connector
 	p.log = l
+	p.env = cli.Environment
 
 	return nil
 }
@@ -79,6 +88,17 @@ func (p *ClusterProvider) Destroy(ctx context.Context, force bool) error {
 	return p.destroyNomad(force)
 }
 
+// Snapshot returns an error, the server and client nodes of a Nomad cluster
+// can not be saved to a snapshot
+func (p *ClusterProvider) Snapshot(ctx context.Context, s *cclients.Snapshot) error {
+	return fmt.Errorf("snapshots are not supported for Nomad clusters, unable to snapshot %s", p.config.Meta.ID)
+}
+
+// Restore returns an error, Nomad clusters can not be saved to a snapshot
+func (p *ClusterProvider) Restore(ctx context.Context, s *cclients.Snapshot) error {
+	return fmt.Errorf("snapshots are not supported for Nomad clusters, unable to restore %s", p.config.Meta.ID)
+}
+
 // Lookup the a clusters current state
 func (p *ClusterProvider) Lookup() ([]string, error) {
 	ids := []string{}
@@ -184,7 +204,7 @@ func (p *ClusterProvider) Refresh(ctx context.Context) error {
 		p.log.Info("Scaling cluster up", "ref", p.config.Meta.ID, "current_scale", len(p.config.ClientContainerName), "new_scale", p.config.ClientNodes)
 
 		for i := len(p.config.ClientContainerName); i < p.config.ClientNodes; i++ {
-			id := utils.FQDN(fmt.Sprintf("%s.client.%s", randomID(), p.config.Meta.Name), p.config.Meta.Module, p.config.Meta.Type)
+			id := p.env.FQDN(fmt.Sprintf("%s.client.%s", randomID(), p.config.Meta.Name), p.config.Meta.Module, p.config.Meta.Type)
 
 			p.log.Debug("Create client node", "ref", p.config.Meta.ID, "client", id)
 
@@ -213,7 +233,7 @@ func (p *ClusterProvider) Refresh(ctx context.Context) error {
 
 	if len(ci) > 0 {
 		p.log.Info("Copied images changed, pushing new copy to the cluster", "ref", p.config.Meta.ID)
-		err := p.ImportLocalDockerImages(ci, false)
+		err := p.ImportLocalDockerImages(ctx, ci, false)
 		if err != nil {
 			return err
 		}
@@ -222,6 +242,104 @@ func (p *ClusterProvider) Refresh(ctx context.Context) error {
 	return nil
 }
 
+// Drifted checks that the server and client containers for the cluster
+// exist
+func (p *ClusterProvider) Drifted(ctx context.Context) ([]string, error) {
+	drift := []string{}
+
+	ids, err := p.client.FindContainerIDs(p.config.ServerContainerName)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(ids) == 0 {
+		drift = append(drift, fmt.Sprintf("server node %s does not exist", p.config.ServerContainerName))
+	}
+
+	for _, cl := range p.config.ClientContainerName {
+		ids, err := p.client.FindContainerIDs(cl)
+		if err != nil {
+			return nil, err
+		}
+
+		if len(ids) == 0 {
+			drift = append(drift, fmt.Sprintf("client node %s does not exist", cl))
+		}
+	}
+
+	return drift, nil
+}
+
+// Stop stops the client and server nodes without removing them, clients are
+// stopped before the server
+func (p *ClusterProvider) Stop(ctx context.Context) error {
+	if ctx.Err() != nil {
+		p.log.Debug("Skipping stop, context cancelled", "ref", p.config.Meta.ID)
+		return nil
+	}
+
+	p.log.Info("Stop Cluster", "ref", p.config.Meta.ID)
+
+	nodes := append(slices.Clone(p.config.ClientContainerName), p.config.ServerContainerName)
+	for _, n := range nodes {
+		ids, err := p.client.FindContainerIDs(n)
+		if err != nil {
+			return err
+		}
+
+		for _, id := range ids {
+			p.log.Debug("Stopping node", "ref", p.config.Meta.ID, "node", n)
+
+			err := p.client.StopContainer(id, false)
+			if err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+// Start starts the server and then the client nodes and waits for all the
+// nodes to be healthy
+func (p *ClusterProvider) Start(ctx context.Context) error {
+	if ctx.Err() != nil {
+		p.log.Debug("Skipping start, context cancelled", "ref", p.config.Meta.ID)
+		return nil
+	}
+
+	p.log.Info("Start Cluster", "ref", p.config.Meta.ID)
+
+	nets := p.config.Networks.ToStartNetworkAttachments()
+
+	nodes := append([]string{p.config.ServerContainerName}, p.config.ClientContainerName...)
+	for _, n := range nodes {
+		ids, err := p.client.FindContainerIDs(n)
+		if err != nil {
+			return err
+		}
+
+		if len(ids) == 0 {
+			return fmt.Errorf("node %s does not exist", n)
+		}
+
+		for _, id := range ids {
+			p.log.Debug("Starting node", "ref", p.config.Meta.ID, "node", n)
+
+			err := p.client.StartContainer(id, nets)
+			if err != nil {
+				return err
+			}
+		}
+	}
+
+	// the server and every client node must be healthy, when there are no
+	// client nodes the server is also the client
+	p.nomadClient.SetConfig(fmt.Sprintf("http://%s", p.config.ExternalIP), p.config.APIPort, len(p.config.ClientContainerName)+1)
+
+	return p.nomadClient.HealthCheckAPI(ctx, startTimeout)
+}
+
 func (p *ClusterProvider) Changed() (bool, error) {
 	p.log.Debug("Checking changes", "ref", p.config.Meta.ID)
 
@@ -239,7 +357,10 @@ func (p *ClusterProvider) Changed() (bool, error) {
 }
 
 // ImportLocalDockerImages fetches Docker images stored on the local client and imports them into the cluster
-func (p *ClusterProvider) ImportLocalDockerImages(images []ctypes.Image, force bool) error {
+func (p *ClusterProvider) ImportLocalDockerImages(ctx context.Context, images []ctypes.Image, force bool) (err error) {
+	ctx, span := tracing.Start(ctx, "ImportLocalDockerImages", attribute.String(tracing.AttributeResourceID, p.config.Meta.ID))
+	defer func() { tracing.End(span, err) }()
+
 	ids, err := p.Lookup()
 	if err != nil {
 		return err
@@ -252,7 +373,9 @@ func (p *ClusterProvider) ImportLocalDockerImages(images []ctypes.Image, force b
 			continue
 		}
 
-		err := p.client.PullImage(i, false)
+		err := tracing.Trace(ctx, "PullImage", func() error {
+			return p.client.PullImage(i, false)
+		}, tracing.Images(i.Name))
 		if err != nil {
 			return err
 		}
@@ -261,8 +384,12 @@ func (p *ClusterProvider) ImportLocalDockerImages(images []ctypes.Image, force b
 	}
 
 	// import to volume
-	vn := utils.FQDNVolumeName(utils.ImageVolumeName)
-	imagesFile, err := p.client.CopyLocalDockerImagesToVolume(imgs, vn, force)
+	vn := p.env.FQDNVolumeName(utils.ImageVolumeName)
+	var imagesFile []string
+	err = tracing.Trace(ctx, "CopyLocalDockerImagesToVolume", func() (err error) {
+		imagesFile, err = p.client.CopyLocalDockerImagesToVolume(imgs, vn, force)
+		return err
+	}, tracing.Images(imgs...))
 	if err != nil {
 		return err
 	}
@@ -323,7 +450,7 @@ func (p *ClusterProvider) createNomad(ctx context.Context) error {
 
 	// check the client nodes do not already exist
 	for i := 0; i < p.config.ClientNodes; i++ {
-		ids, err := p.client.FindContainerIDs(utils.FQDN(fmt.Sprintf("%d.client.%s", i+1, p.config.Meta.Name), p.config.Meta.Module, p.config.Meta.Type))
+		ids, err := p.client.FindContainerIDs(p.env.FQDN(fmt.Sprintf("%d.client.%s", i+1, p.config.Meta.Name), p.config.Meta.Module, p.config.Meta.Type))
 		if len(ids) > 0 {
 			return fmt.Errorf("client already exists")
 		}
@@ -334,7 +461,7 @@ func (p *ClusterProvider) createNomad(ctx context.Context) error {
 	}
 
 	// check the server does not already exist
-	ids, err := p.client.FindContainerIDs(utils.FQDN(fmt.Sprintf("server.%s", p.config.Meta.Name), p.config.Meta.Module, p.config.Meta.Type))
+	ids, err := p.client.FindContainerIDs(p.env.FQDN(fmt.Sprintf("server.%s", p.config.Meta.Name), p.config.Meta.Module, p.config.Meta.Type))
 	if len(ids) > 0 {
 		return fmt.Errorf("cluster already exists")
 	}
@@ -344,7 +471,10 @@ func (p *ClusterProvider) createNomad(ctx context.Context) error {
 	}
 
 	// pull the container image
-	err = p.client.PullImage(p.config.Image.ToClientImage(), false)
+	img := p.config.Image.ToClientImage()
+	err = tracing.Trace(ctx, "PullImage", func() error {
+		return p.client.PullImage(img, false)
+	}, tracing.Images(img.Name))
 	if err != nil {
 		return err
 	}
@@ -362,7 +492,7 @@ func (p *ClusterProvider) createNomad(ctx context.Context) error {
 
 	// set the API server port to a random number
 	p.config.ConnectorPort = rand.Intn(utils.MaxRandomPort-utils.MinRandomPort) + utils.MinRandomPort
-	p.config.ConfigDir = path.Join(utils.JumppadHome(), strings.Replace(p.config.Meta.ID, ".", "_", -1), "config")
+	p.config.ConfigDir = path.Join(p.env.WorkspaceHome(), strings.Replace(p.config.Meta.ID, ".", "_", -1), "config")
 
 	// set the external IP to the address where the docker daemon is running
 	p.config.ExternalIP = utils.GetDockerIP()
@@ -386,7 +516,7 @@ func (p *ClusterProvider) createNomad(ctx context.Context) error {
 	}
 
 	name := fmt.Sprintf("server.%s", p.config.Meta.Name)
-	p.config.ServerContainerName = utils.FQDN(name, p.config.Meta.Module, p.config.Meta.Type)
+	p.config.ServerContainerName = p.env.FQDN(name, p.config.Meta.Module, p.config.Meta.Type)
 
 	cMutex := sync.Mutex{}
 	clientFQDN := []string{}
@@ -439,7 +569,7 @@ func (p *ClusterProvider) createNomad(ctx context.Context) error {
 	// import the images to the servers container d instance
 	// importing images means that Nomad does not need to pull from a remote docker hub
 	if len(p.config.CopyImages) > 0 {
-		err := p.ImportLocalDockerImages(p.config.CopyImages.ToClientImages(), false)
+		err := p.ImportLocalDockerImages(ctx, p.config.CopyImages.ToClientImages(), false)
 		if err != nil {
 			return fmt.Errorf("unable to copy images to cluster: %w", err)
 		}
@@ -474,7 +604,7 @@ func (p *ClusterProvider) createServerNode(img ctypes.Image, volumeID string, is
 	// create the server
 	// since the server is just a container create the container config and provider
 	name := fmt.Sprintf("server.%s", p.config.Meta.Name)
-	fqrn := utils.FQDN(name, p.config.Meta.Module, p.config.Meta.Type)
+	fqrn := p.env.FQDN(name, p.config.Meta.Module, p.config.Meta.Type)
 
 	cc := &ctypes.Container{
 		Name: fqrn,
@@ -599,7 +729,7 @@ func (p *ClusterProvider) createClientNode(id string, image, volumeID, serverID
 	// create the server
 	// since the server is just a container create the container config and provider
 	name := fmt.Sprintf("%s.client.%s", id, p.config.Meta.Name)
-	fqrn := utils.FQDN(name, p.config.Meta.Module, p.config.Meta.Type)
+	fqrn := p.env.FQDN(name, p.config.Meta.Module, p.config.Meta.Type)
 	cc := &ctypes.Container{
 		Name: fqrn,
 	}
@@ -718,8 +848,8 @@ func (p *ClusterProvider) createDockerConfig() (string, error) {
 	}
 
 	// set the cache details
-	dc.Proxies.HTTP = utils.ImageCacheAddress()
-	dc.Proxies.HTTPS = utils.ImageCacheAddress()
+	dc.Proxies.HTTP = p.env.ImageCacheAddress()
+	dc.Proxies.HTTPS = p.env.ImageCacheAddress()
 
 	// write the config to a file
 	data, err := json.MarshalIndent(dc, "", "  ")
@@ -734,7 +864,7 @@ func (p *ClusterProvider) createDockerConfig() (string, error) {
 
 func (p *ClusterProvider) appendProxyEnv(cc *ctypes.Container) error {
 	// load the CA from a file
-	ca, err := os.ReadFile(filepath.Join(utils.CertsDir(""), "/root.cert"))
+	ca, err := os.ReadFile(filepath.Join(p.env.CertsDir(""), "/root.cert"))
 	if err != nil {
 		return fmt.Errorf("unable to read root CA for proxy: %s", err)
 	}
@@ -749,7 +879,7 @@ func (p *ClusterProvider) deployConnector() error {
 
 	// generate the certificates
 	// generate the certificates for the service
-	cb, err := p.connector.GetLocalCertBundle(utils.CertsDir(""))
+	cb, err := p.connector.GetLocalCertBundle(p.env.CertsDir(""))
 	if err != nil {
 		return fmt.Errorf("unable to fetch root certificates for ingress: %s", err)
 	}
@@ -764,7 +894,7 @@ func (p *ClusterProvider) deployConnector() error {
 			fmt.Sprintf("%s:%d", utils.GetDockerIP(), p.config.ConnectorPort),
 		},
 		[]string{utils.GetDockerIP()},
-		utils.CertsDir(p.config.Meta.ID),
+		p.env.CertsDir(p.config.Meta.ID),
 	)
 
 	// load the certs into a string so that they can be embedded into the config
@@ -2,6 +2,7 @@ package nomad
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
 	"os"
 	"time"
@@ -9,6 +10,7 @@ import (
 	htypes "github.com/jumppad-labs/hclconfig/types"
 	"github.com/jumppad-labs/jumppad/pkg/clients"
 	"github.com/jumppad-labs/jumppad/pkg/clients/nomad"
+	"github.com/jumppad-labs/jumppad/pkg/jumppad/events"
 	"github.com/jumppad-labs/jumppad/pkg/utils"
 	sdk "github.com/jumppad-labs/plugin-sdk"
 )
@@ -29,6 +31,10 @@ func (p *JobProvider) Init(cfg htypes.Resource, l sdk.Logger) error {
 		return err
 	}
 
+	return p.InitWithClients(cfg, l, cli)
+}
+
+func (p *JobProvider) InitWithClients(cfg htypes.Resource, l sdk.Logger, cli *clients.Clients) error {
 	c, ok := cfg.(*NomadJob)
 	if !ok {
 		return fmt.Errorf("unable to initialize NomadJob provider, resource is not of type NomadJob")
@@ -69,6 +75,8 @@ func (p *JobProvider) Create(ctx context.Context) error {
 		}
 
 		for _, j := range p.config.HealthCheck.Jobs {
+			events.HealthCheck(ctx, p.config.Meta.ID, p.config.Meta.Type, fmt.Sprintf("waiting for job '%s'", j))
+
 			for {
 				if ctx.Err() != nil {
 					return fmt.Errorf("context cancelled, unable to wait for job health")
@@ -83,6 +91,7 @@ func (p *JobProvider) Create(ctx context.Context) error {
 				s, err := p.client.JobRunning(j)
 				if err == nil && s {
 					p.log.Debug("Health passed for", "ref", p.config.Meta.ID, "job", j)
+					events.HealthCheck(ctx, p.config.Meta.ID, p.config.Meta.Type, fmt.Sprintf("job '%s' is running", j))
 					break
 				}
 
@@ -156,6 +165,34 @@ func (p *JobProvider) Refresh(ctx context.Context) error {
 	return p.Create(context.Background())
 }
 
+// Drifted checks that the jobs defined in the config are running
+func (p *JobProvider) Drifted(ctx context.Context) ([]string, error) {
+	nomadCluster := p.config.Cluster
+	p.client.SetConfig(fmt.Sprintf("http://%s", nomadCluster.ExternalIP), nomadCluster.APIPort, nomadCluster.ClientNodes)
+
+	drift := []string{}
+
+	for _, f := range p.config.Paths {
+		jj, err := p.client.ParseJob(f)
+		if err != nil {
+			return nil, err
+		}
+
+		job := struct{ ID string }{}
+		err = json.Unmarshal(jj, &job)
+		if err != nil {
+			return nil, fmt.Errorf("unable to read job ID from %s: %w", f, err)
+		}
+
+		running, err := p.client.JobRunning(job.ID)
+		if err != nil || !running {
+			drift = append(drift, fmt.Sprintf("job %s is not running", job.ID))
+		}
+	}
+
+	return drift, nil
+}
+
 func (p *JobProvider) Changed() (bool, error) {
 	cp, err := p.getChangedPaths()
 	if err != nil {
@@ -4,8 +4,8 @@ import (
 	"fmt"
 
 	"github.com/jumppad-labs/hclconfig/types"
-	"github.com/jumppad-labs/jumppad/pkg/config"
 	ctypes "github.com/jumppad-labs/jumppad/pkg/config/resources/container"
+	"github.com/jumppad-labs/jumppad/pkg/config/resources/lifecycle"
 	"github.com/jumppad-labs/jumppad/pkg/utils"
 )
 
@@ -17,6 +17,8 @@ type NomadCluster struct {
 	// embedded type holding name, etc
 	types.ResourceBase `hcl:",remain"`
 
+	Lifecycle *lifecycle.Lifecycle `hcl:"lifecycle,block" json:"lifecycle,omitempty"`
+
 	Networks      ctypes.NetworkAttachments `hcl:"network,block" json:"networks,omitempty"` // Attach to the correct network // only when Image is specified
 	Image         *ctypes.Image             `hcl:"image,block" json:"images,omitempty"`     // optional image to use for the cluster
 	ClientNodes   int                       `hcl:"client_nodes,optional" json:"client_nodes,omitempty"`
@@ -107,38 +109,6 @@ func (n *NomadCluster) Process() error {
 		}
 	}
 
-	// do we have an existing resource in the state?
-	// if so we need to set any computed resources for dependents
-	c, err := config.LoadState()
-	if err == nil {
-		// try and find the resource in the state
-		r, _ := c.FindResource(n.Meta.ID)
-		if r != nil {
-			state := r.(*NomadCluster)
-			n.ExternalIP = state.ExternalIP
-			n.ConfigDir = state.ConfigDir
-			n.ServerContainerName = state.ServerContainerName
-			n.ClientContainerName = state.ClientContainerName
-			n.APIPort = state.APIPort
-			n.ConnectorPort = state.ConnectorPort
-
-			// add the image ids from the state, this allows the tracking of
-			// pushed images so that they can be automatically updated
-
-			// add the image id from state
-			for x, img := range n.CopyImages {
-				for _, sImg := range state.CopyImages {
-					if img.Name == sImg.Name && img.Username == sImg.Username {
-						n.CopyImages[x].ID = sImg.ID
-					}
-				}
-			}
-
-			// the network name is set
-			copy(n.Networks, state.Networks)
-		}
-	}
-
 	// set the default port if not set
 	if n.APIPort == 0 {
 		n.APIPort = 4646
@@ -146,3 +116,28 @@ func (n *NomadCluster) Process() error {
 
 	return nil
 }
+
+func (n *NomadCluster) RestoreState(r types.Resource) {
+	state := r.(*NomadCluster)
+	n.ExternalIP = state.ExternalIP
+	n.ConfigDir = state.ConfigDir
+	n.ServerContainerName = state.ServerContainerName
+	n.ClientContainerName = state.ClientContainerName
+	n.APIPort = state.APIPort
+	n.ConnectorPort = state.ConnectorPort
+
+	// add the image ids from the state, this allows the tracking of
+	// pushed images so that they can be automatically updated
+
+	// add the image id from state
+	for x, img := range n.CopyImages {
+		for _, sImg := range state.CopyImages {
+			if img.Name == sImg.Name && img.Username == sImg.Username {
+				n.CopyImages[x].ID = sImg.ID
+			}
+		}
+	}
+
+	// the network name is set
+	copy(n.Networks, state.Networks)
+}
@@ -90,6 +90,10 @@ func TestNomadClusterSetsOutputsFromState(t *testing.T) {
 
 	c.Process()
 
+	sf, err := config.LoadState()
+	require.NoError(t, err)
+	config.RestoreState(c, sf)
+
 	require.Equal(t, "127.0.0.1", c.ExternalIP)
 	require.Equal(t, "server.something.something", c.ServerContainerName)
 	require.Equal(t, []string{"1.client.something.something", "2.client.something.something"}, c.ClientContainerName)
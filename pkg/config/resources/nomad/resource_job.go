@@ -2,8 +2,8 @@ package nomad
 
 import (
 	"github.com/jumppad-labs/hclconfig/types"
-	"github.com/jumppad-labs/jumppad/pkg/config"
 	"github.com/jumppad-labs/jumppad/pkg/config/resources/healthcheck"
+	"github.com/jumppad-labs/jumppad/pkg/config/resources/lifecycle"
 	"github.com/jumppad-labs/jumppad/pkg/utils"
 )
 
@@ -15,6 +15,8 @@ type NomadJob struct {
 	// embedded type holding name, etc
 	types.ResourceBase `hcl:",remain"`
 
+	Lifecycle *lifecycle.Lifecycle `hcl:"lifecycle,block" json:"lifecycle,omitempty"`
+
 	// Cluster is the name of the cluster to apply configuration to
 	Cluster NomadCluster `hcl:"cluster" json:"cluster"`
 
@@ -36,15 +38,10 @@ func (n *NomadJob) Process() error {
 		n.Paths[i] = utils.EnsureAbsolute(p, n.Meta.File)
 	}
 
-	cfg, err := config.LoadState()
-	if err == nil {
-		// try and find the resource in the state
-		r, _ := cfg.FindResource(n.Meta.ID)
-		if r != nil {
-			state := r.(*NomadJob)
-			n.JobChecksums = state.JobChecksums
-		}
-	}
-
 	return nil
 }
+
+func (n *NomadJob) RestoreState(r types.Resource) {
+	state := r.(*NomadJob)
+	n.JobChecksums = state.JobChecksums
+}
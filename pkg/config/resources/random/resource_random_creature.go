@@ -2,7 +2,7 @@ package random
 
 import (
 	"github.com/jumppad-labs/hclconfig/types"
-	"github.com/jumppad-labs/jumppad/pkg/config"
+	"github.com/jumppad-labs/jumppad/pkg/config/resources/lifecycle"
 )
 
 // TypeRandomCreature is the resource for generating random creatures
@@ -12,24 +12,15 @@ const TypeRandomCreature string = "random_creature"
 type RandomCreature struct {
 	types.ResourceBase `hcl:",remain"`
 
+	Lifecycle *lifecycle.Lifecycle `hcl:"lifecycle,block" json:"lifecycle,omitempty"`
+
 	// Output parameters
 	Value string `hcl:"value,optional" json:"value"`
 }
 
-func (c *RandomCreature) Process() error {
-	// do we have an existing resource in the state?
-	// if so we need to set any computed resources for dependents
-	cfg, err := config.LoadState()
-	if err == nil {
-		// try and find the resource in the state
-		r, _ := cfg.FindResource(c.Meta.ID)
-		if r != nil {
-			state := r.(*RandomCreature)
-			c.Value = state.Value
-		}
-	}
-
-	return nil
+func (c *RandomCreature) RestoreState(r types.Resource) {
+	state := r.(*RandomCreature)
+	c.Value = state.Value
 }
 
 func boolPointer(value bool) *bool {
@@ -2,7 +2,7 @@ package random
 
 import (
 	"github.com/jumppad-labs/hclconfig/types"
-	"github.com/jumppad-labs/jumppad/pkg/config"
+	"github.com/jumppad-labs/jumppad/pkg/config/resources/lifecycle"
 )
 
 // TypeRandomID is the resource for generating random IDs
@@ -12,6 +12,8 @@ const TypeRandomID string = "random_id"
 type RandomID struct {
 	types.ResourceBase `hcl:",remain"`
 
+	Lifecycle *lifecycle.Lifecycle `hcl:"lifecycle,block" json:"lifecycle,omitempty"`
+
 	ByteLength int64 `hcl:"byte_length" json:"byte_length"`
 
 	// Output parameters
@@ -20,20 +22,9 @@ type RandomID struct {
 	Dec    string `hcl:"dec,optional" json:"dec"`
 }
 
-func (c *RandomID) Process() error {
-	// do we have an existing resource in the state?
-	// if so we need to set any computed resources for dependents
-	cfg, err := config.LoadState()
-	if err == nil {
-		// try and find the resource in the state
-		r, _ := cfg.FindResource(c.Meta.ID)
-		if r != nil {
-			state := r.(*RandomID)
-			c.Base64 = state.Base64
-			c.Hex = state.Hex
-			c.Dec = state.Dec
-		}
-	}
-
-	return nil
+func (c *RandomID) RestoreState(r types.Resource) {
+	state := r.(*RandomID)
+	c.Base64 = state.Base64
+	c.Hex = state.Hex
+	c.Dec = state.Dec
 }
@@ -2,7 +2,7 @@ package random
 
 import (
 	"github.com/jumppad-labs/hclconfig/types"
-	"github.com/jumppad-labs/jumppad/pkg/config"
+	"github.com/jumppad-labs/jumppad/pkg/config/resources/lifecycle"
 )
 
 // TypeRandomNumber is the resource for generating random numbers
@@ -12,6 +12,8 @@ const TypeRandomNumber string = "random_number"
 type RandomNumber struct {
 	types.ResourceBase `hcl:",remain"`
 
+	Lifecycle *lifecycle.Lifecycle `hcl:"lifecycle,block" json:"lifecycle,omitempty"`
+
 	Minimum int `hcl:"minimum" json:"minimum"`
 	Maximum int `hcl:"maximum" json:"maximum"`
 
@@ -19,18 +21,7 @@ type RandomNumber struct {
 	Value int `hcl:"value,optional" json:"value"`
 }
 
-func (c *RandomNumber) Process() error {
-	// do we have an existing resource in the state?
-	// if so we need to set any computed resources for dependents
-	cfg, err := config.LoadState()
-	if err == nil {
-		// try and find the resource in the state
-		r, _ := cfg.FindResource(c.Meta.ID)
-		if r != nil {
-			state := r.(*RandomNumber)
-			c.Value = state.Value
-		}
-	}
-
-	return nil
+func (c *RandomNumber) RestoreState(r types.Resource) {
+	state := r.(*RandomNumber)
+	c.Value = state.Value
 }
@@ -2,7 +2,7 @@ package random
 
 import (
 	"github.com/jumppad-labs/hclconfig/types"
-	"github.com/jumppad-labs/jumppad/pkg/config"
+	"github.com/jumppad-labs/jumppad/pkg/config/resources/lifecycle"
 )
 
 // TypeRandomPassword is the resource for generating random passwords
@@ -12,6 +12,8 @@ const TypeRandomPassword string = "random_password"
 type RandomPassword struct {
 	types.ResourceBase `hcl:",remain"`
 
+	Lifecycle *lifecycle.Lifecycle `hcl:"lifecycle,block" json:"lifecycle,omitempty"`
+
 	Length int64 `hcl:"length" json:"lenght"`
 
 	OverrideSpecial string `hcl:"override_special,optional" json:"override_special"`
@@ -26,7 +28,7 @@ type RandomPassword struct {
 	MinUpper   int64 `hcl:"min_upper,optional" json:"min_upper"`
 
 	// Output parameters
-	Value string `hcl:"value,optional" json:"value"`
+	Value string `hcl:"value,optional" json:"value" sensitive:"true"`
 }
 
 func (c *RandomPassword) Process() error {
@@ -46,17 +48,10 @@ func (c *RandomPassword) Process() error {
 		c.Upper = boolPointer(true)
 	}
 
-	// do we have an existing resource in the state?
-	// if so we need to set any computed resources for dependents
-	cfg, err := config.LoadState()
-	if err == nil {
-		// try and find the resource in the state
-		r, _ := cfg.FindResource(c.Meta.ID)
-		if r != nil {
-			state := r.(*RandomPassword)
-			c.Value = state.Value
-		}
-	}
-
 	return nil
 }
+
+func (c *RandomPassword) RestoreState(r types.Resource) {
+	state := r.(*RandomPassword)
+	c.Value = state.Value
+}
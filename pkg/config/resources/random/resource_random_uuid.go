@@ -2,7 +2,7 @@ package random
 
 import (
 	"github.com/jumppad-labs/hclconfig/types"
-	"github.com/jumppad-labs/jumppad/pkg/config"
+	"github.com/jumppad-labs/jumppad/pkg/config/resources/lifecycle"
 )
 
 // TypeRandomUUID is the resource for generating random UUIDs
@@ -12,22 +12,13 @@ const TypeRandomUUID string = "random_uuid"
 type RandomUUID struct {
 	types.ResourceBase `hcl:",remain"`
 
+	Lifecycle *lifecycle.Lifecycle `hcl:"lifecycle,block" json:"lifecycle,omitempty"`
+
 	// Output parameters
 	Value string `hcl:"value,optional" json:"value"`
 }
 
-func (c *RandomUUID) Process() error {
-	// do we have an existing resource in the state?
-	// if so we need to set any computed resources for dependents
-	cfg, err := config.LoadState()
-	if err == nil {
-		// try and find the resource in the state
-		r, _ := cfg.FindResource(c.Meta.ID)
-		if r != nil {
-			state := r.(*RandomUUID)
-			c.Value = state.Value
-		}
-	}
-
-	return nil
+func (c *RandomUUID) RestoreState(r types.Resource) {
+	state := r.(*RandomUUID)
+	c.Value = state.Value
 }
@@ -9,6 +9,7 @@ import (
 
 	"github.com/infinytum/raymond/v2"
 	htypes "github.com/jumppad-labs/hclconfig/types"
+	"github.com/jumppad-labs/jumppad/pkg/clients/container"
 	"github.com/jumppad-labs/jumppad/pkg/utils"
 	sdk "github.com/jumppad-labs/plugin-sdk"
 	"github.com/zclconf/go-cty/cty"
@@ -135,6 +136,16 @@ func (p *TemplateProvider) Destroy(ctx context.Context, force bool) error {
 	return nil
 }
 
+// Snapshot does nothing, the template is rendered again when restored
+func (p *TemplateProvider) Snapshot(ctx context.Context, s *container.Snapshot) error {
+	return nil
+}
+
+// Restore renders the template to the destination
+func (p *TemplateProvider) Restore(ctx context.Context, s *container.Snapshot) error {
+	return p.Create(ctx)
+}
+
 // Lookup satisfies the interface method but is not implemented by Template
 func (p *TemplateProvider) Lookup() ([]string, error) {
 	return []string{}, nil
@@ -5,7 +5,7 @@ import (
 	"strings"
 
 	"github.com/jumppad-labs/hclconfig/types"
-	"github.com/jumppad-labs/jumppad/pkg/config"
+	"github.com/jumppad-labs/jumppad/pkg/config/resources/lifecycle"
 	"github.com/jumppad-labs/jumppad/pkg/utils"
 	"github.com/zclconf/go-cty/cty"
 )
@@ -17,6 +17,8 @@ const TypeTemplate string = "template"
 type Template struct {
 	types.ResourceBase `hcl:",remain"`
 
+	Lifecycle *lifecycle.Lifecycle `hcl:"lifecycle,block" json:"lifecycle,omitempty"`
+
 	Source      string               `hcl:"source" json:"source"`                          // Source template to be processed as string
 	Destination string               `hcl:"destination" json:"destination"`                // Destination filename to write
 	Variables   map[string]cty.Value `hcl:"variables,optional" json:"variables,omitempty"` // Variables to be processed in the template
@@ -40,15 +42,10 @@ func (t *Template) Process() error {
 		t.Source = strings.Replace(t.Source, "\r\n", "\n", -1)
 	}
 
-	cfg, err := config.LoadState()
-	if err == nil {
-		// try and find the resource in the state
-		r, _ := cfg.FindResource(t.Meta.ID)
-		if r != nil {
-			kstate := r.(*Template)
-			t.Checksum = kstate.Checksum
-		}
-	}
-
 	return nil
 }
+
+func (t *Template) RestoreState(r types.Resource) {
+	kstate := r.(*Template)
+	t.Checksum = kstate.Checksum
+}
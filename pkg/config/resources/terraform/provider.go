@@ -18,6 +18,7 @@ import (
 	"github.com/jumppad-labs/jumppad/pkg/clients"
 	cclient "github.com/jumppad-labs/jumppad/pkg/clients/container"
 	ctypes "github.com/jumppad-labs/jumppad/pkg/clients/container/types"
+	"github.com/jumppad-labs/jumppad/pkg/jumppad/tracing"
 	"github.com/jumppad-labs/jumppad/pkg/utils"
 	sdk "github.com/jumppad-labs/plugin-sdk"
 	"github.com/kennygrant/sanitize"
@@ -33,22 +34,28 @@ type TerraformProvider struct {
 	config *Terraform
 	client cclient.ContainerTasks
 	log    sdk.Logger
+	env    utils.Environment
 }
 
 func (p *TerraformProvider) Init(cfg htypes.Resource, l sdk.Logger) error {
-	c, ok := cfg.(*Terraform)
-	if !ok {
-		return fmt.Errorf("unable to initialize Terraform provider, resource is not of type Terraform")
-	}
-
 	cli, err := clients.GenerateClients(l)
 	if err != nil {
 		return err
 	}
 
+	return p.InitWithClients(cfg, l, cli)
+}
+
+func (p *TerraformProvider) InitWithClients(cfg htypes.Resource, l sdk.Logger, cli *clients.Clients) error {
+	c, ok := cfg.(*Terraform)
+	if !ok {
+		return fmt.Errorf("unable to initialize Terraform provider, resource is not of type Terraform")
+	}
+
 	p.config = c
 	p.client = cli.ContainerTasks
 	p.log = l
+	p.env = cli.Environment
 
 	return nil
 }
@@ -68,7 +75,7 @@ func (p *TerraformProvider) Create(ctx context.Context) error {
 	}
 
 	// terraform init & terraform apply
-	id, err := p.createContainer()
+	id, err := p.createContainer(ctx)
 	if err != nil {
 		return fmt.Errorf("unable to create container for terraform.%s: %w", p.config.Meta.Name, err)
 	}
@@ -112,7 +119,7 @@ func (p *TerraformProvider) Destroy(ctx context.Context, force bool) error {
 
 	p.log.Info("Destroy Terraform", "ref", p.config.Meta.ID)
 
-	id, err := p.createContainer()
+	id, err := p.createContainer(ctx)
 	if err != nil {
 		return fmt.Errorf("unable to create container for Terraform.%s: %w", p.config.Meta.Name, err)
 	}
@@ -126,7 +133,7 @@ func (p *TerraformProvider) Destroy(ctx context.Context, force bool) error {
 	}
 
 	// remove the temp folders
-	os.RemoveAll(terraformStateFolder(p.config))
+	os.RemoveAll(terraformStateFolder(p.env, p.config))
 
 	return err
 }
@@ -184,7 +191,7 @@ func (p *TerraformProvider) generateVariables() error {
 		return nil
 	}
 
-	statePath := terraformStateFolder(p.config)
+	statePath := terraformStateFolder(p.env, p.config)
 
 	f := hclwrite.NewEmptyFile()
 	root := f.Body()
@@ -206,10 +213,10 @@ func (p *TerraformProvider) generateVariables() error {
 	return nil
 }
 
-func (p *TerraformProvider) createContainer() (string, error) {
-	fqdn := utils.FQDN(p.config.Meta.Name, p.config.Meta.Module, p.config.Meta.Type)
-	statePath := terraformStateFolder(p.config)
-	cachePath := terraformCacheFolder()
+func (p *TerraformProvider) createContainer(ctx context.Context) (string, error) {
+	fqdn := p.env.FQDN(p.config.Meta.Name, p.config.Meta.Module, p.config.Meta.Type)
+	statePath := terraformStateFolder(p.env, p.config)
+	cachePath := terraformCacheFolder(p.env)
 
 	image := fmt.Sprintf("%s:%s", terraformImageName, p.config.Version)
 
@@ -276,7 +283,9 @@ func (p *TerraformProvider) createContainer() (string, error) {
 	tf.Command = []string{"-f", "/dev/null"} // ensure container does not immediately exit
 
 	// pull any images needed for this container
-	err := p.client.PullImage(*tf.Image, false)
+	err := tracing.Trace(ctx, "PullImage", func() error {
+		return p.client.PullImage(*tf.Image, false)
+	}, tracing.Images(tf.Image.Name))
 	if err != nil {
 		p.log.Error("Error pulling container image", "ref", p.config.Meta.ID, "image", tf.Image.Name)
 
@@ -309,7 +318,7 @@ func (p *TerraformProvider) terraformApply(containerid string) error {
 		envs = append(envs, fmt.Sprintf("%s=%s", k, v))
 	}
 
-	tfvarFlag := getTerraformVarsFlag(p.config)
+	tfvarFlag := getTerraformVarsFlag(p.env, p.config)
 	wd := path.Join("/config", p.config.WorkingDirectory)
 
 	script := `#!/bin/sh
@@ -348,7 +357,7 @@ func (p *TerraformProvider) terraformApply(containerid string) error {
 }
 
 func (p *TerraformProvider) generateOutput() error {
-	statePath := terraformStateFolder(p.config)
+	statePath := terraformStateFolder(p.env, p.config)
 	outputPath := path.Join(statePath, "output.json")
 
 	data, err := os.ReadFile(outputPath)
@@ -405,7 +414,7 @@ func (p *TerraformProvider) terraformDestroy(containerid string) error {
 
 	// check to see if the state files exist, if not then this resource might not
 	// have been created correctly so just exit
-	statePath := terraformStateFolder(p.config)
+	statePath := terraformStateFolder(p.env, p.config)
 	_, err := os.Stat(path.Join(statePath, "terraform.tfstate"))
 	if err != nil {
 		return nil
@@ -418,7 +427,7 @@ func (p *TerraformProvider) terraformDestroy(containerid string) error {
 
 	wd := path.Join("/config", p.config.WorkingDirectory)
 
-	tfvarFlag := getTerraformVarsFlag(p.config)
+	tfvarFlag := getTerraformVarsFlag(p.env, p.config)
 
 	script := `#!/bin/sh
   terraform init \
@@ -443,9 +452,9 @@ func (p *TerraformProvider) terraformDestroy(containerid string) error {
 	return nil
 }
 
-func getTerraformVarsFlag(r *Terraform) string {
+func getTerraformVarsFlag(env utils.Environment, r *Terraform) string {
 	// do we have a vars file
-	statePath := terraformStateFolder(r)
+	statePath := terraformStateFolder(env, r)
 	tfvarFlag := ` \
     -var-file=/var/lib/terraform/terraform.tfvars
   `
@@ -459,13 +468,14 @@ func getTerraformVarsFlag(r *Terraform) string {
 	return tfvarFlag
 }
 
-// GetTerraformFolder creates the terraform directory used by the application
-func terraformStateFolder(r *Terraform) string {
+// terraformStateFolder creates the terraform directory for the resource in
+// the workspace
+func terraformStateFolder(env utils.Environment, r *Terraform) string {
 	p := sanitize.Path(resources.FQRNFromResource(r).String())
 	p = strings.Replace(p, ".", "_", -1)
 	p = strings.Replace(p, "-", "_", -1)
 
-	data := filepath.Join(utils.JumppadHome(), "terraform", "state", p)
+	data := filepath.Join(env.WorkspaceHome(), "terraform", "state", p)
 
 	// create the folder if it does not exist
 	os.MkdirAll(data, 0755)
@@ -474,7 +484,7 @@ func terraformStateFolder(r *Terraform) string {
 	return data
 }
 
-func terraformCacheFolder() string {
+func terraformCacheFolder(env utils.Environment) string {
 	// the cache folder is
-	return utils.CacheFolder("terraform", 0755)
+	return env.CacheFolder("terraform", 0755)
 }
@@ -11,6 +11,7 @@ import (
 	ctypes "github.com/jumppad-labs/jumppad/pkg/clients/container/types"
 	"github.com/jumppad-labs/jumppad/pkg/clients/logger"
 	"github.com/jumppad-labs/jumppad/pkg/config/resources/container"
+	"github.com/jumppad-labs/jumppad/pkg/utils"
 	"github.com/stretchr/testify/mock"
 	"github.com/stretchr/testify/require"
 	"github.com/zclconf/go-cty/cty"
@@ -22,7 +23,7 @@ func setupProvider(t *testing.T, c *Terraform) (*TerraformProvider, *mocks.Conta
 	os.Setenv("HOME", tmpDir)
 
 	// output should always exist
-	sd := terraformStateFolder(c)
+	sd := terraformStateFolder(utils.Environment{}, c)
 	os.WriteFile(path.Join(sd, "output.json"), []byte("{\"abc\": {\"value\": \"123\"}}"), 0655)
 
 	mc := &mocks.ContainerTasks{}
@@ -32,7 +33,7 @@ func setupProvider(t *testing.T, c *Terraform) (*TerraformProvider, *mocks.Conta
 	mc.Mock.On("RemoveContainer", "abc", true).Return(nil)
 
 	l := logger.NewTestLogger(t)
-	p := &TerraformProvider{c, mc, l}
+	p := &TerraformProvider{c, mc, l, utils.Environment{}}
 
 	return p, mc, sd
 }
@@ -97,11 +98,11 @@ func TestCreatesTerraformContainerWithTheCorrectValues(t *testing.T) {
 	require.Equal(t, "/config", c.Volumes[0].Destination)
 
 	// check the state volume has been added
-	require.Equal(t, terraformStateFolder(res), c.Volumes[1].Source)
+	require.Equal(t, terraformStateFolder(utils.Environment{}, res), c.Volumes[1].Source)
 	require.Equal(t, "/var/lib/terraform", c.Volumes[1].Destination)
 
 	// check the plugin cache has been added
-	require.Equal(t, terraformCacheFolder(), c.Volumes[2].Source)
+	require.Equal(t, terraformCacheFolder(utils.Environment{}), c.Volumes[2].Source)
 	require.Equal(t, "/var/lib/terraform.d", c.Volumes[2].Destination)
 }
 
@@ -5,8 +5,8 @@ import (
 	"strings"
 
 	"github.com/jumppad-labs/hclconfig/types"
-	"github.com/jumppad-labs/jumppad/pkg/config"
 	ctypes "github.com/jumppad-labs/jumppad/pkg/config/resources/container"
+	"github.com/jumppad-labs/jumppad/pkg/config/resources/lifecycle"
 	"github.com/jumppad-labs/jumppad/pkg/utils"
 	"github.com/zclconf/go-cty/cty"
 )
@@ -18,6 +18,8 @@ const TypeTerraform string = "terraform"
 type Terraform struct {
 	types.ResourceBase `hcl:",remain"`
 
+	Lifecycle *lifecycle.Lifecycle `hcl:"lifecycle,block" json:"lifecycle,omitempty"`
+
 	Networks []ctypes.NetworkAttachment `hcl:"network,block" json:"networks,omitempty"` // Attach to the correct network // only when Image is specified
 
 	Source           string            `hcl:"source" json:"source"`                                          // Source directory containing Terraform config
@@ -61,17 +63,11 @@ func (t *Terraform) Process() error {
 		t.Version = "1.9.8"
 	}
 
-	// restore the applyoutput from the state
-	cfg, err := config.LoadState()
-	if err == nil {
-		// try and find the resource in the state
-		r, _ := cfg.FindResource(t.Meta.ID)
-		if r != nil {
-			kstate := r.(*Terraform)
-			t.ApplyOutput = kstate.ApplyOutput
-			t.SourceChecksum = kstate.SourceChecksum
-		}
-	}
-
 	return nil
 }
+
+func (t *Terraform) RestoreState(r types.Resource) {
+	kstate := r.(*Terraform)
+	t.ApplyOutput = kstate.ApplyOutput
+	t.SourceChecksum = kstate.SourceChecksum
+}
@@ -0,0 +1,325 @@
+package config
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+
+	"github.com/hashicorp/hcl/v2"
+	"github.com/hashicorp/hcl/v2/hclparse"
+	"github.com/hashicorp/hcl/v2/hclsyntax"
+	"github.com/jumppad-labs/hclconfig"
+	"github.com/jumppad-labs/hclconfig/resources"
+	"github.com/jumppad-labs/hclconfig/types"
+	"github.com/jumppad-labs/jumppad/pkg/jumppad/constants"
+	"github.com/jumppad-labs/jumppad/pkg/utils"
+	"github.com/zclconf/go-cty/cty"
+	"github.com/zclconf/go-cty/cty/convert"
+	"github.com/zclconf/go-cty/cty/function"
+)
+
+// SensitiveFunction is the name of the function that marks the value of a
+// variable or output as sensitive, i.e.
+//
+//	variable "db_password" {
+//	  default = sensitive("password")
+//	}
+const SensitiveFunction = "sensitive"
+
+// sensitiveTag is the struct tag that marks resource fields containing
+// secrets. String fields are tagged with `sensitive:"true"`, struct fields
+// are tagged with the names of the fields in the struct that contain the
+// secret, i.e. `sensitive:"Contents"`.
+const sensitiveTag = "sensitive"
+
+// resourcePackage is the prefix of the packages whose types are searched
+// for sensitive fields, other types such as hcl expressions are skipped
+const resourcePackage = "github.com/jumppad-labs/jumppad/"
+
+// IsSensitive returns true when the resource has been marked as sensitive
+func IsSensitive(r types.Resource) bool {
+	s, _ := r.Metadata().Properties[constants.PropertySensitive].(bool)
+	return s
+}
+
+// MarkSensitive marks variables and outputs whose value is wrapped with the
+// sensitive function and returns true when the resource is sensitive. Path
+// is the file or folder containing the configuration, it is used to find
+// variables as the parser does not record the file they are defined in.
+func MarkSensitive(r types.Resource, path string) bool {
+	m := r.Metadata()
+
+	attr := ""
+	switch m.Type {
+	case resources.TypeVariable:
+		attr = "default"
+	case resources.TypeOutput:
+		attr = "value"
+	default:
+		return false
+	}
+
+	if IsSensitive(r) {
+		return true
+	}
+
+	files := []string{m.File}
+	if m.File == "" {
+		// variables in modules can not be found without the file
+		if m.Module != "" {
+			return false
+		}
+
+		files = configFiles(path)
+	}
+
+	for _, f := range files {
+		if sensitiveBlock(f, m.Type, m.Name, attr) {
+			if m.Properties == nil {
+				m.Properties = map[string]any{}
+			}
+
+			m.Properties[constants.PropertySensitive] = true
+			return true
+		}
+	}
+
+	return false
+}
+
+// SensitiveValues returns the values of the fields in the resource that are
+// tagged as sensitive and the value of sensitive outputs
+func SensitiveValues(r types.Resource) []string {
+	values := []string{}
+
+	if o, ok := r.(*resources.Output); ok && IsSensitive(r) {
+		values = append(values, stringValues(reflect.ValueOf(o.Value))...)
+	}
+
+	return append(values, sensitiveFields(reflect.ValueOf(r))...)
+}
+
+// SensitiveConfigValues returns the sensitive values of all resources in
+// the config
+func SensitiveConfigValues(c *hclconfig.Config) []string {
+	values := []string{}
+	if c == nil {
+		return values
+	}
+
+	for _, r := range c.Resources {
+		values = append(values, SensitiveValues(r)...)
+	}
+
+	return values
+}
+
+// VariableValues returns the values that a variable can have, the value is
+// resolved by the parser so all possible sources are returned: the given
+// variables, the environment, the variables file and the default
+func VariableValues(r types.Resource, variables map[string]string, variablesFile string) []string {
+	v, ok := r.(*resources.Variable)
+	if !ok {
+		return nil
+	}
+
+	name := v.Meta.Name
+	values := []string{}
+
+	if val, ok := variables[name]; ok {
+		values = append(values, val)
+	}
+
+	if val, ok := os.LookupEnv(VariableEnvPrefix + name); ok {
+		values = append(values, val)
+	}
+
+	if variablesFile != "" {
+		f, diag := hclparse.NewParser().ParseHCLFile(variablesFile)
+		if !diag.HasErrors() {
+			attrs, _ := f.Body.JustAttributes()
+			if a, ok := attrs[name]; ok {
+				values = append(values, expressionValue(a.Expr)...)
+			}
+		}
+	}
+
+	if a, ok := v.Default.(*hcl.Attribute); ok {
+		values = append(values, expressionValue(a.Expr)...)
+	}
+
+	return values
+}
+
+// sensitiveBlock returns true when the attribute of the block with the given
+// type and name calls the sensitive function
+func sensitiveBlock(file, blockType, name, attr string) bool {
+	f, diag := hclparse.NewParser().ParseHCLFile(file)
+	if diag.HasErrors() {
+		return false
+	}
+
+	body, ok := f.Body.(*hclsyntax.Body)
+	if !ok {
+		return false
+	}
+
+	for _, b := range body.Blocks {
+		if b.Type != blockType || len(b.Labels) == 0 || b.Labels[0] != name {
+			continue
+		}
+
+		a, ok := b.Body.Attributes[attr]
+		if !ok {
+			return false
+		}
+
+		fc, ok := a.Expr.(*hclsyntax.FunctionCallExpr)
+		return ok && fc.Name == SensitiveFunction
+	}
+
+	return false
+}
+
+// configFiles returns the config files at path
+func configFiles(path string) []string {
+	if utils.IsHCLFile(path) {
+		return []string{path}
+	}
+
+	files, _ := filepath.Glob(filepath.Join(path, "*.hcl"))
+	return files
+}
+
+// expressionValue evaluates expressions that only contain literals and the
+// sensitive function
+func expressionValue(expr hcl.Expression) []string {
+	ctx := &hcl.EvalContext{
+		Functions: map[string]function.Function{
+			SensitiveFunction: function.New(&function.Spec{
+				Params: []function.Parameter{{Name: "value", Type: cty.String}},
+				Type:   function.StaticReturnType(cty.String),
+				Impl: func(args []cty.Value, _ cty.Type) (cty.Value, error) {
+					return args[0], nil
+				},
+			}),
+		},
+	}
+
+	val, diag := expr.Value(ctx)
+	if diag.HasErrors() {
+		return nil
+	}
+
+	val, err := convert.Convert(val, cty.String)
+	if err != nil || val.IsNull() || !val.IsKnown() {
+		return nil
+	}
+
+	return []string{val.AsString()}
+}
+
+// sensitiveFields returns the values of the tagged fields in v
+func sensitiveFields(v reflect.Value) []string {
+	values := []string{}
+
+	switch v.Kind() {
+	case reflect.Pointer, reflect.Interface:
+		if !v.IsNil() {
+			values = append(values, sensitiveFields(v.Elem())...)
+		}
+	case reflect.Slice, reflect.Array:
+		for i := 0; i < v.Len(); i++ {
+			values = append(values, sensitiveFields(v.Index(i))...)
+		}
+	case reflect.Map:
+		iter := v.MapRange()
+		for iter.Next() {
+			values = append(values, sensitiveFields(iter.Value())...)
+		}
+	case reflect.Struct:
+		t := v.Type()
+		if !strings.HasPrefix(t.PkgPath(), resourcePackage) {
+			return values
+		}
+
+		for i := 0; i < t.NumField(); i++ {
+			f := t.Field(i)
+			if !f.IsExported() {
+				continue
+			}
+
+			tag, ok := f.Tag.Lookup(sensitiveTag)
+			if !ok {
+				values = append(values, sensitiveFields(v.Field(i))...)
+				continue
+			}
+
+			values = append(values, taggedValues(v.Field(i), tag)...)
+		}
+	}
+
+	return values
+}
+
+// taggedValues returns the secrets in a tagged field
+func taggedValues(v reflect.Value, tag string) []string {
+	for v.Kind() == reflect.Pointer || v.Kind() == reflect.Interface {
+		if v.IsNil() {
+			return nil
+		}
+
+		v = v.Elem()
+	}
+
+	if tag == "true" || v.Kind() != reflect.Struct {
+		return stringValues(v)
+	}
+
+	values := []string{}
+	for _, name := range strings.Split(tag, ",") {
+		f := v.FieldByName(strings.TrimSpace(name))
+		if !f.IsValid() {
+			panic(fmt.Sprintf("sensitive field %s does not exist in %s", name, v.Type()))
+		}
+
+		values = append(values, stringValues(f)...)
+	}
+
+	return values
+}
+
+// stringValues returns all the strings contained in v
+func stringValues(v reflect.Value) []string {
+	values := []string{}
+
+	switch v.Kind() {
+	case reflect.String:
+		if s := v.String(); s != "" {
+			values = append(values, s)
+		}
+	case reflect.Pointer, reflect.Interface:
+		if !v.IsNil() {
+			values = append(values, stringValues(v.Elem())...)
+		}
+	case reflect.Slice, reflect.Array:
+		for i := 0; i < v.Len(); i++ {
+			values = append(values, stringValues(v.Index(i))...)
+		}
+	case reflect.Map:
+		iter := v.MapRange()
+		for iter.Next() {
+			values = append(values, stringValues(iter.Value())...)
+		}
+	case reflect.Struct:
+		for i := 0; i < v.NumField(); i++ {
+			if v.Type().Field(i).IsExported() {
+				values = append(values, stringValues(v.Field(i))...)
+			}
+		}
+	}
+
+	return values
+}
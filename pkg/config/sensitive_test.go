@@ -0,0 +1,117 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+
+	"github.com/jumppad-labs/hclconfig/resources"
+	"github.com/jumppad-labs/hclconfig/types"
+	"github.com/stretchr/testify/require"
+)
+
+type testKey struct {
+	Path     string `json:"path"`
+	Contents string `json:"contents"`
+}
+
+type testAuth struct {
+	Username string `json:"username"`
+	Password string `json:"password" sensitive:"true"`
+}
+
+type testSensitiveResource struct {
+	types.ResourceBase `hcl:",remain"`
+
+	Auth       *testAuth  `json:"auth"`
+	Images     []testAuth `json:"images"`
+	PrivateKey testKey    `json:"private_key" sensitive:"Contents"`
+}
+
+const sensitiveConfig = `
+variable "password" {
+  default = sensitive("default-password")
+}
+
+variable "username" {
+  default = "admin"
+}
+
+output "password" {
+  value = sensitive(variable.password)
+}
+
+output "username" {
+  value = variable.username
+}
+`
+
+func parseSensitiveConfig(t *testing.T, vars map[string]string) (map[string]types.Resource, string) {
+	dir := t.TempDir()
+	err := os.WriteFile(filepath.Join(dir, "main.hcl"), []byte(sensitiveConfig), 0644)
+	require.NoError(t, err)
+
+	found := map[string]types.Resource{}
+	mu := sync.Mutex{}
+
+	p := NewParser(func(r types.Resource) error {
+		MarkSensitive(r, dir)
+
+		mu.Lock()
+		defer mu.Unlock()
+		found[r.Metadata().ID] = r
+
+		return nil
+	}, vars, nil)
+
+	_, err = p.ParseDirectory(dir)
+	require.NoError(t, err)
+
+	return found, dir
+}
+
+func TestSensitiveValuesReturnsTaggedFields(t *testing.T) {
+	r := &testSensitiveResource{
+		Auth:       &testAuth{Username: "admin", Password: "auth-password"},
+		Images:     []testAuth{{Username: "nic", Password: "image-password"}},
+		PrivateKey: testKey{Path: "/certs/key.pem", Contents: "private-key"},
+	}
+
+	values := SensitiveValues(r)
+	require.ElementsMatch(t, []string{"auth-password", "image-password", "private-key"}, values)
+}
+
+func TestMarkSensitiveMarksVariablesAndOutputs(t *testing.T) {
+	found, _ := parseSensitiveConfig(t, nil)
+
+	require.True(t, IsSensitive(found["variable.password"]))
+	require.True(t, IsSensitive(found["output.password"]))
+	require.False(t, IsSensitive(found["variable.username"]))
+	require.False(t, IsSensitive(found["output.username"]))
+}
+
+func TestSensitiveValuesReturnsSensitiveOutputs(t *testing.T) {
+	found, _ := parseSensitiveConfig(t, nil)
+
+	require.Equal(t, []string{"default-password"}, SensitiveValues(found["output.password"]))
+	require.Empty(t, SensitiveValues(found["output.username"]))
+}
+
+func TestVariableValuesReturnsAllSources(t *testing.T) {
+	t.Setenv(VariableEnvPrefix+"password", "env-password")
+
+	vars := map[string]string{"password": "cli-password"}
+	found, dir := parseSensitiveConfig(t, vars)
+
+	varsFile := filepath.Join(dir, "vars.hcl")
+	err := os.WriteFile(varsFile, []byte(`password = "file-password"`), 0644)
+	require.NoError(t, err)
+
+	values := VariableValues(found["variable.password"], vars, varsFile)
+	require.Equal(t, []string{"cli-password", "env-password", "file-password", "default-password"}, values)
+}
+
+func TestVariableValuesReturnsNilForOtherResources(t *testing.T) {
+	require.Nil(t, VariableValues(&resources.Output{}, nil, ""))
+}
@@ -0,0 +1,179 @@
+package config
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+
+	"github.com/jumppad-labs/hclconfig"
+	"github.com/jumppad-labs/jumppad/pkg/clients/container"
+)
+
+// ErrSnapshotNotFound is returned when a snapshot does not exist
+var ErrSnapshotNotFound = errors.New("snapshot not found")
+
+// ErrInvalidSnapshotName is returned when a snapshot name can not be used
+// as an image tag
+var ErrInvalidSnapshotName = fmt.Errorf("snapshot names must start with a letter or number and only contain lowercase letters, numbers and -, and be a maximum of 32 characters")
+
+var snapshotNameRegex = regexp.MustCompile(`^[a-z0-9][a-z0-9\-]{0,31}$`)
+
+// ValidateSnapshotName checks that the name can be used for a snapshot, the
+// name is used as the tag of the images created for the snapshot
+func ValidateSnapshotName(name string) error {
+	if !snapshotNameRegex.MatchString(name) {
+		return ErrInvalidSnapshotName
+	}
+
+	return nil
+}
+
+// SnapshotExists returns true when a snapshot with the given name is stored
+// in the folder dir
+func SnapshotExists(dir, name string) bool {
+	_, err := os.Stat(snapshotPath(filepath.Join(dir, name)))
+	return err == nil
+}
+
+// SaveSnapshot writes the snapshot and the state it was taken from to the
+// folder of the snapshot, the state is encrypted in the same way as the
+// current state
+func SaveSnapshot(s *container.Snapshot, c *hclconfig.Config) error {
+	err := os.MkdirAll(s.Dir, os.ModePerm)
+	if err != nil {
+		return fmt.Errorf("unable to create snapshot directory '%s', error: %s", s.Dir, err)
+	}
+
+	d, err := c.ToJSON()
+	if err != nil {
+		return fmt.Errorf("unable to serialize config to JSON: %s", err)
+	}
+
+	d, err = encryptState(d)
+	if err != nil {
+		return fmt.Errorf("unable to encrypt state: %s", err)
+	}
+
+	err = os.WriteFile(filepath.Join(s.Dir, "state.json"), d, 0644)
+	if err != nil {
+		return fmt.Errorf("unable to write snapshot state, error: %s", err)
+	}
+
+	sd, err := json.MarshalIndent(s, "", "  ")
+	if err != nil {
+		return fmt.Errorf("unable to serialize snapshot: %s", err)
+	}
+
+	err = os.WriteFile(snapshotPath(s.Dir), sd, 0644)
+	if err != nil {
+		return fmt.Errorf("unable to write snapshot '%s', error: %s", s.Name, err)
+	}
+
+	return nil
+}
+
+// LoadSnapshot reads the snapshot with the given name and the state it was
+// taken from
+func LoadSnapshot(dir, name string) (*container.Snapshot, *hclconfig.Config, error) {
+	s, err := readSnapshot(filepath.Join(dir, name))
+	if err != nil {
+		return nil, nil, err
+	}
+
+	d, err := os.ReadFile(filepath.Join(s.Dir, "state.json"))
+	if err != nil {
+		return nil, nil, fmt.Errorf("unable to read snapshot state: %s", err)
+	}
+
+	d, err = decryptState(d)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	p := NewParser(nil, nil, nil)
+	c, err := p.UnmarshalJSON(d)
+	if err != nil {
+		return nil, nil, fmt.Errorf("unable to unmarshal snapshot state: %s", err)
+	}
+
+	return s, c, nil
+}
+
+// ListSnapshots returns the snapshots stored in the folder dir, oldest first
+func ListSnapshots(dir string) ([]*container.Snapshot, error) {
+	entries, err := os.ReadDir(dir)
+	if os.IsNotExist(err) {
+		return []*container.Snapshot{}, nil
+	}
+
+	if err != nil {
+		return nil, fmt.Errorf("unable to read snapshots directory '%s', error: %s", dir, err)
+	}
+
+	snapshots := []*container.Snapshot{}
+	for _, e := range entries {
+		if !e.IsDir() {
+			continue
+		}
+
+		s, err := readSnapshot(filepath.Join(dir, e.Name()))
+		if errors.Is(err, ErrSnapshotNotFound) {
+			// folders of incomplete snapshots do not contain a snapshot file
+			continue
+		}
+
+		if err != nil {
+			return nil, err
+		}
+
+		snapshots = append(snapshots, s)
+	}
+
+	sort.Slice(snapshots, func(i, j int) bool {
+		return snapshots[i].Created.Before(snapshots[j].Created)
+	})
+
+	return snapshots, nil
+}
+
+// RemoveSnapshot deletes the snapshot with the given name, the images
+// created for the snapshot are not removed
+func RemoveSnapshot(dir, name string) error {
+	if !SnapshotExists(dir, name) {
+		return fmt.Errorf("%w: %s", ErrSnapshotNotFound, name)
+	}
+
+	return os.RemoveAll(filepath.Join(dir, name))
+}
+
+func readSnapshot(sd string) (*container.Snapshot, error) {
+	d, err := os.ReadFile(snapshotPath(sd))
+	if os.IsNotExist(err) {
+		return nil, fmt.Errorf("%w: %s", ErrSnapshotNotFound, filepath.Base(sd))
+	}
+
+	if err != nil {
+		return nil, fmt.Errorf("unable to read snapshot '%s', error: %s", filepath.Base(sd), err)
+	}
+
+	s := &container.Snapshot{}
+	err = json.Unmarshal(d, s)
+	if err != nil {
+		return nil, fmt.Errorf("unable to unmarshal snapshot '%s': %s", filepath.Base(sd), err)
+	}
+
+	s.Dir = sd
+	if s.Containers == nil {
+		s.Containers = map[string]container.SnapshotContainer{}
+	}
+
+	return s, nil
+}
+
+func snapshotPath(sd string) string {
+	return filepath.Join(sd, "snapshot.json")
+}
@@ -0,0 +1,103 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/jumppad-labs/hclconfig"
+	"github.com/jumppad-labs/hclconfig/resources"
+	"github.com/jumppad-labs/hclconfig/types"
+	"github.com/jumppad-labs/jumppad/pkg/clients/container"
+	"github.com/jumppad-labs/jumppad/testutils"
+	"github.com/stretchr/testify/require"
+)
+
+func saveTestSnapshot(t *testing.T, dir, name string, created time.Time) *container.Snapshot {
+	c := hclconfig.NewConfig()
+	err := c.AppendResource(&resources.Variable{
+		ResourceBase: types.ResourceBase{
+			Meta: types.Meta{ID: "variable.one", Name: "one", Type: resources.TypeVariable},
+		},
+	})
+	require.NoError(t, err)
+
+	s := container.NewSnapshot(name, filepath.Join(dir, name))
+	s.Created = created
+	s.Containers["web"] = container.SnapshotContainer{Image: s.ImageName("web"), Volumes: true}
+
+	err = SaveSnapshot(s, c)
+	require.NoError(t, err)
+
+	return s
+}
+
+func TestValidateSnapshotName(t *testing.T) {
+	require.NoError(t, ValidateSnapshotName("before-upgrade"))
+	require.ErrorIs(t, ValidateSnapshotName("Before_Upgrade"), ErrInvalidSnapshotName)
+	require.ErrorIs(t, ValidateSnapshotName("-test"), ErrInvalidSnapshotName)
+}
+
+func TestSaveSnapshotAndLoadSnapshot(t *testing.T) {
+	testutils.SetupState(t, "")
+	dir := t.TempDir()
+
+	saveTestSnapshot(t, dir, "test", time.Now())
+	require.True(t, SnapshotExists(dir, "test"))
+
+	s, c, err := LoadSnapshot(dir, "test")
+	require.NoError(t, err)
+
+	require.Equal(t, "test", s.Name)
+	require.Equal(t, filepath.Join(dir, "test"), s.Dir)
+	require.Equal(t, "jumppad.dev/snapshot/web:test", s.Containers["web"].Image)
+	require.True(t, s.Containers["web"].Volumes)
+
+	_, err = c.FindResource("variable.one")
+	require.NoError(t, err)
+}
+
+func TestLoadSnapshotReturnsErrorWhenNotExists(t *testing.T) {
+	testutils.SetupState(t, "")
+
+	_, _, err := LoadSnapshot(t.TempDir(), "test")
+	require.ErrorIs(t, err, ErrSnapshotNotFound)
+}
+
+func TestListSnapshotsReturnsOldestFirst(t *testing.T) {
+	testutils.SetupState(t, "")
+	dir := t.TempDir()
+
+	saveTestSnapshot(t, dir, "two", time.Now())
+	saveTestSnapshot(t, dir, "one", time.Now().Add(-1*time.Hour))
+
+	// incomplete snapshots are ignored
+	os.MkdirAll(filepath.Join(dir, "three"), os.ModePerm)
+
+	snapshots, err := ListSnapshots(dir)
+	require.NoError(t, err)
+	require.Len(t, snapshots, 2)
+	require.Equal(t, "one", snapshots[0].Name)
+	require.Equal(t, "two", snapshots[1].Name)
+}
+
+func TestListSnapshotsReturnsEmptyWhenNoSnapshots(t *testing.T) {
+	snapshots, err := ListSnapshots(filepath.Join(t.TempDir(), "snapshots"))
+	require.NoError(t, err)
+	require.Empty(t, snapshots)
+}
+
+func TestRemoveSnapshot(t *testing.T) {
+	testutils.SetupState(t, "")
+	dir := t.TempDir()
+
+	saveTestSnapshot(t, dir, "test", time.Now())
+
+	err := RemoveSnapshot(dir, "test")
+	require.NoError(t, err)
+	require.False(t, SnapshotExists(dir, "test"))
+
+	err = RemoveSnapshot(dir, "test")
+	require.ErrorIs(t, err, ErrSnapshotNotFound)
+}
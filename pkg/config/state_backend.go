@@ -0,0 +1,204 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"strconv"
+
+	"github.com/hashicorp/hcl/v2/hclsimple"
+	"github.com/jumppad-labs/jumppad/pkg/utils"
+)
+
+const (
+	// BackendLocal stores the state in a file in the workspace folder
+	BackendLocal = "local"
+
+	// BackendHTTP stores the state with a remote HTTP server
+	BackendHTTP = "http"
+)
+
+// Environment variables that configure the state backend, these take
+// precedence over the settings file
+const (
+	EnvStateBackend           = "JUMPPAD_STATE_BACKEND"
+	EnvStateHTTPAddress       = "JUMPPAD_STATE_HTTP_ADDRESS"
+	EnvStateHTTPLockAddress   = "JUMPPAD_STATE_HTTP_LOCK_ADDRESS"
+	EnvStateHTTPUnlockAddress = "JUMPPAD_STATE_HTTP_UNLOCK_ADDRESS"
+	EnvStateHTTPUsername      = "JUMPPAD_STATE_HTTP_USERNAME"
+	EnvStateHTTPPassword      = "JUMPPAD_STATE_HTTP_PASSWORD"
+	EnvStateHistory           = "JUMPPAD_STATE_HISTORY"
+)
+
+// ErrStateNotFound is returned by a backend when no state exists
+var ErrStateNotFound = errors.New("state not found")
+
+// ErrLockNotHeld is returned by a backend when a lock is released that does
+// not exist or that is held by another process
+var ErrLockNotHeld = errors.New("lock is not held")
+
+// StateBackend defines an interface for the storage of the state
+type StateBackend interface {
+	// Load returns the raw state, ErrStateNotFound is returned when
+	// the state does not exist
+	Load() ([]byte, error)
+
+	// Save replaces the stored state
+	Save(d []byte) error
+
+	// Remove deletes the stored state
+	Remove() error
+
+	// Lock acquires the lock for the state, if the state is already locked
+	// a LockedError containing the details of the current lock is returned
+	Lock(l *StateLock) error
+
+	// Unlock releases the lock for the state, ErrLockNotHeld is returned
+	// when the state is not locked or is locked by another process
+	Unlock(l *StateLock) error
+}
+
+// LockedError is returned by a backend when the state is locked
+type LockedError struct {
+	// Current is the lock that is currently held, nil if the
+	// backend was unable to return the details
+	Current *StateLock
+}
+
+func (e *LockedError) Error() string {
+	if e.Current == nil {
+		return ErrStateLocked.Error()
+	}
+
+	return fmt.Sprintf(
+		"%s, id: %s, pid: %d, host: %s, command: '%s', locked at: %s",
+		ErrStateLocked,
+		e.Current.ID,
+		e.Current.PID,
+		e.Current.Hostname,
+		e.Current.Command,
+		e.Current.Created.Format("2006-01-02T15:04:05Z07:00"),
+	)
+}
+
+func (e *LockedError) Unwrap() error {
+	return ErrStateLocked
+}
+
+// Settings are the user defined settings for a workspace, they are read
+// from the jumppad block in the settings.hcl file in the workspace folder
+//
+//	jumppad {
+//	  docker_context = "buildbox"
+//
+//	  state_backend "http" {
+//	    address = "https://state.example.com/training"
+//	  }
+//	}
+type Settings struct {
+	StateBackend *StateBackendSettings `hcl:"state_backend,block"`
+
+	// StateHistory is the number of previous states that are kept by
+	// backends that support history
+	StateHistory *int `hcl:"state_history,optional"`
+
+	// DockerContext is the Docker context used to create resources, the
+	// --context flag takes precedence over this
+	DockerContext string `hcl:"docker_context,optional"`
+}
+
+// StateBackendSettings configure the backend used to store the state
+type StateBackendSettings struct {
+	Type string `hcl:"type,label"`
+
+	// Address is the URL of the state for the http backend
+	Address string `hcl:"address,optional"`
+
+	// LockAddress and UnlockAddress are the URLs used to lock and unlock
+	// the state, when not set Address is used
+	LockAddress   string `hcl:"lock_address,optional"`
+	UnlockAddress string `hcl:"unlock_address,optional"`
+
+	// Username and Password are used for basic authentication
+	Username string `hcl:"username,optional"`
+	Password string `hcl:"password,optional"`
+}
+
+type settingsFile struct {
+	Jumppad *Settings `hcl:"jumppad,block"`
+}
+
+// LoadSettings reads the settings for the current workspace, empty settings
+// are returned when the settings file does not exist
+func LoadSettings() (*Settings, error) {
+	if _, err := os.Stat(utils.SettingsPath()); err != nil {
+		return &Settings{}, nil
+	}
+
+	sf := &settingsFile{}
+	err := hclsimple.DecodeFile(utils.SettingsPath(), nil, sf)
+	if err != nil {
+		return nil, fmt.Errorf("unable to read settings file '%s': %s", utils.SettingsPath(), err)
+	}
+
+	if sf.Jumppad == nil {
+		return &Settings{}, nil
+	}
+
+	return sf.Jumppad, nil
+}
+
+// NewStateBackend returns the state backend configured by the environment
+// variables or the settings file, when neither is set the local backend
+// is used
+func NewStateBackend() (StateBackend, error) {
+	s, err := LoadSettings()
+	if err != nil {
+		return nil, err
+	}
+
+	bs := s.StateBackend
+	if bs == nil {
+		bs = &StateBackendSettings{Type: BackendLocal}
+	}
+
+	// environment variables override the settings file
+	if v := os.Getenv(EnvStateBackend); v != "" && v != bs.Type {
+		bs = &StateBackendSettings{Type: v}
+	}
+
+	setFromEnv(&bs.Address, EnvStateHTTPAddress)
+	setFromEnv(&bs.LockAddress, EnvStateHTTPLockAddress)
+	setFromEnv(&bs.UnlockAddress, EnvStateHTTPUnlockAddress)
+	setFromEnv(&bs.Username, EnvStateHTTPUsername)
+	setFromEnv(&bs.Password, EnvStateHTTPPassword)
+
+	history := DefaultStateHistory
+	if s.StateHistory != nil {
+		history = *s.StateHistory
+	}
+
+	if v := os.Getenv(EnvStateHistory); v != "" {
+		history, err = strconv.Atoi(v)
+		if err != nil {
+			return nil, fmt.Errorf("invalid value for %s, expected a number: %s", EnvStateHistory, err)
+		}
+	}
+
+	switch bs.Type {
+	case BackendLocal:
+		lb := NewLocalBackend()
+		lb.history = history
+		return lb, nil
+	case BackendHTTP:
+		return NewHTTPBackend(bs)
+	}
+
+	return nil, fmt.Errorf("unknown state backend '%s', valid backends are '%s' and '%s'", bs.Type, BackendLocal, BackendHTTP)
+}
+
+func setFromEnv(v *string, env string) {
+	if e := os.Getenv(env); e != "" {
+		*v = e
+	}
+}
@@ -0,0 +1,181 @@
+package config
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+)
+
+// HTTPBackend stores the state with a remote HTTP server.
+//
+// The state is fetched with GET and stored with POST to the address, it is
+// deleted with DELETE. The state is locked and unlocked by sending the
+// lock details as JSON using the LOCK and UNLOCK methods, when the state is
+// already locked the server responds with 423 Locked or 409 Conflict and
+// the details of the current lock.
+type HTTPBackend struct {
+	address       string
+	lockAddress   string
+	unlockAddress string
+	username      string
+	password      string
+	client        *http.Client
+}
+
+// NewHTTPBackend creates a backend from the given settings
+func NewHTTPBackend(s *StateBackendSettings) (*HTTPBackend, error) {
+	if s.Address == "" {
+		return nil, fmt.Errorf("the http state backend requires an address")
+	}
+
+	b := &HTTPBackend{
+		address:       s.Address,
+		lockAddress:   s.LockAddress,
+		unlockAddress: s.UnlockAddress,
+		username:      s.Username,
+		password:      s.Password,
+		client:        &http.Client{Timeout: 30 * time.Second},
+	}
+
+	if b.lockAddress == "" {
+		b.lockAddress = b.address
+	}
+
+	if b.unlockAddress == "" {
+		b.unlockAddress = b.address
+	}
+
+	return b, nil
+}
+
+func (b *HTTPBackend) Load() ([]byte, error) {
+	resp, err := b.do(http.MethodGet, b.address, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	switch resp.StatusCode {
+	case http.StatusOK:
+	case http.StatusNotFound, http.StatusNoContent:
+		return nil, ErrStateNotFound
+	default:
+		return nil, fmt.Errorf("unexpected status code %d fetching state from %s", resp.StatusCode, b.address)
+	}
+
+	d, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("unable to read state from %s: %s", b.address, err)
+	}
+
+	if len(d) == 0 {
+		return nil, ErrStateNotFound
+	}
+
+	return d, nil
+}
+
+func (b *HTTPBackend) Save(d []byte) error {
+	resp, err := b.do(http.MethodPost, b.address, d)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	switch resp.StatusCode {
+	case http.StatusOK, http.StatusCreated, http.StatusNoContent:
+		return nil
+	}
+
+	return fmt.Errorf("unexpected status code %d saving state to %s", resp.StatusCode, b.address)
+}
+
+func (b *HTTPBackend) Remove() error {
+	resp, err := b.do(http.MethodDelete, b.address, nil)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	switch resp.StatusCode {
+	case http.StatusOK, http.StatusNoContent, http.StatusNotFound:
+		return nil
+	}
+
+	return fmt.Errorf("unexpected status code %d removing state from %s", resp.StatusCode, b.address)
+}
+
+func (b *HTTPBackend) Lock(l *StateLock) error {
+	d, err := json.Marshal(l)
+	if err != nil {
+		return err
+	}
+
+	resp, err := b.do("LOCK", b.lockAddress, d)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	switch resp.StatusCode {
+	case http.StatusOK:
+		return nil
+	case http.StatusLocked, http.StatusConflict:
+		// the server may return the details of the current lock
+		current := &StateLock{}
+		if json.NewDecoder(resp.Body).Decode(current) != nil || current.ID == "" {
+			current = nil
+		}
+
+		return &LockedError{Current: current}
+	}
+
+	return fmt.Errorf("unexpected status code %d locking state at %s", resp.StatusCode, b.lockAddress)
+}
+
+func (b *HTTPBackend) Unlock(l *StateLock) error {
+	d, err := json.Marshal(l)
+	if err != nil {
+		return err
+	}
+
+	resp, err := b.do("UNLOCK", b.unlockAddress, d)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	switch resp.StatusCode {
+	case http.StatusOK:
+		return nil
+	case http.StatusNotFound, http.StatusLocked, http.StatusConflict:
+		return fmt.Errorf("%w, status code %d unlocking state at %s", ErrLockNotHeld, resp.StatusCode, b.unlockAddress)
+	}
+
+	return fmt.Errorf("unexpected status code %d unlocking state at %s", resp.StatusCode, b.unlockAddress)
+}
+
+func (b *HTTPBackend) do(method, address string, body []byte) (*http.Response, error) {
+	req, err := http.NewRequest(method, address, bytes.NewReader(body))
+	if err != nil {
+		return nil, fmt.Errorf("unable to create request for %s: %s", address, err)
+	}
+
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+
+	if b.username != "" {
+		req.SetBasicAuth(b.username, b.password)
+	}
+
+	resp, err := b.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("unable to contact state backend %s: %s", address, err)
+	}
+
+	return resp, nil
+}
@@ -0,0 +1,190 @@
+package config
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/jumppad-labs/hclconfig"
+	"github.com/jumppad-labs/jumppad/pkg/utils"
+	"github.com/jumppad-labs/jumppad/testutils"
+	"github.com/stretchr/testify/require"
+)
+
+// stubStateServer is a minimal implementation of a http state server
+type stubStateServer struct {
+	state []byte
+	lock  []byte
+	m     sync.Mutex
+
+	// unlockStatus is returned for UNLOCK requests when it is set, the lock
+	// is removed as if it had been removed by another process
+	unlockStatus int
+}
+
+func (s *stubStateServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	s.m.Lock()
+	defer s.m.Unlock()
+
+	body, _ := io.ReadAll(r.Body)
+
+	switch r.Method {
+	case http.MethodGet:
+		if s.state == nil {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+
+		w.Write(s.state)
+	case http.MethodPost:
+		s.state = body
+	case http.MethodDelete:
+		s.state = nil
+	case "LOCK":
+		if s.lock != nil {
+			w.WriteHeader(http.StatusLocked)
+			w.Write(s.lock)
+			return
+		}
+
+		s.lock = body
+	case "UNLOCK":
+		s.lock = nil
+
+		if s.unlockStatus != 0 {
+			w.WriteHeader(s.unlockStatus)
+		}
+	default:
+		w.WriteHeader(http.StatusMethodNotAllowed)
+	}
+}
+
+func setupHTTPBackend(t *testing.T) *stubStateServer {
+	testutils.SetupState(t, "")
+
+	s := &stubStateServer{}
+	ts := httptest.NewServer(s)
+	t.Cleanup(ts.Close)
+
+	t.Setenv(EnvStateBackend, BackendHTTP)
+	t.Setenv(EnvStateHTTPAddress, ts.URL+"/state")
+
+	return s
+}
+
+func TestHTTPBackendSavesAndLoadsState(t *testing.T) {
+	s := setupHTTPBackend(t)
+
+	_, err := LoadState()
+	require.Error(t, err)
+
+	c := hclconfig.NewConfig()
+	err = SaveState(c)
+	require.NoError(t, err)
+	require.NotNil(t, s.state)
+
+	_, err = LoadState()
+	require.NoError(t, err)
+	require.NotEmpty(t, StateChecksum())
+
+	err = RemoveState()
+	require.NoError(t, err)
+	require.Nil(t, s.state)
+}
+
+func TestHTTPBackendLocksState(t *testing.T) {
+	s := setupHTTPBackend(t)
+
+	l, err := LockState(0)
+	require.NoError(t, err)
+	require.NotNil(t, s.lock)
+
+	_, err = LockState(300 * time.Millisecond)
+	require.ErrorIs(t, err, ErrStateLocked)
+
+	err = l.Unlock()
+	require.NoError(t, err)
+	require.Nil(t, s.lock)
+}
+
+func TestHTTPBackendReturnsCurrentLockWhenLocked(t *testing.T) {
+	s := setupHTTPBackend(t)
+
+	current := &StateLock{ID: "abc", PID: 1, Hostname: "ci-runner", Command: "jumppad up"}
+	s.lock, _ = json.Marshal(current)
+
+	b, err := NewStateBackend()
+	require.NoError(t, err)
+
+	err = b.Lock(&StateLock{ID: "123"})
+
+	le := &LockedError{}
+	require.ErrorAs(t, err, &le)
+	require.Equal(t, "abc", le.Current.ID)
+	require.Equal(t, "ci-runner", le.Current.Hostname)
+}
+
+func TestHTTPBackendLocksStateWhenStaleLockRemovedByAnotherProcess(t *testing.T) {
+	s := setupHTTPBackend(t)
+
+	// run a process that exits immediately so that its pid is no longer in use
+	c := exec.Command("go", "version")
+	require.NoError(t, c.Run())
+
+	s.lock, _ = json.Marshal(&StateLock{ID: "abc", PID: c.Process.Pid, Hostname: utils.GetHostname()})
+	s.unlockStatus = http.StatusNotFound
+
+	l, err := LockState(0)
+	require.NoError(t, err)
+
+	current := &StateLock{}
+	require.NoError(t, json.Unmarshal(s.lock, current))
+	require.Equal(t, l.ID, current.ID)
+}
+
+func TestNewStateBackendReadsSettingsFile(t *testing.T) {
+	testutils.SetupState(t, "")
+
+	err := writeSettings(`
+jumppad {
+  state_backend "http" {
+    address = "http://localhost:9999/state"
+    username = "nic"
+  }
+}
+`)
+	require.NoError(t, err)
+
+	b, err := NewStateBackend()
+	require.NoError(t, err)
+
+	hb, ok := b.(*HTTPBackend)
+	require.True(t, ok)
+	require.Equal(t, "http://localhost:9999/state", hb.address)
+	require.Equal(t, "http://localhost:9999/state", hb.lockAddress)
+	require.Equal(t, "nic", hb.username)
+}
+
+func TestNewStateBackendReturnsErrorForUnknownBackend(t *testing.T) {
+	testutils.SetupState(t, "")
+	t.Setenv(EnvStateBackend, "s3")
+
+	_, err := NewStateBackend()
+	require.Error(t, err)
+}
+
+func writeSettings(settings string) error {
+	err := os.MkdirAll(filepath.Dir(utils.SettingsPath()), os.ModePerm)
+	if err != nil {
+		return err
+	}
+
+	return os.WriteFile(utils.SettingsPath(), []byte(settings), 0644)
+}
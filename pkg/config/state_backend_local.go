@@ -0,0 +1,212 @@
+package config
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/jumppad-labs/jumppad/pkg/utils"
+)
+
+// LocalBackend stores the state in a file in the current workspace,
+// previous versions of the state are kept in the history folder
+type LocalBackend struct {
+	history int
+
+	// dir is the folder containing the state, when empty the state folder
+	// of the current workspace is used
+	dir string
+}
+
+// NewLocalBackend creates a backend that stores the state on the local
+// filesystem
+func NewLocalBackend() *LocalBackend {
+	return &LocalBackend{history: DefaultStateHistory}
+}
+
+// NewLocalBackendWithDir creates a backend that stores the state in the
+// given folder rather than the folder of the current workspace
+func NewLocalBackendWithDir(dir string) *LocalBackend {
+	return &LocalBackend{history: DefaultStateHistory, dir: dir}
+}
+
+func (b *LocalBackend) Load() ([]byte, error) {
+	d, err := os.ReadFile(b.statePath())
+	if os.IsNotExist(err) {
+		return nil, ErrStateNotFound
+	}
+
+	return d, err
+}
+
+func (b *LocalBackend) Save(d []byte) error {
+	err := os.MkdirAll(b.stateDir(), os.ModePerm)
+	if err != nil {
+		return fmt.Errorf("unable to create directory for state file '%s', error: %s", b.stateDir(), err)
+	}
+
+	// write the state to a temporary file and rename it so that the state
+	// is never partially written
+	f, err := os.CreateTemp(b.stateDir(), "state-*.tmp")
+	if err != nil {
+		return fmt.Errorf("unable to create temporary state file in '%s', error: %s", b.stateDir(), err)
+	}
+
+	err = f.Chmod(0644)
+	if err == nil {
+		_, err = f.Write(d)
+	}
+
+	if err == nil {
+		err = f.Sync()
+	}
+
+	if cerr := f.Close(); err == nil {
+		err = cerr
+	}
+
+	if err != nil {
+		os.Remove(f.Name())
+		return fmt.Errorf("unable to write state file '%s', error: %s", f.Name(), err)
+	}
+
+	err = os.Rename(f.Name(), b.statePath())
+	if err != nil {
+		os.Remove(f.Name())
+		return fmt.Errorf("unable to write state file '%s', error: %s", b.statePath(), err)
+	}
+
+	return b.appendHistory(d)
+}
+
+func (b *LocalBackend) Remove() error {
+	err := os.Remove(b.statePath())
+	if err != nil && !os.IsNotExist(err) {
+		return err
+	}
+
+	return nil
+}
+
+// Lock atomically creates the lock file in the state folder. The lock is
+// written to a temporary file that is linked into place so that the lock file
+// is never partially written, a lock file that can not be read was left by a
+// process that exited while writing it and is treated as stale.
+func (b *LocalBackend) Lock(l *StateLock) error {
+	err := os.MkdirAll(b.stateDir(), os.ModePerm)
+	if err != nil {
+		return fmt.Errorf("unable to create directory for state file '%s', error: %s", b.stateDir(), err)
+	}
+
+	d, err := json.Marshal(l)
+	if err != nil {
+		return err
+	}
+
+	tmp, err := os.CreateTemp(b.stateDir(), "lock-*.tmp")
+	if err != nil {
+		return fmt.Errorf("unable to create lock file '%s', error: %s", b.lockPath(), err)
+	}
+	defer os.Remove(tmp.Name())
+
+	_, err = tmp.Write(d)
+	tmp.Close()
+	if err != nil {
+		return fmt.Errorf("unable to create lock file '%s', error: %s", b.lockPath(), err)
+	}
+
+	for attempt := 0; ; attempt++ {
+		err = os.Link(tmp.Name(), b.lockPath())
+		if err == nil {
+			return nil
+		}
+
+		if !os.IsExist(err) {
+			return fmt.Errorf("unable to create lock file '%s', error: %s", b.lockPath(), err)
+		}
+
+		current, rerr := b.readLock()
+		if rerr == nil {
+			return &LockedError{Current: current}
+		}
+
+		if attempt > 0 {
+			return &LockedError{}
+		}
+
+		// the lock may have been released since the link failed
+		if _, serr := os.Stat(b.lockPath()); os.IsNotExist(serr) {
+			continue
+		}
+
+		err = os.Remove(b.lockPath())
+		if err != nil && !os.IsNotExist(err) {
+			return fmt.Errorf("lock file '%s' can not be read and is treated as stale, unable to remove it: %s", b.lockPath(), err)
+		}
+	}
+}
+
+// Unlock removes the lock file, a lock held by another process is
+// never removed
+func (b *LocalBackend) Unlock(l *StateLock) error {
+	current, err := b.readLock()
+	if errors.Is(err, os.ErrNotExist) {
+		return fmt.Errorf("%w, the lock file does not exist", ErrLockNotHeld)
+	}
+
+	if err != nil {
+		return err
+	}
+
+	if current.ID != l.ID {
+		return fmt.Errorf("%w, lock is held by another process, id: %s, pid: %d", ErrLockNotHeld, current.ID, current.PID)
+	}
+
+	err = os.Remove(b.lockPath())
+	if os.IsNotExist(err) {
+		return fmt.Errorf("%w, the lock file does not exist", ErrLockNotHeld)
+	}
+
+	if err != nil {
+		return fmt.Errorf("unable to remove lock file '%s', error: %s", b.lockPath(), err)
+	}
+
+	return nil
+}
+
+func (b *LocalBackend) readLock() (*StateLock, error) {
+	d, err := os.ReadFile(b.lockPath())
+	if err != nil {
+		return nil, fmt.Errorf("unable to read lock file: %w", err)
+	}
+
+	l := &StateLock{}
+	err = json.Unmarshal(d, l)
+	if err != nil {
+		return nil, fmt.Errorf("unable to unmarshal lock file: %s", err)
+	}
+
+	return l, nil
+}
+
+func (b *LocalBackend) stateDir() string {
+	if b.dir != "" {
+		return b.dir
+	}
+
+	return utils.StateDir()
+}
+
+func (b *LocalBackend) statePath() string {
+	return filepath.Join(b.stateDir(), "/state.json")
+}
+
+func (b *LocalBackend) lockPath() string {
+	return filepath.Join(b.stateDir(), "/state.lock")
+}
+
+func (b *LocalBackend) historyDir() string {
+	return filepath.Join(b.stateDir(), "/history")
+}
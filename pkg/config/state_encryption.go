@@ -0,0 +1,174 @@
+package config
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/hkdf"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+)
+
+const (
+	// EnvStateKey is the environment variable containing the key used to
+	// encrypt the state, when neither the key nor a key file are set the
+	// state is stored in plain text
+	EnvStateKey = "JUMPPAD_STATE_KEY"
+
+	// EnvStateKeyFile is the environment variable containing the path of a
+	// file that contains the key used to encrypt the state
+	EnvStateKeyFile = "JUMPPAD_STATE_KEY_FILE"
+)
+
+// stateEncryption identifies the algorithm used to encrypt the state
+const stateEncryption = "aes-256-gcm"
+
+// minStateKeyLength is the minimum length of a state key, keys should be
+// random values, i.e. the output of `openssl rand -base64 32`
+const minStateKeyLength = 16
+
+// ErrStateEncrypted is returned when the state is encrypted and no key
+// has been set
+var ErrStateEncrypted = fmt.Errorf("the state is encrypted, set %s or %s to the key used to encrypt it", EnvStateKey, EnvStateKeyFile)
+
+// encryptedState is the document that is saved in place of the state when
+// encryption is enabled, it is JSON so that backends and the history can
+// store it in the same way as a plain text state
+type encryptedState struct {
+	Encryption string `json:"encryption"`
+	Nonce      []byte `json:"nonce"`
+	Data       []byte `json:"data"`
+}
+
+// encryptState encrypts the state when a key has been set, otherwise the
+// state is returned unchanged.
+//
+// The nonce is derived from the state so that the same state always
+// encrypts to the same document, this allows the history and the state
+// checksum to detect when the state has not changed.
+func encryptState(d []byte) ([]byte, error) {
+	key, err := stateKey()
+	if err != nil || key == nil {
+		return d, err
+	}
+
+	aead, nonceKey, err := stateCipher(key)
+	if err != nil {
+		return nil, err
+	}
+
+	mac := hmac.New(sha256.New, nonceKey)
+	mac.Write(d)
+	nonce := mac.Sum(nil)[:aead.NonceSize()]
+
+	return json.Marshal(&encryptedState{
+		Encryption: stateEncryption,
+		Nonce:      nonce,
+		Data:       aead.Seal(nil, nonce, d, nil),
+	})
+}
+
+// decryptState decrypts an encrypted state, a plain text state is returned
+// unchanged so that existing state can be read after enabling encryption
+func decryptState(d []byte) ([]byte, error) {
+	es := &encryptedState{}
+	if err := json.Unmarshal(d, es); err != nil || es.Encryption == "" {
+		return d, nil
+	}
+
+	if es.Encryption != stateEncryption {
+		return nil, fmt.Errorf("the state is encrypted with an unsupported algorithm: %s", es.Encryption)
+	}
+
+	key, err := stateKey()
+	if err != nil {
+		return nil, err
+	}
+
+	if key == nil {
+		return nil, ErrStateEncrypted
+	}
+
+	aead, _, err := stateCipher(key)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(es.Nonce) != aead.NonceSize() {
+		return nil, fmt.Errorf("unable to decrypt state, the nonce is invalid")
+	}
+
+	s, err := aead.Open(nil, es.Nonce, es.Data, nil)
+	if err != nil {
+		return nil, fmt.Errorf("unable to decrypt state, the key does not match the key used to encrypt it")
+	}
+
+	return s, nil
+}
+
+// Encrypt encrypts data that may contain secrets from the state, such as a
+// saved plan, with the state key. When no key has been set the data is
+// returned unchanged.
+func Encrypt(d []byte) ([]byte, error) {
+	return encryptState(d)
+}
+
+// Decrypt decrypts data encrypted with Encrypt, plain text data is returned
+// unchanged
+func Decrypt(d []byte) ([]byte, error) {
+	return decryptState(d)
+}
+
+// stateKey returns the key set in the environment or nil when encryption
+// is not enabled
+func stateKey() ([]byte, error) {
+	key := os.Getenv(EnvStateKey)
+
+	if file := os.Getenv(EnvStateKeyFile); key == "" && file != "" {
+		d, err := os.ReadFile(file)
+		if err != nil {
+			return nil, fmt.Errorf("unable to read state key file: %s", err)
+		}
+
+		key = strings.TrimSpace(string(d))
+	}
+
+	if key == "" {
+		return nil, nil
+	}
+
+	if len(key) < minStateKeyLength {
+		return nil, fmt.Errorf("the state key must be at least %d characters", minStateKeyLength)
+	}
+
+	return []byte(key), nil
+}
+
+// stateCipher derives the encryption key and the key used to generate
+// nonces from the state key
+func stateCipher(key []byte) (cipher.AEAD, []byte, error) {
+	encKey, err := hkdf.Key(sha256.New, key, nil, "jumppad state encryption", 32)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	nonceKey, err := hkdf.Key(sha256.New, key, nil, "jumppad state nonce", 32)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	block, err := aes.NewCipher(encKey)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	aead, err := cipher.NewGCM(block)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return aead, nonceKey, nil
+}
@@ -0,0 +1,118 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/jumppad-labs/hclconfig"
+	"github.com/jumppad-labs/jumppad/pkg/utils"
+	"github.com/jumppad-labs/jumppad/testutils"
+	"github.com/stretchr/testify/require"
+)
+
+const testStateKey = "0123456789abcdef0123456789abcdef"
+
+func TestSaveStateWithKeyEncryptsState(t *testing.T) {
+	testutils.SetupState(t, "")
+	t.Setenv(EnvStateKey, testStateKey)
+
+	saveTestState(t, "secret")
+
+	d, err := os.ReadFile(utils.StatePath())
+	require.NoError(t, err)
+	require.Contains(t, string(d), stateEncryption)
+	require.NotContains(t, string(d), "variable.secret")
+
+	c, err := LoadState()
+	require.NoError(t, err)
+
+	_, err = c.FindResource("variable.secret")
+	require.NoError(t, err)
+}
+
+func TestLoadStateWithKeyFileDecryptsState(t *testing.T) {
+	testutils.SetupState(t, "")
+
+	file := filepath.Join(t.TempDir(), "state.key")
+	err := os.WriteFile(file, []byte(testStateKey+"\n"), 0600)
+	require.NoError(t, err)
+
+	t.Setenv(EnvStateKeyFile, file)
+	saveTestState(t, "secret")
+
+	t.Setenv(EnvStateKeyFile, "")
+	t.Setenv(EnvStateKey, testStateKey)
+
+	c, err := LoadState()
+	require.NoError(t, err)
+
+	_, err = c.FindResource("variable.secret")
+	require.NoError(t, err)
+}
+
+func TestLoadStateWithoutKeyReturnsError(t *testing.T) {
+	testutils.SetupState(t, "")
+	t.Setenv(EnvStateKey, testStateKey)
+
+	saveTestState(t, "secret")
+
+	t.Setenv(EnvStateKey, "")
+
+	_, err := LoadState()
+	require.ErrorIs(t, err, ErrStateEncrypted)
+}
+
+func TestLoadStateWithWrongKeyReturnsError(t *testing.T) {
+	testutils.SetupState(t, "")
+	t.Setenv(EnvStateKey, testStateKey)
+
+	saveTestState(t, "secret")
+
+	t.Setenv(EnvStateKey, "fedcba9876543210fedcba9876543210")
+
+	_, err := LoadState()
+	require.ErrorContains(t, err, "key does not match")
+}
+
+func TestLoadStateWithKeyReadsPlainTextState(t *testing.T) {
+	testutils.SetupState(t, "")
+
+	saveTestState(t, "one")
+
+	t.Setenv(EnvStateKey, testStateKey)
+
+	c, err := LoadState()
+	require.NoError(t, err)
+
+	_, err = c.FindResource("variable.one")
+	require.NoError(t, err)
+}
+
+func TestSaveStateWithShortKeyReturnsError(t *testing.T) {
+	testutils.SetupState(t, "")
+	t.Setenv(EnvStateKey, "short")
+
+	err := SaveState(hclconfig.NewConfig())
+	require.ErrorContains(t, err, "at least")
+}
+
+func TestEncryptedStateHistoryIsReadable(t *testing.T) {
+	testutils.SetupState(t, "")
+	t.Setenv(EnvStateKey, testStateKey)
+
+	saveTestState(t, "one")
+	saveTestState(t, "one")
+	saveTestState(t, "one", "two")
+
+	versions, err := StateHistory()
+	require.NoError(t, err)
+	require.Len(t, versions, 2)
+	require.Equal(t, 2, versions[0].ResourceCount())
+
+	_, c, err := LoadStateVersion(1)
+	require.NoError(t, err)
+
+	_, err = c.FindResource("variable.one")
+	require.NoError(t, err)
+}
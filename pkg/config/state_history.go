@@ -0,0 +1,260 @@
+package config
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/jumppad-labs/hclconfig"
+)
+
+// DefaultStateHistory is the number of previous states that are kept
+// when not set in the settings
+const DefaultStateHistory = 10
+
+// ErrHistoryNotSupported is returned when the configured backend does
+// not keep previous versions of the state
+var ErrHistoryNotSupported = errors.New("the state backend does not support history")
+
+// ErrStateVersionNotFound is returned when a version does not exist in
+// the history
+var ErrStateVersionNotFound = errors.New("state version not found")
+
+// Version is the version of jumppad that is recorded with each state
+// in the history
+var Version = "dev"
+
+// StateVersion is a previous version of the state
+type StateVersion struct {
+	// Serial is incremented every time the state changes
+	Serial int `json:"serial"`
+
+	// Created is the time the state was saved
+	Created time.Time `json:"created"`
+
+	// Version of jumppad that saved the state
+	Version string `json:"version"`
+
+	// State is the raw state
+	State json.RawMessage `json:"state"`
+}
+
+// HistoryBackend is implemented by backends that keep previous versions
+// of the state
+type HistoryBackend interface {
+	// History returns the stored versions of the state, newest first
+	History() ([]*StateVersion, error)
+
+	// LoadVersion returns the version of the state with the given serial
+	LoadVersion(serial int) (*StateVersion, error)
+}
+
+// StateHistory returns the previous versions of the state, newest first
+func StateHistory() ([]*StateVersion, error) {
+	hb, err := historyBackend()
+	if err != nil {
+		return nil, err
+	}
+
+	versions, err := hb.History()
+	if err != nil {
+		return nil, err
+	}
+
+	for _, sv := range versions {
+		sv.State, err = decryptState(sv.State)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return versions, nil
+}
+
+// LoadStateVersion returns the version of the state with the given serial
+// from the history
+func LoadStateVersion(serial int) (*StateVersion, *hclconfig.Config, error) {
+	hb, err := historyBackend()
+	if err != nil {
+		return nil, nil, err
+	}
+
+	sv, err := hb.LoadVersion(serial)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	sv.State, err = decryptState(sv.State)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	p := NewParser(nil, nil, nil)
+	c, err := p.UnmarshalJSON(sv.State)
+	if err != nil {
+		return nil, nil, fmt.Errorf("unable to unmarshal state version %d: %s", serial, err)
+	}
+
+	return sv, c, nil
+}
+
+func historyBackend() (HistoryBackend, error) {
+	b, err := NewStateBackend()
+	if err != nil {
+		return nil, err
+	}
+
+	hb, ok := b.(HistoryBackend)
+	if !ok {
+		return nil, ErrHistoryNotSupported
+	}
+
+	return hb, nil
+}
+
+func (b *LocalBackend) History() ([]*StateVersion, error) {
+	serials, err := b.historySerials()
+	if err != nil {
+		return nil, err
+	}
+
+	versions := []*StateVersion{}
+	for i := len(serials) - 1; i >= 0; i-- {
+		sv, err := b.LoadVersion(serials[i])
+		if err != nil {
+			return nil, err
+		}
+
+		versions = append(versions, sv)
+	}
+
+	return versions, nil
+}
+
+func (b *LocalBackend) LoadVersion(serial int) (*StateVersion, error) {
+	d, err := os.ReadFile(b.historyPath(serial))
+	if os.IsNotExist(err) {
+		return nil, fmt.Errorf("%w: %d", ErrStateVersionNotFound, serial)
+	}
+
+	if err != nil {
+		return nil, fmt.Errorf("unable to read state version %d: %s", serial, err)
+	}
+
+	sv := &StateVersion{}
+	err = json.Unmarshal(d, sv)
+	if err != nil {
+		return nil, fmt.Errorf("unable to unmarshal state version %d: %s", serial, err)
+	}
+
+	return sv, nil
+}
+
+// appendHistory adds the state to the history when it differs from the
+// latest version, versions older than the history limit are removed
+func (b *LocalBackend) appendHistory(d []byte) error {
+	if b.history < 1 {
+		return nil
+	}
+
+	err := os.MkdirAll(b.historyDir(), os.ModePerm)
+	if err != nil {
+		return fmt.Errorf("unable to create state history directory '%s', error: %s", b.historyDir(), err)
+	}
+
+	serials, err := b.historySerials()
+	if err != nil {
+		return err
+	}
+
+	serial := 1
+	if len(serials) > 0 {
+		last := serials[len(serials)-1]
+		serial = last + 1
+
+		// nothing has changed since the last version
+		if sv, err := b.LoadVersion(last); err == nil && bytes.Equal(compactJSON(sv.State), compactJSON(d)) {
+			return nil
+		}
+	}
+
+	hd, err := json.Marshal(&StateVersion{
+		Serial:  serial,
+		Created: time.Now(),
+		Version: Version,
+		State:   compactJSON(d),
+	})
+	if err != nil {
+		return fmt.Errorf("unable to serialize state version: %s", err)
+	}
+
+	err = os.WriteFile(b.historyPath(serial), hd, 0644)
+	if err != nil {
+		return fmt.Errorf("unable to write state version '%s', error: %s", b.historyPath(serial), err)
+	}
+
+	serials = append(serials, serial)
+	for len(serials) > b.history {
+		os.Remove(b.historyPath(serials[0]))
+		serials = serials[1:]
+	}
+
+	return nil
+}
+
+// historySerials returns the serials of the stored versions, oldest first
+func (b *LocalBackend) historySerials() ([]int, error) {
+	entries, err := os.ReadDir(b.historyDir())
+	if os.IsNotExist(err) {
+		return []int{}, nil
+	}
+
+	if err != nil {
+		return nil, fmt.Errorf("unable to read state history directory '%s', error: %s", b.historyDir(), err)
+	}
+
+	serials := []int{}
+	for _, e := range entries {
+		s, err := strconv.Atoi(strings.TrimSuffix(e.Name(), ".json"))
+		if err != nil || e.IsDir() {
+			continue
+		}
+
+		serials = append(serials, s)
+	}
+
+	sort.Ints(serials)
+
+	return serials, nil
+}
+
+func (b *LocalBackend) historyPath(serial int) string {
+	return filepath.Join(b.historyDir(), fmt.Sprintf("%d.json", serial))
+}
+
+func compactJSON(d []byte) []byte {
+	b := &bytes.Buffer{}
+	if json.Compact(b, d) != nil {
+		return d
+	}
+
+	return b.Bytes()
+}
+
+// ResourceCount returns the number of resources in the state version
+func (sv *StateVersion) ResourceCount() int {
+	s := struct {
+		Resources []json.RawMessage `json:"resources"`
+	}{}
+
+	json.Unmarshal(sv.State, &s)
+
+	return len(s.Resources)
+}
@@ -0,0 +1,104 @@
+package config
+
+import (
+	"testing"
+
+	"github.com/jumppad-labs/hclconfig"
+	"github.com/jumppad-labs/hclconfig/resources"
+	"github.com/jumppad-labs/hclconfig/types"
+	"github.com/jumppad-labs/jumppad/testutils"
+	"github.com/stretchr/testify/require"
+)
+
+func saveTestState(t *testing.T, names ...string) {
+	c := hclconfig.NewConfig()
+	for _, n := range names {
+		r := &resources.Variable{
+			ResourceBase: types.ResourceBase{
+				Meta: types.Meta{ID: "variable." + n, Name: n, Type: resources.TypeVariable},
+			},
+		}
+
+		err := c.AppendResource(r)
+		require.NoError(t, err)
+	}
+
+	err := SaveState(c)
+	require.NoError(t, err)
+}
+
+func TestSaveStateAddsVersionToHistory(t *testing.T) {
+	testutils.SetupState(t, "")
+	Version = "v1.2.3"
+	t.Cleanup(func() { Version = "dev" })
+
+	saveTestState(t, "one")
+	saveTestState(t, "one", "two")
+
+	versions, err := StateHistory()
+	require.NoError(t, err)
+	require.Len(t, versions, 2)
+
+	require.Equal(t, 2, versions[0].Serial)
+	require.Equal(t, "v1.2.3", versions[0].Version)
+	require.Equal(t, 2, versions[0].ResourceCount())
+	require.False(t, versions[0].Created.IsZero())
+
+	require.Equal(t, 1, versions[1].Serial)
+	require.Equal(t, 1, versions[1].ResourceCount())
+}
+
+func TestSaveStateDoesNotAddUnchangedStateToHistory(t *testing.T) {
+	testutils.SetupState(t, "")
+
+	saveTestState(t, "one")
+	saveTestState(t, "one")
+
+	versions, err := StateHistory()
+	require.NoError(t, err)
+	require.Len(t, versions, 1)
+}
+
+func TestSaveStatePrunesHistory(t *testing.T) {
+	testutils.SetupState(t, "")
+	t.Setenv(EnvStateHistory, "2")
+
+	saveTestState(t, "one")
+	saveTestState(t, "one", "two")
+	saveTestState(t, "one", "two", "three")
+
+	versions, err := StateHistory()
+	require.NoError(t, err)
+	require.Len(t, versions, 2)
+	require.Equal(t, 3, versions[0].Serial)
+	require.Equal(t, 2, versions[1].Serial)
+
+	_, _, err = LoadStateVersion(1)
+	require.ErrorIs(t, err, ErrStateVersionNotFound)
+}
+
+func TestLoadStateVersionReturnsConfig(t *testing.T) {
+	testutils.SetupState(t, "")
+
+	saveTestState(t, "one")
+	saveTestState(t, "one", "two")
+
+	sv, c, err := LoadStateVersion(1)
+	require.NoError(t, err)
+	require.Equal(t, 1, sv.Serial)
+
+	_, err = c.FindResource("variable.one")
+	require.NoError(t, err)
+
+	_, err = c.FindResource("variable.two")
+	require.Error(t, err)
+}
+
+func TestStateHistoryReturnsErrorForHTTPBackend(t *testing.T) {
+	testutils.SetupState(t, "")
+	t.Setenv(EnvStateBackend, BackendHTTP)
+	t.Setenv(EnvStateHTTPAddress, "http://localhost:1234/state")
+
+	_, err := StateHistory()
+	require.ErrorIs(t, err, ErrHistoryNotSupported)
+}
@@ -0,0 +1,110 @@
+package config
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/jumppad-labs/jumppad/pkg/utils"
+)
+
+// ErrStateLocked is returned when the state is locked by another process
+var ErrStateLocked = errors.New("state is locked by another process")
+
+// lockRetryInterval is the interval between attempts to acquire a lock
+var lockRetryInterval = 250 * time.Millisecond
+
+// StateLock is an advisory lock that prevents concurrent modification
+// of the state by multiple jumppad processes
+type StateLock struct {
+	// ID uniquely identifies the lock
+	ID string `json:"id"`
+
+	// PID of the process holding the lock
+	PID int `json:"pid"`
+
+	// Hostname of the machine running the process
+	Hostname string `json:"hostname"`
+
+	// Command is the command that acquired the lock
+	Command string `json:"command"`
+
+	// Created is the time the lock was acquired
+	Created time.Time `json:"created"`
+
+	backend StateBackend
+}
+
+// LockState acquires the lock on the state, if the state is locked by
+// another running process LockState retries until timeout expires. Locks
+// held by processes on this machine that are no longer running are removed.
+func LockState(timeout time.Duration) (*StateLock, error) {
+	b, err := NewStateBackend()
+	if err != nil {
+		return nil, err
+	}
+
+	id := make([]byte, 16)
+	rand.Read(id)
+
+	l := &StateLock{
+		ID:       hex.EncodeToString(id),
+		PID:      os.Getpid(),
+		Hostname: utils.GetHostname(),
+		Command:  strings.Join(os.Args, " "),
+		backend:  b,
+	}
+
+	deadline := time.Now().Add(timeout)
+
+	for {
+		l.Created = time.Now()
+
+		err := b.Lock(l)
+		if err == nil {
+			return l, nil
+		}
+
+		le := &LockedError{}
+		if !errors.As(err, &le) {
+			return nil, err
+		}
+
+		if le.Current != nil && isStale(le.Current) {
+			// the process holding the lock has exited, remove and try again.
+			// Another process may have found the same stale lock and removed
+			// it first, the lock is then free or held by that process.
+			err := b.Unlock(le.Current)
+			if err != nil && !errors.Is(err, ErrLockNotHeld) {
+				return nil, fmt.Errorf("unable to remove stale lock: %s", err)
+			}
+
+			continue
+		}
+
+		if !time.Now().Before(deadline) {
+			return nil, le
+		}
+
+		time.Sleep(lockRetryInterval)
+	}
+}
+
+// Unlock releases the lock on the state
+func (l *StateLock) Unlock() error {
+	return l.backend.Unlock(l)
+}
+
+// isStale returns true when the lock was created by a process on this
+// machine that is no longer running
+func isStale(l *StateLock) bool {
+	if l.Hostname != "" && l.Hostname != utils.GetHostname() {
+		return false
+	}
+
+	return !utils.ProcessRunning(l.PID)
+}
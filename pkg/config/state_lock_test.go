@@ -0,0 +1,158 @@
+package config
+
+import (
+	"encoding/json"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/jumppad-labs/hclconfig"
+	"github.com/jumppad-labs/jumppad/pkg/utils"
+	"github.com/jumppad-labs/jumppad/testutils"
+	"github.com/stretchr/testify/require"
+)
+
+func writeLock(t *testing.T, pid int) {
+	d, err := json.Marshal(&StateLock{PID: pid, Command: "jumppad up", Created: time.Now()})
+	require.NoError(t, err)
+
+	os.MkdirAll(utils.StateDir(), os.ModePerm)
+	err = os.WriteFile(utils.StateLockPath(), d, 0644)
+	require.NoError(t, err)
+}
+
+func TestLockStateCreatesLockFile(t *testing.T) {
+	testutils.SetupState(t, "")
+
+	l, err := LockState(0)
+	require.NoError(t, err)
+
+	current, err := NewLocalBackend().readLock()
+	require.NoError(t, err)
+	require.Equal(t, os.Getpid(), current.PID)
+
+	err = l.Unlock()
+	require.NoError(t, err)
+	require.NoFileExists(t, utils.StateLockPath())
+}
+
+func TestLockStateReturnsErrorWhenLocked(t *testing.T) {
+	testutils.SetupState(t, "")
+
+	l, err := LockState(0)
+	require.NoError(t, err)
+	defer l.Unlock()
+
+	_, err = LockState(300 * time.Millisecond)
+	require.ErrorIs(t, err, ErrStateLocked)
+}
+
+func TestLockStateWaitsForLockToBeReleased(t *testing.T) {
+	testutils.SetupState(t, "")
+
+	l, err := LockState(0)
+	require.NoError(t, err)
+
+	go func() {
+		time.Sleep(300 * time.Millisecond)
+		l.Unlock()
+	}()
+
+	l2, err := LockState(5 * time.Second)
+	require.NoError(t, err)
+	require.NoError(t, l2.Unlock())
+}
+
+func TestLockStateRemovesStaleLock(t *testing.T) {
+	testutils.SetupState(t, "")
+
+	// run a process that exits immediately so that its pid is no longer in use
+	c := exec.Command("go", "version")
+	require.NoError(t, c.Run())
+
+	writeLock(t, c.Process.Pid)
+
+	l, err := LockState(0)
+	require.NoError(t, err)
+	require.NoError(t, l.Unlock())
+}
+
+func TestLockStateRemovesUnreadableLock(t *testing.T) {
+	tt := map[string]string{
+		"empty":     "",
+		"truncated": `{"id":"abc","pid":12`,
+	}
+
+	for name, contents := range tt {
+		t.Run(name, func(t *testing.T) {
+			testutils.SetupState(t, "")
+
+			os.MkdirAll(utils.StateDir(), os.ModePerm)
+			err := os.WriteFile(utils.StateLockPath(), []byte(contents), 0644)
+			require.NoError(t, err)
+
+			l, err := LockState(0)
+			require.NoError(t, err)
+
+			current, err := NewLocalBackend().readLock()
+			require.NoError(t, err)
+			require.Equal(t, l.ID, current.ID)
+
+			require.NoError(t, l.Unlock())
+		})
+	}
+}
+
+func TestLockStateDoesNotLeaveTemporaryFiles(t *testing.T) {
+	testutils.SetupState(t, "")
+
+	l, err := LockState(0)
+	require.NoError(t, err)
+	defer l.Unlock()
+
+	_, err = LockState(0)
+	require.ErrorIs(t, err, ErrStateLocked)
+
+	files, err := filepath.Glob(filepath.Join(utils.StateDir(), "*.tmp"))
+	require.NoError(t, err)
+	require.Empty(t, files)
+}
+
+func TestUnlockDoesNotRemoveLockHeldByAnotherProcess(t *testing.T) {
+	testutils.SetupState(t, "")
+
+	l, err := LockState(0)
+	require.NoError(t, err)
+
+	writeLock(t, os.Getppid())
+
+	err = l.Unlock()
+	require.ErrorIs(t, err, ErrLockNotHeld)
+	require.FileExists(t, utils.StateLockPath())
+}
+
+func TestUnlockReturnsErrorWhenLockRemoved(t *testing.T) {
+	testutils.SetupState(t, "")
+
+	l, err := LockState(0)
+	require.NoError(t, err)
+
+	require.NoError(t, os.Remove(utils.StateLockPath()))
+
+	err = l.Unlock()
+	require.ErrorIs(t, err, ErrLockNotHeld)
+}
+
+func TestSaveStateDoesNotLeaveTemporaryFiles(t *testing.T) {
+	testutils.SetupState(t, "")
+
+	err := SaveState(hclconfig.NewConfig())
+	require.NoError(t, err)
+
+	files, err := filepath.Glob(filepath.Join(utils.StateDir(), "*.tmp"))
+	require.NoError(t, err)
+	require.Empty(t, files)
+	require.FileExists(t, utils.StatePath())
+}
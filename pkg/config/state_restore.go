@@ -0,0 +1,34 @@
+package config
+
+import (
+	"reflect"
+
+	"github.com/jumppad-labs/hclconfig"
+	"github.com/jumppad-labs/hclconfig/types"
+)
+
+// StateRestorer is implemented by resources with computed fields, when the
+// configuration is parsed the computed fields are restored from the state so
+// that they can be referenced by dependent resources
+type StateRestorer interface {
+	// RestoreState copies the computed fields from the version of the
+	// resource in the state, state is always the same type as the resource
+	RestoreState(state types.Resource)
+}
+
+// RestoreState copies the computed fields of the resource from the matching
+// resource in the state, nothing is restored when the resource is not in the
+// state or its type has changed
+func RestoreState(r types.Resource, state *hclconfig.Config) {
+	sr, ok := r.(StateRestorer)
+	if !ok || state == nil {
+		return
+	}
+
+	s, err := state.FindResource(r.Metadata().ID)
+	if err != nil || reflect.TypeOf(s) != reflect.TypeOf(r) {
+		return
+	}
+
+	sr.RestoreState(s)
+}
@@ -33,15 +33,26 @@ func RegisterResource(name string, r types.Resource, p sdk.Provider) {
 	}
 }
 
+// VariableEnvPrefix is the prefix of environment variables that set the
+// value of variables, i.e. JUMPPAD_VAR_version=1.0 sets var.version
+const VariableEnvPrefix = "JUMPPAD_VAR_"
+
 // setupHCLConfig configures the HCLConfig package and registers the custom types
 func NewParser(callback hclconfig.WalkCallback, variables map[string]string, variablesFiles []string) *hclconfig.Parser {
+	return NewParserWithEnvironment(utils.Environment{}, callback, variables, variablesFiles)
+}
+
+// NewParserWithEnvironment creates a parser that uses the jumppad home folder
+// and workspace of the given environment for the module cache and the
+// folders returned by the jumppad and data functions
+func NewParserWithEnvironment(env utils.Environment, callback hclconfig.WalkCallback, variables map[string]string, variablesFiles []string) *hclconfig.Parser {
 	cfg := hclconfig.DefaultOptions()
 
 	cfg.Callback = callback
-	cfg.VariableEnvPrefix = "JUMPPAD_VAR_"
+	cfg.VariableEnvPrefix = VariableEnvPrefix
 	cfg.Variables = variables
 	cfg.VariablesFiles = variablesFiles
-	cfg.ModuleCache = path.Join(utils.JumppadHome(), "modules")
+	cfg.ModuleCache = path.Join(env.JumppadHome(), "modules")
 
 	p := hclconfig.NewParser(cfg)
 
@@ -50,14 +61,17 @@ func NewParser(callback hclconfig.WalkCallback, variables map[string]string, var
 		p.RegisterType(k, v)
 	}
 
+	hf := homeFunctions{env: env}
+
 	// Register the custom functions
-	p.RegisterFunction("jumppad", customHCLFuncJumppad)
+	p.RegisterFunction("jumppad", hf.customHCLFuncJumppad)
 	p.RegisterFunction("docker_ip", customHCLFuncDockerIP)
 	p.RegisterFunction("docker_host", customHCLFuncDockerHost)
-	p.RegisterFunction("data", customHCLFuncDataFolder)
-	p.RegisterFunction("data_with_permissions", customHCLFuncDataFolderWithPermissions)
+	p.RegisterFunction("data", hf.customHCLFuncDataFolder)
+	p.RegisterFunction("data_with_permissions", hf.customHCLFuncDataFolderWithPermissions)
 	p.RegisterFunction("system", customHCLFuncSystem)
 	p.RegisterFunction("exists", customHCLFuncExists)
+	p.RegisterFunction(SensitiveFunction, customHCLFuncSensitive)
 
 	return p
 }
@@ -2,18 +2,32 @@ package config
 
 import (
 	"fmt"
-	"os"
 
 	"github.com/jumppad-labs/hclconfig"
 	"github.com/jumppad-labs/jumppad/pkg/utils"
 )
 
 func LoadState() (*hclconfig.Config, error) {
-	d, err := os.ReadFile(utils.StatePath())
+	b, err := NewStateBackend()
+	if err != nil {
+		return hclconfig.NewConfig(), err
+	}
+
+	return LoadStateFrom(b)
+}
+
+// LoadStateFrom reads the state from the given backend
+func LoadStateFrom(b StateBackend) (*hclconfig.Config, error) {
+	d, err := b.Load()
 	if err != nil {
 		return hclconfig.NewConfig(), fmt.Errorf("unable to read state file: %s", err)
 	}
 
+	d, err = decryptState(d)
+	if err != nil {
+		return hclconfig.NewConfig(), err
+	}
+
 	p := NewParser(nil, nil, nil)
 	c, err := p.UnmarshalJSON(d)
 	if err != nil {
@@ -24,21 +38,58 @@ func LoadState() (*hclconfig.Config, error) {
 }
 
 func SaveState(c *hclconfig.Config) error {
-	// save the state regardless of error
+	b, err := NewStateBackend()
+	if err != nil {
+		return err
+	}
+
+	return SaveStateTo(b, c)
+}
+
+// SaveStateTo writes the state to the given backend
+func SaveStateTo(b StateBackend, c *hclconfig.Config) error {
 	d, err := c.ToJSON()
 	if err != nil {
 		return fmt.Errorf("unable to serialize config to JSON: %s", err)
 	}
 
-	err = os.MkdirAll(utils.StateDir(), os.ModePerm)
+	d, err = encryptState(d)
 	if err != nil {
-		return fmt.Errorf("unable to create directory for state file '%s', error: %s", utils.StateDir(), err)
+		return fmt.Errorf("unable to encrypt state: %s", err)
 	}
 
-	err = os.WriteFile(utils.StatePath(), d, os.ModePerm)
+	return b.Save(d)
+}
+
+// RemoveState deletes the state
+func RemoveState() error {
+	b, err := NewStateBackend()
+	if err != nil {
+		return err
+	}
+
+	return b.Remove()
+}
+
+// StateChecksum returns the checksum of the current state or an empty
+// string when no state exists
+func StateChecksum() string {
+	b, err := NewStateBackend()
+	if err != nil {
+		return ""
+	}
+
+	return StateChecksumFrom(b)
+}
+
+// StateChecksumFrom returns the checksum of the state in the given backend
+// or an empty string when no state exists
+func StateChecksumFrom(b StateBackend) string {
+	d, err := b.Load()
 	if err != nil {
-		return fmt.Errorf("unable to write state file '%s', error: %s", utils.StatePath(), err)
+		return ""
 	}
 
-	return nil
+	cs, _ := utils.HashString(string(d))
+	return cs
 }
@@ -0,0 +1,176 @@
+package jumppad
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/jumppad-labs/hclconfig"
+	"github.com/jumppad-labs/hclconfig/resources"
+	"github.com/jumppad-labs/hclconfig/types"
+	"github.com/jumppad-labs/jumppad/pkg/jumppad/constants"
+)
+
+// Cascade explains why a resource will be recreated when a resource that
+// it references is recreated
+type Cascade struct {
+	// ID is the resource that will be recreated
+	ID string `json:"id"`
+
+	// Cause is the recreated resource that ID references, this can itself
+	// be recreated because of a cascade
+	Cause string `json:"cause"`
+}
+
+// PendingCascades returns the created resources in the state that will be
+// recreated by the next apply because they reference a tainted, drifted or
+// failed resource, the result is sorted by ID
+func PendingCascades(state *hclconfig.Config) []Cascade {
+	cascaded := cascadeReplacements(state, recreatedInState(state), func(r types.Resource) bool {
+		return r.Metadata().Properties[constants.PropertyStatus] == constants.StatusCreated
+	})
+
+	cascades := []Cascade{}
+	for id, cause := range cascaded {
+		cascades = append(cascades, Cascade{ID: id, Cause: cause})
+	}
+
+	sort.Slice(cascades, func(i, j int) bool { return cascades[i].ID < cascades[j].ID })
+
+	return cascades
+}
+
+// taintCascades marks the created resources in the state that reference a
+// resource that will be recreated as tainted, they are destroyed and created
+// again when they are processed
+func (e *EngineImpl) taintCascades(parsed, state *hclconfig.Config) {
+	recreated := map[string]bool{}
+	for id := range recreatedInState(state) {
+		if e.targeted == nil || e.targeted[id] {
+			recreated[id] = true
+		}
+	}
+
+	cascaded := cascadeReplacements(parsed, recreated, func(r types.Resource) bool {
+		sr, err := state.FindResource(r.Metadata().ID)
+		return err == nil && e.isTargeted(r) &&
+			sr.Metadata().Properties[constants.PropertyStatus] == constants.StatusCreated
+	})
+
+	for id, cause := range cascaded {
+		sr, _ := state.FindResource(id)
+		sr.Metadata().Properties[constants.PropertyStatus] = constants.StatusTainted
+
+		e.log.Info("Recreating resource, it references a recreated resource", "ref", id, "cause", cause)
+	}
+}
+
+// recreatedInState returns the IDs of the resources in the state that are
+// destroyed and created again by the next apply
+func recreatedInState(state *hclconfig.Config) map[string]bool {
+	recreated := map[string]bool{}
+	if state == nil {
+		return recreated
+	}
+
+	for _, r := range state.Resources {
+		if r.GetDisabled() {
+			continue
+		}
+
+		switch r.Metadata().Properties[constants.PropertyStatus] {
+		case constants.StatusTainted, constants.StatusDrifted, constants.StatusFailed:
+			recreated[r.Metadata().ID] = true
+		}
+	}
+
+	return recreated
+}
+
+// cascadeReplacements returns the resources in c that reference one of the
+// recreated resources, directly or through another cascaded resource, keyed
+// by ID with the resource they reference. Recreating a resource changes the
+// attributes that are computed by the provider, such as addresses and
+// kubeconfig, so anything that referenced them is stale. Resources that only
+// list a recreated resource in depends_on are not cascaded.
+//
+// Only resources for which replaceable returns true are cascaded, outputs
+// and locals pass the cascade on to the resources that reference them but
+// are not returned.
+func cascadeReplacements(c *hclconfig.Config, recreated map[string]bool, replaceable func(r types.Resource) bool) map[string]string {
+	cascaded := map[string]string{}
+	if c == nil || len(recreated) == 0 {
+		return cascaded
+	}
+
+	// outputs and locals that reference a recreated resource
+	passed := map[string]string{}
+
+	for added := true; added; {
+		added = false
+
+		for _, r := range c.Resources {
+			id := r.Metadata().ID
+			if recreated[id] || cascaded[id] != "" || passed[id] != "" || r.GetDisabled() {
+				continue
+			}
+
+			cause := referencedCause(r, func(ref string) bool {
+				return recreated[ref] || cascaded[ref] != "" || passed[ref] != ""
+			})
+
+			if cause == "" {
+				continue
+			}
+
+			switch r.Metadata().Type {
+			case resources.TypeOutput, resources.TypeLocal:
+				passed[id] = cause
+				added = true
+			case resources.TypeModule, resources.TypeVariable:
+				// modules and variables do not reference computed values
+			default:
+				if replaceable(r) {
+					cascaded[id] = cause
+					added = true
+				}
+			}
+		}
+	}
+
+	// report the recreated resource rather than the output or local that
+	// passed the cascade on
+	for id, cause := range cascaded {
+		for passed[cause] != "" {
+			cause = passed[cause]
+		}
+
+		cascaded[id] = cause
+	}
+
+	return cascaded
+}
+
+// referencedCause returns the first resource referenced by r for which
+// matches returns true, or an empty string
+func referencedCause(r types.Resource, matches func(ref string) bool) string {
+	links := append([]string{}, r.Metadata().Links...)
+	sort.Strings(links)
+
+	for _, l := range links {
+		fqrn, err := resources.ParseFQRN(l)
+		if err != nil {
+			continue
+		}
+
+		ref := fqrn.AppendParentModule(r.Metadata().Module).StringWithoutAttribute()
+		if matches(ref) {
+			return ref
+		}
+	}
+
+	return ""
+}
+
+func cascadeReason(cause string) string {
+	return fmt.Sprintf("references %s which will be recreated", cause)
+}
@@ -0,0 +1,152 @@
+package jumppad
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/jumppad-labs/jumppad/pkg/config"
+	"github.com/jumppad-labs/jumppad/pkg/config/mocks"
+	"github.com/jumppad-labs/jumppad/pkg/jumppad/constants"
+	"github.com/jumppad-labs/jumppad/testutils"
+	"github.com/stretchr/testify/require"
+)
+
+const cascadeConfig = `
+resource "random_number" "base" {
+  minimum = 1
+  maximum = 10
+}
+
+resource "random_number" "ordered" {
+  depends_on = ["resource.random_number.base"]
+
+  minimum = 1
+  maximum = 10
+}
+
+resource "random_number" "direct" {
+  minimum = 1
+  maximum = resource.random_number.base.value
+}
+
+local "base" {
+  value = resource.random_number.base.value
+}
+
+resource "random_number" "local" {
+  minimum = 1
+  maximum = local.base
+}
+
+resource "random_number" "chained" {
+  minimum = 1
+  maximum = resource.random_number.direct.value
+}
+`
+
+func setupCascade(t *testing.T) (*EngineImpl, *mocks.Providers, string) {
+	path := filepath.Join(t.TempDir(), "main.hcl")
+	err := os.WriteFile(path, []byte(cascadeConfig), 0644)
+	require.NoError(t, err)
+
+	e, mp := setupTests(t, nil)
+
+	_, err = e.Apply(context.Background(), path)
+	require.NoError(t, err)
+
+	return e, mp, path
+}
+
+func taintInState(t *testing.T, id string) {
+	sf := testLoadState(t)
+
+	r, err := sf.FindResource(id)
+	require.NoError(t, err)
+
+	r.Metadata().Properties[constants.PropertyStatus] = constants.StatusTainted
+
+	err = config.SaveState(sf)
+	require.NoError(t, err)
+}
+
+func TestPlanRecreatesResourcesReferencingRecreatedResource(t *testing.T) {
+	e, _, path := setupCascade(t)
+	taintInState(t, "resource.random_number.base")
+
+	p, err := e.Plan(path, nil, "")
+	require.NoError(t, err)
+
+	c := findChange(t, p, "resource.random_number.direct")
+	require.Equal(t, ActionRecreate, c.Action)
+	require.Contains(t, c.Reasons, "references resource.random_number.base which will be recreated")
+
+	// locals pass the cascade on to the resources that reference them
+	c = findChange(t, p, "resource.random_number.local")
+	require.Equal(t, ActionRecreate, c.Action)
+	require.Contains(t, c.Reasons, "references resource.random_number.base which will be recreated")
+
+	c = findChange(t, p, "resource.random_number.chained")
+	require.Equal(t, ActionRecreate, c.Action)
+	require.Contains(t, c.Reasons, "references resource.random_number.direct which will be recreated")
+
+	// depends_on does not reference any computed attributes
+	c = findChange(t, p, "resource.random_number.ordered")
+	require.Equal(t, ActionNone, c.Action)
+}
+
+func TestApplyRecreatesResourcesReferencingRecreatedResource(t *testing.T) {
+	e, mp, path := setupCascade(t)
+	taintInState(t, "resource.random_number.base")
+
+	_, err := e.Apply(context.Background(), path)
+	require.NoError(t, err)
+
+	destroyed := map[string]int{}
+	for i, p := range mp.Providers {
+		destroyed[getMetaFromMock(mp, i).ID] += len(testutils.GetCalls(&p.Mock, "Destroy"))
+	}
+
+	require.Equal(t, 1, destroyed["resource.random_number.base"])
+	require.Equal(t, 1, destroyed["resource.random_number.direct"])
+	require.Equal(t, 1, destroyed["resource.random_number.local"])
+	require.Equal(t, 1, destroyed["resource.random_number.chained"])
+	require.Equal(t, 0, destroyed["resource.random_number.ordered"])
+
+	sf := testLoadState(t)
+	r, err := sf.FindResource("resource.random_number.direct")
+	require.NoError(t, err)
+	require.Equal(t, constants.StatusCreated, r.Metadata().Properties[constants.PropertyStatus])
+}
+
+func TestApplyWithTargetsDoesNotCascadeToUntargetedResources(t *testing.T) {
+	e, mp, path := setupCascade(t)
+	taintInState(t, "resource.random_number.base")
+
+	e.SetTargets([]string{"resource.random_number.direct"})
+
+	_, err := e.Apply(context.Background(), path)
+	require.NoError(t, err)
+
+	destroyed := map[string]int{}
+	for i, p := range mp.Providers {
+		destroyed[getMetaFromMock(mp, i).ID] += len(testutils.GetCalls(&p.Mock, "Destroy"))
+	}
+
+	require.Equal(t, 1, destroyed["resource.random_number.direct"])
+	require.Equal(t, 0, destroyed["resource.random_number.chained"])
+}
+
+func TestPendingCascadesReturnsResourcesReferencingTaintedResource(t *testing.T) {
+	_, _, _ = setupCascade(t)
+	taintInState(t, "resource.random_number.base")
+
+	cascades := PendingCascades(testLoadState(t))
+
+	require.Equal(t, []Cascade{
+		{ID: "resource.random_number.chained", Cause: "resource.random_number.direct"},
+		{ID: "resource.random_number.direct", Cause: "resource.random_number.base"},
+		{ID: "resource.random_number.local", Cause: "resource.random_number.base"},
+	}, cascades)
+}
@@ -3,6 +3,10 @@ package constants
 // PropertyStatus is the key for the Metadata property that contains the status
 const PropertyStatus = "status"
 
+// PropertySensitive is the key for the Metadata property that is set on
+// variables and outputs that contain sensitive values
+const PropertySensitive = "sensitive"
+
 const (
 	// StatusCreated is set once the resource has been successfully created
 	StatusCreated = "created"
@@ -17,4 +21,13 @@ const (
 	// StatusDisabled indicates that the resources has been disabled and no
 	// resources have been created
 	StatusDisabled = "disabled"
+
+	// StatusDrifted indicates that the resource was created but the running
+	// resource no longer matches the state, drifted resources are re-created
+	// on the next Apply
+	StatusDrifted = "drifted"
+
+	// StatusStopped indicates that the containers for the resource have been
+	// stopped, stopped resources are started by start or the next Apply
+	StatusStopped = "stopped"
 )
@@ -0,0 +1,153 @@
+package jumppad
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	"github.com/jumppad-labs/hclconfig/types"
+	"github.com/jumppad-labs/jumppad/pkg/config"
+	"github.com/jumppad-labs/jumppad/pkg/jumppad/constants"
+	"github.com/jumppad-labs/jumppad/pkg/jumppad/events"
+	"github.com/jumppad-labs/jumppad/pkg/jumppad/tracing"
+	"go.opentelemetry.io/otel/attribute"
+)
+
+// ResourceDrift describes the differences between a resource in the state
+// and the running resource
+type ResourceDrift struct {
+	ID      string   `json:"id"`
+	Type    string   `json:"type"`
+	Reasons []string `json:"reasons"`
+
+	// Repaired is true when the resource was re-created to fix the drift
+	Repaired bool `json:"repaired,omitempty"`
+}
+
+// Drift asks the provider of each created resource in the state to compare
+// the resource with the running resources. Resources that have drifted are
+// marked with the drifted status so that they are re-created by the next
+// Apply, when repair is true the drifted resources are re-created
+// immediately.
+func (e *EngineImpl) Drift(ctx context.Context, repair bool) ([]*ResourceDrift, error) {
+	ctx, span := tracing.StartRoot(ctx, e.tracer, "jumppad.drift")
+
+	drifts, err := e.drift(ctx, repair)
+	tracing.End(span, err)
+
+	return drifts, err
+}
+
+func (e *EngineImpl) drift(ctx context.Context, repair bool) ([]*ResourceDrift, error) {
+	e.log.Info("Checking resources for drift")
+	e.ctx = events.NewContext(ctx, e.events)
+
+	// nothing has been created so there is nothing to check
+	c, err := e.loadState()
+	if err != nil {
+		e.log.Debug("Unable to load state", "error", err)
+		return []*ResourceDrift{}, nil
+	}
+
+	e.config = c
+	e.redactor.Add(config.SensitiveConfigValues(c)...)
+
+	drifts := []*ResourceDrift{}
+
+	for _, r := range c.Resources {
+		if r.GetDisabled() {
+			continue
+		}
+
+		status := r.Metadata().Properties[constants.PropertyStatus]
+		if status != constants.StatusCreated && status != constants.StatusDrifted {
+			continue
+		}
+
+		reasons, err := e.detectDrift(r)
+		if err != nil {
+			return nil, err
+		}
+
+		if len(reasons) == 0 {
+			r.Metadata().Properties[constants.PropertyStatus] = constants.StatusCreated
+			continue
+		}
+
+		e.log.Debug("Resource has drifted", "id", r.Metadata().ID, "reasons", reasons)
+
+		r.Metadata().Properties[constants.PropertyStatus] = constants.StatusDrifted
+		drifts = append(drifts, &ResourceDrift{
+			ID:      r.Metadata().ID,
+			Type:    r.Metadata().Type,
+			Reasons: reasons,
+		})
+	}
+
+	sort.Slice(drifts, func(i, j int) bool {
+		return drifts[i].ID < drifts[j].ID
+	})
+
+	var repairErr error
+	if repair && len(drifts) > 0 {
+		repairErr = e.repairDrift(drifts)
+	}
+
+	err = e.saveState(e.config)
+	if err != nil {
+		return nil, fmt.Errorf("unable to save state: %s", err)
+	}
+
+	return drifts, repairErr
+}
+
+func (e *EngineImpl) detectDrift(r types.Resource) ([]string, error) {
+	p := e.providers.GetProvider(r)
+	if p == nil {
+		return nil, fmt.Errorf("unable to create provider for resource Name: %s, Type: %s", r.Metadata().Name, r.Metadata().Type)
+	}
+
+	dd, ok := p.(config.DriftDetector)
+	if !ok {
+		return nil, nil
+	}
+
+	ctx, span := tracing.Start(e.ctx, "provider.drift",
+		attribute.String(tracing.AttributeResourceID, r.Metadata().ID),
+		attribute.String(tracing.AttributeResourceType, r.Metadata().Type),
+	)
+
+	reasons, err := dd.Drifted(ctx)
+	tracing.End(span, err)
+	if err != nil {
+		return nil, fmt.Errorf("unable to check resource %s for drift: %s", r.Metadata().ID, err)
+	}
+
+	return reasons, nil
+}
+
+// repairDrift re-creates the drifted resources in dependency order
+func (e *EngineImpl) repairDrift(drifts []*ResourceDrift) error {
+	drifted := map[string]*ResourceDrift{}
+	for _, d := range drifts {
+		drifted[d.ID] = d
+	}
+
+	return e.config.Walk(func(r types.Resource) error {
+		d, ok := drifted[r.Metadata().ID]
+		if !ok {
+			return nil
+		}
+
+		e.log.Info("Repairing drifted resource", "id", r.Metadata().ID)
+
+		err := e.createResource(r)
+		if err != nil {
+			return err
+		}
+
+		d.Repaired = r.Metadata().Properties[constants.PropertyStatus] == constants.StatusCreated
+
+		return nil
+	}, false)
+}
@@ -0,0 +1,95 @@
+package jumppad
+
+import (
+	"context"
+	"testing"
+
+	"github.com/jumppad-labs/jumppad/pkg/jumppad/constants"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDriftMarksDriftedResources(t *testing.T) {
+	e, mp := setupTestsWithState(t, nil, existingState)
+	mp.Drift = map[string][]string{"container": {"container does not exist"}}
+
+	drifts, err := e.Drift(context.Background(), false)
+	require.NoError(t, err)
+	require.Len(t, drifts, 1)
+	require.Equal(t, "resource.container.container", drifts[0].ID)
+	require.Equal(t, []string{"container does not exist"}, drifts[0].Reasons)
+	require.False(t, drifts[0].Repaired)
+
+	testAssertMethodCalled(t, mp, "Create", 0)
+
+	sf := testLoadState(t)
+	r, err := sf.FindResource("resource.container.container")
+	require.NoError(t, err)
+	require.Equal(t, constants.StatusDrifted, r.Metadata().Properties[constants.PropertyStatus])
+
+	r, err = sf.FindResource("resource.network.cloud")
+	require.NoError(t, err)
+	require.Equal(t, constants.StatusCreated, r.Metadata().Properties[constants.PropertyStatus])
+}
+
+func TestDriftRepairRecreatesDriftedResources(t *testing.T) {
+	e, mp := setupTestsWithState(t, nil, existingState)
+	mp.Drift = map[string][]string{"container": {"container does not exist"}}
+
+	drifts, err := e.Drift(context.Background(), true)
+	require.NoError(t, err)
+	require.Len(t, drifts, 1)
+	require.True(t, drifts[0].Repaired)
+
+	testAssertMethodCalled(t, mp, "Destroy", 1)
+	testAssertMethodCalled(t, mp, "Create", 1)
+
+	sf := testLoadState(t)
+	r, err := sf.FindResource("resource.container.container")
+	require.NoError(t, err)
+	require.Equal(t, constants.StatusCreated, r.Metadata().Properties[constants.PropertyStatus])
+}
+
+func TestDriftClearsDriftWhenResourceMatches(t *testing.T) {
+	e, _ := setupTestsWithState(t, nil, driftedState)
+
+	drifts, err := e.Drift(context.Background(), false)
+	require.NoError(t, err)
+	require.Empty(t, drifts)
+
+	sf := testLoadState(t)
+	r, err := sf.FindResource("resource.network.onprem")
+	require.NoError(t, err)
+	require.Equal(t, constants.StatusCreated, r.Metadata().Properties[constants.PropertyStatus])
+}
+
+func TestApplyRecreatesDriftedResources(t *testing.T) {
+	e, mp := setupTestsWithState(t, nil, driftedState)
+
+	_, err := e.Apply(context.Background(), "../../examples/single_file")
+	require.NoError(t, err)
+
+	// the drifted network is destroyed and re-created
+	testAssertMethodCalled(t, mp, "Destroy", 1)
+
+	sf := testLoadState(t)
+	r, err := sf.FindResource("resource.network.onprem")
+	require.NoError(t, err)
+	require.Equal(t, constants.StatusCreated, r.Metadata().Properties[constants.PropertyStatus])
+}
+
+var driftedState = `
+{
+  "resources": [
+  {
+      "meta": {
+        "name": "onprem",
+        "properties": {
+          "status": "drifted"
+        },
+        "type": "network"
+      },
+      "subnet": "10.6.0.0/16"
+  }
+  ]
+}
+`
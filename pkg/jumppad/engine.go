@@ -3,23 +3,30 @@ package jumppad
 import (
 
 	// "fmt"
+	"sort"
 
 	"context"
 	"fmt"
-	"log"
-	"os"
 	"path/filepath"
+	"sync"
+	"time"
 
 	"github.com/jumppad-labs/hclconfig"
 	hclerrors "github.com/jumppad-labs/hclconfig/errors"
 	"github.com/jumppad-labs/hclconfig/resources"
 	"github.com/jumppad-labs/hclconfig/types"
+	"github.com/jumppad-labs/jumppad/pkg/clients/container"
 	"github.com/jumppad-labs/jumppad/pkg/clients/logger"
 	"github.com/jumppad-labs/jumppad/pkg/config"
 	"github.com/jumppad-labs/jumppad/pkg/config/resources/cache"
+	"github.com/jumppad-labs/jumppad/pkg/config/resources/lifecycle"
 	"github.com/jumppad-labs/jumppad/pkg/config/resources/network"
 	"github.com/jumppad-labs/jumppad/pkg/jumppad/constants"
+	"github.com/jumppad-labs/jumppad/pkg/jumppad/events"
+	"github.com/jumppad-labs/jumppad/pkg/jumppad/tracing"
 	"github.com/jumppad-labs/jumppad/pkg/utils"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
 )
 
 // Clients contains clients which are responsible for creating and destroying resources
@@ -39,6 +46,67 @@ type Engine interface {
 	Destroy(ctx context.Context, force bool) error
 	Config() *hclconfig.Config
 	Diff(path string, variables map[string]string, variablesFile string) (new []types.Resource, changed []types.Resource, removed []types.Resource, cfg *hclconfig.Config, err error)
+
+	// Plan compares the configuration with the state and returns the
+	// changes that applying the configuration would make
+	Plan(path string, variables map[string]string, variablesFile string) (*Plan, error)
+
+	// ApplyPlan applies a plan created by Plan, the plan is rejected when
+	// the state or configuration has changed since it was created
+	ApplyPlan(ctx context.Context, plan *Plan) (*hclconfig.Config, error)
+
+	// Rollback reconciles the running resources with the given state,
+	// resources that are not in the state or have changed are destroyed and
+	// resources missing from the current state are created
+	Rollback(ctx context.Context, target *hclconfig.Config) (*hclconfig.Config, error)
+
+	// SetParallelism sets the maximum number of resources that are created
+	// or destroyed at the same time
+	SetParallelism(n int)
+
+	// Drift compares the resources in the state with the running resources,
+	// drifted resources are marked so that they are re-created by the next
+	// Apply or are re-created immediately when repair is true
+	Drift(ctx context.Context, repair bool) ([]*ResourceDrift, error)
+
+	// Graph returns the dependency graph of the resources in the
+	// configuration at the given path
+	Graph(path string, variables map[string]string, variablesFile string) (*Graph, error)
+
+	// Import adopts an existing object such as a Docker container as the
+	// resource with the given id, the resource is added to the state so that
+	// it is managed by the next Apply
+	Import(ctx context.Context, path string, variables map[string]string, variablesFile string, id string, objectID string) (types.Resource, error)
+
+	// SetTargets restricts Apply, Plan and Destroy to the given resources
+	// and the resources related to them
+	SetTargets(targets []string)
+
+	// SetEventHandler sets the handler that receives the events emitted
+	// while resources are created and destroyed
+	SetEventHandler(h events.Handler)
+
+	// SetAtomic enables atomic applies, when an apply fails or is cancelled
+	// the changes it made are rolled back to the previous state
+	SetAtomic(atomic bool)
+
+	// SetTracerProvider sets the provider used to record spans for
+	// operations, setting nil disables tracing
+	SetTracerProvider(tp trace.TracerProvider)
+
+	// Stop stops the containers of the resources in the state without
+	// destroying them
+	Stop(ctx context.Context) error
+
+	// Start starts the containers of the stopped resources in the state
+	Start(ctx context.Context) error
+
+	// Snapshot saves the containers of the resources in the state to a
+	// snapshot with the given name
+	Snapshot(ctx context.Context, name string) (*container.Snapshot, error)
+
+	// Restore creates the resources in the snapshot with the given name
+	Restore(ctx context.Context, name string) (*hclconfig.Config, error)
 }
 
 // EngineImpl is responsible for creating and destroying resources
@@ -48,25 +116,157 @@ type EngineImpl struct {
 	config    *hclconfig.Config
 	ctx       context.Context
 	force     bool
+
+	parallelism int
+	scheduler   *scheduler
+
+	// targets are the resources that operations are restricted to, targeted
+	// contains the IDs of the resources processed by the current operation
+	targets  []string
+	targeted map[string]bool
+
+	// events receives the lifecycle events for resources, when nil no
+	// events are emitted
+	events events.Handler
+
+	// tracer records spans for operations, when nil spans are only recorded
+	// when the context passed to an operation contains a span
+	tracer trace.TracerProvider
+
+	// state is the backend for the state, when nil the backend configured
+	// for the current workspace is used
+	state config.StateBackend
+
+	// home is the jumppad home folder, when empty the home folder of the
+	// current user is used
+	home string
+
+	// workspace is the name of the workspace, when empty the workspace
+	// selected by the user is used
+	workspace string
+
+	// redactor receives the sensitive values found in the config and state
+	// so that they can be removed from output
+	redactor *logger.Redactor
+
+	atomic bool
+
+	// interrupted contains the resources that were in progress when the
+	// context of the current operation was cancelled
+	interrupted   []string
+	interruptLock sync.Mutex
+
+	// cacheLock guards changes to the image cache which can be made
+	// by concurrently created networks and registries
+	cacheLock sync.Mutex
+}
+
+// Option configures optional behaviour of the engine
+type Option func(e *EngineImpl)
+
+// WithEventHandler sets the handler that receives the events emitted while
+// resources are created and destroyed
+func WithEventHandler(h events.Handler) Option {
+	return func(e *EngineImpl) {
+		e.events = h
+	}
+}
+
+// WithTracerProvider sets the provider used to record spans for the
+// operations of the engine
+func WithTracerProvider(tp trace.TracerProvider) Option {
+	return func(e *EngineImpl) {
+		e.tracer = tp
+	}
+}
+
+// WithRedactor sets the redactor that sensitive values are added to as
+// they are found in the configuration and state
+func WithRedactor(r *logger.Redactor) Option {
+	return func(e *EngineImpl) {
+		e.redactor = r
+	}
+}
+
+// WithStateBackend sets the backend that the state is read from and written
+// to, by default the backend configured for the current workspace is used
+func WithStateBackend(b config.StateBackend) Option {
+	return func(e *EngineImpl) {
+		e.state = b
+	}
+}
+
+// WithHome sets the jumppad home folder used for the module cache and the
+// folders returned by the jumppad and data functions, unless a state backend
+// is set the state is also stored in the home folder
+func WithHome(dir string) Option {
+	return func(e *EngineImpl) {
+		e.home = dir
+	}
+}
+
+// WithWorkspace sets the workspace that the state, data folders and the
+// folders returned by the data function are stored in
+func WithWorkspace(name string) Option {
+	return func(e *EngineImpl) {
+		e.workspace = name
+	}
 }
 
 // New creates a new Jumppad engine
-func New(p config.Providers, l logger.Logger) (Engine, error) {
+func New(p config.Providers, l logger.Logger, opts ...Option) (Engine, error) {
 	e := &EngineImpl{}
 	e.log = l
 	e.providers = p
+	e.parallelism = DefaultParallelism
 
-	// Set the standard writer to our logger as the DAG uses the standard library log.
-	log.SetOutput(l.StandardWriter())
+	for _, o := range opts {
+		o(e)
+	}
+
+	if e.state == nil && e.home != "" {
+		e.state = config.NewLocalBackendWithDir(filepath.Join(e.environment().WorkspaceHome(), "state"))
+	}
 
 	return e, nil
 }
 
+// environment returns the jumppad home folder and workspace of the engine
+func (e *EngineImpl) environment() utils.Environment {
+	return utils.Environment{Home: e.home, Workspace: e.workspace}
+}
+
 // Config returns the parsed config
 func (e *EngineImpl) Config() *hclconfig.Config {
 	return e.config
 }
 
+// SetParallelism sets the maximum number of resources that are created
+// or destroyed at the same time, setting a value of 1 processes resources
+// one at a time, values less than 1 use the DefaultParallelism
+func (e *EngineImpl) SetParallelism(n int) {
+	e.parallelism = n
+}
+
+// SetEventHandler sets the handler that receives the events emitted while
+// resources are created and destroyed, setting nil disables events
+func (e *EngineImpl) SetEventHandler(h events.Handler) {
+	e.events = h
+}
+
+// SetAtomic enables atomic applies, when any resource fails to be created
+// or the context is cancelled the resources created by the apply are
+// destroyed and the previous state is restored
+func (e *EngineImpl) SetAtomic(atomic bool) {
+	e.atomic = atomic
+}
+
+// SetTracerProvider sets the provider used to record spans for the
+// operations of the engine, setting nil disables tracing
+func (e *EngineImpl) SetTracerProvider(tp trace.TracerProvider) {
+	e.tracer = tp
+}
+
 // ParseConfig parses the given Jumppad files and creating the resource types but does
 // not apply or destroy the resources.
 // This function can be used to check the validity of a configuration without making changes
@@ -96,11 +296,15 @@ func (e *EngineImpl) ParseConfigWithVariables(path string, vars map[string]strin
 
 	e.config = hclconfig.NewConfig()
 
+	_, span := tracing.Start(e.ctx, "jumppad.parse")
+
 	err = e.readAndProcessConfig(path, vars, variablesFile, func(r types.Resource) error {
 		e.config.AppendResource(r)
 		return nil
 	})
 
+	tracing.End(span, err)
+
 	return e.config, err
 }
 
@@ -108,96 +312,27 @@ func (e *EngineImpl) ParseConfigWithVariables(path string, vars map[string]strin
 func (e *EngineImpl) Diff(path string, variables map[string]string, variablesFile string) (
 	[]types.Resource, []types.Resource, []types.Resource, *hclconfig.Config, error) {
 
+	plan, err := e.Plan(path, variables, variablesFile)
+	if err != nil {
+		return nil, nil, nil, nil, err
+	}
+
 	var new []types.Resource
 	var changed []types.Resource
 	var removed []types.Resource
 
-	// load the stack
-	past, _ := config.LoadState()
-
-	// Parse the config to check it is valid
-	res, parseErr := e.ParseConfigWithVariables(path, variables, variablesFile)
-
-	if parseErr != nil {
-		// cast the error to a config error
-		ce := parseErr.(*hclerrors.ConfigError)
-
-		// if we have parser errors return them
-		// if not it is possible to get process errors at this point as the
-		// callbacks have not been called for the providers, any referenced
-		// resources will not be found, it is ok to ignore these errors
-		if ce.ContainsErrors() {
-			fmt.Println("Error parsing config", parseErr)
-			return nil, nil, nil, nil, parseErr
-		}
-	}
-
-	unchanged := []types.Resource{}
-
-	for _, r := range res.Resources {
-		// does the resource exist
-		cr, err := past.FindResource(r.Metadata().ID)
-
-		// check if the resource has been found
-		if err != nil {
-			// resource does not exist
-			new = append(new, r)
-			continue
-		}
-
-		// check if the hcl resource text has changed
-		if cr.Metadata().Checksum.Parsed != r.Metadata().Checksum.Parsed {
-			// resource has changes rebuild
-			changed = append(changed, r)
-			continue
-		}
-
-		unchanged = append(unchanged, r)
-	}
-
-	// check if there are resources in the state that are no longer
-	// in the config
-	for _, r := range past.Resources {
-		// if this is the image cache continue as this is always added
-		if r.Metadata().Type == cache.TypeImageCache {
-			continue
-		}
-
-		found := false
-		for _, r2 := range res.Resources {
-			if r.Metadata().ID == r2.Metadata().ID {
-				found = true
-				break
-			}
-		}
-
-		if !found {
-			removed = append(removed, r)
+	for _, c := range plan.Changes {
+		switch {
+		case c.hasReason(ReasonRemoved):
+			removed = append(removed, c.resource)
+		case !c.inState:
+			new = append(new, c.resource)
+		case c.hasReason(ReasonChecksum), c.hasReason(ReasonProviderChanged):
+			changed = append(changed, c.resource)
 		}
 	}
 
-	// loop through the remaining resources and call changed on the provider
-	// to see if any internal properties that have changed
-	for _, r := range unchanged {
-		// call changed on when not disabled
-		if !r.GetDisabled() {
-			p := e.providers.GetProvider(r)
-			if p == nil {
-				return nil, nil, nil, nil, fmt.Errorf("unable to create provider for resource Name: %s, Type: %s. Please check the provider is registered in providers.go", r.Metadata().Name, r.Metadata().Type)
-			}
-
-			c, err := p.Changed()
-			if err != nil {
-				return nil, nil, nil, nil, fmt.Errorf("unable to determine if resource has changed Name: %s, Type: %s", r.Metadata().Name, r.Metadata().Type)
-			}
-
-			if c {
-				changed = append(changed, r)
-			}
-		}
-	}
-
-	return new, changed, removed, res, nil
+	return new, changed, removed, plan.config, nil
 }
 
 // Apply the configuration and create or destroy the resources
@@ -207,7 +342,17 @@ func (e *EngineImpl) Apply(ctx context.Context, path string) (*hclconfig.Config,
 
 // ApplyWithVariables applies the current config creating the resources
 func (e *EngineImpl) ApplyWithVariables(ctx context.Context, path string, vars map[string]string, variablesFile string) (*hclconfig.Config, error) {
-	e.ctx = ctx
+	ctx, span := tracing.StartRoot(ctx, e.tracer, "jumppad.apply")
+
+	c, err := e.applyWithVariables(ctx, path, vars, variablesFile)
+	tracing.End(span, err)
+
+	return c, err
+}
+
+func (e *EngineImpl) applyWithVariables(ctx context.Context, path string, vars map[string]string, variablesFile string) (*hclconfig.Config, error) {
+	e.ctx = events.NewContext(ctx, e.events)
+	e.resetInterrupted()
 
 	// abs paths
 	var err error
@@ -226,18 +371,40 @@ func (e *EngineImpl) ApplyWithVariables(ctx context.Context, path string, vars m
 	}
 
 	// get a diff of resources
-	_, _, removed, _, err := e.Diff(path, vars, variablesFile)
+	_, _, removed, parsed, err := e.Diff(path, vars, variablesFile)
 	if err != nil {
 		return nil, err
 	}
 
+	e.scheduler = newScheduler(ctx, e.parallelism, parsed)
+	defer func() { e.scheduler = nil }()
+
 	// load the state
-	c, err := config.LoadState()
+	c, err := e.loadState()
 	if err != nil {
 		e.log.Debug("unable to load state", "error", err)
 	}
 
 	e.config = c
+	e.redactor.Add(config.SensitiveConfigValues(c)...)
+
+	// keep a copy of the state to restore when an atomic apply fails
+	var previous *hclconfig.Config
+	if e.atomic {
+		previous, err = e.loadState()
+		if err != nil {
+			previous = hclconfig.NewConfig()
+		}
+	}
+
+	e.targeted, err = e.applyTargets(parsed, c)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { e.targeted = nil }()
+
+	// resources that reference a recreated resource are recreated with it
+	e.taintCascades(parsed, c)
 
 	// check to see we already have an image cache
 	_, err = c.FindResourcesByType(cache.TypeImageCache)
@@ -263,7 +430,9 @@ func (e *EngineImpl) ApplyWithVariables(ctx context.Context, path string, vars m
 		}
 
 		// create the cache
+		_, span := e.startSpan(ca, events.OperationCreate)
 		err := p.Create(ctx)
+		tracing.End(span, err)
 		if err != nil {
 			ca.Meta.Properties[constants.PropertyStatus] = constants.StatusFailed
 		} else {
@@ -276,7 +445,7 @@ func (e *EngineImpl) ApplyWithVariables(ctx context.Context, path string, vars m
 		e.config.AppendResource(ca)
 
 		// save the state
-		config.SaveState(e.config)
+		e.saveState(e.config)
 
 		if err != nil {
 			return nil, fmt.Errorf("unable to create image cache %s", err)
@@ -284,10 +453,17 @@ func (e *EngineImpl) ApplyWithVariables(ctx context.Context, path string, vars m
 	}
 
 	// finally we can process and create resources
+	e.createReadyResources(path, vars, variablesFile)
+
 	processErr := e.readAndProcessConfig(path, vars, variablesFile, e.createCallback)
 
 	// we need to remove any resources that are in the state but not in the config
 	for _, r := range removed {
+		// resources are kept in the state when the operation is cancelled
+		if e.ctx.Err() != nil {
+			break
+		}
+
 		e.log.Debug("removing resource in state but not current config", "id", r.Metadata().ID)
 
 		p := e.providers.GetProvider(r)
@@ -297,7 +473,11 @@ func (e *EngineImpl) ApplyWithVariables(ctx context.Context, path string, vars m
 		}
 
 		// call destroy
-		err := p.Destroy(e.ctx, e.force)
+		st := e.emitStart(r, events.OperationDestroy)
+		dctx, span := e.startSpan(r, events.OperationDestroy)
+		err := p.Destroy(dctx, e.force)
+		tracing.End(span, err)
+		e.emitResult(r, events.OperationDestroy, st, err)
 		if err != nil {
 			processErr = fmt.Errorf("unable to destroy resource Name: %s, Type: %s", r.Metadata().Name, r.Metadata().Type)
 			continue
@@ -307,27 +487,89 @@ func (e *EngineImpl) ApplyWithVariables(ctx context.Context, path string, vars m
 	}
 
 	// save the state regardless of error
-	stateErr := config.SaveState(e.config)
+	stateErr := e.saveState(e.config)
 	if stateErr != nil {
 		e.log.Info("Unable to save state", "error", stateErr)
 	}
 
+	// errors returned by providers that were stopped are replaced with a
+	// summary of the interrupted resources
+	if err := e.interruptedError(ctx); err != nil {
+		processErr = err
+	}
+
+	if e.atomic && processErr != nil {
+		return e.rollbackApply(ctx, previous, processErr)
+	}
+
 	return e.config, processErr
 }
 
+// rollbackApply restores the state from before a failed atomic apply, the
+// rollback is not cancelled with the context of the apply so that it
+// completes when the apply was interrupted
+func (e *EngineImpl) rollbackApply(ctx context.Context, previous *hclconfig.Config, applyErr error) (*hclconfig.Config, error) {
+	e.log.Error("Apply failed, rolling back changes", "error", applyErr)
+
+	// resources that failed may only partially exist, errors destroying
+	// them must not stop the rollback
+	force := e.force
+	e.force = true
+	defer func() { e.force = force }()
+
+	c, err := e.Rollback(context.WithoutCancel(ctx), previous)
+	if err != nil {
+		return c, fmt.Errorf("%s, unable to roll back changes: %s", applyErr, err)
+	}
+
+	return c, fmt.Errorf("%w, all changes have been rolled back", applyErr)
+}
+
 // Destroy the resources defined by the state
 func (e *EngineImpl) Destroy(ctx context.Context, force bool) error {
+	ctx, span := tracing.StartRoot(ctx, e.tracer, "jumppad.destroy")
+
+	err := e.destroy(ctx, force)
+	tracing.End(span, err)
+
+	return err
+}
+
+func (e *EngineImpl) destroy(ctx context.Context, force bool) error {
 	e.log.Info("Destroying resources", "force", force)
 	e.force = force
-	e.ctx = ctx
+	e.ctx = events.NewContext(ctx, e.events)
+	e.resetInterrupted()
 
 	// load the state
-	c, err := config.LoadState()
+	c, err := e.loadState()
 	if err != nil {
 		e.log.Debug("State file does not exist")
 	}
 
 	e.config = c
+	e.redactor.Add(config.SensitiveConfigValues(c)...)
+	e.scheduler = newScheduler(ctx, e.parallelism, nil)
+	defer func() { e.scheduler = nil }()
+
+	e.targeted, err = e.destroyTargets(c)
+	if err != nil {
+		return err
+	}
+	defer func() { e.targeted = nil }()
+
+	// fail before any resources are destroyed
+	protected := []string{}
+	for _, r := range c.Resources {
+		if e.isTargeted(r) && !r.GetDisabled() && preventsDestroy(r) {
+			protected = append(protected, r.Metadata().ID)
+		}
+	}
+
+	if len(protected) > 0 {
+		sort.Strings(protected)
+		return &PreventDestroyError{Resources: protected}
+	}
 
 	// run through the graph and call the destroy callback
 	// disabled resources are not included in this callback
@@ -335,14 +577,113 @@ func (e *EngineImpl) Destroy(ctx context.Context, force bool) error {
 	// should have the correct dependency graph to be
 	// destroyed last
 	err = e.config.Walk(e.destroyCallback, true)
+
+	// resources that were not destroyed before the operation was
+	// cancelled remain in the state
+	if ierr := e.interruptedError(ctx); ierr != nil {
+		stateErr := e.saveState(e.config)
+		if stateErr != nil {
+			e.log.Info("Unable to save state", "error", stateErr)
+		}
+
+		return ierr
+	}
+
 	if err != nil {
 
 		// return the process error
 		return fmt.Errorf("error trying to call Destroy on provider: %s", err)
 	}
 
+	// resources that were not targeted remain in the state
+	if e.targeted != nil {
+		return e.saveState(e.config)
+	}
+
 	// remove the state
-	return os.Remove(utils.StatePath())
+	return e.removeState()
+}
+
+// Rollback reconciles the running resources with the target state, any
+// resource in the current state that is not in the target, or that has a
+// different configuration, is destroyed then the resources in the target
+// that are not running are created
+func (e *EngineImpl) Rollback(ctx context.Context, target *hclconfig.Config) (*hclconfig.Config, error) {
+	ctx, span := tracing.StartRoot(ctx, e.tracer, "jumppad.rollback")
+
+	c, err := e.rollback(ctx, target)
+	tracing.End(span, err)
+
+	return c, err
+}
+
+func (e *EngineImpl) rollback(ctx context.Context, target *hclconfig.Config) (*hclconfig.Config, error) {
+	e.log.Info("Rolling back state")
+	e.ctx = events.NewContext(ctx, e.events)
+	e.resetInterrupted()
+
+	c, err := e.loadState()
+	if err != nil {
+		e.log.Debug("State file does not exist")
+	}
+
+	e.config = c
+	e.redactor.Add(config.SensitiveConfigValues(c)...)
+	e.scheduler = newScheduler(ctx, e.parallelism, nil)
+	defer func() { e.scheduler = nil }()
+
+	// destroy the resources that do not match the target, dependents are
+	// destroyed before their dependencies
+	err = e.config.Walk(func(r types.Resource) error {
+		if r.Metadata().ID == "resource.image_cache.default" {
+			return nil
+		}
+
+		tr, err := target.FindResource(r.Metadata().ID)
+		if err == nil && tr.Metadata().Checksum.Parsed == r.Metadata().Checksum.Parsed &&
+			r.Metadata().Properties[constants.PropertyStatus] == constants.StatusCreated {
+			return nil
+		}
+
+		e.log.Debug("Destroying resource not matching target state", "id", r.Metadata().ID)
+		return e.destroyCallback(r)
+	}, true)
+
+	if err != nil {
+		stateErr := e.saveState(e.config)
+		if stateErr != nil {
+			e.log.Info("Unable to save state", "error", stateErr)
+		}
+
+		return e.config, fmt.Errorf("error trying to call Destroy on provider: %s", err)
+	}
+
+	// create the resources in the target that are no longer running
+	processErr := target.Walk(func(r types.Resource) error {
+		if _, err := e.config.FindResource(r.Metadata().ID); err == nil {
+			return nil
+		}
+
+		e.log.Debug("Creating resource from target state", "id", r.Metadata().ID)
+
+		// the status is from the target state, clear it so that the
+		// resource is created
+		delete(r.Metadata().Properties, constants.PropertyStatus)
+		return e.createResource(r)
+	}, false)
+
+	// disabled resources are not walked, add them to the state
+	err = e.appendDisabledResources(target)
+	if err != nil && processErr == nil {
+		processErr = err
+	}
+
+	stateErr := e.saveState(e.config)
+	if stateErr != nil {
+		e.log.Info("Unable to save state", "error", stateErr)
+	}
+
+	return e.config, processErr
 }
 
 // ResourceCount defines the number of resources in a plan
@@ -361,38 +702,23 @@ func (e *EngineImpl) ResourceCountForType(t string) int {
 }
 
 func (e *EngineImpl) readAndProcessConfig(path string, variables map[string]string, variablesFile string, callback hclconfig.WalkCallback) error {
-	var parseError error
-	var parsedConfig *hclconfig.Config
-
 	if path == "" {
 		return nil
 	}
 
-	variablesFiles := []string{}
-	if variablesFile != "" {
-		variablesFiles = append(variablesFiles, variablesFile)
-	}
+	parsedConfig, parseError := e.parseConfig(path, variables, variablesFile, callback)
 
-	hclParser := config.NewParser(callback, variables, variablesFiles)
+	// wait for any resources that are still being created
+	if e.scheduler != nil {
+		if err := e.scheduler.wait(e.createResource); err != nil {
+			ce, ok := parseError.(*hclerrors.ConfigError)
+			if !ok {
+				ce = hclerrors.NewConfigError()
+				parseError = ce
+			}
 
-	if utils.IsHCLFile(path) {
-		// ParseFile processes the HCL, builds a graph of resources then calls
-		// the callback for each resource in order
-		//
-		// We are not using the returned config as the resources are added to the
-		// state on the callback
-		//
-		// If the callback returns an error we need to save the state and exit
-		parsedConfig, parseError = hclParser.ParseFile(path)
-	} else {
-		// ParseFolder processes the HCL, builds a graph of resources then calls
-		// the callback for each resource in order
-		//
-		// We are not using the returned config as the resources are added to the
-		// state on the callback
-		//
-		// If the callback returns an error we need to save the state and exit
-		parsedConfig, parseError = hclParser.ParseDirectory(path)
+			ce.Errors = append(ce.Errors, err.(*hclerrors.ConfigError).Errors...)
+		}
 	}
 
 	// process is not called for disabled resources, add manually
@@ -410,6 +736,69 @@ func (e *EngineImpl) readAndProcessConfig(path string, variables map[string]stri
 	return parseError
 }
 
+// parseConfig parses the configuration at path and calls the callback for
+// each resource in the order of the dependency graph
+func (e *EngineImpl) parseConfig(path string, variables map[string]string, variablesFile string, callback hclconfig.WalkCallback) (*hclconfig.Config, error) {
+	variablesFiles := []string{}
+	if variablesFile != "" {
+		variablesFiles = append(variablesFiles, variablesFile)
+	}
+
+	// computed fields are restored from the state before dependent
+	// resources are parsed
+	state, err := e.loadState()
+	if err != nil {
+		e.log.Debug("Unable to load state", "error", err)
+	}
+
+	// sensitive values are added before the resource is created so that
+	// they are redacted from any errors, the checksum of resources that
+	// ignore changes must be set before they are compared with the state
+	hclParser := config.NewParserWithEnvironment(e.environment(), func(r types.Resource) error {
+		// Apply parses the config with Diff before any resources are changed
+		// so invalid lifecycle blocks are reported before anything is created
+		if _, err := lifecycle.FromResource(r).Parse(); err != nil {
+			return fmt.Errorf("invalid lifecycle: %s", err)
+		}
+
+		// the checksum is calculated before the computed fields of the
+		// resource are restored, like the checksum set by the parser
+		if err := applyIgnoreChanges(r); err != nil {
+			return err
+		}
+
+		config.RestoreState(r, state)
+
+		if config.MarkSensitive(r, path) {
+			e.redactor.Add(config.VariableValues(r, variables, variablesFile)...)
+		}
+
+		e.redactor.Add(config.SensitiveValues(r)...)
+
+		return callback(r)
+	}, variables, variablesFiles)
+
+	if utils.IsHCLFile(path) {
+		// ParseFile processes the HCL, builds a graph of resources then calls
+		// the callback for each resource in order
+		//
+		// We are not using the returned config as the resources are added to the
+		// state on the callback
+		//
+		// If the callback returns an error we need to save the state and exit
+		return hclParser.ParseFile(path)
+	}
+
+	// ParseFolder processes the HCL, builds a graph of resources then calls
+	// the callback for each resource in order
+	//
+	// We are not using the returned config as the resources are added to the
+	// state on the callback
+	//
+	// If the callback returns an error we need to save the state and exit
+	return hclParser.ParseDirectory(path)
+}
+
 // destroyDisabledResources destroys any resrouces that were created but
 // have subsequently been set to disabled
 func (e *EngineImpl) destroyDisabledResources(ctx context.Context, force bool) error {
@@ -418,7 +807,7 @@ func (e *EngineImpl) destroyDisabledResources(ctx context.Context, force bool) e
 	// these respurces should be destroyed
 
 	for _, r := range e.config.Resources {
-		if r.GetDisabled() &&
+		if r.GetDisabled() && e.isTargeted(r) &&
 			r.Metadata().Properties[constants.PropertyStatus] == constants.StatusCreated {
 
 			p := e.providers.GetProvider(r)
@@ -428,13 +817,18 @@ func (e *EngineImpl) destroyDisabledResources(ctx context.Context, force bool) e
 			}
 
 			// call destroy
-			err := p.Destroy(ctx, force)
+			st := e.emitStart(r, events.OperationDestroy)
+			dctx, span := e.startSpan(r, events.OperationDestroy)
+			err := p.Destroy(dctx, force)
+			tracing.End(span, err)
 			if err != nil {
 				r.Metadata().Properties[constants.PropertyStatus] = constants.StatusFailed
+				e.emitResult(r, events.OperationDestroy, st, err)
 				return fmt.Errorf("unable to destroy resource Name: %s, Type: %s", r.Metadata().Name, r.Metadata().Type)
 			}
 
 			r.Metadata().Properties[constants.PropertyStatus] = constants.StatusDisabled
+			e.emitResult(r, events.OperationDestroy, st, nil)
 		}
 	}
 
@@ -473,6 +867,82 @@ func (e *EngineImpl) createCallback(r types.Resource) error {
 		return nil
 	}
 
+	// resources that are not targeted are left unchanged in the state
+	if !e.isTargeted(r) {
+		return nil
+	}
+
+	// resources created by createReadyResources are not created again
+	if ok, err := e.scheduler.join(r); ok {
+		return err
+	}
+
+	// resources that are not referenced by other resources are created
+	// once the parser has finished, this allows independent branches of the
+	// graph to be created concurrently
+	if e.scheduler.canDefer(r) {
+		e.scheduler.add(r, true)
+		return nil
+	}
+
+	t := e.scheduler.add(r, false)
+	e.scheduler.run(t, e.createResource)
+
+	return t.err
+}
+
+// createReadyResources creates the resources in the configuration
+// concurrently before it is processed with createCallback, the parser holds
+// a lock while the callback runs so resources created by the callback are
+// created one at a time. The configuration is parsed each time resources
+// complete, resources whose dependencies have all been created are decoded
+// with the values of their dependencies and started.
+func (e *EngineImpl) createReadyResources(path string, variables map[string]string, variablesFile string) {
+	if !e.scheduler.concurrent() {
+		return
+	}
+
+	for e.ctx.Err() == nil {
+		ready := []*resourceTask{}
+		lock := sync.Mutex{}
+
+		// resources in different modules are parsed concurrently, errors
+		// are returned when the configuration is processed
+		e.parseConfig(path, variables, variablesFile, func(r types.Resource) error {
+			if !e.isTargeted(r) || !e.scheduler.ready(r) {
+				return nil
+			}
+
+			t := e.scheduler.add(r, true)
+
+			lock.Lock()
+			ready = append(ready, t)
+			lock.Unlock()
+
+			return nil
+		})
+
+		// tasks are started once the parser has finished with the resources
+		for _, t := range ready {
+			e.scheduler.start(t, e.createResource)
+		}
+
+		if len(ready) == 0 && !e.scheduler.running() {
+			return
+		}
+
+		e.scheduler.waitAny()
+	}
+}
+
+// createResource calls the provider to create, refresh or re-create the
+// given resource and adds it to the state
+func (e *EngineImpl) createResource(r types.Resource) error {
+	// if the context is cancelled skip
+	if e.ctx.Err() != nil {
+		return nil
+	}
+
 	p := e.providers.GetProvider(r)
 	if p == nil {
 		r.Metadata().Properties[constants.PropertyStatus] = constants.StatusFailed
@@ -493,35 +963,89 @@ func (e *EngineImpl) createCallback(r types.Resource) error {
 		}
 	}
 
+	ls, err := lifecycleSettings(r)
+	if err != nil {
+		r.Metadata().Properties[constants.PropertyStatus] = constants.StatusFailed
+		e.config.AppendResource(r)
+
+		return err
+	}
+
+	destroy := func(ctx context.Context) error {
+		return p.Destroy(ctx, false)
+	}
+
+	// stopped resources are started and then refreshed, resources that fail
+	// to start are marked as failed and are re-created
+	if r.Metadata().Properties[constants.PropertyStatus] == constants.StatusStopped {
+		err := e.startStoppedResource(r, p)
+		if err != nil {
+			e.log.Debug("Unable to start resource, re-creating", "ref", r.Metadata().ID, "error", err)
+		}
+	}
+
 	var providerError error
 	switch r.Metadata().Properties[constants.PropertyStatus] {
 	case constants.StatusCreated:
-		providerError = p.Refresh(e.ctx)
+		st := e.emitStart(r, events.OperationRefresh)
+		ctx, span := e.startSpan(r, events.OperationRefresh)
+		providerError = e.withRetries(ctx, r, ls, ls.CreateTimeout, p.Refresh, nil)
 		if providerError != nil {
 			r.Metadata().Properties[constants.PropertyStatus] = constants.StatusFailed
 		}
 
+		tracing.End(span, providerError)
+
+		e.emitResult(r, events.OperationRefresh, st, providerError)
+
 	// Normal case for PendingUpdate is do nothing
 	// PendingModification causes a resource to be
 	// destroyed before created
-	case constants.StatusTainted:
+	case constants.StatusTainted, constants.StatusDrifted:
 		fallthrough
 
 	// Always attempt to destroy and re-create failed resources
 	case constants.StatusFailed:
-		providerError = p.Destroy(e.ctx, false)
+		st := e.emitStart(r, events.OperationDestroy)
+		ctx, span := e.startSpan(r, events.OperationDestroy)
+		providerError = e.withRetries(ctx, r, ls, ls.DestroyTimeout, destroy, nil)
 		if providerError != nil {
 			r.Metadata().Properties[constants.PropertyStatus] = constants.StatusFailed
 		}
 
+		tracing.End(span, providerError)
+
+		e.emitResult(r, events.OperationDestroy, st, providerError)
+
 		fallthrough // failed resources should always attempt recreation
 
 	default:
+		st := e.emitStart(r, events.OperationCreate)
+		ctx, span := e.startSpan(r, events.OperationCreate)
 		r.Metadata().Properties[constants.PropertyStatus] = constants.StatusCreated
-		providerError = p.Create(e.ctx)
+
+		// remove anything left by a failed attempt before retrying
+		cleanup := func() {
+			if err := withTimeout(ctx, ls.DestroyTimeout, destroy); err != nil {
+				e.log.Debug("Unable to clean up failed resource", "ref", r.Metadata().ID, "error", err)
+			}
+		}
+
+		providerError = e.withRetries(ctx, r, ls, ls.CreateTimeout, p.Create, cleanup)
 		if providerError != nil {
 			r.Metadata().Properties[constants.PropertyStatus] = constants.StatusFailed
 		}
+
+		// providers set generated secrets such as passwords when creating
+		e.redactor.Add(config.SensitiveValues(r)...)
+
+		tracing.End(span, providerError)
+
+		e.emitResult(r, events.OperationCreate, st, providerError)
+	}
+
+	if e.ctx.Err() != nil {
+		e.markInterrupted(r)
 	}
 
 	// add the resource to the state
@@ -533,6 +1057,9 @@ func (e *EngineImpl) createCallback(r types.Resource) error {
 	// did we just create a network, if so we need to attach the image cache
 	// to the network and set the dependency
 	if r.Metadata().Type == network.TypeNetwork && r.Metadata().Properties[constants.PropertyStatus] == constants.StatusCreated {
+		e.cacheLock.Lock()
+		defer e.cacheLock.Unlock()
+
 		// get the image cache
 		ic, err := e.config.FindResource("resource.image_cache.default")
 		if err == nil {
@@ -548,6 +1075,9 @@ func (e *EngineImpl) createCallback(r types.Resource) error {
 	}
 
 	if r.Metadata().Type == cache.TypeRegistry && r.Metadata().Properties[constants.PropertyStatus] == constants.StatusCreated {
+		e.cacheLock.Lock()
+		defer e.cacheLock.Unlock()
+
 		// get the image cache
 		ic, err := e.config.FindResource("resource.image_cache.default")
 		if err == nil {
@@ -598,6 +1128,10 @@ func (e *EngineImpl) destroyCallback(r types.Resource) error {
 		return nil
 	}
 
+	if !e.isTargeted(r) {
+		return nil
+	}
+
 	fqrn := resources.FQRNFromResource(r)
 
 	// do nothing for disabled resources
@@ -608,6 +1142,10 @@ func (e *EngineImpl) destroyCallback(r types.Resource) error {
 		return nil
 	}
 
+	if preventsDestroy(r) {
+		return &PreventDestroyError{Resources: []string{r.Metadata().ID}}
+	}
+
 	p := e.providers.GetProvider(r)
 
 	if p == nil {
@@ -615,9 +1153,41 @@ func (e *EngineImpl) destroyCallback(r types.Resource) error {
 		return fmt.Errorf("unable to create provider for resource Name: %s, Type: %s", r.Metadata().Name, r.Metadata().Type)
 	}
 
-	err := p.Destroy(e.ctx, e.force)
+	// the graph walker destroys independent resources concurrently, limit
+	// the number of resources destroyed at the same time
+	if !e.scheduler.acquire() {
+		return nil
+	}
+	defer e.scheduler.release()
+
+	// resources with an invalid lifecycle are never created, they are
+	// destroyed without retries
+	ls, err := lifecycleSettings(r)
+	if err != nil {
+		ls = &lifecycle.Settings{}
+	}
+
+	st := e.emitStart(r, events.OperationDestroy)
+	ctx, span := e.startSpan(r, events.OperationDestroy)
+
+	err = e.withRetries(ctx, r, ls, ls.DestroyTimeout, func(ctx context.Context) error {
+		return p.Destroy(ctx, e.force)
+	}, nil)
 	if err != nil && !e.force {
 		r.Metadata().Properties[constants.PropertyStatus] = constants.StatusFailed
+	}
+
+	tracing.End(span, err)
+
+	e.emitResult(r, events.OperationDestroy, st, err)
+
+	// the resource may still exist so it is kept in the state
+	if e.ctx.Err() != nil {
+		e.markInterrupted(r)
+		return nil
+	}
+
+	if err != nil && !e.force {
 		return fmt.Errorf("unable to destroy resource Name: %s, Type: %s, Error: %s", r.Metadata().Name, r.Metadata().Type, err)
 	}
 
@@ -626,3 +1196,67 @@ func (e *EngineImpl) destroyCallback(r types.Resource) error {
 
 	return nil
 }
+
+// emitStart emits the start event for an operation on the resource and
+// returns the time that the operation started
+func (e *EngineImpl) emitStart(r types.Resource, op events.Operation) time.Time {
+	st := time.Now()
+
+	if e.events != nil {
+		e.events(events.Event{
+			Time:         st,
+			Type:         events.TypeStart,
+			Operation:    op,
+			ID:           r.Metadata().ID,
+			ResourceType: r.Metadata().Type,
+			Status:       statusString(r),
+		})
+	}
+
+	return st
+}
+
+// emitResult emits a success or failure event for an operation on the
+// resource depending on the error returned by the provider
+func (e *EngineImpl) emitResult(r types.Resource, op events.Operation, started time.Time, err error) {
+	if e.events == nil {
+		return
+	}
+
+	ev := events.Event{
+		Time:         time.Now(),
+		Type:         events.TypeSuccess,
+		Operation:    op,
+		ID:           r.Metadata().ID,
+		ResourceType: r.Metadata().Type,
+		Status:       statusString(r),
+		Duration:     time.Since(started),
+	}
+
+	if err != nil {
+		ev.Type = events.TypeFailure
+		ev.Error = err.Error()
+	}
+
+	// destroyed resources are removed from the state
+	if op == events.OperationDestroy && err == nil {
+		ev.Status = ""
+	}
+
+	e.events(ev)
+}
+
+// startSpan starts a span for an operation on the resource, the returned
+// context must be passed to the provider so that spans started by the
+// provider and its clients are children of the operation
+func (e *EngineImpl) startSpan(r types.Resource, op events.Operation) (context.Context, trace.Span) {
+	return tracing.Start(e.ctx, "provider."+string(op),
+		attribute.String(tracing.AttributeResourceID, r.Metadata().ID),
+		attribute.String(tracing.AttributeResourceType, r.Metadata().Type),
+	)
+}
+
+func statusString(r types.Resource) string {
+	s, _ := r.Metadata().Properties[constants.PropertyStatus].(string)
+	return s
+}
@@ -5,10 +5,14 @@ import (
 	"fmt"
 	"log"
 	"os"
+	"path/filepath"
 	"strings"
+	"sync"
 	"testing"
+	"time"
 
 	"github.com/jumppad-labs/hclconfig"
+	"github.com/jumppad-labs/hclconfig/resources"
 	"github.com/jumppad-labs/hclconfig/types"
 	"github.com/jumppad-labs/jumppad/pkg/clients/logger"
 	"github.com/jumppad-labs/jumppad/pkg/config"
@@ -16,8 +20,14 @@ import (
 	"github.com/jumppad-labs/jumppad/pkg/config/resources/cache"
 	"github.com/jumppad-labs/jumppad/pkg/config/resources/container"
 	"github.com/jumppad-labs/jumppad/pkg/jumppad/constants"
+	"github.com/jumppad-labs/jumppad/pkg/jumppad/events"
+	"github.com/jumppad-labs/jumppad/pkg/jumppad/tracing"
 	"github.com/jumppad-labs/jumppad/pkg/utils"
 	"github.com/jumppad-labs/jumppad/testutils"
+	sdk "github.com/jumppad-labs/plugin-sdk"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
 
 	"github.com/stretchr/testify/mock"
 	"github.com/stretchr/testify/require"
@@ -147,6 +157,9 @@ func TestApplyAddsCustomRegistriesToImageCache(t *testing.T) {
 func TestApplyWithSingleFileAndVariables(t *testing.T) {
 	e, mp := setupTests(t, nil)
 
+	// create the resources in order
+	e.SetParallelism(1)
+
 	_, err := e.ApplyWithVariables(context.Background(), "../../examples/single_file/container.hcl", nil, "../../examples/single_file/default.vars")
 	require.NoError(t, err)
 	require.Len(t, e.config.Resources, 7) // 6 resources in the file plus the image cache
@@ -355,6 +368,166 @@ func TestApplyCallsProviderDestroyForDisabledResources(t *testing.T) {
 	testAssertMethodCalled(t, mp, "Create", 0) // ImageCache are always created
 }
 
+func TestApplyWithParallelismCallsProviderCreateForEachProvider(t *testing.T) {
+	e, mp := setupTests(t, nil)
+	e.SetParallelism(DefaultParallelism)
+
+	_, err := e.Apply(context.Background(), "../../examples/single_k3s_cluster")
+	require.NoError(t, err)
+
+	rc := len(e.config.Resources)
+	testAssertMethodCalled(t, mp, "Create", rc)
+	testAssertMethodCalled(t, mp, "Refresh", 1)
+
+	sf := testLoadState(t)
+	require.Equal(t, 11, sf.ResourceCount())
+
+	for _, r := range sf.Resources {
+		require.Equal(t, constants.StatusCreated, r.Metadata().Properties[constants.PropertyStatus], r.Metadata().ID)
+	}
+}
+
+var concurrentConfig = `
+resource "network" "one" {
+  subnet = "10.10.0.0/16"
+}
+
+resource "network" "two" {
+  subnet = "10.11.0.0/16"
+}
+
+resource "container" "one" {
+  image {
+    name = "alpine"
+  }
+
+  network {
+    id = resource.network.one.meta.id
+  }
+}
+
+resource "container" "two" {
+  image {
+    name = "alpine"
+  }
+
+  network {
+    id = resource.network.two.meta.id
+  }
+}
+
+output "one" {
+  value = resource.container.one.network.0.assigned_address
+}
+
+output "two" {
+  value = resource.container.two.network.0.assigned_address
+}
+`
+
+// concurrentProviders records the number of containers that are created at
+// the same time, each container waits for the other to start
+type concurrentProviders struct {
+	*mocks.Providers
+
+	lock    sync.Mutex
+	running int
+	max     int
+}
+
+func (p *concurrentProviders) GetProvider(r types.Resource) sdk.Provider {
+	m := p.Providers.GetProvider(r).(*mocks.Provider)
+
+	c, ok := r.(*container.Container)
+	if !ok {
+		return m
+	}
+
+	for _, call := range m.ExpectedCalls {
+		if call.Method != "Create" {
+			continue
+		}
+
+		call.Run(func(args mock.Arguments) {
+			p.lock.Lock()
+			p.running++
+			p.max = max(p.max, p.running)
+			p.lock.Unlock()
+
+			for i := 0; i < 100; i++ {
+				p.lock.Lock()
+				done := p.max > 1
+				p.lock.Unlock()
+
+				if done {
+					break
+				}
+
+				time.Sleep(10 * time.Millisecond)
+			}
+
+			c.Networks[0].AssignedAddress = strings.Replace(c.Networks[0].ID, "resource.network.", "address.", 1)
+
+			p.lock.Lock()
+			p.running--
+			p.lock.Unlock()
+		})
+	}
+
+	return m
+}
+
+func TestApplyWithParallelismCreatesReferencedResourcesConcurrently(t *testing.T) {
+	e, mp := setupTests(t, nil)
+	e.SetParallelism(DefaultParallelism)
+
+	cp := &concurrentProviders{Providers: mp}
+	e.providers = cp
+
+	cf := filepath.Join(t.TempDir(), "config.hcl")
+	err := os.WriteFile(cf, []byte(concurrentConfig), 0644)
+	require.NoError(t, err)
+
+	_, err = e.Apply(context.Background(), cf)
+	require.NoError(t, err)
+
+	// the containers reference the networks and are referenced by the
+	// outputs, both are created at the same time
+	require.Equal(t, 2, cp.max)
+
+	// dependent resources are decoded with the created values
+	sf := testLoadState(t)
+
+	o, err := sf.FindResource("output.one")
+	require.NoError(t, err)
+	require.Equal(t, "address.one", o.(*resources.Output).Value)
+
+	o, err = sf.FindResource("output.two")
+	require.NoError(t, err)
+	require.Equal(t, "address.two", o.(*resources.Output).Value)
+}
+
+func TestApplyWithParallelismReturnsErrorForDeferredResource(t *testing.T) {
+	e, _ := setupTests(t, map[string]error{"consul_lan": fmt.Errorf("boom")})
+	e.SetParallelism(DefaultParallelism)
+
+	// the ingress consul_lan is not referenced by any other resource so
+	// is created after the config has been processed
+	_, err := e.Apply(context.Background(), "../../examples/single_k3s_cluster")
+	require.Error(t, err)
+	require.Contains(t, err.Error(), `unable to create resource "resource.ingress.consul_lan"`)
+
+	sf := testLoadState(t)
+
+	r, err := sf.FindResource("resource.ingress.consul_lan")
+	require.NoError(t, err)
+	require.Equal(t, constants.StatusFailed, r.Metadata().Properties[constants.PropertyStatus])
+
+	r, err = sf.FindResource("resource.helm.vault")
+	require.NoError(t, err)
+	require.Equal(t, constants.StatusCreated, r.Metadata().Properties[constants.PropertyStatus])
+}
+
 func TestApplyCallsProviderRefreshForCreatedResources(t *testing.T) {
 	e, mp := setupTestsWithState(t, nil, existingState)
 
@@ -391,6 +564,17 @@ func TestDestroyCallsProviderDestroyForEachProvider(t *testing.T) {
 	require.NoFileExists(t, utils.StatePath())
 }
 
+func TestDestroyWithParallelismCallsProviderDestroyForEachProvider(t *testing.T) {
+	e, mp := setupTestsWithState(t, nil, existingState)
+	e.SetParallelism(DefaultParallelism)
+
+	err := e.Destroy(context.Background(), false)
+	require.NoError(t, err)
+
+	testAssertMethodCalled(t, mp, "Destroy", 4)
+	require.NoFileExists(t, utils.StatePath())
+}
+
 func TestDestroyNotCallsProviderDestroyForResourcesDisabled(t *testing.T) {
 	e, mp := setupTestsWithState(t, nil, disabledState)
 
@@ -408,11 +592,31 @@ func TestDestroyNotCallsProviderDestroyForResourcesDisabled(t *testing.T) {
 func TestDestroyCallsProviderGenerateErrorStopsExecution(t *testing.T) {
 	e, mp := setupTestsWithState(t, map[string]error{"mycontainer": fmt.Errorf("boom")}, complexState)
 
+	// destroy the resources one at a time
+	e.SetParallelism(1)
+
 	err := e.Destroy(context.Background(), false)
 	require.Error(t, err)
 
-	// should have call destroy for each provider
-	testAssertMethodCalled(t, mp, "Destroy", 2)
+	// the image cache does not depend on the container and may be destroyed
+	// before the error stops the walk, destroy must be called once for the
+	// failed container
+	var failed types.Resource
+	for i := range mp.Providers {
+		if getMetaFromMock(mp, i).Name == "mycontainer" {
+			failed = getResourceFromMock(mp, i)
+		}
+	}
+
+	require.NotNil(t, failed)
+	testAssertMethodCalled(t, mp, "Destroy", 1, failed)
+
+	// the resources the container depends on are not destroyed
+	_, err = e.config.FindResource("resource.network.cloud")
+	require.NoError(t, err)
+
+	_, err = e.config.FindResource("resource.template.mytemplate")
+	require.NoError(t, err)
 
 	// state should not be removed
 	require.FileExists(t, utils.StatePath())
@@ -428,6 +632,132 @@ func TestDestroyFailSetsStatus(t *testing.T) {
 	require.Equal(t, constants.StatusFailed, r.Metadata().Properties[constants.PropertyStatus])
 }
 
+func TestApplyWithTargetsOnlyCreatesTargetsAndDependencies(t *testing.T) {
+	e, _ := setupTests(t, nil)
+	e.SetTargets([]string{"resource.template.consul_config"})
+
+	_, err := e.Apply(context.Background(), "../../examples/single_file/container.hcl")
+	require.NoError(t, err)
+
+	sf := testLoadState(t)
+
+	_, err = sf.FindResource("resource.template.consul_config")
+	require.NoError(t, err)
+
+	_, err = sf.FindResource("resource.network.onprem")
+	require.Error(t, err)
+
+	_, err = sf.FindResource("resource.container.consul")
+	require.Error(t, err)
+}
+
+func TestApplyWithTargetsCreatesDependencies(t *testing.T) {
+	e, _ := setupTests(t, nil)
+	e.SetTargets([]string{"resource.container.consul"})
+
+	_, err := e.Apply(context.Background(), "../../examples/single_file/container.hcl")
+	require.NoError(t, err)
+
+	sf := testLoadState(t)
+
+	for _, id := range []string{"resource.container.consul", "resource.network.onprem", "resource.template.consul_config"} {
+		_, err = sf.FindResource(id)
+		require.NoError(t, err, id)
+	}
+}
+
+func TestApplyWithTargetsDoesNotRemoveUntargetedResources(t *testing.T) {
+	e, mp := setupTestsWithState(t, nil, existingState)
+	e.SetTargets([]string{"resource.network.onprem"})
+
+	_, err := e.Apply(context.Background(), "../../examples/single_file")
+	require.NoError(t, err)
+
+	testAssertMethodCalled(t, mp, "Destroy", 0)
+
+	sf := testLoadState(t)
+
+	for _, id := range []string{"resource.network.cloud", "resource.container.container", "resource.network.onprem"} {
+		_, err = sf.FindResource(id)
+		require.NoError(t, err, id)
+	}
+
+	_, err = sf.FindResource("resource.container.consul")
+	require.Error(t, err)
+}
+
+func TestApplyWithTargetsRemovesTargetedResources(t *testing.T) {
+	e, mp := setupTestsWithState(t, nil, existingState)
+	e.SetTargets([]string{"resource.network.cloud"})
+
+	_, err := e.Apply(context.Background(), "../../examples/single_file")
+	require.NoError(t, err)
+
+	testAssertMethodCalled(t, mp, "Destroy", 1)
+
+	sf := testLoadState(t)
+
+	_, err = sf.FindResource("resource.network.cloud")
+	require.Error(t, err)
+
+	_, err = sf.FindResource("resource.container.container")
+	require.NoError(t, err)
+}
+
+func TestApplyWithUnknownTargetReturnsError(t *testing.T) {
+	e, _ := setupTests(t, nil)
+	e.SetTargets([]string{"resource.container.missing"})
+
+	_, err := e.Apply(context.Background(), "../../examples/single_file/container.hcl")
+	require.ErrorContains(t, err, "does not exist")
+}
+
+func TestDestroyWithTargetsDestroysDependents(t *testing.T) {
+	e, mp := setupTestsWithState(t, nil, targetState)
+	e.SetTargets([]string{"resource.network.cloud"})
+
+	err := e.Destroy(context.Background(), false)
+	require.NoError(t, err)
+
+	// the network and the container that depends on it
+	testAssertMethodCalled(t, mp, "Destroy", 2)
+
+	sf := testLoadState(t)
+	require.Equal(t, 2, sf.ResourceCount())
+
+	_, err = sf.FindResource("resource.container.other")
+	require.NoError(t, err)
+
+	_, err = sf.FindResource("resource.image_cache.default")
+	require.NoError(t, err)
+}
+
+func TestRollbackDestroysChangedAndRemovedResources(t *testing.T) {
+	e, mp := setupTestsWithState(t, nil, existingState)
+
+	target, err := config.NewParser(nil, nil, nil).UnmarshalJSON([]byte(rollbackState))
+	require.NoError(t, err)
+
+	_, err = e.Rollback(context.Background(), target)
+	require.NoError(t, err)
+
+	// the container has changed and the template is not in the target
+	testAssertMethodCalled(t, mp, "Destroy", 2)
+
+	// the changed container and the new container are created
+	testAssertMethodCalled(t, mp, "Create", 2)
+
+	sf := testLoadState(t)
+	require.Equal(t, 4, sf.ResourceCount())
+
+	_, err = sf.FindResource("resource.template.consul_config")
+	require.Error(t, err)
+
+	r, err := sf.FindResource("resource.container.web")
+	require.NoError(t, err)
+	require.Equal(t, constants.StatusCreated, r.Metadata().Properties[constants.PropertyStatus])
+}
+
 func TestParseConfig(t *testing.T) {
 	e, mp := setupTests(t, nil)
 
@@ -597,6 +927,111 @@ var existingState = `
 }
 `
 
+var targetState = `
+{
+  "resources": [
+  {
+      "meta": {
+        "name": "cloud",
+        "properties": {
+          "status": "created"
+        },
+        "type": "network"
+      },
+      "subnet": "10.15.0.0/16"
+  },
+  {
+      "meta": {
+        "name": "default",
+        "properties": {
+          "status": "created"
+        },
+        "type": "image_cache"
+      },
+      "depends_on": ["resource.network.cloud"]
+  },
+  {
+      "meta": {
+        "name": "container",
+        "properties": {
+          "status": "created"
+        },
+        "type": "container"
+      },
+      "depends_on": ["resource.network.cloud"],
+      "image": {
+        "name": "test"
+      }
+  },
+  {
+      "meta": {
+        "name": "other",
+        "properties": {
+          "status": "created"
+        },
+        "type": "container"
+      },
+      "image": {
+        "name": "test"
+      }
+  }
+  ]
+}
+`
+
+var rollbackState = `
+{
+  "resources": [
+  {
+      "meta": {
+        "name": "cloud",
+        "properties": {
+          "status": "created"
+        },
+        "type": "network"
+      },
+      "subnet": "10.15.0.0/16"
+  },
+  {
+      "meta": {
+        "name": "default",
+        "properties": {
+          "status": "created"
+        },
+        "type": "image_cache"
+      }
+  },
+  {
+      "meta": {
+        "name": "container",
+        "checksum": {
+          "parsed": "abc123"
+        },
+        "properties": {
+          "status": "created"
+        },
+        "type": "container"
+      },
+      "image": {
+        "name": "test"
+      }
+  },
+  {
+      "meta": {
+        "name": "web",
+        "properties": {
+          "status": "created"
+        },
+        "type": "container"
+      },
+      "image": {
+        "name": "test"
+      }
+  }
+  ]
+}
+`
+
 var singleFileState = `
 {
   "resources": [
@@ -785,3 +1220,394 @@ var disabledAndCreatedState = `
   ]
 }
 `
+
+type eventRecorder struct {
+	events []events.Event
+	lock   sync.Mutex
+}
+
+func (r *eventRecorder) handle(e events.Event) {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	r.events = append(r.events, e)
+}
+
+func (r *eventRecorder) forResource(id string) []events.Event {
+	evs := []events.Event{}
+	for _, e := range r.events {
+		if e.ID == id {
+			evs = append(evs, e)
+		}
+	}
+
+	return evs
+}
+
+func TestApplyEmitsResourceEvents(t *testing.T) {
+	e, _ := setupTests(t, nil)
+
+	rec := &eventRecorder{}
+	e.SetEventHandler(rec.handle)
+
+	_, err := e.Apply(context.Background(), "../../examples/single_file/container.hcl")
+	require.NoError(t, err)
+
+	evs := rec.forResource("resource.network.onprem")
+	require.Len(t, evs, 2)
+
+	require.Equal(t, events.TypeStart, evs[0].Type)
+	require.Equal(t, events.OperationCreate, evs[0].Operation)
+	require.Equal(t, "network", evs[0].ResourceType)
+
+	require.Equal(t, events.TypeSuccess, evs[1].Type)
+	require.Equal(t, events.OperationCreate, evs[1].Operation)
+	require.Equal(t, constants.StatusCreated, evs[1].Status)
+	require.Empty(t, evs[1].Error)
+}
+
+func TestApplyRecordsSpansForProviderOperations(t *testing.T) {
+	e, _ := setupTests(t, map[string]error{"onprem": fmt.Errorf("boom")})
+
+	exp := tracetest.NewInMemoryExporter()
+	tp := sdktrace.NewTracerProvider(sdktrace.WithSyncer(exp))
+	e.SetTracerProvider(tp)
+
+	_, err := e.Apply(context.Background(), "../../examples/single_file/container.hcl")
+	require.Error(t, err)
+
+	spans := map[string]tracetest.SpanStub{}
+	for _, s := range exp.GetSpans() {
+		id := s.Name
+		for _, a := range s.Attributes {
+			if a.Key == tracing.AttributeResourceID {
+				id = s.Name + " " + a.Value.AsString()
+			}
+		}
+
+		spans[id] = s
+	}
+
+	root := spans["jumppad.apply"]
+	require.Equal(t, codes.Error, root.Status.Code)
+	require.False(t, root.Parent.IsValid())
+
+	for _, name := range []string{"jumppad.parse", "jumppad.diff", "provider.create resource.network.onprem"} {
+		require.Contains(t, spans, name)
+		require.Equal(t, root.SpanContext.SpanID(), spans[name].Parent.SpanID(), name)
+	}
+
+	require.Equal(t, codes.Error, spans["provider.create resource.network.onprem"].Status.Code)
+}
+
+func TestApplyEmitsFailureEvents(t *testing.T) {
+	e, _ := setupTests(t, map[string]error{"onprem": fmt.Errorf("boom")})
+
+	rec := &eventRecorder{}
+	e.SetEventHandler(rec.handle)
+
+	_, err := e.Apply(context.Background(), "../../examples/single_file/container.hcl")
+	require.Error(t, err)
+
+	evs := rec.forResource("resource.network.onprem")
+	require.Len(t, evs, 2)
+
+	require.Equal(t, events.TypeFailure, evs[1].Type)
+	require.Equal(t, constants.StatusFailed, evs[1].Status)
+	require.Equal(t, "boom", evs[1].Error)
+}
+
+func TestApplyEmitsRefreshEventsForCreatedResources(t *testing.T) {
+	e, _ := setupTestsWithState(t, nil, singleFileState)
+
+	rec := &eventRecorder{}
+	e.SetEventHandler(rec.handle)
+
+	_, err := e.Apply(context.Background(), "../../examples/single_file/container.hcl")
+	require.NoError(t, err)
+
+	evs := rec.forResource("resource.network.onprem")
+	require.Len(t, evs, 2)
+	require.Equal(t, events.OperationRefresh, evs[0].Operation)
+	require.Equal(t, events.TypeSuccess, evs[1].Type)
+}
+
+func TestDestroyEmitsResourceEvents(t *testing.T) {
+	e, _ := setupTestsWithState(t, nil, complexState)
+
+	rec := &eventRecorder{}
+	e.SetEventHandler(rec.handle)
+
+	err := e.Destroy(context.Background(), false)
+	require.NoError(t, err)
+
+	evs := rec.forResource("resource.container.mycontainer")
+	require.Len(t, evs, 2)
+
+	require.Equal(t, events.TypeStart, evs[0].Type)
+	require.Equal(t, events.OperationDestroy, evs[0].Operation)
+	require.Equal(t, events.TypeSuccess, evs[1].Type)
+	require.Equal(t, events.OperationDestroy, evs[1].Operation)
+}
+
+func TestApplyEmitsDestroyEventsForRemovedResources(t *testing.T) {
+	e, _ := setupTestsWithState(t, nil, complexState)
+
+	rec := &eventRecorder{}
+	e.SetEventHandler(rec.handle)
+
+	_, err := e.Apply(context.Background(), "../../examples/single_file/container.hcl")
+	require.NoError(t, err)
+
+	evs := rec.forResource("resource.container.mycontainer")
+	require.Len(t, evs, 2)
+
+	require.Equal(t, events.TypeStart, evs[0].Type)
+	require.Equal(t, events.OperationDestroy, evs[0].Operation)
+	require.Equal(t, events.TypeSuccess, evs[1].Type)
+	require.Equal(t, events.OperationDestroy, evs[1].Operation)
+}
+
+func TestApplyEmitsDestroyEventsForDisabledResources(t *testing.T) {
+	e, _ := setupTestsWithState(t, nil, disabledAndCreatedState)
+
+	rec := &eventRecorder{}
+	e.SetEventHandler(rec.handle)
+
+	_, err := e.Apply(context.Background(), "../../examples/disabled/config.hcl")
+	require.NoError(t, err)
+
+	evs := rec.forResource("resource.container.consul_disabled")
+	require.Len(t, evs, 2)
+
+	require.Equal(t, events.TypeStart, evs[0].Type)
+	require.Equal(t, events.OperationDestroy, evs[0].Operation)
+	require.Equal(t, events.TypeSuccess, evs[1].Type)
+	require.Equal(t, events.OperationDestroy, evs[1].Operation)
+}
+
+func TestAtomicApplyRollsBackCreatedResourcesOnFailure(t *testing.T) {
+	e, mp := setupTests(t, map[string]error{"consul": fmt.Errorf("boom")})
+	e.SetAtomic(true)
+
+	_, err := e.Apply(context.Background(), "../../examples/single_file/container.hcl")
+	require.ErrorContains(t, err, "rolled back")
+
+	// the network, template and failed container are destroyed
+	testAssertMethodCalled(t, mp, "Destroy", 3)
+
+	sf := testLoadState(t)
+	for _, r := range sf.Resources {
+		require.Contains(t, []string{"image_cache", "variable"}, r.Metadata().Type, r.Metadata().ID)
+	}
+}
+
+func TestAtomicApplyKeepsPreviousResourcesOnFailure(t *testing.T) {
+	e, _ := setupTests(t, nil)
+
+	cf := filepath.Join(t.TempDir(), "config.hcl")
+	err := os.WriteFile(cf, []byte(atomicConfig), 0644)
+	require.NoError(t, err)
+
+	_, err = e.Apply(context.Background(), cf)
+	require.NoError(t, err)
+
+	// add a container that fails to the configuration
+	mp := mocks.NewProviders(map[string]error{"web": fmt.Errorf("boom")})
+	mp.On("GetProvider", mock.Anything)
+
+	e.providers = mp
+	e.SetAtomic(true)
+
+	err = os.WriteFile(cf, []byte(atomicConfig+atomicConfigContainer), 0644)
+	require.NoError(t, err)
+
+	_, err = e.Apply(context.Background(), cf)
+	require.ErrorContains(t, err, "rolled back")
+
+	// the network existed before the apply and is not destroyed
+	for i := range mp.Providers {
+		if getMetaFromMock(mp, i).Name == "private" {
+			mp.Providers[i].AssertNotCalled(t, "Destroy", mock.Anything, mock.Anything)
+		}
+	}
+
+	sf := testLoadState(t)
+	r, err := sf.FindResource("resource.network.private")
+	require.NoError(t, err)
+	require.Equal(t, constants.StatusCreated, r.Metadata().Properties[constants.PropertyStatus])
+
+	_, err = sf.FindResource("resource.container.web")
+	require.Error(t, err)
+}
+
+func TestAtomicApplyRollsBackWhenCancelled(t *testing.T) {
+	e, _ := setupTestsWithState(t, nil, atomicState)
+	e.SetAtomic(true)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	_, err := e.Apply(ctx, "../../examples/single_file/container.hcl")
+	require.ErrorIs(t, err, ErrInterrupted)
+	require.ErrorContains(t, err, "rolled back")
+
+	sf := testLoadState(t)
+	_, err = sf.FindResource("resource.network.onprem")
+	require.NoError(t, err)
+}
+
+func TestApplyWithoutAtomicLeavesFailedResources(t *testing.T) {
+	e, _ := setupTests(t, map[string]error{"consul": fmt.Errorf("boom")})
+
+	_, err := e.Apply(context.Background(), "../../examples/single_file/container.hcl")
+	require.Error(t, err)
+	require.NotContains(t, err.Error(), "rolled back")
+
+	sf := testLoadState(t)
+	r, err := sf.FindResource("resource.container.consul")
+	require.NoError(t, err)
+	require.Equal(t, constants.StatusFailed, r.Metadata().Properties[constants.PropertyStatus])
+}
+
+var atomicConfig = `
+resource "network" "private" {
+  subnet = "10.7.0.0/16"
+}
+`
+
+var atomicConfigContainer = `
+resource "container" "web" {
+  image {
+    name = "nginx"
+  }
+
+  network {
+    id = resource.network.private.meta.id
+  }
+}
+`
+
+var atomicState = `
+{
+  "resources": [
+  {
+      "meta": {
+        "id": "resource.network.onprem",
+        "name": "onprem",
+        "properties": {
+          "status": "created"
+        },
+        "type": "network"
+      },
+      "subnet": "10.6.0.0/16"
+  },
+  {
+      "meta": {
+        "id": "resource.image_cache.default",
+        "name": "default",
+        "properties": {
+          "status": "created"
+        },
+        "type": "image_cache"
+      }
+  }
+  ]
+}
+`
+
+func TestApplyMarksInterruptedResourcesAsFailed(t *testing.T) {
+	e, _ := setupTests(t, nil)
+
+	// cancel the context once the network has started to be created
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	e.SetEventHandler(func(ev events.Event) {
+		if ev.ID == "resource.network.onprem" && ev.Type == events.TypeStart {
+			cancel()
+		}
+	})
+
+	_, err := e.Apply(ctx, "../../examples/single_file/container.hcl")
+	require.ErrorIs(t, err, ErrInterrupted)
+
+	ie := &InterruptedError{}
+	require.ErrorAs(t, err, &ie)
+	require.Contains(t, ie.Interrupted, "resource.network.onprem")
+
+	sf := testLoadState(t)
+	r, err := sf.FindResource("resource.network.onprem")
+	require.NoError(t, err)
+	require.Equal(t, constants.StatusFailed, r.Metadata().Properties[constants.PropertyStatus])
+
+	// the container depends on the network and is never started
+	_, err = sf.FindResource("resource.container.consul")
+	require.Error(t, err)
+}
+
+func TestDestroyKeepsStateWhenInterrupted(t *testing.T) {
+	e, _ := setupTestsWithState(t, nil, complexState)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	e.SetEventHandler(func(ev events.Event) {
+		if ev.ID == "resource.container.mycontainer" && ev.Type == events.TypeStart {
+			cancel()
+		}
+	})
+
+	err := e.Destroy(ctx, false)
+	require.ErrorIs(t, err, ErrInterrupted)
+	require.FileExists(t, utils.StatePath())
+
+	sf := testLoadState(t)
+	r, err := sf.FindResource("resource.container.mycontainer")
+	require.NoError(t, err)
+	require.Equal(t, constants.StatusFailed, r.Metadata().Properties[constants.PropertyStatus])
+
+	// resources the container depends on are not destroyed
+	r, err = sf.FindResource("resource.network.cloud")
+	require.NoError(t, err)
+	require.Equal(t, constants.StatusCreated, r.Metadata().Properties[constants.PropertyStatus])
+}
+
+func TestApplyAddsSensitiveValuesToRedactor(t *testing.T) {
+	e, _ := setupTests(t, nil)
+	e.redactor = logger.NewRedactor()
+
+	dir := t.TempDir()
+	err := os.WriteFile(filepath.Join(dir, "main.hcl"), []byte(`
+variable "registry_password" {
+  default = sensitive("default-password")
+}
+
+resource "container" "app" {
+  image {
+    name     = "private/app:latest"
+    username = "admin"
+    password = variable.registry_password
+  }
+}
+
+output "token" {
+  value = sensitive("output-token")
+}
+`), 0644)
+	require.NoError(t, err)
+
+	vars := map[string]string{"registry_password": "cli-password"}
+	_, err = e.ApplyWithVariables(context.Background(), dir, vars, "")
+	require.NoError(t, err)
+
+	out := e.redactor.Redact("cli-password default-password output-token admin")
+	require.Equal(t, "(sensitive) (sensitive) (sensitive) admin", out)
+
+	// sensitive values in the state are redacted by later operations
+	e.redactor = logger.NewRedactor()
+	err = e.Destroy(context.Background(), false)
+	require.NoError(t, err)
+
+	require.Equal(t, "(sensitive) (sensitive)", e.redactor.Redact("cli-password output-token"))
+}
@@ -0,0 +1,145 @@
+package events
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"sync"
+	"time"
+)
+
+// Type is the type of an event
+type Type string
+
+const (
+	// TypeStart is emitted when an operation on a resource starts
+	TypeStart Type = "start"
+
+	// TypeSuccess is emitted when an operation on a resource completes
+	TypeSuccess Type = "success"
+
+	// TypeFailure is emitted when an operation on a resource fails, the
+	// event contains the error returned by the provider
+	TypeFailure Type = "failure"
+
+	// TypeHealthCheck is emitted by providers to report the progress of
+	// a health check
+	TypeHealthCheck Type = "health_check"
+)
+
+// Operation is the action the engine is performing on a resource
+type Operation string
+
+const (
+	// OperationCreate creates a resource that does not exist
+	OperationCreate Operation = "create"
+
+	// OperationRefresh updates a resource that has already been created
+	OperationRefresh Operation = "refresh"
+
+	// OperationDestroy removes a resource
+	OperationDestroy Operation = "destroy"
+
+	// OperationStop stops the containers of a resource without removing them
+	OperationStop Operation = "stop"
+
+	// OperationStart starts the containers of a stopped resource
+	OperationStart Operation = "start"
+
+	// OperationSnapshot saves the containers of a resource to a snapshot
+	OperationSnapshot Operation = "snapshot"
+
+	// OperationRestore creates a resource from a snapshot
+	OperationRestore Operation = "restore"
+)
+
+// Event describes a change to a resource
+type Event struct {
+	Time      time.Time `json:"time"`
+	Type      Type      `json:"type"`
+	Operation Operation `json:"operation,omitempty"`
+
+	// ID is the fully qualified resource name of the resource
+	ID           string `json:"id"`
+	ResourceType string `json:"resource_type"`
+
+	// Status is the status of the resource after the event
+	Status string `json:"status,omitempty"`
+
+	// Duration is the time the operation took, it is only set for
+	// success and failure events
+	Duration time.Duration `json:"-"`
+
+	Error   string `json:"error,omitempty"`
+	Message string `json:"message,omitempty"`
+}
+
+// MarshalJSON writes the duration as milliseconds so that it can be read
+// without knowledge of Go durations
+func (e Event) MarshalJSON() ([]byte, error) {
+	type event Event
+
+	return json.Marshal(struct {
+		event
+		Duration int64 `json:"duration_ms,omitempty"`
+	}{
+		event:    event(e),
+		Duration: e.Duration.Milliseconds(),
+	})
+}
+
+// Handler is called for every event emitted by the engine, handlers can
+// be called concurrently
+type Handler func(e Event)
+
+// NewJSONLinesHandler returns a handler that writes each event as a single
+// line of JSON to the given writer
+func NewJSONLinesHandler(w io.Writer) Handler {
+	lock := sync.Mutex{}
+	enc := json.NewEncoder(w)
+
+	return func(e Event) {
+		lock.Lock()
+		defer lock.Unlock()
+
+		enc.Encode(e)
+	}
+}
+
+type contextKey struct{}
+
+// NewContext returns a context that carries the given handler, providers
+// use the context to emit events while they are creating a resource
+func NewContext(ctx context.Context, h Handler) context.Context {
+	if h == nil {
+		return ctx
+	}
+
+	return context.WithValue(ctx, contextKey{}, h)
+}
+
+// Emit sends the event to the handler in the context, the event is
+// discarded when the context does not contain a handler
+func Emit(ctx context.Context, e Event) {
+	h, ok := ctx.Value(contextKey{}).(Handler)
+	if !ok || h == nil {
+		return
+	}
+
+	if e.Time.IsZero() {
+		e.Time = time.Now()
+	}
+
+	h(e)
+}
+
+// HealthCheck emits a health check event for the resource with the
+// given ID and type
+func HealthCheck(ctx context.Context, id, resourceType, message string) {
+	Emit(ctx, Event{
+		Type:         TypeHealthCheck,
+		ID:           id,
+		ResourceType: resourceType,
+		Message:      message,
+	})
+}
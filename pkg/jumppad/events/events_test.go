@@ -0,0 +1,56 @@
+package events
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestJSONLinesHandlerWritesEventPerLine(t *testing.T) {
+	out := bytes.NewBuffer(nil)
+	h := NewJSONLinesHandler(out)
+
+	h(Event{Type: TypeStart, Operation: OperationCreate, ID: "resource.container.web", ResourceType: "container"})
+	h(Event{Type: TypeFailure, Operation: OperationCreate, ID: "resource.container.web", ResourceType: "container", Status: "failed", Duration: 1500 * time.Millisecond, Error: "boom"})
+
+	lines := strings.Split(strings.TrimSpace(out.String()), "\n")
+	require.Len(t, lines, 2)
+
+	ev := map[string]interface{}{}
+	err := json.Unmarshal([]byte(lines[1]), &ev)
+	require.NoError(t, err)
+
+	require.Equal(t, "failure", ev["type"])
+	require.Equal(t, "create", ev["operation"])
+	require.Equal(t, "resource.container.web", ev["id"])
+	require.Equal(t, "container", ev["resource_type"])
+	require.Equal(t, "failed", ev["status"])
+	require.Equal(t, float64(1500), ev["duration_ms"])
+	require.Equal(t, "boom", ev["error"])
+}
+
+func TestEmitSendsEventToHandlerInContext(t *testing.T) {
+	received := []Event{}
+	ctx := NewContext(context.Background(), func(e Event) {
+		received = append(received, e)
+	})
+
+	HealthCheck(ctx, "resource.container.web", "container", "waiting")
+
+	require.Len(t, received, 1)
+	require.Equal(t, TypeHealthCheck, received[0].Type)
+	require.Equal(t, "waiting", received[0].Message)
+	require.False(t, received[0].Time.IsZero())
+}
+
+func TestEmitWithoutHandlerDoesNothing(t *testing.T) {
+	require.NotPanics(t, func() {
+		Emit(context.Background(), Event{Type: TypeStart})
+		Emit(NewContext(context.Background(), nil), Event{Type: TypeStart})
+	})
+}
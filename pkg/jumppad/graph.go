@@ -0,0 +1,326 @@
+package jumppad
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/jumppad-labs/hclconfig"
+	"github.com/jumppad-labs/hclconfig/resources"
+	"github.com/jumppad-labs/hclconfig/types"
+	"github.com/jumppad-labs/jumppad/pkg/jumppad/constants"
+)
+
+// Graph is the dependency graph of the resources in a configuration
+type Graph struct {
+	Nodes []*GraphNode `json:"nodes"`
+
+	// Edges point from a resource to the resource it depends on
+	Edges []*GraphEdge `json:"edges"`
+}
+
+// GraphNode is a resource in the dependency graph
+type GraphNode struct {
+	ID       string `json:"id"`
+	Type     string `json:"type"`
+	Module   string `json:"module,omitempty"`
+	Disabled bool   `json:"disabled"`
+
+	// Status is the status of the resource in the state, empty when the
+	// resource is not in the state
+	Status string `json:"status,omitempty"`
+
+	// Highlighted is set for the resources related to the resource passed
+	// to Highlight
+	Highlighted bool `json:"highlighted,omitempty"`
+}
+
+// GraphEdge is a dependency between two resources, From depends on To
+type GraphEdge struct {
+	From        string `json:"from"`
+	To          string `json:"to"`
+	Highlighted bool   `json:"highlighted,omitempty"`
+}
+
+// Graph parses the configuration at path and returns the dependency graph
+// of its resources, nodes are annotated with the status of the resource in
+// the state
+func (e *EngineImpl) Graph(path string, variables map[string]string, variablesFile string) (*Graph, error) {
+	parsed, err := e.ParseConfigWithVariables(path, variables, variablesFile)
+	if err != nil {
+		return nil, err
+	}
+
+	state, err := e.loadState()
+	if err != nil {
+		e.log.Debug("Unable to load state", "error", err)
+	}
+
+	return newGraph(parsed, state), nil
+}
+
+func newGraph(c, state *hclconfig.Config) *Graph {
+	g := &Graph{Nodes: []*GraphNode{}, Edges: []*GraphEdge{}}
+	nodes := map[string]bool{}
+
+	for _, r := range c.Resources {
+		n := &GraphNode{
+			ID:       r.Metadata().ID,
+			Type:     r.Metadata().Type,
+			Module:   r.Metadata().Module,
+			Disabled: r.GetDisabled(),
+		}
+
+		if state != nil {
+			if sr, err := state.FindResource(n.ID); err == nil {
+				n.Status, _ = sr.Metadata().Properties[constants.PropertyStatus].(string)
+			}
+		}
+
+		g.Nodes = append(g.Nodes, n)
+		nodes[n.ID] = true
+	}
+
+	edges := map[string]bool{}
+	for _, r := range c.Resources {
+		for _, d := range graphDependencies(r) {
+			key := r.Metadata().ID + " " + d
+			if !nodes[d] || d == r.Metadata().ID || edges[key] {
+				continue
+			}
+
+			edges[key] = true
+			g.Edges = append(g.Edges, &GraphEdge{From: r.Metadata().ID, To: d})
+		}
+	}
+
+	sort.Slice(g.Nodes, func(i, j int) bool { return g.Nodes[i].ID < g.Nodes[j].ID })
+	sort.Slice(g.Edges, func(i, j int) bool {
+		if g.Edges[i].From != g.Edges[j].From {
+			return g.Edges[i].From < g.Edges[j].From
+		}
+
+		return g.Edges[i].To < g.Edges[j].To
+	})
+
+	return g
+}
+
+// graphDependencies returns the IDs of the resources and modules that the
+// resource depends on, unlike resourceDependencies modules are not expanded
+// to the resources they contain
+func graphDependencies(r types.Resource) []string {
+	ids := []string{}
+
+	refs := append([]string{}, r.GetDependencies()...)
+	refs = append(refs, r.Metadata().Links...)
+
+	for _, d := range refs {
+		fqrn, err := resources.ParseFQRN(d)
+		if err != nil {
+			continue
+		}
+
+		rel := fqrn.AppendParentModule(r.Metadata().Module)
+		if rel.Type == resources.TypeModule {
+			ids = append(ids, rel.String())
+			continue
+		}
+
+		ids = append(ids, rel.StringWithoutAttribute())
+	}
+
+	return ids
+}
+
+// Highlight marks the resource with the given id, the resources it depends
+// on and the resources that depend on it, along with the edges between them
+func (g *Graph) Highlight(id string) error {
+	nodes := map[string]*GraphNode{}
+	for _, n := range g.Nodes {
+		nodes[n.ID] = n
+	}
+
+	n, ok := nodes[id]
+	if !ok {
+		return fmt.Errorf("resource '%s' does not exist in the configuration", id)
+	}
+
+	n.Highlighted = true
+
+	// walk the dependencies and the dependents separately so that siblings
+	// that share a dependency are not highlighted
+	g.highlightFrom(id, nodes, func(e *GraphEdge) (string, string) { return e.From, e.To })
+	g.highlightFrom(id, nodes, func(e *GraphEdge) (string, string) { return e.To, e.From })
+
+	return nil
+}
+
+func (g *Graph) highlightFrom(id string, nodes map[string]*GraphNode, direction func(e *GraphEdge) (string, string)) {
+	visited := map[string]bool{id: true}
+	next := []string{id}
+
+	for len(next) > 0 {
+		current := next[0]
+		next = next[1:]
+
+		for _, e := range g.Edges {
+			from, to := direction(e)
+			if from != current {
+				continue
+			}
+
+			e.Highlighted = true
+			nodes[to].Highlighted = true
+
+			if !visited[to] {
+				visited[to] = true
+				next = append(next, to)
+			}
+		}
+	}
+}
+
+// DOT returns the graph in the Graphviz DOT format, the resources in a
+// module are grouped in a cluster
+func (g *Graph) DOT() string {
+	sb := &strings.Builder{}
+	sb.WriteString("digraph jumppad {\n")
+	sb.WriteString("  rankdir = \"RL\";\n")
+	sb.WriteString("  node [shape = \"box\"];\n")
+
+	modules, grouped := g.modules()
+	for _, n := range grouped[""] {
+		fmt.Fprintf(sb, "  %s\n", dotNode(n))
+	}
+
+	for i, m := range modules {
+		fmt.Fprintf(sb, "  subgraph \"cluster_%d\" {\n", i)
+		fmt.Fprintf(sb, "    label = %q;\n", "module."+m)
+
+		for _, n := range grouped[m] {
+			fmt.Fprintf(sb, "    %s\n", dotNode(n))
+		}
+
+		sb.WriteString("  }\n")
+	}
+
+	for _, e := range g.Edges {
+		attrs := ""
+		if e.Highlighted {
+			attrs = " [color = \"red\", penwidth = 2]"
+		}
+
+		fmt.Fprintf(sb, "  %q -> %q%s;\n", e.From, e.To, attrs)
+	}
+
+	sb.WriteString("}\n")
+
+	return sb.String()
+}
+
+func dotNode(n *GraphNode) string {
+	attrs := []string{fmt.Sprintf("label = %q", strings.Join(nodeLabel(n), "\n"))}
+
+	if n.Disabled {
+		attrs = append(attrs, `style = "dashed"`, `fontcolor = "gray"`)
+	}
+
+	if n.Highlighted {
+		attrs = append(attrs, `color = "red"`, "penwidth = 2")
+	}
+
+	return fmt.Sprintf("%q [%s];", n.ID, strings.Join(attrs, ", "))
+}
+
+// Mermaid returns the graph as a Mermaid flowchart, the resources in a
+// module are grouped in a subgraph
+func (g *Graph) Mermaid() string {
+	ids := map[string]string{}
+	for i, n := range g.Nodes {
+		ids[n.ID] = fmt.Sprintf("n%d", i)
+	}
+
+	sb := &strings.Builder{}
+	sb.WriteString("flowchart RL\n")
+
+	modules, grouped := g.modules()
+	for _, n := range grouped[""] {
+		fmt.Fprintf(sb, "  %s\n", mermaidNode(ids[n.ID], n))
+	}
+
+	for i, m := range modules {
+		fmt.Fprintf(sb, "  subgraph m%d[\"module.%s\"]\n", i, m)
+
+		for _, n := range grouped[m] {
+			fmt.Fprintf(sb, "    %s\n", mermaidNode(ids[n.ID], n))
+		}
+
+		sb.WriteString("  end\n")
+	}
+
+	for _, e := range g.Edges {
+		fmt.Fprintf(sb, "  %s --> %s\n", ids[e.From], ids[e.To])
+	}
+
+	sb.WriteString("  classDef disabled stroke-dasharray: 5 5,color:#999\n")
+	sb.WriteString("  classDef highlighted stroke:#f00,stroke-width:2px\n")
+
+	for _, n := range g.Nodes {
+		if n.Disabled {
+			fmt.Fprintf(sb, "  class %s disabled\n", ids[n.ID])
+		}
+
+		if n.Highlighted {
+			fmt.Fprintf(sb, "  class %s highlighted\n", ids[n.ID])
+		}
+	}
+
+	for i, e := range g.Edges {
+		if e.Highlighted {
+			fmt.Fprintf(sb, "  linkStyle %d stroke:#f00,stroke-width:2px\n", i)
+		}
+	}
+
+	return sb.String()
+}
+
+func mermaidNode(id string, n *GraphNode) string {
+	// quotes can not be escaped in mermaid labels
+	label := strings.ReplaceAll(strings.Join(nodeLabel(n), "<br/>"), `"`, "'")
+	return fmt.Sprintf("%s[\"%s\"]", id, label)
+}
+
+// nodeLabel returns the lines of the label for a node
+func nodeLabel(n *GraphNode) []string {
+	lines := []string{n.ID}
+
+	if n.Disabled {
+		lines = append(lines, "disabled")
+	}
+
+	if n.Status != "" {
+		lines = append(lines, "status: "+n.Status)
+	}
+
+	return lines
+}
+
+// modules returns the sorted names of the modules in the graph and the
+// nodes grouped by module, nodes not in a module are grouped under ""
+func (g *Graph) modules() ([]string, map[string][]*GraphNode) {
+	modules := []string{}
+	grouped := map[string][]*GraphNode{}
+
+	for _, n := range g.Nodes {
+		if _, ok := grouped[n.Module]; !ok && n.Module != "" {
+			modules = append(modules, n.Module)
+		}
+
+		grouped[n.Module] = append(grouped[n.Module], n)
+	}
+
+	sort.Strings(modules)
+
+	return modules, grouped
+}
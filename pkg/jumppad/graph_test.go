@@ -0,0 +1,150 @@
+package jumppad
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/jumppad-labs/jumppad/pkg/jumppad/constants"
+	"github.com/stretchr/testify/require"
+)
+
+const graphModuleConfig = `
+module "app" {
+  source = "./app"
+}
+
+resource "random_number" "port" {
+  depends_on = ["module.app"]
+
+  minimum = 1
+  maximum = 10
+}
+`
+
+const graphModuleResources = `
+resource "random_id" "id" {
+  byte_length = 4
+}
+`
+
+func findGraphNode(g *Graph, id string) *GraphNode {
+	for _, n := range g.Nodes {
+		if n.ID == id {
+			return n
+		}
+	}
+
+	return nil
+}
+
+func findGraphEdge(g *Graph, from, to string) *GraphEdge {
+	for _, e := range g.Edges {
+		if e.From == from && e.To == to {
+			return e
+		}
+	}
+
+	return nil
+}
+
+func TestGraphReturnsDependencies(t *testing.T) {
+	e, _ := setupTests(t, nil)
+
+	g, err := e.Graph("../../examples/single_file", nil, "")
+	require.NoError(t, err)
+
+	n := findGraphNode(g, "resource.container.consul")
+	require.NotNil(t, n)
+	require.Equal(t, "container", n.Type)
+	require.Empty(t, n.Status)
+
+	require.NotNil(t, findGraphEdge(g, "resource.container.consul", "resource.network.onprem"))
+	require.NotNil(t, findGraphEdge(g, "resource.container.consul", "resource.template.consul_config"))
+	require.NotNil(t, findGraphEdge(g, "output.consul_addr", "resource.container.consul"))
+}
+
+func TestGraphAddsStatusFromState(t *testing.T) {
+	e, _ := setupTests(t, nil)
+
+	_, err := e.Apply(context.Background(), "../../examples/single_file")
+	require.NoError(t, err)
+
+	g, err := e.Graph("../../examples/single_file", nil, "")
+	require.NoError(t, err)
+
+	n := findGraphNode(g, "resource.container.consul")
+	require.Equal(t, constants.StatusCreated, n.Status)
+}
+
+func TestGraphMarksDisabledResources(t *testing.T) {
+	e, _ := setupTests(t, nil)
+
+	g, err := e.Graph("../../examples/disabled", nil, "")
+	require.NoError(t, err)
+
+	require.True(t, findGraphNode(g, "resource.container.consul_disabled").Disabled)
+	require.False(t, findGraphNode(g, "resource.container.consul_enabled").Disabled)
+
+	require.Contains(t, g.DOT(), `"resource.container.consul_disabled" [label = "resource.container.consul_disabled\ndisabled", style = "dashed"`)
+}
+
+func TestGraphGroupsModules(t *testing.T) {
+	dir := t.TempDir()
+	err := os.WriteFile(filepath.Join(dir, "main.hcl"), []byte(graphModuleConfig), 0644)
+	require.NoError(t, err)
+
+	err = os.MkdirAll(filepath.Join(dir, "app"), 0755)
+	require.NoError(t, err)
+
+	err = os.WriteFile(filepath.Join(dir, "app", "main.hcl"), []byte(graphModuleResources), 0644)
+	require.NoError(t, err)
+
+	e, _ := setupTests(t, nil)
+
+	g, err := e.Graph(dir, nil, "")
+	require.NoError(t, err)
+
+	n := findGraphNode(g, "module.app.resource.random_id.id")
+	require.NotNil(t, n)
+	require.Equal(t, "app", n.Module)
+
+	require.NotNil(t, findGraphEdge(g, "resource.random_number.port", "module.app"))
+
+	require.Contains(t, g.DOT(), "label = \"module.app\";")
+	require.Contains(t, g.Mermaid(), "subgraph m0[\"module.app\"]")
+}
+
+func TestGraphHighlightMarksRelatedResources(t *testing.T) {
+	e, _ := setupTests(t, nil)
+
+	g, err := e.Graph("../../examples/single_file", nil, "")
+	require.NoError(t, err)
+
+	err = g.Highlight("resource.network.onprem")
+	require.NoError(t, err)
+
+	// the network, the container that depends on it and the output that
+	// depends on the container
+	require.True(t, findGraphNode(g, "resource.network.onprem").Highlighted)
+	require.True(t, findGraphNode(g, "resource.container.consul").Highlighted)
+	require.True(t, findGraphNode(g, "output.consul_addr").Highlighted)
+
+	// the template is a dependency of the container not the network
+	require.False(t, findGraphNode(g, "resource.template.consul_config").Highlighted)
+	require.False(t, findGraphEdge(g, "resource.container.consul", "resource.template.consul_config").Highlighted)
+	require.True(t, findGraphEdge(g, "resource.container.consul", "resource.network.onprem").Highlighted)
+
+	require.Contains(t, g.Mermaid(), "linkStyle 1 stroke:#f00")
+}
+
+func TestGraphHighlightReturnsErrorWhenResourceNotFound(t *testing.T) {
+	e, _ := setupTests(t, nil)
+
+	g, err := e.Graph("../../examples/single_file", nil, "")
+	require.NoError(t, err)
+
+	err = g.Highlight("resource.container.missing")
+	require.Error(t, err)
+}
@@ -0,0 +1,123 @@
+package jumppad
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/jumppad-labs/hclconfig"
+	"github.com/jumppad-labs/hclconfig/resources"
+	"github.com/jumppad-labs/hclconfig/types"
+	"github.com/jumppad-labs/jumppad/pkg/config"
+	"github.com/jumppad-labs/jumppad/pkg/jumppad/constants"
+	"github.com/jumppad-labs/jumppad/pkg/jumppad/events"
+	"github.com/jumppad-labs/jumppad/pkg/jumppad/tracing"
+	"go.opentelemetry.io/otel/attribute"
+)
+
+// Import adopts an object that was created outside of jumppad, such as a
+// Docker container, as the resource with the given id in the configuration.
+// The provider of the resource inspects the object to set the computed
+// fields and the resource is added to the state with the created status so
+// that the next Apply manages the object instead of creating a new one.
+func (e *EngineImpl) Import(ctx context.Context, path string, variables map[string]string, variablesFile string, id string, objectID string) (types.Resource, error) {
+	ctx, span := tracing.StartRoot(ctx, e.tracer, "jumppad.import",
+		attribute.String(tracing.AttributeResourceID, id),
+	)
+
+	r, err := e.importResource(ctx, path, variables, variablesFile, id, objectID)
+	tracing.End(span, err)
+
+	return r, err
+}
+
+func (e *EngineImpl) importResource(ctx context.Context, path string, variables map[string]string, variablesFile string, id string, objectID string) (types.Resource, error) {
+	e.ctx = events.NewContext(ctx, e.events)
+
+	parsed, err := e.ParseConfigWithVariables(path, variables, variablesFile)
+	if err != nil {
+		return nil, err
+	}
+
+	r, err := parsed.FindResource(id)
+	if err != nil {
+		return nil, fmt.Errorf("unable to find resource %s in the configuration", id)
+	}
+
+	if r.GetDisabled() {
+		return nil, fmt.Errorf("unable to import resource %s, the resource is disabled", id)
+	}
+
+	c, err := e.loadState()
+	if err != nil {
+		e.log.Debug("Unable to load state", "error", err)
+		c = hclconfig.NewConfig()
+	}
+
+	e.config = c
+	e.redactor.Add(config.SensitiveConfigValues(c)...)
+
+	// resources that failed or are pending can be replaced by the imported
+	// object, created resources are already managed by jumppad
+	if sr, err := c.FindResource(id); err == nil {
+		if sr.Metadata().Properties[constants.PropertyStatus] == constants.StatusCreated {
+			return nil, fmt.Errorf("resource %s already exists in the state", id)
+		}
+
+		err = c.RemoveResource(sr)
+		if err != nil {
+			return nil, fmt.Errorf(`unable to remove resource "%s" from state, %s`, id, err)
+		}
+	}
+
+	p := e.providers.GetProvider(r)
+	if p == nil {
+		return nil, fmt.Errorf("unable to create provider for resource Name: %s, Type: %s", r.Metadata().Name, r.Metadata().Type)
+	}
+
+	im, ok := p.(config.Importer)
+	if !ok {
+		return nil, fmt.Errorf("resources of type %s do not support import", r.Metadata().Type)
+	}
+
+	e.log.Info("Importing resource", "ref", id, "object", objectID)
+
+	pctx, span := tracing.Start(e.ctx, "provider.import",
+		attribute.String(tracing.AttributeResourceID, id),
+		attribute.String(tracing.AttributeResourceType, r.Metadata().Type),
+	)
+
+	err = im.Import(pctx, objectID)
+	tracing.End(span, err)
+	if err != nil {
+		return nil, fmt.Errorf("unable to import %s as resource %s: %s", objectID, id, err)
+	}
+
+	// resources that the imported resource depends on are created by the
+	// next Apply unless they are imported first
+	for _, d := range r.GetDependencies() {
+		fqrn, err := resources.ParseFQRN(d)
+		if err != nil || fqrn.Type == resources.TypeModule {
+			continue
+		}
+
+		dep := fqrn.AppendParentModule(r.Metadata().Module)
+		if _, err := c.FindResource(dep.StringWithoutAttribute()); err != nil {
+			e.log.Warn("Dependency of imported resource is not in the state, it will be created by the next apply", "ref", id, "dependency", dep.StringWithoutAttribute())
+		}
+	}
+
+	r.Metadata().Properties[constants.PropertyStatus] = constants.StatusCreated
+	e.redactor.Add(config.SensitiveValues(r)...)
+
+	err = c.AppendResource(r)
+	if err != nil {
+		return nil, fmt.Errorf(`unable add resource "%s" to state, %s`, id, err)
+	}
+
+	err = e.saveState(c)
+	if err != nil {
+		return nil, fmt.Errorf("unable to save state: %s", err)
+	}
+
+	return r, nil
+}
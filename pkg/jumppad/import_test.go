@@ -0,0 +1,79 @@
+package jumppad
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/jumppad-labs/jumppad/pkg/jumppad/constants"
+	"github.com/jumppad-labs/jumppad/testutils"
+	"github.com/stretchr/testify/require"
+)
+
+func TestImportAddsResourceToState(t *testing.T) {
+	e, mp := setupTests(t, nil)
+
+	r, err := e.Import(context.Background(), "../../examples/single_file", nil, "", "resource.container.consul", "consul-dev")
+	require.NoError(t, err)
+	require.Equal(t, "resource.container.consul", r.Metadata().ID)
+
+	testAssertMethodCalled(t, mp, "Import", 1)
+	testAssertMethodCalled(t, mp, "Create", 0)
+
+	calls := testutils.GetCalls(&mp.Providers[0].Mock, "Import")
+	require.Equal(t, "consul-dev", calls[0].Arguments[1])
+
+	sf := testLoadState(t)
+	r, err = sf.FindResource("resource.container.consul")
+	require.NoError(t, err)
+	require.Equal(t, constants.StatusCreated, r.Metadata().Properties[constants.PropertyStatus])
+}
+
+func TestApplyDoesNotCreateImportedResource(t *testing.T) {
+	e, mp := setupTests(t, nil)
+
+	_, err := e.Import(context.Background(), "../../examples/single_file", nil, "", "resource.container.consul", "consul-dev")
+	require.NoError(t, err)
+
+	_, err = e.Apply(context.Background(), "../../examples/single_file")
+	require.NoError(t, err)
+
+	// the imported container is refreshed instead of created
+	refreshed := 0
+	for i, p := range mp.Providers {
+		if getMetaFromMock(mp, i).ID != "resource.container.consul" {
+			continue
+		}
+
+		require.Empty(t, testutils.GetCalls(&p.Mock, "Create"))
+		refreshed += len(testutils.GetCalls(&p.Mock, "Refresh"))
+	}
+
+	require.Equal(t, 1, refreshed)
+}
+
+func TestImportReturnsErrorWhenResourceInState(t *testing.T) {
+	e, _ := setupTests(t, nil)
+
+	_, err := e.Import(context.Background(), "../../examples/single_file", nil, "", "resource.container.consul", "consul-dev")
+	require.NoError(t, err)
+
+	_, err = e.Import(context.Background(), "../../examples/single_file", nil, "", "resource.container.consul", "consul-dev")
+	require.ErrorContains(t, err, "already exists in the state")
+}
+
+func TestImportReturnsErrorWhenResourceNotInConfig(t *testing.T) {
+	e, mp := setupTests(t, nil)
+
+	_, err := e.Import(context.Background(), "../../examples/single_file", nil, "", "resource.container.missing", "consul-dev")
+	require.Error(t, err)
+
+	testAssertMethodCalled(t, mp, "Import", 0)
+}
+
+func TestImportReturnsProviderError(t *testing.T) {
+	e, _ := setupTests(t, map[string]error{"consul": fmt.Errorf("boom")})
+
+	_, err := e.Import(context.Background(), "../../examples/single_file", nil, "", "resource.container.consul", "consul-dev")
+	require.ErrorContains(t, err, "boom")
+}
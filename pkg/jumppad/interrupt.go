@@ -0,0 +1,79 @@
+package jumppad
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/jumppad-labs/hclconfig/types"
+	"github.com/jumppad-labs/jumppad/pkg/jumppad/constants"
+)
+
+// ErrInterrupted is returned when an operation is stopped because its
+// context was cancelled
+var ErrInterrupted = errors.New("operation interrupted")
+
+// InterruptedError is returned by Apply and Destroy when the context is
+// cancelled, resources that had not been started are left unchanged
+type InterruptedError struct {
+	// Interrupted contains the IDs of the resources that were being
+	// created or destroyed when the operation was cancelled, these
+	// resources are marked as failed in the state
+	Interrupted []string
+}
+
+func (e *InterruptedError) Error() string {
+	if len(e.Interrupted) == 0 {
+		return fmt.Sprintf("%s, no resources were in progress", ErrInterrupted)
+	}
+
+	return fmt.Sprintf(
+		"%s, %d resources were in progress and have been marked as failed: %s",
+		ErrInterrupted,
+		len(e.Interrupted),
+		strings.Join(e.Interrupted, ", "),
+	)
+}
+
+func (e *InterruptedError) Unwrap() error {
+	return ErrInterrupted
+}
+
+// markInterrupted marks a resource whose provider was running when the
+// context was cancelled as failed, the provider may have stopped part way
+// through so the resource can not be trusted
+func (e *EngineImpl) markInterrupted(r types.Resource) {
+	e.log.Info("Resource was interrupted and has been marked as failed", "ref", r.Metadata().ID)
+	r.Metadata().Properties[constants.PropertyStatus] = constants.StatusFailed
+
+	e.interruptLock.Lock()
+	defer e.interruptLock.Unlock()
+
+	e.interrupted = append(e.interrupted, r.Metadata().ID)
+}
+
+// interruptedError returns an InterruptedError when the given context has
+// been cancelled, otherwise nil is returned
+func (e *EngineImpl) interruptedError(ctx context.Context) error {
+	if ctx.Err() == nil {
+		return nil
+	}
+
+	e.interruptLock.Lock()
+	defer e.interruptLock.Unlock()
+
+	ids := append([]string{}, e.interrupted...)
+	sort.Strings(ids)
+
+	return &InterruptedError{Interrupted: ids}
+}
+
+// resetInterrupted clears the resources interrupted by a previous operation
+func (e *EngineImpl) resetInterrupted() {
+	e.interruptLock.Lock()
+	defer e.interruptLock.Unlock()
+
+	e.interrupted = nil
+}
@@ -0,0 +1,141 @@
+package jumppad
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/jumppad-labs/hclconfig/types"
+	"github.com/jumppad-labs/jumppad/pkg/config/resources/lifecycle"
+	"github.com/jumppad-labs/jumppad/pkg/jumppad/constants"
+)
+
+// lifecycleSettings returns the parsed lifecycle block for the resource
+func lifecycleSettings(r types.Resource) (*lifecycle.Settings, error) {
+	s, err := lifecycle.FromResource(r).Parse()
+	if err != nil {
+		return nil, fmt.Errorf(`invalid lifecycle for resource "%s": %s`, r.Metadata().ID, err)
+	}
+
+	return s, nil
+}
+
+// PreventDestroyError is returned when an operation would destroy or
+// re-create resources that have prevent_destroy set in their lifecycle
+type PreventDestroyError struct {
+	Resources []string
+}
+
+func (e *PreventDestroyError) Error() string {
+	return fmt.Sprintf(
+		"unable to continue, %s would be destroyed but prevent_destroy is set in the lifecycle, set prevent_destroy to false to allow the resource to be destroyed",
+		strings.Join(e.Resources, ", "),
+	)
+}
+
+// preventsDestroy returns true when the resource has prevent_destroy set,
+// resources that failed to create are not protected as they were never
+// fully created
+func preventsDestroy(r types.Resource) bool {
+	if r.Metadata().Properties[constants.PropertyStatus] == constants.StatusFailed {
+		return false
+	}
+
+	l := lifecycle.FromResource(r)
+	return l != nil && l.PreventDestroy
+}
+
+// applyIgnoreChanges replaces the parsed checksum of a resource that
+// ignores changes with a checksum of the configuration without the ignored
+// attributes, the checksum is saved to the state so changes to ignored
+// attributes are not detected when the configuration is compared
+func applyIgnoreChanges(r types.Resource) error {
+	ls, err := lifecycleSettings(r)
+	if err != nil || len(ls.IgnoreChanges) == 0 {
+		return err
+	}
+
+	cs, err := lifecycle.Checksum(r, ls.IgnoreChanges)
+	if err != nil {
+		return err
+	}
+
+	r.Metadata().Checksum.Parsed = cs
+
+	return nil
+}
+
+// withRetries calls the provider operation op until it succeeds or the
+// number of retries for the resource is exhausted. Each attempt is given a
+// child of ctx that is cancelled after timeout, when cleanup is not nil it
+// is called after each failed attempt before the operation is retried.
+func (e *EngineImpl) withRetries(ctx context.Context, r types.Resource, ls *lifecycle.Settings, timeout time.Duration, op func(ctx context.Context) error, cleanup func()) error {
+	var err error
+
+	for attempt := 0; attempt <= ls.Retries; attempt++ {
+		if attempt > 0 {
+			e.log.Info(
+				"Retrying resource",
+				"ref", r.Metadata().ID,
+				"attempt", attempt,
+				"retries", ls.Retries,
+				"backoff", ls.RetryBackoff,
+				"error", err,
+			)
+
+			if cleanup != nil {
+				cleanup()
+			}
+
+			select {
+			case <-ctx.Done():
+				return ErrInterrupted
+			case <-time.After(ls.RetryBackoff):
+			}
+		}
+
+		err = withTimeout(ctx, timeout, op)
+
+		// providers often return without error when they are cancelled,
+		// the operation may not have completed
+		if ctx.Err() != nil {
+			if err == nil {
+				err = ErrInterrupted
+			}
+
+			return err
+		}
+
+		if err == nil {
+			return nil
+		}
+	}
+
+	return err
+}
+
+// withTimeout calls op with a context that is cancelled after the given
+// timeout, a timeout of zero does not set a deadline. Many providers return
+// without error when their context is cancelled, an operation that exceeds
+// the deadline is always treated as failed.
+func withTimeout(ctx context.Context, timeout time.Duration, op func(ctx context.Context) error) error {
+	if timeout <= 0 {
+		return op(ctx)
+	}
+
+	tctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	err := op(tctx)
+	if errors.Is(tctx.Err(), context.DeadlineExceeded) && ctx.Err() == nil {
+		if err == nil {
+			return fmt.Errorf("timeout after %s", timeout)
+		}
+
+		return fmt.Errorf("timeout after %s: %w", timeout, err)
+	}
+
+	return err
+}
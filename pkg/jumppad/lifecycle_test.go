@@ -0,0 +1,285 @@
+package jumppad
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/jumppad-labs/jumppad/pkg/config/mocks"
+	"github.com/jumppad-labs/jumppad/pkg/jumppad/constants"
+	"github.com/stretchr/testify/require"
+)
+
+func writeLifecycleConfig(t *testing.T, lifecycle string) string {
+	cf := filepath.Join(t.TempDir(), "config.hcl")
+	err := os.WriteFile(cf, []byte(fmt.Sprintf(lifecycleConfig, lifecycle)), 0644)
+	require.NoError(t, err)
+
+	return cf
+}
+
+func TestApplyRetriesFailedResources(t *testing.T) {
+	e, mp := setupTests(t, map[string]error{"onprem": fmt.Errorf("boom")})
+	cf := writeLifecycleConfig(t, `retries = 2
+    retry_backoff = "1ms"`)
+
+	_, err := e.Apply(context.Background(), cf)
+	require.Error(t, err)
+
+	// the network is created three times and cleaned up between attempts
+	p := mp.Providers[findProviderIndex(t, mp, "onprem")]
+	p.AssertNumberOfCalls(t, "Create", 3)
+	p.AssertNumberOfCalls(t, "Destroy", 2)
+
+	sf := testLoadState(t)
+	r, err := sf.FindResource("resource.network.onprem")
+	require.NoError(t, err)
+	require.Equal(t, constants.StatusFailed, r.Metadata().Properties[constants.PropertyStatus])
+}
+
+func TestApplyWithoutLifecycleDoesNotRetry(t *testing.T) {
+	e, mp := setupTests(t, map[string]error{"onprem": fmt.Errorf("boom")})
+	cf := writeLifecycleConfig(t, "")
+
+	_, err := e.Apply(context.Background(), cf)
+	require.Error(t, err)
+
+	p := mp.Providers[findProviderIndex(t, mp, "onprem")]
+	p.AssertNumberOfCalls(t, "Create", 1)
+	p.AssertNumberOfCalls(t, "Destroy", 0)
+}
+
+func TestApplyWithInvalidLifecycleReturnsError(t *testing.T) {
+	e, _ := setupTests(t, nil)
+	cf := writeLifecycleConfig(t, `create_timeout = "soon"`)
+
+	_, err := e.Apply(context.Background(), cf)
+	require.ErrorContains(t, err, "create_timeout")
+}
+
+func TestPlanWithInvalidLifecycleReturnsErrorWithLocation(t *testing.T) {
+	e, _ := setupTests(t, nil)
+	cf := writeLifecycleConfig(t, `retries = -1`)
+
+	_, err := e.Plan(cf, nil, "")
+	require.ErrorContains(t, err, "retries")
+	require.ErrorContains(t, err, fmt.Sprintf("%s:2,1", cf))
+}
+
+func TestApplyWithInvalidLifecycleDoesNotCreateResources(t *testing.T) {
+	e, mp := setupTests(t, nil)
+
+	cf := filepath.Join(t.TempDir(), "config.hcl")
+	err := os.WriteFile(cf, []byte(`
+resource "network" "first" {
+  subnet = "10.6.0.0/16"
+}
+
+resource "network" "second" {
+  subnet = "10.7.0.0/16"
+
+  depends_on = ["resource.network.first"]
+
+  lifecycle {
+    destroy_timeout = "later"
+  }
+}
+`), 0644)
+	require.NoError(t, err)
+
+	_, err = e.Apply(context.Background(), cf)
+	require.ErrorContains(t, err, "destroy_timeout")
+
+	testAssertMethodCalled(t, mp, "Create", 0)
+}
+
+func TestWithTimeoutReturnsErrorWhenDeadlineExceeded(t *testing.T) {
+	err := withTimeout(context.Background(), 10*time.Millisecond, func(ctx context.Context) error {
+		<-ctx.Done()
+
+		// providers often return nil when the context is cancelled
+		return nil
+	})
+
+	require.ErrorContains(t, err, "timeout after 10ms")
+}
+
+func TestWithTimeoutDoesNotSetDeadlineForZeroTimeout(t *testing.T) {
+	err := withTimeout(context.Background(), 0, func(ctx context.Context) error {
+		_, ok := ctx.Deadline()
+		require.False(t, ok)
+
+		return nil
+	})
+
+	require.NoError(t, err)
+}
+
+func findProviderIndex(t *testing.T, mp *mocks.Providers, name string) int {
+	for i := range mp.Providers {
+		if getMetaFromMock(mp, i).Name == name {
+			return i
+		}
+	}
+
+	require.Fail(t, "provider not found", name)
+	return -1
+}
+
+var lifecycleConfig = `
+resource "network" "onprem" {
+  subnet = "10.6.0.0/16"
+
+  lifecycle {
+    %s
+  }
+}
+`
+
+var guardedConfig = `
+resource "network" "onprem" {
+  subnet = "%s"
+
+  %s
+}
+`
+
+func writeGuardedConfig(t *testing.T, cf, subnet, lifecycle string) {
+	err := os.WriteFile(cf, []byte(fmt.Sprintf(guardedConfig, subnet, lifecycle)), 0644)
+	require.NoError(t, err)
+}
+
+func TestPlanIgnoresChangesToIgnoredAttributes(t *testing.T) {
+	e, _ := setupTests(t, nil)
+	cf := filepath.Join(t.TempDir(), "config.hcl")
+	lifecycle := `lifecycle {
+    ignore_changes = ["subnet"]
+  }`
+
+	writeGuardedConfig(t, cf, "10.6.0.0/16", lifecycle)
+	_, err := e.Apply(context.Background(), cf)
+	require.NoError(t, err)
+
+	writeGuardedConfig(t, cf, "10.7.0.0/16", lifecycle)
+	p, err := e.Plan(cf, nil, "")
+	require.NoError(t, err)
+
+	c := findChange(t, p, "resource.network.onprem")
+	require.Equal(t, ActionNone, c.Action)
+	require.Empty(t, c.Attributes)
+}
+
+var ignoreChangesVariableConfig = `
+variable "subnet" {
+  default = "10.6.0.0/16"
+}
+
+resource "network" "onprem" {
+  subnet = variable.subnet
+
+  lifecycle {
+    ignore_changes = ["enable_ipv6"]
+  }
+}
+`
+
+func TestPlanWithIgnoreChangesDetectsVariableChanges(t *testing.T) {
+	e, _ := setupTests(t, nil)
+	cf := filepath.Join(t.TempDir(), "config.hcl")
+	err := os.WriteFile(cf, []byte(ignoreChangesVariableConfig), 0644)
+	require.NoError(t, err)
+
+	_, err = e.ApplyWithVariables(context.Background(), cf, map[string]string{"subnet": "10.6.0.0/16"}, "")
+	require.NoError(t, err)
+
+	p, err := e.Plan(cf, map[string]string{"subnet": "10.6.0.0/16"}, "")
+	require.NoError(t, err)
+	require.Equal(t, ActionNone, findChange(t, p, "resource.network.onprem").Action)
+
+	p, err = e.Plan(cf, map[string]string{"subnet": "10.7.0.0/16"}, "")
+	require.NoError(t, err)
+	require.NotEqual(t, ActionNone, findChange(t, p, "resource.network.onprem").Action)
+}
+
+func TestPlanWithPreventDestroyReturnsErrorWhenResourceChanged(t *testing.T) {
+	e, _ := setupTests(t, nil)
+	cf := filepath.Join(t.TempDir(), "config.hcl")
+	lifecycle := `lifecycle {
+    prevent_destroy = true
+  }`
+
+	writeGuardedConfig(t, cf, "10.6.0.0/16", lifecycle)
+	_, err := e.Apply(context.Background(), cf)
+	require.NoError(t, err)
+
+	writeGuardedConfig(t, cf, "10.7.0.0/16", lifecycle)
+	_, err = e.Plan(cf, nil, "")
+
+	pe := &PreventDestroyError{}
+	require.ErrorAs(t, err, &pe)
+	require.Equal(t, []string{"resource.network.onprem"}, pe.Resources)
+}
+
+func TestApplyWithPreventDestroyDoesNotDestroyRemovedResources(t *testing.T) {
+	e, mp := setupTests(t, nil)
+	cf := filepath.Join(t.TempDir(), "config.hcl")
+
+	writeGuardedConfig(t, cf, "10.6.0.0/16", `lifecycle {
+    prevent_destroy = true
+  }`)
+	_, err := e.Apply(context.Background(), cf)
+	require.NoError(t, err)
+
+	err = os.WriteFile(cf, []byte(`resource "network" "other" {
+  subnet = "10.8.0.0/16"
+}`), 0644)
+	require.NoError(t, err)
+
+	_, err = e.Apply(context.Background(), cf)
+	require.ErrorAs(t, err, new(*PreventDestroyError))
+
+	testAssertMethodCalled(t, mp, "Destroy", 0)
+
+	_, err = testLoadState(t).FindResource("resource.network.onprem")
+	require.NoError(t, err)
+}
+
+func TestPlanAllowsAddingPreventDestroy(t *testing.T) {
+	e, _ := setupTests(t, nil)
+	cf := filepath.Join(t.TempDir(), "config.hcl")
+
+	writeGuardedConfig(t, cf, "10.6.0.0/16", "")
+	_, err := e.Apply(context.Background(), cf)
+	require.NoError(t, err)
+
+	writeGuardedConfig(t, cf, "10.6.0.0/16", `lifecycle {
+    prevent_destroy = true
+  }`)
+	p, err := e.Plan(cf, nil, "")
+	require.NoError(t, err)
+
+	c := findChange(t, p, "resource.network.onprem")
+	require.Equal(t, ActionRefresh, c.Action)
+}
+
+func TestDestroyWithPreventDestroyReturnsError(t *testing.T) {
+	e, mp := setupTests(t, nil)
+	cf := filepath.Join(t.TempDir(), "config.hcl")
+
+	writeGuardedConfig(t, cf, "10.6.0.0/16", `lifecycle {
+    prevent_destroy = true
+  }`)
+	_, err := e.Apply(context.Background(), cf)
+	require.NoError(t, err)
+
+	err = e.Destroy(context.Background(), false)
+	require.ErrorContains(t, err, "prevent_destroy")
+
+	testAssertMethodCalled(t, mp, "Destroy", 0)
+
+	_, err = testLoadState(t).FindResource("resource.network.onprem")
+	require.NoError(t, err)
+}
@@ -5,10 +5,18 @@ package mocks
 import (
 	context "context"
 
+	container "github.com/jumppad-labs/jumppad/pkg/clients/container"
+
 	hclconfig "github.com/jumppad-labs/hclconfig"
 
+	events "github.com/jumppad-labs/jumppad/pkg/jumppad/events"
+
+	jumppad "github.com/jumppad-labs/jumppad/pkg/jumppad"
+
 	mock "github.com/stretchr/testify/mock"
 
+	trace "go.opentelemetry.io/otel/trace"
+
 	types "github.com/jumppad-labs/hclconfig/types"
 )
 
@@ -69,6 +77,32 @@ func (_m *Engine) ApplyWithVariables(ctx context.Context, path string, variables
 	return r0, r1
 }
 
+// ApplyPlan provides a mock function with given fields: ctx, plan
+func (_m *Engine) ApplyPlan(ctx context.Context, plan *jumppad.Plan) (*hclconfig.Config, error) {
+	ret := _m.Called(ctx, plan)
+
+	var r0 *hclconfig.Config
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, *jumppad.Plan) (*hclconfig.Config, error)); ok {
+		return rf(ctx, plan)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, *jumppad.Plan) *hclconfig.Config); ok {
+		r0 = rf(ctx, plan)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*hclconfig.Config)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, *jumppad.Plan) error); ok {
+		r1 = rf(ctx, plan)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // Config provides a mock function with given fields:
 func (_m *Engine) Config() *hclconfig.Config {
 	ret := _m.Called()
@@ -152,6 +186,84 @@ func (_m *Engine) Diff(path string, variables map[string]string, variablesFile s
 	return r0, r1, r2, r3, r4
 }
 
+// Drift provides a mock function with given fields: ctx, repair
+func (_m *Engine) Drift(ctx context.Context, repair bool) ([]*jumppad.ResourceDrift, error) {
+	ret := _m.Called(ctx, repair)
+
+	var r0 []*jumppad.ResourceDrift
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, bool) ([]*jumppad.ResourceDrift, error)); ok {
+		return rf(ctx, repair)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, bool) []*jumppad.ResourceDrift); ok {
+		r0 = rf(ctx, repair)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*jumppad.ResourceDrift)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, bool) error); ok {
+		r1 = rf(ctx, repair)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Graph provides a mock function with given fields: path, variables, variablesFile
+func (_m *Engine) Graph(path string, variables map[string]string, variablesFile string) (*jumppad.Graph, error) {
+	ret := _m.Called(path, variables, variablesFile)
+
+	var r0 *jumppad.Graph
+	var r1 error
+	if rf, ok := ret.Get(0).(func(string, map[string]string, string) (*jumppad.Graph, error)); ok {
+		return rf(path, variables, variablesFile)
+	}
+	if rf, ok := ret.Get(0).(func(string, map[string]string, string) *jumppad.Graph); ok {
+		r0 = rf(path, variables, variablesFile)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*jumppad.Graph)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(string, map[string]string, string) error); ok {
+		r1 = rf(path, variables, variablesFile)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Import provides a mock function with given fields: ctx, path, variables, variablesFile, id, objectID
+func (_m *Engine) Import(ctx context.Context, path string, variables map[string]string, variablesFile string, id string, objectID string) (types.Resource, error) {
+	ret := _m.Called(ctx, path, variables, variablesFile, id, objectID)
+
+	var r0 types.Resource
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, map[string]string, string, string, string) (types.Resource, error)); ok {
+		return rf(ctx, path, variables, variablesFile, id, objectID)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, map[string]string, string, string, string) types.Resource); ok {
+		r0 = rf(ctx, path, variables, variablesFile, id, objectID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(types.Resource)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, map[string]string, string, string, string) error); ok {
+		r1 = rf(ctx, path, variables, variablesFile, id, objectID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // ParseConfig provides a mock function with given fields: _a0
 func (_m *Engine) ParseConfig(_a0 string) (*hclconfig.Config, error) {
 	ret := _m.Called(_a0)
@@ -204,11 +316,168 @@ func (_m *Engine) ParseConfigWithVariables(_a0 string, _a1 map[string]string, _a
 	return r0, r1
 }
 
+// Plan provides a mock function with given fields: path, variables, variablesFile
+func (_m *Engine) Plan(path string, variables map[string]string, variablesFile string) (*jumppad.Plan, error) {
+	ret := _m.Called(path, variables, variablesFile)
+
+	var r0 *jumppad.Plan
+	var r1 error
+	if rf, ok := ret.Get(0).(func(string, map[string]string, string) (*jumppad.Plan, error)); ok {
+		return rf(path, variables, variablesFile)
+	}
+	if rf, ok := ret.Get(0).(func(string, map[string]string, string) *jumppad.Plan); ok {
+		r0 = rf(path, variables, variablesFile)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*jumppad.Plan)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(string, map[string]string, string) error); ok {
+		r1 = rf(path, variables, variablesFile)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Restore provides a mock function with given fields: ctx, name
+func (_m *Engine) Restore(ctx context.Context, name string) (*hclconfig.Config, error) {
+	ret := _m.Called(ctx, name)
+
+	var r0 *hclconfig.Config
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) (*hclconfig.Config, error)); ok {
+		return rf(ctx, name)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string) *hclconfig.Config); ok {
+		r0 = rf(ctx, name)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*hclconfig.Config)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(ctx, name)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Rollback provides a mock function with given fields: ctx, target
+func (_m *Engine) Rollback(ctx context.Context, target *hclconfig.Config) (*hclconfig.Config, error) {
+	ret := _m.Called(ctx, target)
+
+	var r0 *hclconfig.Config
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, *hclconfig.Config) (*hclconfig.Config, error)); ok {
+		return rf(ctx, target)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, *hclconfig.Config) *hclconfig.Config); ok {
+		r0 = rf(ctx, target)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*hclconfig.Config)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, *hclconfig.Config) error); ok {
+		r1 = rf(ctx, target)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// SetAtomic provides a mock function with given fields: atomic
+func (_m *Engine) SetAtomic(atomic bool) {
+	_m.Called(atomic)
+}
+
+// SetEventHandler provides a mock function with given fields: h
+func (_m *Engine) SetEventHandler(h events.Handler) {
+	_m.Called(h)
+}
+
+// SetParallelism provides a mock function with given fields: n
+func (_m *Engine) SetParallelism(n int) {
+	_m.Called(n)
+}
+
+// SetTargets provides a mock function with given fields: targets
+func (_m *Engine) SetTargets(targets []string) {
+	_m.Called(targets)
+}
+
+// SetTracerProvider provides a mock function with given fields: tp
+func (_m *Engine) SetTracerProvider(tp trace.TracerProvider) {
+	_m.Called(tp)
+}
+
 type mockConstructorTestingTNewEngine interface {
 	mock.TestingT
 	Cleanup(func())
 }
 
+// Snapshot provides a mock function with given fields: ctx, name
+func (_m *Engine) Snapshot(ctx context.Context, name string) (*container.Snapshot, error) {
+	ret := _m.Called(ctx, name)
+
+	var r0 *container.Snapshot
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) (*container.Snapshot, error)); ok {
+		return rf(ctx, name)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string) *container.Snapshot); ok {
+		r0 = rf(ctx, name)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*container.Snapshot)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(ctx, name)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Start provides a mock function with given fields: ctx
+func (_m *Engine) Start(ctx context.Context) error {
+	ret := _m.Called(ctx)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context) error); ok {
+		r0 = rf(ctx)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// Stop provides a mock function with given fields: ctx
+func (_m *Engine) Stop(ctx context.Context) error {
+	ret := _m.Called(ctx)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context) error); ok {
+		r0 = rf(ctx)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
 // NewEngine creates a new instance of Engine. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
 func NewEngine(t mockConstructorTestingTNewEngine) *Engine {
 	mock := &Engine{}
@@ -0,0 +1,441 @@
+package jumppad
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+	"sync"
+
+	"github.com/jumppad-labs/hclconfig"
+	hclerrors "github.com/jumppad-labs/hclconfig/errors"
+	"github.com/jumppad-labs/hclconfig/resources"
+	"github.com/jumppad-labs/hclconfig/types"
+)
+
+// DefaultParallelism is the default number of resources the engine will
+// create or destroy at the same time
+const DefaultParallelism = 10
+
+// resourceBaseType is the type of the metadata embedded in resources
+var resourceBaseType = reflect.TypeOf(types.ResourceBase{})
+
+// errDependencyFailed is returned for a resource that was not created
+// because one of the resources it depends on failed
+var errDependencyFailed = errors.New("dependency failed")
+
+// resourceTask tracks the creation of a single resource
+type resourceTask struct {
+	resource types.Resource
+	deferred bool
+	once     sync.Once
+	done     chan struct{}
+	err      error
+}
+
+// scheduler controls the concurrent creation of resources.
+//
+// The hclconfig parser decodes a resource only after all the resources it
+// depends on have been processed by the callback and it holds a lock on the
+// evaluation context while the callback runs, this means that resources
+// created by the callback are created one at a time. To create resources
+// concurrently, limited by parallelism, the configuration is parsed each
+// time resources complete and any resource whose dependencies have all
+// been created is started. When the configuration is finally processed the
+// created resources are not created again, any remaining resources whose
+// attributes are not referenced by any other resource are deferred until
+// the parser has finished. Any resource that depends on a running resource
+// waits for it to complete before it is created.
+type scheduler struct {
+	ctx        context.Context
+	slots      chan struct{}
+	parsed     *hclconfig.Config
+	referenced map[string]bool
+	tasks      map[string]*resourceTask
+	completed  chan struct{}
+	lock       sync.Mutex
+}
+
+// newScheduler creates a scheduler for the given parsed configuration,
+// parsed can be nil when the scheduler is only used to limit parallelism
+func newScheduler(ctx context.Context, parallelism int, parsed *hclconfig.Config) *scheduler {
+	if parallelism < 1 {
+		parallelism = DefaultParallelism
+	}
+
+	s := &scheduler{
+		ctx:        ctx,
+		slots:      make(chan struct{}, parallelism),
+		parsed:     parsed,
+		referenced: map[string]bool{},
+		tasks:      map[string]*resourceTask{},
+		completed:  make(chan struct{}, 1),
+	}
+
+	if parsed == nil {
+		return s
+	}
+
+	// build a list of all the resources that have attributes referenced
+	// by other resources
+	for _, r := range parsed.Resources {
+		for _, l := range r.Metadata().Links {
+			fqrn, err := resources.ParseFQRN(l)
+			if err != nil {
+				continue
+			}
+
+			rel := fqrn.AppendParentModule(r.Metadata().Module)
+			s.referenced[rel.StringWithoutAttribute()] = true
+		}
+	}
+
+	return s
+}
+
+// acquire blocks until a slot is available, false is returned if the
+// context is cancelled before a slot becomes available
+func (s *scheduler) acquire() bool {
+	select {
+	case s.slots <- struct{}{}:
+		return true
+	case <-s.ctx.Done():
+		return false
+	}
+}
+
+func (s *scheduler) release() {
+	<-s.slots
+}
+
+// isCreated returns true when the resource is created by a provider,
+// modules, variables, outputs and locals are only processed by the parser
+func isCreated(r types.Resource) bool {
+	switch r.Metadata().Type {
+	case resources.TypeModule, resources.TypeVariable, resources.TypeOutput, resources.TypeLocal:
+		return false
+	}
+
+	return true
+}
+
+// concurrent returns true when more than one resource can be created at
+// the same time
+func (s *scheduler) concurrent() bool {
+	return cap(s.slots) > 1 && s.parsed != nil
+}
+
+// canDefer returns true when the creation of the resource does not need to
+// complete before the parser processes the resources that depend on it
+func (s *scheduler) canDefer(r types.Resource) bool {
+	if cap(s.slots) < 2 || !isCreated(r) {
+		return false
+	}
+
+	return !s.referenced[r.Metadata().ID]
+}
+
+// ready returns true when the resource has not been started and all the
+// resources it depends on have been created without error. Resources that
+// have been created are set to the created values so that the resources
+// that reference them are decoded with the created values.
+func (s *scheduler) ready(r types.Resource) bool {
+	if s.restore(r) || !isCreated(r) {
+		return false
+	}
+
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	for _, id := range s.dependencyIDs(r) {
+		t, ok := s.tasks[id]
+		if !ok {
+			return false
+		}
+
+		select {
+		case <-t.done:
+			if t.err != nil {
+				return false
+			}
+		default:
+			return false
+		}
+	}
+
+	return true
+}
+
+// restore sets the resource decoded by the parser to the values of the
+// resource created by its task, true is returned when the resource has a
+// task
+func (s *scheduler) restore(r types.Resource) bool {
+	s.lock.Lock()
+	t, ok := s.tasks[r.Metadata().ID]
+	s.lock.Unlock()
+
+	if !ok {
+		return false
+	}
+
+	select {
+	case <-t.done:
+	default:
+		return true
+	}
+
+	rv := reflect.ValueOf(r)
+	tv := reflect.ValueOf(t.resource)
+	if tv.Type() != rv.Type() || rv.Kind() != reflect.Pointer || tv == rv || rv.Elem().Kind() != reflect.Struct {
+		return true
+	}
+
+	// the metadata is not changed as the parser reads it when finding
+	// other resources
+	rv, tv = rv.Elem(), tv.Elem()
+	for i := 0; i < rv.NumField(); i++ {
+		if rv.Type().Field(i).Type == resourceBaseType || !rv.Field(i).CanSet() {
+			continue
+		}
+
+		rv.Field(i).Set(tv.Field(i))
+	}
+
+	return true
+}
+
+// join is called when the configuration is processed, true is returned when
+// the resource was created before the configuration was processed. The
+// parser is set to the created values of resources that are referenced by
+// other resources and the error from creating the resource is returned.
+func (s *scheduler) join(r types.Resource) (bool, error) {
+	s.lock.Lock()
+	t, ok := s.tasks[r.Metadata().ID]
+	s.lock.Unlock()
+
+	if !ok {
+		return false, nil
+	}
+
+	if !s.referenced[r.Metadata().ID] {
+		return true, nil
+	}
+
+	<-t.done
+	s.restore(r)
+
+	// the error has been returned to the parser
+	s.lock.Lock()
+	t.deferred = false
+	s.lock.Unlock()
+
+	return true, t.err
+}
+
+// running returns true when any task has not completed
+func (s *scheduler) running() bool {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	for _, t := range s.tasks {
+		select {
+		case <-t.done:
+		default:
+			return true
+		}
+	}
+
+	return false
+}
+
+// waitAny blocks until a task completes or the context is cancelled
+func (s *scheduler) waitAny() {
+	select {
+	case <-s.completed:
+	case <-s.ctx.Done():
+	}
+}
+
+// modules returns the module resources that contain the given resource
+func (s *scheduler) modules(r types.Resource) []types.Resource {
+	mods := []types.Resource{}
+	if r.Metadata().Module == "" || s.parsed == nil {
+		return mods
+	}
+
+	parts := strings.Split(r.Metadata().Module, ".")
+	for i := range parts {
+		m, err := s.parsed.FindResource("module." + strings.Join(parts[:i+1], "."))
+		if err == nil {
+			mods = append(mods, m)
+		}
+	}
+
+	return mods
+}
+
+// add creates a new task for the given resource
+func (s *scheduler) add(r types.Resource, deferred bool) *resourceTask {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	t := &resourceTask{
+		resource: r,
+		deferred: deferred,
+		done:     make(chan struct{}),
+	}
+
+	s.tasks[r.Metadata().ID] = t
+
+	return t
+}
+
+// dependencyIDs returns the ids of the created resources that the given
+// resource, or the modules that contain it, depend on. Dependencies on
+// modules, outputs and locals are replaced with the resources they depend on.
+func (s *scheduler) dependencyIDs(r types.Resource) []string {
+	ids := []string{}
+	s.collectDependencies(r, map[string]bool{}, &ids)
+
+	return ids
+}
+
+func (s *scheduler) collectDependencies(r types.Resource, seen map[string]bool, ids *[]string) {
+	for _, dr := range append([]types.Resource{r}, s.modules(r)...) {
+		for _, d := range dr.GetDependencies() {
+			fqrn, err := resources.ParseFQRN(d)
+			if err != nil {
+				continue
+			}
+
+			rel := fqrn.AppendParentModule(dr.Metadata().Module)
+
+			if s.parsed == nil {
+				*ids = append(*ids, rel.StringWithoutAttribute())
+				continue
+			}
+
+			// when the dependency is a module, depend on all resources in the module
+			deps := []types.Resource{}
+			if rel.Type == resources.TypeModule {
+				deps, _ = s.parsed.FindModuleResources(rel.String(), true)
+			} else if dep, err := s.parsed.FindResource(rel.StringWithoutAttribute()); err == nil {
+				deps = append(deps, dep)
+			}
+
+			for _, dep := range deps {
+				id := dep.Metadata().ID
+				if seen[id] {
+					continue
+				}
+
+				seen[id] = true
+
+				if !isCreated(dep) {
+					s.collectDependencies(dep, seen, ids)
+					continue
+				}
+
+				*ids = append(*ids, id)
+			}
+		}
+	}
+}
+
+// dependencies returns the tasks for the resources that the given resource
+// depends on
+func (s *scheduler) dependencies(r types.Resource) []*resourceTask {
+	ids := s.dependencyIDs(r)
+
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	deps := []*resourceTask{}
+	for _, id := range ids {
+		if t, ok := s.tasks[id]; ok && t.resource != r {
+			deps = append(deps, t)
+		}
+	}
+
+	return deps
+}
+
+// run executes the task, waiting for any dependencies before calling
+// the given function
+func (s *scheduler) run(t *resourceTask, create func(types.Resource) error) {
+	t.once.Do(func() {
+		defer func() {
+			close(t.done)
+
+			select {
+			case s.completed <- struct{}{}:
+			default:
+			}
+		}()
+
+		for _, d := range s.dependencies(t.resource) {
+			s.start(d, create)
+			<-d.done
+
+			if d.err != nil {
+				t.err = fmt.Errorf(`%w: "%s"`, errDependencyFailed, d.resource.Metadata().ID)
+				return
+			}
+		}
+
+		if !s.acquire() {
+			return
+		}
+		defer s.release()
+
+		t.err = create(t.resource)
+	})
+}
+
+// start runs the task in the background
+func (s *scheduler) start(t *resourceTask, create func(types.Resource) error) {
+	go s.run(t, create)
+}
+
+// wait starts any deferred tasks and blocks until all tasks have completed,
+// errors from deferred tasks are returned as a ConfigError
+func (s *scheduler) wait(create func(types.Resource) error) error {
+	s.lock.Lock()
+	tasks := []*resourceTask{}
+	for _, t := range s.tasks {
+		tasks = append(tasks, t)
+	}
+	s.lock.Unlock()
+
+	for _, t := range tasks {
+		s.start(t, create)
+	}
+
+	ce := hclerrors.NewConfigError()
+
+	for _, t := range tasks {
+		<-t.done
+
+		// errors from resources that are not deferred have already been
+		// returned to the parser
+		if !t.deferred || t.err == nil || errors.Is(t.err, errDependencyFailed) {
+			continue
+		}
+
+		pe := &hclerrors.ParserError{}
+		pe.Filename = t.resource.Metadata().File
+		pe.Line = t.resource.Metadata().Line
+		pe.Column = t.resource.Metadata().Column
+		pe.Message = fmt.Sprintf(`unable to create resource "%s": %s`, t.resource.Metadata().ID, t.err)
+		pe.Level = hclerrors.ParserErrorLevelError
+
+		ce.AppendError(pe)
+	}
+
+	if len(ce.Errors) > 0 {
+		return ce
+	}
+
+	return nil
+}
@@ -0,0 +1,644 @@
+package jumppad
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/jumppad-labs/hclconfig"
+	hclerrors "github.com/jumppad-labs/hclconfig/errors"
+	"github.com/jumppad-labs/hclconfig/types"
+	"github.com/jumppad-labs/jumppad/pkg/config"
+	"github.com/jumppad-labs/jumppad/pkg/config/resources/cache"
+	"github.com/jumppad-labs/jumppad/pkg/config/resources/lifecycle"
+	"github.com/jumppad-labs/jumppad/pkg/jumppad/constants"
+	"github.com/jumppad-labs/jumppad/pkg/jumppad/tracing"
+	"github.com/jumppad-labs/jumppad/pkg/utils"
+)
+
+const (
+	// ActionNone indicates that the resource is up to date, created resources
+	// are still refreshed by the provider on apply
+	ActionNone = "none"
+
+	// ActionCreate indicates that the resource will be created
+	ActionCreate = "create"
+
+	// ActionRefresh indicates that the resource has changes and the provider
+	// will be called to refresh it
+	ActionRefresh = "refresh"
+
+	// ActionRecreate indicates that the resource will be destroyed and then
+	// created
+	ActionRecreate = "recreate"
+
+	// ActionDestroy indicates that the resource will be destroyed
+	ActionDestroy = "destroy"
+)
+
+const (
+	ReasonNotInState      = "resource does not exist in the state"
+	ReasonNotCreated      = "resource has not been created"
+	ReasonChecksum        = "resource configuration has changed"
+	ReasonProviderChanged = "provider reported that the resource has changed"
+	ReasonTainted         = "resource has been tainted"
+	ReasonFailed          = "resource failed to create"
+	ReasonRemoved         = "resource has been removed from the configuration"
+	ReasonDisabled        = "resource has been disabled"
+	ReasonDrifted         = "running resource does not match the state"
+	ReasonStopped         = "resource has been stopped"
+)
+
+// Plan describes the changes that applying a configuration will make to
+// the resources in the state
+type Plan struct {
+	// Path is the absolute path of the configuration
+	Path string `json:"path"`
+
+	// Variables and VariablesFile are the variables used to parse the
+	// configuration
+	Variables     map[string]string `json:"variables,omitempty"`
+	VariablesFile string            `json:"variables_file,omitempty"`
+
+	// StateChecksum is the checksum of the state file when the plan was
+	// created, empty if there was no state
+	StateChecksum string `json:"state_checksum"`
+
+	// ConfigChecksum is the checksum of the parsed resources in the
+	// configuration when the plan was created
+	ConfigChecksum string `json:"config_checksum"`
+
+	// Targets restricts the plan to the given resources and their
+	// dependencies
+	Targets []string `json:"targets,omitempty"`
+
+	// Changes is the list of changes for every resource in the config
+	// and state
+	Changes []*ResourceChange `json:"changes"`
+
+	config *hclconfig.Config
+}
+
+// ResourceChange describes the change for a single resource
+type ResourceChange struct {
+	ID     string `json:"id"`
+	Type   string `json:"type"`
+	Module string `json:"module,omitempty"`
+
+	// Action is the action that will be performed on the resource
+	Action string `json:"action"`
+
+	// Reasons explains why the action will be performed
+	Reasons []string `json:"reasons,omitempty"`
+
+	// Attributes lists the attributes that differ between the state and
+	// the configuration
+	Attributes []AttributeChange `json:"attributes,omitempty"`
+
+	// resource is the resource from the config, or the state when the
+	// resource is being removed
+	resource types.Resource
+	inState  bool
+}
+
+// AttributeChange describes a change to a single resource attribute
+type AttributeChange struct {
+	Path   string `json:"path"`
+	Before any    `json:"before,omitempty"`
+	After  any    `json:"after,omitempty"`
+}
+
+// HasChanges returns true if applying the plan will modify any resources
+func (p *Plan) HasChanges() bool {
+	for _, c := range p.Changes {
+		if c.Action != ActionNone {
+			return true
+		}
+	}
+
+	return false
+}
+
+// Count returns the number of resources with the given action
+func (p *Plan) Count(action string) int {
+	count := 0
+	for _, c := range p.Changes {
+		if c.Action == action {
+			count++
+		}
+	}
+
+	return count
+}
+
+// Save writes the plan as JSON to the given file. The plan contains the
+// variables and the values of changed attributes, it is encrypted in the same
+// way as the state and is only readable by the current user.
+func (p *Plan) Save(file string) error {
+	d, err := json.MarshalIndent(p, "", "  ")
+	if err != nil {
+		return fmt.Errorf("unable to serialize plan: %s", err)
+	}
+
+	d, err = config.Encrypt(d)
+	if err != nil {
+		return fmt.Errorf("unable to encrypt plan: %s", err)
+	}
+
+	// an existing plan file keeps its permissions when it is overwritten
+	if err := os.Chmod(file, 0600); err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("unable to write plan file '%s', error: %s", file, err)
+	}
+
+	err = os.WriteFile(file, d, 0600)
+	if err != nil {
+		return fmt.Errorf("unable to write plan file '%s', error: %s", file, err)
+	}
+
+	return nil
+}
+
+// LoadPlan reads a plan that was previously saved with Plan.Save
+func LoadPlan(file string) (*Plan, error) {
+	d, err := os.ReadFile(file)
+	if err != nil {
+		return nil, fmt.Errorf("unable to read plan file '%s', error: %s", file, err)
+	}
+
+	d, err = config.Decrypt(d)
+	if err != nil {
+		return nil, fmt.Errorf("unable to decrypt plan file '%s', error: %w", file, err)
+	}
+
+	p := &Plan{}
+	err = json.Unmarshal(d, p)
+	if err != nil {
+		return nil, fmt.Errorf("unable to parse plan file '%s', error: %s", file, err)
+	}
+
+	return p, nil
+}
+
+func (c *ResourceChange) hasReason(reason string) bool {
+	for _, r := range c.Reasons {
+		if r == reason {
+			return true
+		}
+	}
+
+	return false
+}
+
+// Plan compares the configuration at the given path with the state and
+// returns the changes that Apply would make
+func (e *EngineImpl) Plan(path string, variables map[string]string, variablesFile string) (*Plan, error) {
+	var err error
+	path, err = filepath.Abs(path)
+	if err != nil {
+		return nil, err
+	}
+
+	if variablesFile != "" {
+		variablesFile, err = filepath.Abs(variablesFile)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	plan := &Plan{
+		Path:          path,
+		Variables:     variables,
+		VariablesFile: variablesFile,
+		StateChecksum: e.stateChecksum(),
+		Targets:       e.targets,
+		Changes:       []*ResourceChange{},
+	}
+
+	// load the stack
+	past, _ := e.loadState()
+
+	// Parse the config to check it is valid
+	res, parseErr := e.ParseConfigWithVariables(path, variables, variablesFile)
+
+	if parseErr != nil {
+		// cast the error to a config error
+		ce := parseErr.(*hclerrors.ConfigError)
+
+		// if we have parser errors return them
+		// if not it is possible to get process errors at this point as the
+		// callbacks have not been called for the providers, any referenced
+		// resources will not be found, it is ok to ignore these errors
+		if ce.ContainsErrors() {
+			return nil, parseErr
+		}
+	}
+
+	plan.config = res
+
+	_, span := tracing.Start(e.ctx, "jumppad.diff")
+	defer span.End()
+
+	checksums := map[string]string{}
+	for _, r := range res.Resources {
+		checksums[r.Metadata().ID] = r.Metadata().Checksum.Parsed
+	}
+
+	plan.ConfigChecksum, err = utils.ChecksumFromInterface(checksums)
+	if err != nil {
+		return nil, fmt.Errorf("unable to generate checksum for configuration: %s", err)
+	}
+
+	for _, r := range res.Resources {
+		rc := &ResourceChange{
+			ID:       r.Metadata().ID,
+			Type:     r.Metadata().Type,
+			Module:   r.Metadata().Module,
+			Action:   ActionNone,
+			resource: r,
+		}
+
+		plan.Changes = append(plan.Changes, rc)
+
+		// does the resource exist
+		sr, err := past.FindResource(r.Metadata().ID)
+		if err != nil {
+			if !r.GetDisabled() {
+				rc.Action = ActionCreate
+				rc.Reasons = append(rc.Reasons, ReasonNotInState)
+			}
+
+			continue
+		}
+
+		rc.inState = true
+		rc.Attributes = diffAttributes(sr, r)
+
+		// changes to ignored attributes are excluded from the checksum
+		if ls, err := lifecycleSettings(r); err == nil && len(ls.IgnoreChanges) > 0 {
+			rc.Attributes = withoutIgnored(rc.Attributes, ls.IgnoreChanges)
+		}
+
+		// check if the hcl resource text has changed
+		if sr.Metadata().Checksum.Parsed != r.Metadata().Checksum.Parsed {
+			rc.Reasons = append(rc.Reasons, ReasonChecksum)
+		} else if !r.GetDisabled() {
+			// call changed on the provider to see if any internal properties
+			// have changed
+			p := e.providers.GetProvider(r)
+			if p == nil {
+				return nil, fmt.Errorf("unable to create provider for resource Name: %s, Type: %s. Please check the provider is registered in providers.go", r.Metadata().Name, r.Metadata().Type)
+			}
+
+			c, err := p.Changed()
+			if err != nil {
+				return nil, fmt.Errorf("unable to determine if resource has changed Name: %s, Type: %s", r.Metadata().Name, r.Metadata().Type)
+			}
+
+			if c {
+				rc.Reasons = append(rc.Reasons, ReasonProviderChanged)
+			}
+		}
+
+		switch sr.Metadata().Properties[constants.PropertyStatus] {
+		case constants.StatusCreated:
+			if r.GetDisabled() {
+				rc.Action = ActionDestroy
+				rc.Reasons = append(rc.Reasons, ReasonDisabled)
+				break
+			}
+
+			if len(rc.Reasons) > 0 {
+				rc.Action = ActionRefresh
+			}
+
+		case constants.StatusStopped:
+			if r.GetDisabled() {
+				rc.Action = ActionDestroy
+				rc.Reasons = append(rc.Reasons, ReasonDisabled)
+				break
+			}
+
+			// stopped resources are started before they are refreshed
+			rc.Action = ActionRefresh
+			rc.Reasons = append(rc.Reasons, ReasonStopped)
+
+		case constants.StatusTainted:
+			if !r.GetDisabled() {
+				rc.Action = ActionRecreate
+				rc.Reasons = append(rc.Reasons, ReasonTainted)
+			}
+
+		case constants.StatusFailed:
+			if !r.GetDisabled() {
+				rc.Action = ActionRecreate
+				rc.Reasons = append(rc.Reasons, ReasonFailed)
+			}
+
+		case constants.StatusDrifted:
+			if !r.GetDisabled() {
+				rc.Action = ActionRecreate
+				rc.Reasons = append(rc.Reasons, ReasonDrifted)
+			}
+
+		default:
+			if !r.GetDisabled() {
+				rc.Action = ActionCreate
+				rc.Reasons = append(rc.Reasons, ReasonNotCreated)
+			}
+		}
+	}
+
+	// resources that reference a recreated resource are recreated with it
+	changes := map[string]*ResourceChange{}
+	recreated := map[string]bool{}
+	for _, rc := range plan.Changes {
+		changes[rc.ID] = rc
+		if rc.Action == ActionRecreate {
+			recreated[rc.ID] = true
+		}
+	}
+
+	cascaded := cascadeReplacements(res, recreated, func(r types.Resource) bool {
+		rc := changes[r.Metadata().ID]
+		return rc.inState && (rc.Action == ActionNone || rc.Action == ActionRefresh)
+	})
+
+	for id, cause := range cascaded {
+		changes[id].Action = ActionRecreate
+		changes[id].Reasons = append(changes[id].Reasons, cascadeReason(cause))
+	}
+
+	// check if there are resources in the state that are no longer
+	// in the config
+	for _, r := range past.Resources {
+		// if this is the image cache continue as this is always added
+		if r.Metadata().Type == cache.TypeImageCache {
+			continue
+		}
+
+		if _, err := res.FindResource(r.Metadata().ID); err == nil {
+			continue
+		}
+
+		plan.Changes = append(plan.Changes, &ResourceChange{
+			ID:       r.Metadata().ID,
+			Type:     r.Metadata().Type,
+			Module:   r.Metadata().Module,
+			Action:   ActionDestroy,
+			Reasons:  []string{ReasonRemoved},
+			resource: r,
+			inState:  true,
+		})
+	}
+
+	// only include the targeted resources
+	targeted, err := e.applyTargets(res, past)
+	if err != nil {
+		return nil, err
+	}
+
+	if targeted != nil {
+		changes := []*ResourceChange{}
+		for _, c := range plan.Changes {
+			if targeted[c.ID] {
+				changes = append(changes, c)
+			}
+		}
+
+		plan.Changes = changes
+	}
+
+	err = checkPreventDestroy(plan.Changes, past)
+	if err != nil {
+		return nil, err
+	}
+
+	// the parser processes resources concurrently, sort the changes so that
+	// plans for the same config and state are identical
+	sort.SliceStable(plan.Changes, func(i, j int) bool {
+		return plan.Changes[i].ID < plan.Changes[j].ID
+	})
+
+	return plan, nil
+}
+
+// ApplyPlan applies a plan previously created with Plan, an error is
+// returned if the state or the configuration has changed since the plan
+// was created
+func (e *EngineImpl) ApplyPlan(ctx context.Context, p *Plan) (*hclconfig.Config, error) {
+	if e.stateChecksum() != p.StateChecksum {
+		return nil, fmt.Errorf("the state has changed since the plan was created, please create a new plan")
+	}
+
+	// the targets of the plan only apply to this call, later calls use the
+	// targets set on the engine
+	targets := e.targets
+	e.targets = p.Targets
+	defer func() { e.targets = targets }()
+
+	current, err := e.Plan(p.Path, p.Variables, p.VariablesFile)
+	if err != nil {
+		return nil, err
+	}
+
+	if current.ConfigChecksum != p.ConfigChecksum || !samePlan(p, current) {
+		return nil, fmt.Errorf("the configuration has changed since the plan was created, please create a new plan")
+	}
+
+	return e.ApplyWithVariables(ctx, p.Path, p.Variables, p.VariablesFile)
+}
+
+// samePlan returns true when both plans contain the same changes
+func samePlan(a, b *Plan) bool {
+	ac, err := utils.ChecksumFromInterface(a.Changes)
+	if err != nil {
+		return false
+	}
+
+	bc, err := utils.ChecksumFromInterface(b.Changes)
+	if err != nil {
+		return false
+	}
+
+	return ac == bc
+}
+
+// checkPreventDestroy returns an error when any of the changes would destroy
+// a resource with prevent_destroy set. Providers can re-create resources
+// when they are refreshed so changed resources are also protected, unless
+// the only change is to the lifecycle block, i.e. adding prevent_destroy.
+func checkPreventDestroy(changes []*ResourceChange, past *hclconfig.Config) error {
+	ids := []string{}
+
+	for _, c := range changes {
+		r := c.resource
+
+		switch c.Action {
+		case ActionDestroy:
+			// removed and disabled resources are protected by the lifecycle
+			// they were created with
+			if sr, err := past.FindResource(c.ID); err == nil {
+				r = sr
+			}
+
+		case ActionRecreate:
+			// failed resources were never fully created
+			if c.hasReason(ReasonFailed) {
+				continue
+			}
+
+		case ActionRefresh:
+			if len(c.Reasons) == 1 && c.hasReason(ReasonChecksum) && onlyLifecycleChanged(c.Attributes) {
+				continue
+			}
+
+		default:
+			continue
+		}
+
+		if preventsDestroy(r) {
+			ids = append(ids, c.ID)
+		}
+	}
+
+	if len(ids) > 0 {
+		sort.Strings(ids)
+		return &PreventDestroyError{Resources: ids}
+	}
+
+	return nil
+}
+
+func onlyLifecycleChanged(attrs []AttributeChange) bool {
+	for _, a := range attrs {
+		if !matchesAttribute(a.Path, "lifecycle") {
+			return false
+		}
+	}
+
+	return true
+}
+
+// withoutIgnored removes the changes to ignored attributes
+func withoutIgnored(attrs []AttributeChange, ignore []string) []AttributeChange {
+	changes := []AttributeChange{}
+
+	for _, a := range attrs {
+		ignored := false
+		for _, i := range ignore {
+			if i == lifecycle.IgnoreAll || matchesAttribute(a.Path, i) {
+				ignored = true
+				break
+			}
+		}
+
+		if !ignored {
+			changes = append(changes, a)
+		}
+	}
+
+	return changes
+}
+
+var listIndex = regexp.MustCompile(`\[\d+\]`)
+
+// matchesAttribute returns true when path is the attribute name or is
+// nested inside it, list indexes in the path are not compared so that
+// port.local matches port[1].local
+func matchesAttribute(path, name string) bool {
+	path = listIndex.ReplaceAllString(path, "")
+
+	return path == name || strings.HasPrefix(path, name+".")
+}
+
+// diffAttributes returns the attributes that are different between the
+// resource in the state and the resource in the config, the comparison is
+// made on the JSON representation of the resources so attribute paths use
+// the same names as the state file
+func diffAttributes(before, after types.Resource) []AttributeChange {
+	bm := resourceAttributes(before)
+	am := resourceAttributes(after)
+
+	changes := []AttributeChange{}
+	diffValues("", bm, am, &changes)
+
+	return changes
+}
+
+func resourceAttributes(r types.Resource) map[string]any {
+	attrs := map[string]any{}
+
+	d, err := json.Marshal(r)
+	if err != nil {
+		return attrs
+	}
+
+	json.Unmarshal(d, &attrs)
+
+	// meta contains internal properties such as status and checksums
+	delete(attrs, "meta")
+
+	return attrs
+}
+
+func diffValues(path string, before, after any, changes *[]AttributeChange) {
+	switch b := before.(type) {
+	case map[string]any:
+		a, ok := after.(map[string]any)
+		if !ok {
+			break
+		}
+
+		keys := map[string]bool{}
+		for k := range b {
+			keys[k] = true
+		}
+
+		for k := range a {
+			keys[k] = true
+		}
+
+		sorted := []string{}
+		for k := range keys {
+			sorted = append(sorted, k)
+		}
+
+		sort.Strings(sorted)
+
+		for _, k := range sorted {
+			diffValues(joinPath(path, k), b[k], a[k], changes)
+		}
+
+		return
+
+	case []any:
+		a, ok := after.([]any)
+		if !ok || len(a) != len(b) {
+			break
+		}
+
+		for i := range b {
+			diffValues(fmt.Sprintf("%s[%d]", path, i), b[i], a[i], changes)
+		}
+
+		return
+	}
+
+	if !reflect.DeepEqual(before, after) {
+		*changes = append(*changes, AttributeChange{Path: path, Before: before, After: after})
+	}
+}
+
+func joinPath(path, key string) string {
+	if path == "" {
+		return key
+	}
+
+	return strings.Join([]string{path, key}, ".")
+}
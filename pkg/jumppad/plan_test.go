@@ -0,0 +1,221 @@
+package jumppad
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/jumppad-labs/jumppad/pkg/config"
+	"github.com/jumppad-labs/jumppad/pkg/utils"
+	"github.com/stretchr/testify/require"
+)
+
+func findChange(t *testing.T, p *Plan, id string) *ResourceChange {
+	for _, c := range p.Changes {
+		if c.ID == id {
+			return c
+		}
+	}
+
+	require.Failf(t, "change not found", "no change for resource %s", id)
+	return nil
+}
+
+func TestPlanReturnsCreateForResourcesNotInState(t *testing.T) {
+	e, mp := setupTests(t, nil)
+
+	p, err := e.Plan("../../examples/single_file", nil, "")
+	require.NoError(t, err)
+
+	c := findChange(t, p, "resource.container.consul")
+	require.Equal(t, ActionCreate, c.Action)
+	require.Contains(t, c.Reasons, ReasonNotInState)
+	require.Equal(t, "", p.StateChecksum)
+
+	// planning should never create resources
+	testAssertMethodCalled(t, mp, "Create", 0)
+}
+
+func TestPlanReturnsDestroyForResourcesRemovedFromConfig(t *testing.T) {
+	e, mp := setupTestsWithState(t, nil, existingState)
+
+	p, err := e.Plan("../../examples/single_file", nil, "")
+	require.NoError(t, err)
+	require.NotEmpty(t, p.StateChecksum)
+
+	c := findChange(t, p, "resource.network.cloud")
+	require.Equal(t, ActionDestroy, c.Action)
+	require.Contains(t, c.Reasons, ReasonRemoved)
+
+	c = findChange(t, p, "resource.template.consul_config")
+	require.Equal(t, ActionRefresh, c.Action)
+	require.Contains(t, c.Reasons, ReasonChecksum)
+	require.NotEmpty(t, c.Attributes)
+
+	testAssertMethodCalled(t, mp, "Create", 0)
+	testAssertMethodCalled(t, mp, "Destroy", 0)
+}
+
+func TestPlanReturnsRecreateForTaintedResources(t *testing.T) {
+	e, _ := setupTestsWithState(t, nil, taintedState)
+
+	p, err := e.Plan("../../examples/single_file/container.hcl", nil, "")
+	require.NoError(t, err)
+
+	c := findChange(t, p, "resource.network.onprem")
+	require.Equal(t, ActionRecreate, c.Action)
+	require.Contains(t, c.Reasons, ReasonTainted)
+}
+
+func TestPlanSaveAndLoad(t *testing.T) {
+	e, _ := setupTestsWithState(t, nil, taintedState)
+
+	p, err := e.Plan("../../examples/single_file/container.hcl", nil, "")
+	require.NoError(t, err)
+
+	pf := filepath.Join(t.TempDir(), "jumppad.plan")
+	err = p.Save(pf)
+	require.NoError(t, err)
+
+	lp, err := LoadPlan(pf)
+	require.NoError(t, err)
+
+	require.Equal(t, p.Path, lp.Path)
+	require.Equal(t, p.StateChecksum, lp.StateChecksum)
+	require.Len(t, lp.Changes, len(p.Changes))
+}
+
+func TestPlanSaveWritesFileReadableByOwner(t *testing.T) {
+	e, _ := setupTestsWithState(t, nil, taintedState)
+
+	p, err := e.Plan("../../examples/single_file/container.hcl", nil, "")
+	require.NoError(t, err)
+
+	pf := filepath.Join(t.TempDir(), "jumppad.plan")
+	err = os.WriteFile(pf, []byte(""), 0644)
+	require.NoError(t, err)
+
+	err = p.Save(pf)
+	require.NoError(t, err)
+
+	fi, err := os.Stat(pf)
+	require.NoError(t, err)
+	require.Equal(t, os.FileMode(0600), fi.Mode().Perm())
+}
+
+func TestPlanSaveWithStateKeyEncryptsPlan(t *testing.T) {
+	e, _ := setupTestsWithState(t, nil, taintedState)
+	t.Setenv(config.EnvStateKey, "0123456789abcdef0123456789abcdef")
+
+	p, err := e.Plan("../../examples/single_file/container.hcl", map[string]string{"password": "supersecretpassword"}, "")
+	require.NoError(t, err)
+
+	pf := filepath.Join(t.TempDir(), "jumppad.plan")
+	err = p.Save(pf)
+	require.NoError(t, err)
+
+	d, err := os.ReadFile(pf)
+	require.NoError(t, err)
+	require.NotContains(t, string(d), "supersecretpassword")
+
+	lp, err := LoadPlan(pf)
+	require.NoError(t, err)
+	require.Equal(t, "supersecretpassword", lp.Variables["password"])
+
+	t.Setenv(config.EnvStateKey, "")
+
+	_, err = LoadPlan(pf)
+	require.ErrorIs(t, err, config.ErrStateEncrypted)
+}
+
+func TestApplyPlanCallsProviderCreate(t *testing.T) {
+	e, mp := setupTestsWithState(t, nil, taintedState)
+
+	p, err := e.Plan("../../examples/single_file/container.hcl", nil, "")
+	require.NoError(t, err)
+
+	_, err = e.ApplyPlan(context.Background(), p)
+	require.NoError(t, err)
+
+	testAssertMethodCalled(t, mp, "Destroy", 1)
+	testAssertMethodCalled(t, mp, "Create", 7)
+}
+
+func TestApplyPlanReturnsErrorWhenStateChanged(t *testing.T) {
+	e, mp := setupTestsWithState(t, nil, taintedState)
+
+	p, err := e.Plan("../../examples/single_file/container.hcl", nil, "")
+	require.NoError(t, err)
+
+	err = os.WriteFile(utils.StatePath(), []byte(failedState), 0644)
+	require.NoError(t, err)
+
+	_, err = e.ApplyPlan(context.Background(), p)
+	require.ErrorContains(t, err, "state has changed")
+
+	testAssertMethodCalled(t, mp, "Create", 0)
+}
+
+func TestApplyPlanReturnsErrorWhenConfigChanged(t *testing.T) {
+	e, mp := setupTests(t, nil)
+
+	cf := filepath.Join(t.TempDir(), "config.hcl")
+	err := os.WriteFile(cf, []byte(planConfig), 0644)
+	require.NoError(t, err)
+
+	p, err := e.Plan(cf, nil, "")
+	require.NoError(t, err)
+
+	err = os.WriteFile(cf, []byte(planConfigChanged), 0644)
+	require.NoError(t, err)
+
+	_, err = e.ApplyPlan(context.Background(), p)
+	require.ErrorContains(t, err, "configuration has changed")
+
+	testAssertMethodCalled(t, mp, "Create", 0)
+}
+
+var planConfig = `
+resource "network" "onprem" {
+  subnet = "10.15.0.0/16"
+}
+`
+
+var planConfigChanged = `
+resource "network" "onprem" {
+  subnet = "10.16.0.0/16"
+}
+`
+
+func TestPlanWithTargetsOnlyIncludesTargetsAndDependencies(t *testing.T) {
+	e, _ := setupTests(t, nil)
+	e.SetTargets([]string{"resource.container.consul"})
+
+	p, err := e.Plan("../../examples/single_file/container.hcl", nil, "")
+	require.NoError(t, err)
+	require.Equal(t, []string{"resource.container.consul"}, p.Targets)
+
+	findChange(t, p, "resource.container.consul")
+	findChange(t, p, "resource.network.onprem")
+	findChange(t, p, "resource.template.consul_config")
+
+	for _, c := range p.Changes {
+		require.NotEqual(t, "output.consul_addr", c.ID)
+	}
+}
+
+func TestApplyPlanWithTargetsDoesNotChangeEngineTargets(t *testing.T) {
+	e, _ := setupTests(t, nil)
+	e.SetTargets([]string{"resource.container.consul"})
+
+	p, err := e.Plan("../../examples/single_file/container.hcl", nil, "")
+	require.NoError(t, err)
+
+	e.SetTargets(nil)
+
+	_, err = e.ApplyPlan(context.Background(), p)
+	require.NoError(t, err)
+
+	require.Nil(t, e.targets)
+}
@@ -0,0 +1,242 @@
+package jumppad
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/jumppad-labs/hclconfig"
+	"github.com/jumppad-labs/hclconfig/types"
+	"github.com/jumppad-labs/jumppad/pkg/clients/container"
+	"github.com/jumppad-labs/jumppad/pkg/config"
+	"github.com/jumppad-labs/jumppad/pkg/jumppad/constants"
+	"github.com/jumppad-labs/jumppad/pkg/jumppad/events"
+	"github.com/jumppad-labs/jumppad/pkg/jumppad/tracing"
+	"github.com/jumppad-labs/jumppad/pkg/utils"
+)
+
+// Snapshot saves the containers of the resources in the state to a new
+// snapshot with the given name, the snapshot records the state so that the
+// environment can be restored without running the config. When the snapshot
+// fails the partial snapshot is returned with the error so that the images
+// that were created can be removed.
+func (e *EngineImpl) Snapshot(ctx context.Context, name string) (*container.Snapshot, error) {
+	ctx, span := tracing.StartRoot(ctx, e.tracer, "jumppad.snapshot")
+
+	s, err := e.snapshot(ctx, name)
+	tracing.End(span, err)
+
+	return s, err
+}
+
+func (e *EngineImpl) snapshot(ctx context.Context, name string) (*container.Snapshot, error) {
+	err := config.ValidateSnapshotName(name)
+	if err != nil {
+		return nil, err
+	}
+
+	dir := e.snapshotsDir()
+	if config.SnapshotExists(dir, name) {
+		return nil, fmt.Errorf("snapshot %s already exists", name)
+	}
+
+	e.ctx = events.NewContext(ctx, e.events)
+	e.resetInterrupted()
+
+	c, err := e.loadState()
+	if err != nil || len(c.Resources) == 0 {
+		return nil, fmt.Errorf("unable to create snapshot, no resources have been created")
+	}
+
+	// containers of resources that are not created may not exist or may not
+	// match the state
+	for _, r := range c.Resources {
+		status := statusString(r)
+		if r.GetDisabled() || status == constants.StatusCreated || status == constants.StatusStopped {
+			continue
+		}
+
+		return nil, fmt.Errorf("unable to create snapshot, resource %s has the status %s, run 'jumppad up' before creating a snapshot", r.Metadata().ID, status)
+	}
+
+	e.log.Info("Creating snapshot", "name", name)
+
+	e.config = c
+	e.redactor.Add(config.SensitiveConfigValues(c)...)
+	e.scheduler = newScheduler(ctx, e.parallelism, nil)
+	defer func() { e.scheduler = nil }()
+
+	s := container.NewSnapshot(name, filepath.Join(dir, name))
+
+	err = e.config.Walk(func(r types.Resource) error {
+		if e.ctx.Err() != nil || r.GetDisabled() {
+			return nil
+		}
+
+		p := e.providers.GetProvider(r)
+		if p == nil {
+			return fmt.Errorf("unable to create provider for resource Name: %s, Type: %s", r.Metadata().Name, r.Metadata().Type)
+		}
+
+		sn, ok := p.(config.Snapshotter)
+		if !ok {
+			return nil
+		}
+
+		if !e.scheduler.acquire() {
+			return nil
+		}
+		defer e.scheduler.release()
+
+		return e.snapshotResource(r, sn, s)
+	}, false)
+
+	if ierr := e.interruptedError(ctx); ierr != nil {
+		err = ierr
+	}
+
+	if err != nil {
+		os.RemoveAll(s.Dir)
+		return s, err
+	}
+
+	err = config.SaveSnapshot(s, e.config)
+	if err != nil {
+		os.RemoveAll(s.Dir)
+		return s, fmt.Errorf("unable to save snapshot: %s", err)
+	}
+
+	return s, nil
+}
+
+// Restore creates the resources in the snapshot with the given name, the
+// containers are created from the snapshot and resources that do not have
+// containers are added to the state without being created again. The state
+// must not contain any resources.
+func (e *EngineImpl) Restore(ctx context.Context, name string) (*hclconfig.Config, error) {
+	ctx, span := tracing.StartRoot(ctx, e.tracer, "jumppad.restore")
+
+	c, err := e.restore(ctx, name)
+	tracing.End(span, err)
+
+	return c, err
+}
+
+func (e *EngineImpl) restore(ctx context.Context, name string) (*hclconfig.Config, error) {
+	s, c, err := config.LoadSnapshot(e.snapshotsDir(), name)
+	if err != nil {
+		return nil, err
+	}
+
+	current, err := e.loadState()
+	if err == nil && len(current.Resources) > 0 {
+		return nil, fmt.Errorf("unable to restore snapshot %s, resources have already been created, run 'jumppad down' to remove them", name)
+	}
+
+	e.log.Info("Restoring snapshot", "name", name)
+
+	e.ctx = events.NewContext(ctx, e.events)
+	e.resetInterrupted()
+
+	e.config = c
+	e.redactor.Add(config.SensitiveConfigValues(c)...)
+	e.scheduler = newScheduler(ctx, e.parallelism, nil)
+	defer func() { e.scheduler = nil }()
+
+	// resources are marked as created once they have been restored, any
+	// resources that are not restored are re-created by the next Apply
+	for _, r := range e.config.Resources {
+		if !r.GetDisabled() {
+			r.Metadata().Properties[constants.PropertyStatus] = constants.StatusFailed
+		}
+	}
+
+	walkErr := e.config.Walk(func(r types.Resource) error {
+		if e.ctx.Err() != nil || r.GetDisabled() {
+			return nil
+		}
+
+		p := e.providers.GetProvider(r)
+		if p == nil {
+			return fmt.Errorf("unable to create provider for resource Name: %s, Type: %s", r.Metadata().Name, r.Metadata().Type)
+		}
+
+		sn, ok := p.(config.Snapshotter)
+		if !ok {
+			r.Metadata().Properties[constants.PropertyStatus] = constants.StatusCreated
+			return nil
+		}
+
+		if !e.scheduler.acquire() {
+			return nil
+		}
+		defer e.scheduler.release()
+
+		return e.restoreResource(r, sn, s)
+	}, false)
+
+	err = e.saveState(e.config)
+	if err != nil {
+		return e.config, fmt.Errorf("unable to save state: %s", err)
+	}
+
+	if err := e.interruptedError(ctx); err != nil {
+		return e.config, err
+	}
+
+	return e.config, walkErr
+}
+
+// snapshotResource saves the containers of the resource to the snapshot
+func (e *EngineImpl) snapshotResource(r types.Resource, sn config.Snapshotter, s *container.Snapshot) error {
+	st := e.emitStart(r, events.OperationSnapshot)
+	ctx, span := e.startSpan(r, events.OperationSnapshot)
+
+	err := sn.Snapshot(ctx, s)
+
+	tracing.End(span, err)
+	e.emitResult(r, events.OperationSnapshot, st, err)
+
+	if err != nil {
+		return fmt.Errorf("unable to snapshot resource Name: %s, Type: %s, Error: %s", r.Metadata().Name, r.Metadata().Type, err)
+	}
+
+	return nil
+}
+
+// restoreResource creates the resource from the snapshot, the resource is
+// left marked as failed when it can not be restored
+func (e *EngineImpl) restoreResource(r types.Resource, sn config.Snapshotter, s *container.Snapshot) error {
+	st := e.emitStart(r, events.OperationRestore)
+	ctx, span := e.startSpan(r, events.OperationRestore)
+
+	err := sn.Restore(ctx, s)
+	if err == nil {
+		r.Metadata().Properties[constants.PropertyStatus] = constants.StatusCreated
+	}
+
+	tracing.End(span, err)
+	e.emitResult(r, events.OperationRestore, st, err)
+
+	if e.ctx.Err() != nil {
+		e.markInterrupted(r)
+		return nil
+	}
+
+	if err != nil {
+		return fmt.Errorf("unable to restore resource Name: %s, Type: %s, Error: %s", r.Metadata().Name, r.Metadata().Type, err)
+	}
+
+	return nil
+}
+
+// snapshotsDir returns the folder where snapshots are stored, snapshots are
+// stored in the workspace in the home folder of the engine when it is set
+func (e *EngineImpl) snapshotsDir() string {
+	if e.home != "" {
+		return filepath.Join(e.environment().WorkspaceHome(), "snapshots")
+	}
+
+	return utils.SnapshotsDir()
+}
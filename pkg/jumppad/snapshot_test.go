@@ -0,0 +1,133 @@
+package jumppad
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/jumppad-labs/jumppad/pkg/config"
+	"github.com/jumppad-labs/jumppad/pkg/config/mocks"
+	"github.com/jumppad-labs/jumppad/pkg/jumppad/constants"
+	"github.com/jumppad-labs/jumppad/pkg/utils"
+	"github.com/stretchr/testify/require"
+)
+
+// setupRestoreTests creates a snapshot from the running state and returns an
+// engine with an empty state that can restore it
+func setupRestoreTests(t *testing.T, returnVals map[string]error) (*EngineImpl, *mocks.Providers) {
+	home := t.TempDir()
+
+	e, _ := setupTestsWithState(t, nil, runningState)
+	e.home = home
+
+	_, err := e.Snapshot(context.Background(), "test")
+	require.NoError(t, err)
+
+	e, mp := setupTestsWithState(t, returnVals, "")
+	e.home = home
+
+	return e, mp
+}
+
+func TestSnapshotSavesResources(t *testing.T) {
+	e, mp := setupTestsWithState(t, nil, runningState)
+
+	s, err := e.Snapshot(context.Background(), "test")
+	require.NoError(t, err)
+	require.Equal(t, "test", s.Name)
+
+	testAssertMethodCalled(t, mp, "Snapshot", 2)
+	require.True(t, config.SnapshotExists(utils.SnapshotsDir(), "test"))
+}
+
+func TestSnapshotReturnsErrorWhenNameInvalid(t *testing.T) {
+	e, mp := setupTestsWithState(t, nil, runningState)
+
+	_, err := e.Snapshot(context.Background(), "Not Valid")
+	require.ErrorIs(t, err, config.ErrInvalidSnapshotName)
+
+	testAssertMethodCalled(t, mp, "Snapshot", 0)
+}
+
+func TestSnapshotReturnsErrorWhenSnapshotExists(t *testing.T) {
+	e, _ := setupTestsWithState(t, nil, runningState)
+
+	_, err := e.Snapshot(context.Background(), "test")
+	require.NoError(t, err)
+
+	_, err = e.Snapshot(context.Background(), "test")
+	require.ErrorContains(t, err, "already exists")
+}
+
+func TestSnapshotReturnsErrorWhenNoState(t *testing.T) {
+	e, _ := setupTests(t, nil)
+
+	_, err := e.Snapshot(context.Background(), "test")
+	require.ErrorContains(t, err, "no resources")
+}
+
+func TestSnapshotReturnsErrorWhenResourceFailed(t *testing.T) {
+	e, mp := setupTestsWithState(t, nil, strings.Replace(runningState, `"status": "created"`, `"status": "failed"`, 1))
+
+	_, err := e.Snapshot(context.Background(), "test")
+	require.ErrorContains(t, err, "status failed")
+
+	testAssertMethodCalled(t, mp, "Snapshot", 0)
+}
+
+func TestSnapshotRemovesSnapshotWhenResourceFails(t *testing.T) {
+	e, _ := setupTestsWithState(t, map[string]error{"web": fmt.Errorf("boom")}, runningState)
+
+	s, err := e.Snapshot(context.Background(), "test")
+	require.ErrorContains(t, err, "boom")
+	require.NotNil(t, s)
+
+	require.NoDirExists(t, s.Dir)
+	require.False(t, config.SnapshotExists(utils.SnapshotsDir(), "test"))
+}
+
+func TestRestoreRestoresDependenciesFirst(t *testing.T) {
+	e, mp := setupRestoreTests(t, nil)
+
+	_, err := e.Restore(context.Background(), "test")
+	require.NoError(t, err)
+
+	testAssertMethodCalled(t, mp, "Restore", 2)
+	testAssertMethodCalled(t, mp, "Create", 0)
+	require.Less(t, providerIndex(mp, "cloud"), providerIndex(mp, "web"))
+
+	requireStatus(t, "resource.container.web", constants.StatusCreated)
+	requireStatus(t, "resource.network.cloud", constants.StatusCreated)
+}
+
+func TestRestoreReturnsErrorWhenResourcesExist(t *testing.T) {
+	e, mp := setupTestsWithState(t, nil, runningState)
+	e.home = t.TempDir()
+
+	_, err := e.Snapshot(context.Background(), "test")
+	require.NoError(t, err)
+
+	_, err = e.Restore(context.Background(), "test")
+	require.ErrorContains(t, err, "jumppad down")
+
+	testAssertMethodCalled(t, mp, "Restore", 0)
+}
+
+func TestRestoreReturnsErrorWhenSnapshotNotFound(t *testing.T) {
+	e, _ := setupTests(t, nil)
+	e.home = t.TempDir()
+
+	_, err := e.Restore(context.Background(), "missing")
+	require.ErrorIs(t, err, config.ErrSnapshotNotFound)
+}
+
+func TestRestoreMarksResourcesThatFailToRestoreAsFailed(t *testing.T) {
+	e, _ := setupRestoreTests(t, map[string]error{"web": fmt.Errorf("boom")})
+
+	_, err := e.Restore(context.Background(), "test")
+	require.ErrorContains(t, err, "boom")
+
+	requireStatus(t, "resource.container.web", constants.StatusFailed)
+	requireStatus(t, "resource.network.cloud", constants.StatusCreated)
+}
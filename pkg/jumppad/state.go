@@ -0,0 +1,53 @@
+package jumppad
+
+import (
+	"github.com/jumppad-labs/hclconfig"
+	"github.com/jumppad-labs/jumppad/pkg/config"
+)
+
+// stateBackend returns the backend for the state of the engine
+func (e *EngineImpl) stateBackend() (config.StateBackend, error) {
+	if e.state != nil {
+		return e.state, nil
+	}
+
+	return config.NewStateBackend()
+}
+
+func (e *EngineImpl) loadState() (*hclconfig.Config, error) {
+	b, err := e.stateBackend()
+	if err != nil {
+		return hclconfig.NewConfig(), err
+	}
+
+	return config.LoadStateFrom(b)
+}
+
+func (e *EngineImpl) saveState(c *hclconfig.Config) error {
+	b, err := e.stateBackend()
+	if err != nil {
+		return err
+	}
+
+	return config.SaveStateTo(b, c)
+}
+
+func (e *EngineImpl) removeState() error {
+	b, err := e.stateBackend()
+	if err != nil {
+		return err
+	}
+
+	return b.Remove()
+}
+
+// stateChecksum returns the checksum of the state or an empty string when
+// there is no state
+func (e *EngineImpl) stateChecksum() string {
+	b, err := e.stateBackend()
+	if err != nil {
+		return ""
+	}
+
+	return config.StateChecksumFrom(b)
+}
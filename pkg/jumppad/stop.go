@@ -0,0 +1,194 @@
+package jumppad
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/jumppad-labs/hclconfig/types"
+	"github.com/jumppad-labs/jumppad/pkg/config"
+	"github.com/jumppad-labs/jumppad/pkg/jumppad/constants"
+	"github.com/jumppad-labs/jumppad/pkg/jumppad/events"
+	"github.com/jumppad-labs/jumppad/pkg/jumppad/tracing"
+	sdk "github.com/jumppad-labs/plugin-sdk"
+)
+
+// Stop stops the containers of the created resources in the state without
+// destroying them, dependent resources are stopped before their
+// dependencies. Stopped resources are marked with the stopped status, the
+// status of resources that do not run containers is not changed.
+func (e *EngineImpl) Stop(ctx context.Context) error {
+	ctx, span := tracing.StartRoot(ctx, e.tracer, "jumppad.stop")
+
+	err := e.stop(ctx)
+	tracing.End(span, err)
+
+	return err
+}
+
+func (e *EngineImpl) stop(ctx context.Context) error {
+	e.log.Info("Stopping resources")
+
+	err := e.walkStopped(ctx, true, func(r types.Resource, s config.Stopper) error {
+		if r.Metadata().Properties[constants.PropertyStatus] != constants.StatusCreated {
+			return nil
+		}
+
+		return e.stopResource(r, s)
+	})
+
+	if err != nil {
+		return fmt.Errorf("unable to stop resources: %s", err)
+	}
+
+	return nil
+}
+
+// Start starts the containers of the stopped resources in the state,
+// dependencies are started before the resources that depend on them.
+// Resources that fail to start are marked as failed so that they are
+// re-created by the next Apply.
+func (e *EngineImpl) Start(ctx context.Context) error {
+	ctx, span := tracing.StartRoot(ctx, e.tracer, "jumppad.start")
+
+	err := e.start(ctx)
+	tracing.End(span, err)
+
+	return err
+}
+
+func (e *EngineImpl) start(ctx context.Context) error {
+	e.log.Info("Starting resources")
+
+	err := e.walkStopped(ctx, false, func(r types.Resource, s config.Stopper) error {
+		if r.Metadata().Properties[constants.PropertyStatus] != constants.StatusStopped {
+			return nil
+		}
+
+		return e.startResource(r, s)
+	})
+
+	if err != nil {
+		return fmt.Errorf("unable to start resources: %s", err)
+	}
+
+	return nil
+}
+
+// walkStopped calls f for every enabled resource in the state that has a
+// provider implementing Stopper, the state is saved after the walk
+func (e *EngineImpl) walkStopped(ctx context.Context, reverse bool, f func(r types.Resource, s config.Stopper) error) error {
+	e.ctx = events.NewContext(ctx, e.events)
+	e.resetInterrupted()
+
+	// nothing has been created so there is nothing to stop or start
+	c, err := e.loadState()
+	if err != nil {
+		e.log.Debug("Unable to load state", "error", err)
+		return nil
+	}
+
+	e.config = c
+	e.redactor.Add(config.SensitiveConfigValues(c)...)
+	e.scheduler = newScheduler(ctx, e.parallelism, nil)
+	defer func() { e.scheduler = nil }()
+
+	walkErr := e.config.Walk(func(r types.Resource) error {
+		if e.ctx.Err() != nil || r.GetDisabled() {
+			return nil
+		}
+
+		p := e.providers.GetProvider(r)
+		if p == nil {
+			return fmt.Errorf("unable to create provider for resource Name: %s, Type: %s", r.Metadata().Name, r.Metadata().Type)
+		}
+
+		s, ok := p.(config.Stopper)
+		if !ok {
+			return nil
+		}
+
+		if !e.scheduler.acquire() {
+			return nil
+		}
+		defer e.scheduler.release()
+
+		return f(r, s)
+	}, reverse)
+
+	err = e.saveState(e.config)
+	if err != nil {
+		return fmt.Errorf("unable to save state: %s", err)
+	}
+
+	if err := e.interruptedError(ctx); err != nil {
+		return err
+	}
+
+	return walkErr
+}
+
+// stopResource stops the containers of the resource, the resource keeps its
+// status when it can not be stopped as the containers may still be running
+func (e *EngineImpl) stopResource(r types.Resource, s config.Stopper) error {
+	st := e.emitStart(r, events.OperationStop)
+	ctx, span := e.startSpan(r, events.OperationStop)
+
+	err := s.Stop(ctx)
+	if err == nil {
+		r.Metadata().Properties[constants.PropertyStatus] = constants.StatusStopped
+	}
+
+	tracing.End(span, err)
+	e.emitResult(r, events.OperationStop, st, err)
+
+	if e.ctx.Err() != nil {
+		e.markInterrupted(r)
+		return nil
+	}
+
+	if err != nil {
+		return fmt.Errorf("unable to stop resource Name: %s, Type: %s, Error: %s", r.Metadata().Name, r.Metadata().Type, err)
+	}
+
+	return nil
+}
+
+// startResource starts the containers of a stopped resource, resources that
+// fail to start are marked as failed
+func (e *EngineImpl) startResource(r types.Resource, s config.Stopper) error {
+	st := e.emitStart(r, events.OperationStart)
+	ctx, span := e.startSpan(r, events.OperationStart)
+
+	r.Metadata().Properties[constants.PropertyStatus] = constants.StatusCreated
+
+	err := s.Start(ctx)
+	if err != nil {
+		r.Metadata().Properties[constants.PropertyStatus] = constants.StatusFailed
+	}
+
+	tracing.End(span, err)
+	e.emitResult(r, events.OperationStart, st, err)
+
+	if e.ctx.Err() != nil {
+		e.markInterrupted(r)
+		return nil
+	}
+
+	if err != nil {
+		return fmt.Errorf("unable to start resource Name: %s, Type: %s, Error: %s", r.Metadata().Name, r.Metadata().Type, err)
+	}
+
+	return nil
+}
+
+// startStoppedResource starts a stopped resource before it is refreshed by
+// Apply, resources that do not have containers are marked as created
+func (e *EngineImpl) startStoppedResource(r types.Resource, p sdk.Provider) error {
+	s, ok := p.(config.Stopper)
+	if !ok {
+		r.Metadata().Properties[constants.PropertyStatus] = constants.StatusCreated
+		return nil
+	}
+
+	return e.startResource(r, s)
+}
@@ -0,0 +1,187 @@
+package jumppad
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/jumppad-labs/jumppad/pkg/config/mocks"
+	"github.com/jumppad-labs/jumppad/pkg/jumppad/constants"
+	"github.com/stretchr/testify/require"
+)
+
+func requireStatus(t *testing.T, id, status string) {
+	sf := testLoadState(t)
+	r, err := sf.FindResource(id)
+	require.NoError(t, err)
+	require.Equal(t, status, r.Metadata().Properties[constants.PropertyStatus])
+}
+
+// providerIndex returns the order in which the provider for the resource
+// with the given name was created
+func providerIndex(mp *mocks.Providers, name string) int {
+	for i := range mp.Providers {
+		if getResourceFromMock(mp, i).Metadata().Name == name {
+			return i
+		}
+	}
+
+	return -1
+}
+
+func TestStopStopsDependentsFirst(t *testing.T) {
+	e, mp := setupTestsWithState(t, nil, runningState)
+
+	err := e.Stop(context.Background())
+	require.NoError(t, err)
+
+	testAssertMethodCalled(t, mp, "Stop", 2)
+	testAssertMethodCalled(t, mp, "Destroy", 0)
+	require.Less(t, providerIndex(mp, "web"), providerIndex(mp, "cloud"))
+
+	requireStatus(t, "resource.container.web", constants.StatusStopped)
+	requireStatus(t, "resource.network.cloud", constants.StatusStopped)
+}
+
+func TestStopSkipsResourcesThatAreNotCreated(t *testing.T) {
+	e, mp := setupTestsWithState(t, nil, stoppedState)
+
+	err := e.Stop(context.Background())
+	require.NoError(t, err)
+
+	testAssertMethodCalled(t, mp, "Stop", 0)
+}
+
+func TestStopKeepsStatusWhenStopFails(t *testing.T) {
+	e, _ := setupTestsWithState(t, map[string]error{"web": fmt.Errorf("boom")}, runningState)
+
+	err := e.Stop(context.Background())
+	require.ErrorContains(t, err, "boom")
+
+	requireStatus(t, "resource.container.web", constants.StatusCreated)
+}
+
+func TestStartStartsDependenciesFirst(t *testing.T) {
+	e, mp := setupTestsWithState(t, nil, stoppedState)
+
+	err := e.Start(context.Background())
+	require.NoError(t, err)
+
+	testAssertMethodCalled(t, mp, "Start", 2)
+	testAssertMethodCalled(t, mp, "Create", 0)
+	require.Less(t, providerIndex(mp, "cloud"), providerIndex(mp, "web"))
+
+	requireStatus(t, "resource.container.web", constants.StatusCreated)
+	requireStatus(t, "resource.network.cloud", constants.StatusCreated)
+}
+
+func TestStartMarksResourcesThatFailToStartAsFailed(t *testing.T) {
+	e, _ := setupTestsWithState(t, map[string]error{"web": fmt.Errorf("boom")}, stoppedState)
+
+	err := e.Start(context.Background())
+	require.ErrorContains(t, err, "boom")
+
+	requireStatus(t, "resource.container.web", constants.StatusFailed)
+	requireStatus(t, "resource.network.cloud", constants.StatusCreated)
+}
+
+func TestPlanRefreshesStoppedResources(t *testing.T) {
+	e, _ := setupTestsWithState(t, nil, stoppedNetworkState)
+
+	p, err := e.Plan("../../examples/single_file/container.hcl", nil, "")
+	require.NoError(t, err)
+
+	c := findChange(t, p, "resource.network.onprem")
+	require.Equal(t, ActionRefresh, c.Action)
+	require.Contains(t, c.Reasons, ReasonStopped)
+}
+
+func TestApplyStartsStoppedResources(t *testing.T) {
+	e, mp := setupTestsWithState(t, nil, stoppedNetworkState)
+
+	_, err := e.Apply(context.Background(), "../../examples/single_file")
+	require.NoError(t, err)
+
+	// the network is started and refreshed rather than re-created
+	testAssertMethodCalled(t, mp, "Start", 1)
+	testAssertMethodCalled(t, mp, "Destroy", 0)
+
+	requireStatus(t, "resource.network.onprem", constants.StatusCreated)
+}
+
+var runningState = `
+{
+  "resources": [
+  {
+      "meta": {
+        "name": "cloud",
+        "properties": {
+          "status": "created"
+        },
+        "type": "network"
+      },
+      "subnet": "10.15.0.0/16"
+  },
+  {
+      "meta": {
+        "name": "web",
+        "properties": {
+          "status": "created"
+        },
+        "type": "container"
+      },
+      "depends_on": ["resource.network.cloud"],
+      "image": {
+        "name": "test"
+      }
+  }
+  ]
+}
+`
+
+var stoppedState = `
+{
+  "resources": [
+  {
+      "meta": {
+        "name": "cloud",
+        "properties": {
+          "status": "stopped"
+        },
+        "type": "network"
+      },
+      "subnet": "10.15.0.0/16"
+  },
+  {
+      "meta": {
+        "name": "web",
+        "properties": {
+          "status": "stopped"
+        },
+        "type": "container"
+      },
+      "depends_on": ["resource.network.cloud"],
+      "image": {
+        "name": "test"
+      }
+  }
+  ]
+}
+`
+
+var stoppedNetworkState = `
+{
+  "resources": [
+  {
+      "meta": {
+        "name": "onprem",
+        "properties": {
+          "status": "stopped"
+        },
+        "type": "network"
+      },
+      "subnet": "10.6.0.0/16"
+  }
+  ]
+}
+`
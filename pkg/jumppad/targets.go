@@ -0,0 +1,184 @@
+package jumppad
+
+import (
+	"fmt"
+
+	"github.com/jumppad-labs/hclconfig"
+	"github.com/jumppad-labs/hclconfig/resources"
+	"github.com/jumppad-labs/hclconfig/types"
+	"github.com/jumppad-labs/jumppad/pkg/config/resources/cache"
+)
+
+// SetTargets restricts Apply, Plan and Destroy to the given resources,
+// targets are fully qualified resource names or module names. Apply also
+// processes the dependencies of the targets and Destroy also destroys the
+// resources that depend on the targets. Passing no targets removes the
+// restriction.
+func (e *EngineImpl) SetTargets(targets []string) {
+	e.targets = targets
+}
+
+// isTargeted returns true when the resource should be processed by the
+// current operation
+func (e *EngineImpl) isTargeted(r types.Resource) bool {
+	if e.targeted == nil {
+		return true
+	}
+
+	// variables, locals, outputs and modules do not create anything but
+	// are needed to resolve the targeted resources
+	switch r.Metadata().Type {
+	case resources.TypeModule, resources.TypeVariable, resources.TypeOutput, resources.TypeLocal:
+		return true
+	}
+
+	return e.targeted[r.Metadata().ID]
+}
+
+// applyTargets returns the IDs of the targeted resources and all the
+// resources they depend on, nil is returned when no targets are set.
+// Targets that are not in the configuration are matched against the
+// state so that removed resources can be targeted.
+func (e *EngineImpl) applyTargets(parsed, state *hclconfig.Config) (map[string]bool, error) {
+	if len(e.targets) == 0 {
+		return nil, nil
+	}
+
+	targeted := map[string]bool{}
+
+	for _, t := range e.targets {
+		ids := findTargets(parsed, t)
+		if len(ids) == 0 {
+			// the resource may have been removed from the config
+			ids = findTargets(state, t)
+			if len(ids) == 0 {
+				return nil, fmt.Errorf("target '%s' does not exist in the configuration or the state", t)
+			}
+
+			for _, id := range ids {
+				targeted[id] = true
+			}
+
+			continue
+		}
+
+		for _, id := range ids {
+			addWithDependencies(parsed, id, targeted)
+		}
+	}
+
+	return targeted, nil
+}
+
+// destroyTargets returns the IDs of the targeted resources and all the
+// resources that depend on them, nil is returned when no targets are set
+func (e *EngineImpl) destroyTargets(state *hclconfig.Config) (map[string]bool, error) {
+	if len(e.targets) == 0 {
+		return nil, nil
+	}
+
+	targeted := map[string]bool{}
+
+	for _, t := range e.targets {
+		ids := findTargets(state, t)
+		if len(ids) == 0 {
+			return nil, fmt.Errorf("target '%s' does not exist in the state", t)
+		}
+
+		for _, id := range ids {
+			targeted[id] = true
+		}
+	}
+
+	// add the dependents until no more resources are found
+	for added := true; added; {
+		added = false
+
+		for _, r := range state.Resources {
+			// the image cache depends on every network but is shared by all
+			// resources, it is only destroyed when explicitly targeted
+			if targeted[r.Metadata().ID] || r.Metadata().Type == cache.TypeImageCache {
+				continue
+			}
+
+			for _, d := range resourceDependencies(state, r) {
+				if targeted[d] {
+					targeted[r.Metadata().ID] = true
+					added = true
+					break
+				}
+			}
+		}
+	}
+
+	return targeted, nil
+}
+
+// findTargets returns the IDs of the resources matching the target, when
+// the target is a module the module and all the resources it contains
+// are returned
+func findTargets(c *hclconfig.Config, target string) []string {
+	if c == nil {
+		return nil
+	}
+
+	ids := []string{}
+
+	if r, err := c.FindResource(target); err == nil {
+		ids = append(ids, r.Metadata().ID)
+	}
+
+	fqrn, err := resources.ParseFQRN(target)
+	if err == nil && fqrn.Type == resources.TypeModule {
+		mr, _ := c.FindModuleResources(target, true)
+		for _, r := range mr {
+			ids = append(ids, r.Metadata().ID)
+		}
+	}
+
+	return ids
+}
+
+func addWithDependencies(c *hclconfig.Config, id string, targeted map[string]bool) {
+	if targeted[id] {
+		return
+	}
+
+	targeted[id] = true
+
+	r, err := c.FindResource(id)
+	if err != nil {
+		return
+	}
+
+	for _, d := range resourceDependencies(c, r) {
+		addWithDependencies(c, d, targeted)
+	}
+}
+
+// resourceDependencies returns the IDs of the resources that the given
+// resource depends on or references
+func resourceDependencies(c *hclconfig.Config, r types.Resource) []string {
+	ids := []string{}
+
+	refs := append([]string{}, r.GetDependencies()...)
+	refs = append(refs, r.Metadata().Links...)
+
+	for _, d := range refs {
+		fqrn, err := resources.ParseFQRN(d)
+		if err != nil {
+			continue
+		}
+
+		rel := fqrn.AppendParentModule(r.Metadata().Module)
+
+		if rel.Type == resources.TypeModule {
+			ids = append(ids, findTargets(c, rel.String())...)
+			continue
+		}
+
+		ids = append(ids, rel.StringWithoutAttribute())
+	}
+
+	return ids
+}
@@ -0,0 +1,164 @@
+package tracing
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"time"
+)
+
+// Span is a span read from a trace file
+type Span struct {
+	Name       string
+	TraceID    string
+	SpanID     string
+	ParentID   string
+	Start      time.Time
+	End        time.Time
+	Attributes map[string]string
+	Failed     bool
+}
+
+// Duration is the time between the start and end of the span
+func (s *Span) Duration() time.Duration {
+	return s.End.Sub(s.Start)
+}
+
+// Step is a span on the critical path, depth is the number of parents
+// the span has on the path
+type Step struct {
+	Span  *Span
+	Depth int
+}
+
+// fileSpan is the format the file exporter writes spans in
+type fileSpan struct {
+	Name        string
+	SpanContext struct {
+		TraceID string
+		SpanID  string
+	}
+	Parent struct {
+		SpanID string
+	}
+	StartTime  time.Time
+	EndTime    time.Time
+	Attributes []struct {
+		Key   string
+		Value struct {
+			Value any
+		}
+	}
+	Status struct {
+		Code string
+	}
+}
+
+// ReadFile reads the spans written by the file exporter
+func ReadFile(path string) ([]*Span, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("unable to open trace file: %s", err)
+	}
+	defer f.Close()
+
+	spans := []*Span{}
+	dec := json.NewDecoder(f)
+
+	for {
+		fs := fileSpan{}
+		err := dec.Decode(&fs)
+		if errors.Is(err, io.EOF) {
+			break
+		}
+
+		if err != nil {
+			return nil, fmt.Errorf("unable to read trace file %s: %s", path, err)
+		}
+
+		s := &Span{
+			Name:       fs.Name,
+			TraceID:    fs.SpanContext.TraceID,
+			SpanID:     fs.SpanContext.SpanID,
+			Start:      fs.StartTime,
+			End:        fs.EndTime,
+			Attributes: map[string]string{},
+			Failed:     fs.Status.Code == "Error",
+		}
+
+		// root spans have an invalid parent with an id of zeros
+		if fs.Parent.SpanID != "0000000000000000" {
+			s.ParentID = fs.Parent.SpanID
+		}
+
+		for _, a := range fs.Attributes {
+			s.Attributes[a.Key] = fmt.Sprint(a.Value.Value)
+		}
+
+		spans = append(spans, s)
+	}
+
+	return spans, nil
+}
+
+// CriticalPath returns the spans that determined the duration of the most
+// recent trace in the order they ran. Starting at the root span the child
+// that finished last is followed, then the child that finished last before
+// that child started, until the start of the parent is reached.
+func CriticalPath(spans []*Span) []Step {
+	ids := map[string]bool{}
+	for _, s := range spans {
+		ids[s.SpanID] = true
+	}
+
+	var root *Span
+	children := map[string][]*Span{}
+
+	for _, s := range spans {
+		if s.ParentID == "" || !ids[s.ParentID] {
+			if root == nil || s.Start.After(root.Start) {
+				root = s
+			}
+
+			continue
+		}
+
+		children[s.ParentID] = append(children[s.ParentID], s)
+	}
+
+	if root == nil {
+		return nil
+	}
+
+	return criticalPath(root, children, 0)
+}
+
+func criticalPath(s *Span, children map[string][]*Span, depth int) []Step {
+	kids := children[s.SpanID]
+	sort.SliceStable(kids, func(i, j int) bool {
+		return kids[i].End.After(kids[j].End)
+	})
+
+	// blocking contains the children on the path from the last to finish
+	blocking := []*Span{}
+	cursor := s.End
+
+	for _, c := range kids {
+		if c.TraceID != s.TraceID || c.End.After(cursor) {
+			continue
+		}
+
+		blocking = append(blocking, c)
+		cursor = c.Start
+	}
+
+	path := []Step{{Span: s, Depth: depth}}
+	for i := len(blocking) - 1; i >= 0; i-- {
+		path = append(path, criticalPath(blocking[i], children, depth+1)...)
+	}
+
+	return path
+}
@@ -0,0 +1,89 @@
+package tracing
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel/trace"
+)
+
+func writeTrace(t *testing.T) string {
+	file := filepath.Join(t.TempDir(), "trace.json")
+
+	p, err := NewProvider(context.Background(), Options{File: file})
+	require.NoError(t, err)
+
+	tr := p.Tracer(InstrumentationName)
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	at := func(s int) trace.SpanStartEventOption {
+		return trace.WithTimestamp(start.Add(time.Duration(s) * time.Second))
+	}
+	end := func(s int) trace.SpanEndOption { return trace.WithTimestamp(start.Add(time.Duration(s) * time.Second)) }
+
+	ctx, root := tr.Start(context.Background(), "jumppad.apply", at(0))
+
+	_, parse := tr.Start(ctx, "jumppad.parse", at(0))
+	parse.End(end(1))
+
+	// network is created first, the container and the template run
+	// concurrently after it, the container takes the longest
+	_, network := tr.Start(ctx, "provider.create", at(1))
+	network.End(end(3))
+
+	cctx, container := tr.Start(ctx, "provider.create", at(3))
+	_, pull := tr.Start(cctx, "PullImage", at(3))
+	pull.End(end(8))
+	container.End(end(10))
+
+	_, template := tr.Start(ctx, "provider.create", at(3))
+	template.End(end(4))
+
+	root.End(end(10))
+
+	require.NoError(t, p.Shutdown(context.Background()))
+
+	return file
+}
+
+func TestReadFileReturnsSpans(t *testing.T) {
+	spans, err := ReadFile(writeTrace(t))
+	require.NoError(t, err)
+	require.Len(t, spans, 6)
+
+	for _, s := range spans {
+		if s.Name == "jumppad.apply" {
+			require.Empty(t, s.ParentID)
+			require.Equal(t, 10*time.Second, s.Duration())
+		}
+	}
+}
+
+func TestReadFileWithMissingFileReturnsError(t *testing.T) {
+	_, err := ReadFile(filepath.Join(t.TempDir(), "missing.json"))
+	require.Error(t, err)
+}
+
+func TestCriticalPathFollowsLastFinishingChildren(t *testing.T) {
+	spans, err := ReadFile(writeTrace(t))
+	require.NoError(t, err)
+
+	path := CriticalPath(spans)
+	require.Len(t, path, 5)
+
+	names := []string{}
+	depths := []int{}
+	for _, s := range path {
+		names = append(names, s.Span.Name)
+		depths = append(depths, s.Depth)
+	}
+
+	require.Equal(t, []string{"jumppad.apply", "jumppad.parse", "provider.create", "provider.create", "PullImage"}, names)
+	require.Equal(t, []int{0, 1, 1, 1, 2}, depths)
+
+	// the template is not on the path as the container finished later
+	require.Equal(t, 2*time.Second, path[2].Span.Duration())
+	require.Equal(t, 7*time.Second, path[3].Span.Duration())
+}
@@ -0,0 +1,163 @@
+package tracing
+
+import (
+	"context"
+	"fmt"
+	"os"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracehttp"
+	"go.opentelemetry.io/otel/exporters/stdout/stdouttrace"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// InstrumentationName is the name of the tracer used for all spans
+const InstrumentationName = "github.com/jumppad-labs/jumppad"
+
+const (
+	// AttributeResourceID is the fully qualified name of the resource that
+	// a span operates on
+	AttributeResourceID = "jumppad.resource.id"
+
+	// AttributeResourceType is the type of the resource that a span
+	// operates on
+	AttributeResourceType = "jumppad.resource.type"
+
+	// AttributeImages are the container images that a client call uses
+	AttributeImages = "jumppad.images"
+)
+
+// Start starts a span that is a child of the span in the given context,
+// when the context does not contain a span the returned span does nothing.
+// Spans do not use the global tracer provider, the provider is inherited
+// from the parent span so that only operations started by an engine with
+// tracing enabled are recorded.
+func Start(ctx context.Context, name string, attrs ...attribute.KeyValue) (context.Context, trace.Span) {
+	if ctx == nil {
+		ctx = context.Background()
+	}
+
+	tp := trace.SpanFromContext(ctx).TracerProvider()
+	return tp.Tracer(InstrumentationName).Start(ctx, name, trace.WithAttributes(attrs...))
+}
+
+// StartRoot starts a span using the given provider, when the provider is
+// nil the span is a child of any span in the context
+func StartRoot(ctx context.Context, tp trace.TracerProvider, name string, attrs ...attribute.KeyValue) (context.Context, trace.Span) {
+	if tp == nil {
+		return Start(ctx, name, attrs...)
+	}
+
+	return tp.Tracer(InstrumentationName).Start(ctx, name, trace.WithAttributes(attrs...))
+}
+
+// End records the error on the span and ends it
+func End(span trace.Span, err error) {
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+
+	span.End()
+}
+
+// Trace calls f inside a span, it is used to instrument client calls that
+// do not accept a context
+func Trace(ctx context.Context, name string, f func() error, attrs ...attribute.KeyValue) error {
+	_, span := Start(ctx, name, attrs...)
+
+	err := f()
+	End(span, err)
+
+	return err
+}
+
+// Images returns an attribute containing the names of the given images
+func Images(names ...string) attribute.KeyValue {
+	return attribute.StringSlice(AttributeImages, names)
+}
+
+// Options configure where spans are exported
+type Options struct {
+	// File is the path of a file that spans are written to as JSON
+	File string
+
+	// Endpoint is the URL of an OTLP HTTP collector i.e http://localhost:4318
+	Endpoint string
+
+	// Version is added to the resource of every span
+	Version string
+}
+
+// Provider is a tracer provider that writes spans to the configured
+// exporters
+type Provider struct {
+	*sdktrace.TracerProvider
+	file *os.File
+}
+
+// NewProvider creates a tracer provider that exports spans to a file, an
+// OTLP endpoint or both, nil is returned when no exporter is configured
+func NewProvider(ctx context.Context, o Options) (*Provider, error) {
+	if o.File == "" && o.Endpoint == "" {
+		return nil, nil
+	}
+
+	res := resource.NewSchemaless(
+		attribute.String("service.name", "jumppad"),
+		attribute.String("service.version", o.Version),
+	)
+
+	p := &Provider{}
+	opts := []sdktrace.TracerProviderOption{sdktrace.WithResource(res)}
+
+	if o.File != "" {
+		f, err := os.Create(o.File)
+		if err != nil {
+			return nil, fmt.Errorf("unable to create trace file: %s", err)
+		}
+
+		exp, err := stdouttrace.New(stdouttrace.WithWriter(f))
+		if err != nil {
+			f.Close()
+			return nil, fmt.Errorf("unable to create file exporter: %s", err)
+		}
+
+		p.file = f
+		opts = append(opts, sdktrace.WithBatcher(exp))
+	}
+
+	if o.Endpoint != "" {
+		exp, err := otlptracehttp.New(ctx, otlptracehttp.WithEndpointURL(o.Endpoint))
+		if err != nil {
+			if p.file != nil {
+				p.file.Close()
+			}
+
+			return nil, fmt.Errorf("unable to create OTLP exporter: %s", err)
+		}
+
+		opts = append(opts, sdktrace.WithBatcher(exp))
+	}
+
+	p.TracerProvider = sdktrace.NewTracerProvider(opts...)
+
+	return p, nil
+}
+
+// Shutdown flushes any spans that have not been exported and closes the
+// trace file
+func (p *Provider) Shutdown(ctx context.Context) error {
+	err := p.TracerProvider.Shutdown(ctx)
+
+	if p.file != nil {
+		if cerr := p.file.Close(); cerr != nil && err == nil {
+			err = cerr
+		}
+	}
+
+	return err
+}
@@ -0,0 +1,64 @@
+package tracing
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+)
+
+func setupProvider(t *testing.T) (*sdktrace.TracerProvider, *tracetest.InMemoryExporter) {
+	exp := tracetest.NewInMemoryExporter()
+	tp := sdktrace.NewTracerProvider(sdktrace.WithSyncer(exp))
+	t.Cleanup(func() { tp.Shutdown(context.Background()) })
+
+	return tp, exp
+}
+
+func TestStartWithoutParentDoesNotRecord(t *testing.T) {
+	_, span := Start(context.Background(), "test")
+	defer span.End()
+
+	require.False(t, span.IsRecording())
+}
+
+func TestStartInheritsProviderFromParent(t *testing.T) {
+	tp, exp := setupProvider(t)
+
+	ctx, root := StartRoot(context.Background(), tp, "root")
+	_, child := Start(ctx, "child", attribute.String(AttributeResourceID, "resource.container.consul"))
+
+	child.End()
+	root.End()
+
+	spans := exp.GetSpans()
+	require.Len(t, spans, 2)
+	require.Equal(t, "child", spans[0].Name)
+	require.Equal(t, spans[1].SpanContext.SpanID(), spans[0].Parent.SpanID())
+	require.Contains(t, spans[0].Attributes, attribute.String(AttributeResourceID, "resource.container.consul"))
+}
+
+func TestTraceRecordsErrors(t *testing.T) {
+	tp, exp := setupProvider(t)
+
+	ctx, root := StartRoot(context.Background(), tp, "root")
+	err := Trace(ctx, "PullImage", func() error { return fmt.Errorf("boom") })
+	root.End()
+
+	require.Error(t, err)
+
+	spans := exp.GetSpans()
+	require.Equal(t, "PullImage", spans[0].Name)
+	require.Equal(t, codes.Error, spans[0].Status.Code)
+}
+
+func TestNewProviderWithoutExportersReturnsNil(t *testing.T) {
+	p, err := NewProvider(context.Background(), Options{})
+	require.NoError(t, err)
+	require.Nil(t, p)
+}
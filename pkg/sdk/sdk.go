@@ -0,0 +1,252 @@
+// Package sdk allows the jumppad engine to be embedded in other Go programs.
+//
+// An Engine created by New does not write to stdout or change the standard
+// logger, log output is sent to the logger set with WithLogger and is
+// discarded by default. The state, module cache, certificates, data folders
+// and logs are stored in the home folder set with WithHome and the names of
+// containers, networks and volumes include the workspace set with
+// WithWorkspace. Engines with different home folders can be used at the same
+// time in the same process, engines that share a Docker host must also use
+// different workspaces.
+//
+//	e, err := sdk.New(sdk.WithHome("/tmp/jumppad"))
+//	if err != nil {
+//		return err
+//	}
+//
+//	_, err = e.Apply(ctx, "./config", nil)
+//	if err != nil {
+//		return err
+//	}
+//
+//	out, err := e.Outputs()
+package sdk
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"path/filepath"
+
+	"github.com/jumppad-labs/hclconfig"
+	"github.com/jumppad-labs/hclconfig/resources"
+	"github.com/jumppad-labs/jumppad/pkg/clients"
+	"github.com/jumppad-labs/jumppad/pkg/clients/container"
+	"github.com/jumppad-labs/jumppad/pkg/clients/logger"
+	"github.com/jumppad-labs/jumppad/pkg/config"
+	"github.com/jumppad-labs/jumppad/pkg/jumppad"
+	"github.com/jumppad-labs/jumppad/pkg/jumppad/events"
+	"github.com/jumppad-labs/jumppad/pkg/utils"
+)
+
+// Option configures an Engine
+type Option func(o *options)
+
+type options struct {
+	home      string
+	workspace string
+	state     config.StateBackend
+	log       logger.Logger
+	clients   config.ClientsFactory
+	events    events.Handler
+}
+
+// WithHome sets the folder that the state, module cache and the files
+// created by providers are stored in, when not set the jumppad home folder of
+// the current user is used
+func WithHome(dir string) Option {
+	return func(o *options) {
+		o.home = dir
+	}
+}
+
+// WithWorkspace sets the workspace that the resources are created in, the
+// workspace is added to the names of Docker resources so that engines using
+// the same Docker host do not conflict. When not set the default workspace is
+// used, the workspace selected with the jumppad CLI is ignored.
+func WithWorkspace(name string) Option {
+	return func(o *options) {
+		o.workspace = name
+	}
+}
+
+// WithStateBackend sets the backend that the state is read from and written
+// to, this takes precedence over the state folder in the home folder
+func WithStateBackend(b config.StateBackend) Option {
+	return func(o *options) {
+		o.state = b
+	}
+}
+
+// WithLogger sets the logger that the engine and providers write to
+func WithLogger(l logger.Logger) Option {
+	return func(o *options) {
+		o.log = l
+	}
+}
+
+// WithClients sets the factory that creates the Docker, Kubernetes, Nomad
+// and other clients used by providers, by default the clients are created
+// from the environment. The home folder and workspace of the engine are set
+// on the clients returned by the factory.
+func WithClients(f config.ClientsFactory) Option {
+	return func(o *options) {
+		o.clients = f
+	}
+}
+
+// WithEventHandler sets the handler that receives the events emitted while
+// resources are created and destroyed
+func WithEventHandler(h events.Handler) Option {
+	return func(o *options) {
+		o.events = h
+	}
+}
+
+// Engine creates and destroys the resources defined in jumppad configuration
+type Engine struct {
+	engine jumppad.Engine
+	state  config.StateBackend
+}
+
+// New creates an Engine configured with the given options
+func New(opts ...Option) (*Engine, error) {
+	o := &options{}
+	for _, opt := range opts {
+		opt(o)
+	}
+
+	if o.log == nil {
+		o.log = logger.NewLogger(io.Discard, logger.LogLevelInfo)
+	}
+
+	if o.workspace == "" {
+		o.workspace = utils.DefaultWorkspace
+	}
+
+	err := utils.ValidateWorkspaceName(o.workspace)
+	if err != nil {
+		return nil, err
+	}
+
+	env := utils.Environment{Home: o.home, Workspace: o.workspace}
+
+	if o.state == nil {
+		o.state = config.NewLocalBackendWithDir(filepath.Join(env.WorkspaceHome(), "state"))
+	}
+
+	p := config.NewProvidersWithFactory(o.log, clientsFactory(env, o.clients))
+
+	e, err := jumppad.New(p, o.log,
+		jumppad.WithHome(o.home),
+		jumppad.WithWorkspace(o.workspace),
+		jumppad.WithStateBackend(o.state),
+		jumppad.WithEventHandler(o.events),
+		jumppad.WithRedactor(logger.NewRedactor()),
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Engine{engine: e, state: o.state}, nil
+}
+
+// clientsFactory returns a factory that creates clients for the home folder
+// and workspace of the engine
+func clientsFactory(env utils.Environment, f config.ClientsFactory) config.ClientsFactory {
+	return func(l logger.Logger) (*clients.Clients, error) {
+		if f == nil {
+			return clients.GenerateClientsWithEnvironment(env, l)
+		}
+
+		c, err := f(l)
+		if err != nil {
+			return nil, err
+		}
+
+		c.Environment = env
+		if c.ContainerTasks != nil {
+			c.ContainerTasks.SetEnvironment(env)
+		}
+
+		return c, nil
+	}
+}
+
+// Apply creates or updates the resources in the configuration at path, path
+// can be a file or a folder. Resources in the state that are no longer in the
+// configuration are destroyed.
+func (e *Engine) Apply(ctx context.Context, path string, variables map[string]string) (*hclconfig.Config, error) {
+	return e.engine.ApplyWithVariables(ctx, path, variables, "")
+}
+
+// Destroy destroys all the resources in the state, when force is true
+// resources are removed from the state even if they fail to be destroyed
+func (e *Engine) Destroy(ctx context.Context, force bool) error {
+	return e.engine.Destroy(ctx, force)
+}
+
+// Stop stops the containers of the resources in the state so that they no
+// longer use CPU and memory, the resources are not destroyed
+func (e *Engine) Stop(ctx context.Context) error {
+	return e.engine.Stop(ctx)
+}
+
+// Start starts the containers of the resources stopped by Stop
+func (e *Engine) Start(ctx context.Context) error {
+	return e.engine.Start(ctx)
+}
+
+// Snapshot saves the containers of the resources in the state to a snapshot
+// with the given name. When an error is returned the partial snapshot is
+// returned so that the images that were created can be removed with
+// Snapshot.RemoveImages.
+func (e *Engine) Snapshot(ctx context.Context, name string) (*container.Snapshot, error) {
+	return e.engine.Snapshot(ctx, name)
+}
+
+// Restore creates the resources in the snapshot with the given name, the
+// state must not contain any resources
+func (e *Engine) Restore(ctx context.Context, name string) (*hclconfig.Config, error) {
+	return e.engine.Restore(ctx, name)
+}
+
+// Diff returns the changes that applying the configuration at path would
+// make without changing any resources
+func (e *Engine) Diff(path string, variables map[string]string) (*jumppad.Plan, error) {
+	return e.engine.Plan(path, variables, "")
+}
+
+// Outputs returns the values of the outputs in the state keyed by name,
+// outputs in modules and disabled outputs are not returned. Sensitive values
+// are returned unredacted. When there is no state no outputs are returned.
+func (e *Engine) Outputs() (map[string]interface{}, error) {
+	out := map[string]interface{}{}
+
+	_, err := e.state.Load()
+	if errors.Is(err, config.ErrStateNotFound) {
+		return out, nil
+	}
+
+	c, err := config.LoadStateFrom(e.state)
+	if err != nil {
+		return nil, fmt.Errorf("unable to load state: %s", err)
+	}
+
+	for _, r := range c.Resources {
+		if r.Metadata().Type != resources.TypeOutput || r.GetDisabled() || r.Metadata().Module != "" {
+			continue
+		}
+
+		out[r.Metadata().Name] = r.(*resources.Output).Value
+	}
+
+	return out, nil
+}
+
+// Engine returns the underlying jumppad engine for operations that are not
+// part of the SDK such as drift detection and import
+func (e *Engine) Engine() jumppad.Engine {
+	return e.engine
+}
@@ -0,0 +1,156 @@
+package sdk
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	dcontainer "github.com/docker/docker/api/types/container"
+	"github.com/jumppad-labs/jumppad/pkg/clients"
+	"github.com/jumppad-labs/jumppad/pkg/clients/container/mocks"
+	"github.com/jumppad-labs/jumppad/pkg/clients/logger"
+	"github.com/jumppad-labs/jumppad/pkg/jumppad"
+	"github.com/jumppad-labs/jumppad/pkg/utils"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+const testConfig = `
+variable "max" {
+  default = 10
+}
+
+resource "random_number" "port" {
+  minimum = 1
+  maximum = variable.max
+}
+
+output "port" {
+  value = resource.random_number.port.value
+}
+`
+
+func setupEngine(t *testing.T, opts ...Option) (*Engine, string) {
+	e, home, _ := setupEngineWithMocks(t, opts...)
+	return e, home
+}
+
+func setupEngineWithMocks(t *testing.T, opts ...Option) (*Engine, string, *mocks.ContainerTasks) {
+	home := t.TempDir()
+
+	// the image cache is created by every apply, mock the container
+	// client so that Docker is not needed
+	ct := &mocks.ContainerTasks{}
+	ct.On("SetEnvironment", mock.Anything).Return()
+	ct.On("FindContainerIDs", mock.Anything).Return([]string{"cache"}, nil)
+	ct.On("ContainerInfo", mock.Anything).Return(dcontainer.InspectResponse{NetworkSettings: &dcontainer.NetworkSettings{}}, nil)
+	ct.On("RemoveContainer", mock.Anything, mock.Anything).Return(nil)
+
+	l := logger.NewTestLogger(t)
+	opts = append([]Option{
+		WithHome(home),
+		WithLogger(l),
+		WithClients(func(l logger.Logger) (*clients.Clients, error) {
+			return &clients.Clients{ContainerTasks: ct, Logger: l}, nil
+		}),
+	}, opts...)
+
+	e, err := New(opts...)
+	require.NoError(t, err)
+
+	return e, home, ct
+}
+
+func writeConfig(t *testing.T) string {
+	dir := t.TempDir()
+	err := os.WriteFile(filepath.Join(dir, "main.hcl"), []byte(testConfig), 0644)
+	require.NoError(t, err)
+
+	return dir
+}
+
+func TestEnginesWithDifferentHomesHaveSeparateState(t *testing.T) {
+	t.Setenv(utils.HomeEnvName(), t.TempDir())
+
+	path := writeConfig(t)
+
+	e1, home1 := setupEngine(t)
+	e2, home2 := setupEngine(t)
+
+	_, err := e1.Apply(context.Background(), path, nil)
+	require.NoError(t, err)
+
+	require.FileExists(t, filepath.Join(home1, "state", "state.json"))
+	require.NoFileExists(t, filepath.Join(home2, "state", "state.json"))
+	require.NoFileExists(t, utils.StatePath())
+
+	_, err = e2.Apply(context.Background(), path, map[string]string{"max": "2"})
+	require.NoError(t, err)
+
+	o1, err := e1.Outputs()
+	require.NoError(t, err)
+	require.Contains(t, o1, "port")
+
+	o2, err := e2.Outputs()
+	require.NoError(t, err)
+	require.EqualValues(t, 1, o2["port"])
+
+	err = e1.Destroy(context.Background(), false)
+	require.NoError(t, err)
+
+	o1, err = e1.Outputs()
+	require.NoError(t, err)
+	require.Empty(t, o1)
+
+	o2, err = e2.Outputs()
+	require.NoError(t, err)
+	require.Contains(t, o2, "port")
+}
+
+func TestDiffReturnsChangesWithoutApplying(t *testing.T) {
+	path := writeConfig(t)
+	e, home := setupEngine(t)
+
+	p, err := e.Diff(path, nil)
+	require.NoError(t, err)
+	require.NotEmpty(t, p.Changes)
+
+	for _, c := range p.Changes {
+		require.Equal(t, jumppad.ActionCreate, c.Action)
+	}
+
+	require.NoFileExists(t, filepath.Join(home, "state", "state.json"))
+}
+
+func TestEngineCreatesResourcesInHomeAndWorkspace(t *testing.T) {
+	t.Setenv(utils.HomeEnvName(), t.TempDir())
+
+	path := writeConfig(t)
+	err := os.WriteFile(filepath.Join(path, "data.hcl"), []byte(`
+output "data" {
+  value = data("test")
+}
+`), 0644)
+	require.NoError(t, err)
+
+	e, home, ct := setupEngineWithMocks(t, WithWorkspace("dev"))
+
+	_, err = e.Apply(context.Background(), path, nil)
+	require.NoError(t, err)
+
+	env := utils.Environment{Home: home, Workspace: "dev"}
+	ct.AssertCalled(t, "SetEnvironment", env)
+	ct.AssertCalled(t, "FindContainerIDs", env.FQDN("default", "", "image_cache"))
+
+	require.FileExists(t, filepath.Join(home, "workspaces", "dev", "state", "state.json"))
+
+	o, err := e.Outputs()
+	require.NoError(t, err)
+	require.Equal(t, filepath.Join(home, "workspaces", "dev", "data", "test"), o["data"])
+}
+
+func TestNewReturnsErrorWhenWorkspaceInvalid(t *testing.T) {
+	_, err := New(WithHome(t.TempDir()), WithWorkspace("Not Valid"))
+	require.ErrorIs(t, err, utils.ErrInvalidWorkspaceName)
+}
@@ -61,14 +61,15 @@ func getTarget(id string) (string, error) {
 }
 
 func (a *API) executeScript(target string, script string, workdir string, user string, group string, timeout int) (int, string) {
-	dc, err := container.NewDocker()
+	rt := container.Runtime()
+	dc, err := container.NewClient(rt)
 	if err != nil {
 		return 254, err.Error()
 	}
 
 	il := images.NewImageFileLog(utils.ImageCacheLog())
 	tz := &tar.TarGz{}
-	ct, err := container.NewDockerTasks(dc, il, tz, a.log)
+	ct, err := container.NewContainerTasks(rt, dc, il, tz, a.log)
 
 	if err != nil {
 		return 254, err.Error()
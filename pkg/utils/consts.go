@@ -12,6 +12,10 @@ const ImageVolumeName string = "images"
 // BuildImagePrefix is the default prefix added to any image built by jumppad
 const BuildImagePrefix = "jumppad.dev/localcache"
 
+// SnapshotImagePrefix is the prefix added to the images created when a
+// snapshot of an environment is taken
+const SnapshotImagePrefix = "jumppad.dev/snapshot"
+
 // Name of the Cache resource
 const CacheName string = "docker-cache"
 
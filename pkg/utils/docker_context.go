@@ -0,0 +1,194 @@
+package utils
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// DockerContextEnvVar is the environment variable that selects the Docker
+// context, the --context flag and the docker_context setting set this. It
+// takes precedence over DOCKER_HOST and DOCKER_CONTEXT.
+const DockerContextEnvVar = "JUMPPAD_DOCKER_CONTEXT"
+
+// DockerEndpoint is the address of a Docker daemon and the TLS files used to
+// connect to it
+type DockerEndpoint struct {
+	// Context is the name of the Docker context that the endpoint was read
+	// from, empty when the endpoint is not from a context
+	Context string
+
+	// Host is the address of the daemon such as unix:///var/run/docker.sock,
+	// tcp://10.5.0.2:2376 or ssh://user@buildbox, empty for the default
+	// local daemon
+	Host string
+
+	// CACert, Cert and Key are the paths of the TLS files, empty when the
+	// endpoint does not use TLS
+	CACert string
+	Cert   string
+	Key    string
+
+	SkipTLSVerify bool
+}
+
+// IsRemote returns true when the daemon is not reached through a local
+// socket or pipe
+func (e *DockerEndpoint) IsRemote() bool {
+	return e.Host != "" && !strings.HasPrefix(e.Host, "unix://") && !strings.HasPrefix(e.Host, "npipe://")
+}
+
+// IsSSH returns true when the daemon is reached through ssh
+func (e *DockerEndpoint) IsSSH() bool {
+	return strings.HasPrefix(e.Host, "ssh://")
+}
+
+// HasTLS returns true when TLS files are set or verification is skipped
+func (e *DockerEndpoint) HasTLS() bool {
+	return e.CACert != "" || e.Cert != "" || e.SkipTLSVerify
+}
+
+// Hostname returns the hostname of a remote daemon, or an empty string for a
+// local daemon
+func (e *DockerEndpoint) Hostname() string {
+	if !e.IsRemote() {
+		return ""
+	}
+
+	u, err := url.Parse(e.Host)
+	if err != nil {
+		return ""
+	}
+
+	return u.Hostname()
+}
+
+// GetDockerEndpoint returns the endpoint of the Docker daemon, in order of
+// precedence this is the context set with JUMPPAD_DOCKER_CONTEXT, DOCKER_HOST,
+// the context set with DOCKER_CONTEXT, or the current context in the Docker
+// config file. The default local daemon has an empty Host.
+func GetDockerEndpoint() (*DockerEndpoint, error) {
+	if name := os.Getenv(DockerContextEnvVar); name != "" {
+		return LoadDockerContext(name)
+	}
+
+	if os.Getenv("DOCKER_HOST") != "" {
+		return dockerEndpointFromEnv(), nil
+	}
+
+	if name := os.Getenv("DOCKER_CONTEXT"); name != "" {
+		return LoadDockerContext(name)
+	}
+
+	if name := currentDockerContext(); name != "" {
+		return LoadDockerContext(name)
+	}
+
+	return &DockerEndpoint{}, nil
+}
+
+// LoadDockerContext returns the endpoint of the Docker context with the
+// given name from the Docker context store, the default context is the
+// daemon set with DOCKER_HOST or the default local daemon
+func LoadDockerContext(name string) (*DockerEndpoint, error) {
+	if name == "default" {
+		return dockerEndpointFromEnv(), nil
+	}
+
+	id := dockerContextID(name)
+
+	d, err := os.ReadFile(filepath.Join(DockerConfigDir(), "contexts", "meta", id, "meta.json"))
+	if err != nil {
+		return nil, fmt.Errorf("unable to find Docker context '%s': %s", name, err)
+	}
+
+	meta := struct {
+		Endpoints map[string]struct {
+			Host          string `json:"Host"`
+			SkipTLSVerify bool   `json:"SkipTLSVerify"`
+		} `json:"Endpoints"`
+	}{}
+
+	err = json.Unmarshal(d, &meta)
+	if err != nil {
+		return nil, fmt.Errorf("unable to read Docker context '%s': %s", name, err)
+	}
+
+	de, ok := meta.Endpoints["docker"]
+	if !ok || de.Host == "" {
+		return nil, fmt.Errorf("docker context '%s' does not have a Docker endpoint", name)
+	}
+
+	ep := &DockerEndpoint{Context: name, Host: de.Host, SkipTLSVerify: de.SkipTLSVerify}
+
+	// TLS files are stored separately from the metadata
+	tlsDir := filepath.Join(DockerConfigDir(), "contexts", "tls", id, "docker")
+	setIfExists(&ep.CACert, filepath.Join(tlsDir, "ca.pem"))
+	setIfExists(&ep.Cert, filepath.Join(tlsDir, "cert.pem"))
+	setIfExists(&ep.Key, filepath.Join(tlsDir, "key.pem"))
+
+	return ep, nil
+}
+
+// DockerConfigDir returns the folder containing the Docker config file and
+// context store, this is DOCKER_CONFIG or $HOME/.docker
+func DockerConfigDir() string {
+	if d := os.Getenv("DOCKER_CONFIG"); d != "" {
+		return d
+	}
+
+	return filepath.Join(HomeFolder(), ".docker")
+}
+
+// dockerEndpointFromEnv returns the endpoint set with DOCKER_HOST, TLS files
+// are read from DOCKER_CERT_PATH and verified when DOCKER_TLS_VERIFY is set
+func dockerEndpointFromEnv() *DockerEndpoint {
+	ep := &DockerEndpoint{Host: os.Getenv("DOCKER_HOST")}
+
+	if cp := os.Getenv("DOCKER_CERT_PATH"); cp != "" {
+		ep.CACert = filepath.Join(cp, "ca.pem")
+		ep.Cert = filepath.Join(cp, "cert.pem")
+		ep.Key = filepath.Join(cp, "key.pem")
+		ep.SkipTLSVerify = os.Getenv("DOCKER_TLS_VERIFY") == ""
+	}
+
+	return ep
+}
+
+// currentDockerContext returns the context selected with docker context use,
+// or an empty string when the default context is used
+func currentDockerContext() string {
+	d, err := os.ReadFile(filepath.Join(DockerConfigDir(), "config.json"))
+	if err != nil {
+		return ""
+	}
+
+	cfg := struct {
+		CurrentContext string `json:"currentContext"`
+	}{}
+
+	json.Unmarshal(d, &cfg)
+
+	if cfg.CurrentContext == "default" {
+		return ""
+	}
+
+	return cfg.CurrentContext
+}
+
+// dockerContextID returns the folder name that Docker stores a context in
+func dockerContextID(name string) string {
+	sum := sha256.Sum256([]byte(name))
+	return hex.EncodeToString(sum[:])
+}
+
+func setIfExists(v *string, path string) {
+	if _, err := os.Stat(path); err == nil {
+		*v = path
+	}
+}
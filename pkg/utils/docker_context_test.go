@@ -0,0 +1,153 @@
+package utils
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+// setupDockerConfig creates an empty Docker config folder and clears the
+// environment variables that select the Docker endpoint
+func setupDockerConfig(t *testing.T) string {
+	dir := t.TempDir()
+
+	t.Setenv("DOCKER_CONFIG", dir)
+	t.Setenv("DOCKER_HOST", "")
+	t.Setenv("DOCKER_CONTEXT", "")
+	t.Setenv("DOCKER_CERT_PATH", "")
+	t.Setenv(DockerContextEnvVar, "")
+
+	return dir
+}
+
+func writeDockerContext(t *testing.T, dir, name, host string, tls bool) {
+	meta := map[string]interface{}{
+		"Name":      name,
+		"Endpoints": map[string]interface{}{"docker": map[string]interface{}{"Host": host}},
+	}
+
+	d, _ := json.Marshal(meta)
+
+	metaDir := filepath.Join(dir, "contexts", "meta", dockerContextID(name))
+	require.NoError(t, os.MkdirAll(metaDir, 0755))
+	require.NoError(t, os.WriteFile(filepath.Join(metaDir, "meta.json"), d, 0644))
+
+	if tls {
+		tlsDir := filepath.Join(dir, "contexts", "tls", dockerContextID(name), "docker")
+		require.NoError(t, os.MkdirAll(tlsDir, 0755))
+
+		for _, f := range []string{"ca.pem", "cert.pem", "key.pem"} {
+			require.NoError(t, os.WriteFile(filepath.Join(tlsDir, f), []byte(f), 0644))
+		}
+	}
+}
+
+func TestGetDockerEndpointReturnsDefault(t *testing.T) {
+	setupDockerConfig(t)
+
+	ep, err := GetDockerEndpoint()
+	require.NoError(t, err)
+	require.Equal(t, "", ep.Host)
+	require.False(t, ep.IsRemote())
+}
+
+func TestGetDockerEndpointReadsSelectedContext(t *testing.T) {
+	dir := setupDockerConfig(t)
+	writeDockerContext(t, dir, "buildbox", "tcp://10.5.0.2:2376", true)
+
+	t.Setenv(DockerContextEnvVar, "buildbox")
+
+	ep, err := GetDockerEndpoint()
+	require.NoError(t, err)
+	require.Equal(t, "buildbox", ep.Context)
+	require.Equal(t, "tcp://10.5.0.2:2376", ep.Host)
+	require.Equal(t, filepath.Join(dir, "contexts", "tls", dockerContextID("buildbox"), "docker", "ca.pem"), ep.CACert)
+	require.True(t, ep.HasTLS())
+	require.True(t, ep.IsRemote())
+	require.Equal(t, "10.5.0.2", ep.Hostname())
+}
+
+func TestGetDockerEndpointSelectedContextOverridesDockerHost(t *testing.T) {
+	dir := setupDockerConfig(t)
+	writeDockerContext(t, dir, "buildbox", "ssh://dev@buildbox", false)
+
+	t.Setenv("DOCKER_HOST", "tcp://10.5.0.3:2375")
+	t.Setenv(DockerContextEnvVar, "buildbox")
+
+	ep, err := GetDockerEndpoint()
+	require.NoError(t, err)
+	require.Equal(t, "ssh://dev@buildbox", ep.Host)
+	require.True(t, ep.IsSSH())
+	require.False(t, ep.HasTLS())
+}
+
+func TestGetDockerEndpointDockerHostOverridesDockerContext(t *testing.T) {
+	dir := setupDockerConfig(t)
+	writeDockerContext(t, dir, "buildbox", "ssh://dev@buildbox", false)
+
+	t.Setenv("DOCKER_HOST", "tcp://10.5.0.3:2375")
+	t.Setenv("DOCKER_CONTEXT", "buildbox")
+
+	ep, err := GetDockerEndpoint()
+	require.NoError(t, err)
+	require.Equal(t, "tcp://10.5.0.3:2375", ep.Host)
+	require.Equal(t, "", ep.Context)
+}
+
+func TestGetDockerEndpointReadsTLSFromCertPath(t *testing.T) {
+	setupDockerConfig(t)
+
+	t.Setenv("DOCKER_HOST", "tcp://10.5.0.3:2376")
+	t.Setenv("DOCKER_CERT_PATH", "/certs")
+	t.Setenv("DOCKER_TLS_VERIFY", "1")
+
+	ep, err := GetDockerEndpoint()
+	require.NoError(t, err)
+	require.Equal(t, filepath.Join("/certs", "cert.pem"), ep.Cert)
+	require.False(t, ep.SkipTLSVerify)
+}
+
+func TestGetDockerEndpointReadsCurrentContext(t *testing.T) {
+	dir := setupDockerConfig(t)
+	writeDockerContext(t, dir, "buildbox", "ssh://dev@buildbox", false)
+
+	os.WriteFile(filepath.Join(dir, "config.json"), []byte(`{"currentContext": "buildbox"}`), 0644)
+
+	ep, err := GetDockerEndpoint()
+	require.NoError(t, err)
+	require.Equal(t, "buildbox", ep.Context)
+}
+
+func TestGetDockerEndpointReturnsErrorForMissingContext(t *testing.T) {
+	setupDockerConfig(t)
+	t.Setenv(DockerContextEnvVar, "missing")
+
+	_, err := GetDockerEndpoint()
+	require.ErrorContains(t, err, "unable to find Docker context 'missing'")
+}
+
+func TestDefaultDockerContextUsesDockerHost(t *testing.T) {
+	setupDockerConfig(t)
+
+	t.Setenv("DOCKER_HOST", "tcp://10.5.0.3:2375")
+	t.Setenv(DockerContextEnvVar, "default")
+
+	ep, err := GetDockerEndpoint()
+	require.NoError(t, err)
+	require.Equal(t, "tcp://10.5.0.3:2375", ep.Host)
+}
+
+func TestDockerAddressesUseRemoteHost(t *testing.T) {
+	dir := setupDockerConfig(t)
+	writeDockerContext(t, dir, "buildbox", "ssh://dev@127.0.0.1:2222", false)
+
+	t.Setenv(DockerContextEnvVar, "buildbox")
+
+	require.Equal(t, "127.0.0.1", GetDockerIP())
+
+	// containers mount the socket on the remote host
+	require.Equal(t, "/var/run/docker.sock", GetDockerHost())
+}
@@ -0,0 +1,210 @@
+package utils
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/kennygrant/sanitize"
+)
+
+// Environment is the jumppad home folder and workspace that the folders used
+// by providers and the names of Docker resources are created in. Engines that
+// use different environments can run in the same process without sharing
+// files or Docker resources.
+//
+// Empty fields are resolved from the environment of the current user, the
+// zero value returns the same folders and names as the package functions.
+type Environment struct {
+	// Home is the jumppad home folder, usually $HOME/.jumppad
+	Home string
+	// Workspace is the name of the workspace, when empty the workspace
+	// selected by the user is used
+	Workspace string
+}
+
+// JumppadHome returns the jumppad home folder of the environment
+func (e Environment) JumppadHome() string {
+	if e.Home != "" {
+		return e.Home
+	}
+
+	return JumppadHome()
+}
+
+// WorkspaceName returns the name of the workspace of the environment
+func (e Environment) WorkspaceName() string {
+	if e.Workspace != "" {
+		return e.Workspace
+	}
+
+	return Workspace()
+}
+
+// WorkspaceHome returns the folder that contains the state and data for the
+// workspace of the environment
+func (e Environment) WorkspaceHome() string {
+	ws := e.WorkspaceName()
+	if ws == DefaultWorkspace {
+		return e.JumppadHome()
+	}
+
+	return filepath.Join(e.JumppadHome(), "/workspaces", ws)
+}
+
+// FQDN generates the full qualified name for a container, resources in a
+// workspace other than the default have the workspace added to the domain
+// e.g. consul.container.dev.local.jmpd.in
+func (e Environment) FQDN(name, module, typeName string) string {
+	domain := "local." + LocalTLD
+	if ws := e.WorkspaceName(); ws != DefaultWorkspace {
+		domain = ws + "." + domain
+	}
+
+	fqdn := fmt.Sprintf("%s.%s.%s", name, typeName, domain)
+	if module != "" {
+		fqdn = fmt.Sprintf("%s.%s.%s.%s", name, module, typeName, domain)
+	}
+
+	// ensure that the name is valid for URI schema
+	cleanName, err := ReplaceNonURIChars(fqdn)
+	if err != nil {
+		panic(err)
+	}
+
+	return cleanName
+}
+
+// NetworkName returns the name of the Docker network for a network resource,
+// networks in a workspace other than the default have the workspace appended
+// e.g. main.dev
+func (e Environment) NetworkName(name string) string {
+	if ws := e.WorkspaceName(); ws != DefaultWorkspace {
+		return fmt.Sprintf("%s.%s", name, ws)
+	}
+
+	return name
+}
+
+// FQDNVolumeName creates a full qualified volume name, volumes in a workspace
+// other than the default have the workspace added to the domain
+// e.g. images.volume.dev.jmpd.in
+func (e Environment) FQDNVolumeName(name string) string {
+	// ensure that the name is valid for URI schema
+	cleanName, err := ReplaceNonURIChars(name)
+	if err != nil {
+		panic(err)
+	}
+
+	if ws := e.WorkspaceName(); ws != DefaultWorkspace {
+		return fmt.Sprintf("%s.volume.%s.%s", cleanName, ws, LocalTLD)
+	}
+
+	return fmt.Sprintf("%s.volume.%s", cleanName, LocalTLD)
+}
+
+// CreateKubeConfigPath creates the file path for the KubeConfig file when
+// using Kubernetes cluster
+func (e Environment) CreateKubeConfigPath(id string) (dir, filePath string, dockerPath string) {
+	id, _ = ReplaceNonURIChars(id)
+	dir = filepath.Join(e.WorkspaceHome(), "/config/", id)
+	filePath = filepath.Join(dir, "/kubeconfig.yaml")
+	dockerPath = filepath.Join(dir, "/kubeconfig-docker.yaml")
+
+	// create the folders
+	err := os.MkdirAll(dir, 0755)
+	if err != nil {
+		panic(err)
+	}
+
+	return
+}
+
+// JumppadTemp returns a temporary folder
+func (e Environment) JumppadTemp() string {
+	dir := filepath.Join(e.JumppadHome(), "/tmp")
+	err := os.MkdirAll(dir, 0755)
+	if err != nil {
+		panic(err)
+	}
+
+	return dir
+}
+
+// CertsDir returns the location of the certificates for the given resource
+// used to secure the Jumppad ingress, usually rooted at $HOME/.jumppad/certs.
+// The root certificates returned for an empty name are shared by all
+// workspaces as there is a single connector, the certificates for resources
+// are stored in the workspace.
+func (e Environment) CertsDir(name string) string {
+	certs := filepath.Join(e.JumppadHome(), "/certs")
+	if name != "" {
+		certs = filepath.Join(e.WorkspaceHome(), "/certs", name)
+	}
+	certs = filepath.FromSlash(certs)
+
+	// create the folder if it does not exist
+	os.MkdirAll(certs, os.ModePerm)
+	return certs
+}
+
+// LogsDir returns the location of the logs
+// used to secure the Jumppad ingress, usually $HOME/.jumppad/logs
+func (e Environment) LogsDir() string {
+	logs := filepath.Join(e.JumppadHome(), "/logs")
+
+	os.MkdirAll(logs, os.ModePerm)
+	return logs
+}
+
+// ImageCacheLog returns the location of the image cache log
+func (e Environment) ImageCacheLog() string {
+	return fmt.Sprintf("%s/images.log", e.JumppadHome())
+}
+
+// DataFolder creates the data directory for the workspace used by the
+// application
+func (e Environment) DataFolder(p string, perms os.FileMode) string {
+	return DataFolderIn(e.WorkspaceHome(), p, perms)
+}
+
+// CacheFolder creates the cache directory used by the a provider
+// unlike DataFolders, cache folders are not removed when down is called
+func (e Environment) CacheFolder(p string, perms os.FileMode) string {
+	data := filepath.Join(e.JumppadHome(), "cache", p)
+
+	// create the folder if it does not exist
+	os.MkdirAll(data, perms)
+	os.Chmod(data, perms)
+
+	return data
+}
+
+// LibraryFolder creates the library directory for the workspace used by the
+// application
+func (e Environment) LibraryFolder(p string, perms os.FileMode) string {
+	p = sanitize.Path(p)
+	data := filepath.Join(e.WorkspaceHome(), "library", p)
+
+	// create the folder if it does not exist
+	os.MkdirAll(data, perms)
+	os.Chmod(data, perms)
+
+	return data
+}
+
+// ImageCacheAddress returns the default Image cache used by
+// Nomad and Kubernetes clusters unless the environment variable
+// IMAGE_CACHE_ADDR is set when it returns this value
+func (e Environment) ImageCacheAddress() string {
+	if p := os.Getenv("IMAGE_CACHE_ADDR"); p != "" {
+		return p
+	}
+
+	// each workspace has its own image cache
+	if e.WorkspaceName() != DefaultWorkspace {
+		return fmt.Sprintf("http://%s:3128", e.FQDN("default", "", "image-cache"))
+	}
+
+	return jumppadProxyAddress
+}
@@ -0,0 +1,22 @@
+//go:build !windows
+
+package utils
+
+import (
+	"errors"
+	"os"
+	"syscall"
+)
+
+// ProcessRunning returns true if a process with the given pid is running
+func ProcessRunning(pid int) bool {
+	p, err := os.FindProcess(pid)
+	if err != nil {
+		return false
+	}
+
+	// signal 0 performs the error checking without sending a signal,
+	// EPERM means the process exists but is owned by another user
+	err = p.Signal(syscall.Signal(0))
+	return err == nil || errors.Is(err, syscall.EPERM)
+}
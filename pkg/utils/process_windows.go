@@ -0,0 +1,20 @@
+//go:build windows
+
+package utils
+
+import (
+	"os"
+)
+
+// ProcessRunning returns true if a process with the given pid is running
+func ProcessRunning(pid int) bool {
+	// on windows FindProcess opens a handle to the process and fails
+	// when it does not exist
+	p, err := os.FindProcess(pid)
+	if err != nil {
+		return false
+	}
+
+	p.Release()
+	return true
+}
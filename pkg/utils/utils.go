@@ -9,7 +9,6 @@ import (
 	"io"
 	"math/rand"
 	"net"
-	"net/url"
 	"os"
 	"path/filepath"
 	"regexp"
@@ -94,48 +93,31 @@ func ReplaceNonURIChars(s string) (string, error) {
 	return ret, nil
 }
 
-// FQDN generates the full qualified name for a container
+// FQDN generates the full qualified name for a container, resources in a
+// workspace other than the default have the workspace added to the domain
+// e.g. consul.container.dev.local.jmpd.in
 func FQDN(name, module, typeName string) string {
-	fqdn := fmt.Sprintf("%s.%s.local.%s", name, typeName, LocalTLD)
-	if module != "" {
-		fqdn = fmt.Sprintf("%s.%s.%s.local.%s", name, module, typeName, LocalTLD)
-	}
-
-	// ensure that the name is valid for URI schema
-	cleanName, err := ReplaceNonURIChars(fqdn)
-	if err != nil {
-		panic(err)
-	}
+	return Environment{}.FQDN(name, module, typeName)
+}
 
-	return cleanName
+// NetworkName returns the name of the Docker network for a network resource,
+// networks in a workspace other than the default have the workspace appended
+// e.g. main.dev
+func NetworkName(name string) string {
+	return Environment{}.NetworkName(name)
 }
 
-// FQDNVolumeName creates a full qualified volume name
+// FQDNVolumeName creates a full qualified volume name, volumes in a workspace
+// other than the default have the workspace added to the domain
+// e.g. images.volume.dev.jmpd.in
 func FQDNVolumeName(name string) string {
-	// ensure that the name is valid for URI schema
-	cleanName, err := ReplaceNonURIChars(name)
-	if err != nil {
-		panic(err)
-	}
-
-	return fmt.Sprintf("%s.volume.%s", cleanName, LocalTLD)
+	return Environment{}.FQDNVolumeName(name)
 }
 
 // CreateKubeConfigPath creates the file path for the KubeConfig file when
 // using Kubernetes cluster
 func CreateKubeConfigPath(id string) (dir, filePath string, dockerPath string) {
-	id, _ = ReplaceNonURIChars(id)
-	dir = filepath.Join(JumppadHome(), "/config/", id)
-	filePath = filepath.Join(dir, "/kubeconfig.yaml")
-	dockerPath = filepath.Join(dir, "/kubeconfig-docker.yaml")
-
-	// create the folders
-	err := os.MkdirAll(dir, 0755)
-	if err != nil {
-		panic(err)
-	}
-
-	return
+	return Environment{}.CreateKubeConfigPath(id)
 }
 
 // HomeFolder returns the users homefolder this will be $HOME on windows and mac and
@@ -161,19 +143,13 @@ func JumppadHome() string {
 
 // JumppadTemp returns a temporary folder
 func JumppadTemp() string {
-	dir := filepath.Join(JumppadHome(), "/tmp")
-	err := os.MkdirAll(dir, 0755)
-	if err != nil {
-		panic(err)
-	}
-
-	return dir
+	return Environment{}.JumppadTemp()
 }
 
 // StateDir returns the location of the jumppad
-// state, usually $HOME/.jumppad/state
+// state for the current workspace, usually $HOME/.jumppad/state
 func StateDir() string {
-	return filepath.Join(JumppadHome(), "/state")
+	return filepath.Join(WorkspaceHome(), "/state")
 }
 
 // PluginsDir returns the location of the plugins
@@ -185,23 +161,18 @@ func PluginsDir() string {
 }
 
 // CertsDir returns the location of the certificates for the given resource
-// used to secure the Jumppad ingress, usually rooted at $HOME/.jumppad/certs
+// used to secure the Jumppad ingress, usually rooted at $HOME/.jumppad/certs.
+// The root certificates returned for an empty name are shared by all
+// workspaces as there is a single connector, the certificates for resources
+// are stored in the current workspace.
 func CertsDir(name string) string {
-	certs := filepath.Join(JumppadHome(), "/certs", name)
-	certs = filepath.FromSlash(certs)
-
-	// create the folder if it does not exist
-	os.MkdirAll(certs, os.ModePerm)
-	return certs
+	return Environment{}.CertsDir(name)
 }
 
 // LogsDir returns the location of the logs
 // used to secure the Jumppad ingress, usually $HOME/.jumppad/logs
 func LogsDir() string {
-	logs := filepath.Join(JumppadHome(), "/logs")
-
-	os.MkdirAll(logs, os.ModePerm)
-	return logs
+	return Environment{}.LogsDir()
 }
 
 // StatePath returns the full path for the state file
@@ -209,9 +180,33 @@ func StatePath() string {
 	return filepath.Join(StateDir(), "/state.json")
 }
 
+// StateHistoryDir returns the folder where previous versions of the
+// state are stored
+func StateHistoryDir() string {
+	return filepath.Join(StateDir(), "/history")
+}
+
+// SnapshotsDir returns the folder where the snapshots of the current
+// workspace are stored
+func SnapshotsDir() string {
+	return filepath.Join(WorkspaceHome(), "/snapshots")
+}
+
+// StateLockPath returns the full path for the lock file that guards
+// changes to the state
+func StateLockPath() string {
+	return filepath.Join(StateDir(), "/state.lock")
+}
+
+// SettingsPath returns the full path for the settings file of the
+// current workspace
+func SettingsPath() string {
+	return filepath.Join(WorkspaceHome(), "/settings.hcl")
+}
+
 // ImageCacheLog returns the location of the image cache log
 func ImageCacheLog() string {
-	return fmt.Sprintf("%s/images.log", JumppadHome())
+	return Environment{}.ImageCacheLog()
 }
 
 // IsLocalFolder tests if the given path is a localfolder and can
@@ -297,9 +292,16 @@ func ReleasesFolder() string {
 	return filepath.Join(JumppadHome(), "releases")
 }
 
-// DataFolder creates the data directory used by the application
+// DataFolder creates the data directory for the current workspace used by
+// the application
 func DataFolder(p string, perms os.FileMode) string {
-	data := filepath.Join(JumppadHome(), "data", p)
+	return Environment{}.DataFolder(p, perms)
+}
+
+// DataFolderIn creates the data directory with the given name in the
+// given jumppad home folder
+func DataFolderIn(home string, p string, perms os.FileMode) string {
+	data := filepath.Join(home, "data", p)
 
 	// create the folder if it does not exist
 	os.MkdirAll(data, perms)
@@ -311,48 +313,33 @@ func DataFolder(p string, perms os.FileMode) string {
 // CacheFolder creates the cache directory used by the a provider
 // unlike DataFolders, cache folders are not removed when down is called
 func CacheFolder(p string, perms os.FileMode) string {
-	data := filepath.Join(JumppadHome(), "cache", p)
-
-	// create the folder if it does not exist
-	os.MkdirAll(data, perms)
-	os.Chmod(data, perms)
-
-	return data
+	return Environment{}.CacheFolder(p, perms)
 }
 
-// LibraryFolder creates the library directory used by the application
+// LibraryFolder creates the library directory for the current workspace
+// used by the application
 func LibraryFolder(p string, perms os.FileMode) string {
-	p = sanitize.Path(p)
-	data := filepath.Join(JumppadHome(), "library", p)
-
-	// create the folder if it does not exist
-	os.MkdirAll(data, perms)
-	os.Chmod(data, perms)
-
-	return data
+	return Environment{}.LibraryFolder(p, perms)
 }
 
-// GetDockerHost returns the location of the Docker API depending on the platform
+// GetDockerHost returns the location of the Docker API depending on the platform,
+// for a daemon reached through ssh this is the socket on the remote host
 func GetDockerHost() string {
-	if dh := os.Getenv("DOCKER_HOST"); dh != "" {
-		return dh
+	ep, err := GetDockerEndpoint()
+	if err != nil || ep.Host == "" || ep.IsSSH() {
+		return "/var/run/docker.sock"
 	}
 
-	return "/var/run/docker.sock"
+	return ep.Host
 }
 
-// GetDockerIP returns the location of the Docker Server IP address
+// GetDockerIP returns the location of the Docker Server IP address, for a
+// remote daemon this is the address of the remote host
 func GetDockerIP() string {
-	if dh := os.Getenv("DOCKER_HOST"); dh != "" {
-		if strings.HasPrefix(dh, "tcp://") {
-			u, err := url.Parse(dh)
-			if err == nil {
-				host := strings.Split(u.Host, ":")[0]
-				ip, err := net.LookupHost(host)
-				if err == nil && len(ip) > 0 {
-					return ip[0]
-				}
-			}
+	if ep, err := GetDockerEndpoint(); err == nil && ep.IsRemote() {
+		ip, err := net.LookupHost(ep.Hostname())
+		if err == nil && len(ip) > 0 {
+			return ip[0]
 		}
 	}
 
@@ -428,11 +415,7 @@ func GetLocalIPAndHostname() (string, string) {
 // Nomad and Kubernetes clusters unless the environment variable
 // IMAGE_CACHE_ADDR is set when it returns this value
 func ImageCacheAddress() string {
-	if p := os.Getenv("IMAGE_CACHE_ADDR"); p != "" {
-		return p
-	}
-
-	return jumppadProxyAddress
+	return Environment{}.ImageCacheAddress()
 }
 
 // get all ipaddresses in a subnet
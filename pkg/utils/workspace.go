@@ -0,0 +1,115 @@
+package utils
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// DefaultWorkspace is the workspace used when no other workspace has been
+// selected, it uses the folders in the root of the jumppad home folder
+const DefaultWorkspace = "default"
+
+// WorkspaceEnvVar is the environment variable that overrides the
+// selected workspace
+const WorkspaceEnvVar = "JUMPPAD_WORKSPACE"
+
+var ErrInvalidWorkspaceName = fmt.Errorf("workspace names must start with a letter or number and only contain lowercase letters, numbers and -, and be a maximum of 32 characters")
+
+var workspaceNameRegex = regexp.MustCompile(`^[a-z0-9][a-z0-9\-]{0,31}$`)
+
+// ValidateWorkspaceName checks that the name can be used for a workspace,
+// as the workspace is used in resource FQDNs only characters that are valid
+// in a DNS label are allowed
+func ValidateWorkspaceName(name string) error {
+	if !workspaceNameRegex.MatchString(name) {
+		return ErrInvalidWorkspaceName
+	}
+
+	return nil
+}
+
+// Workspace returns the name of the current workspace, this is the value
+// of the JUMPPAD_WORKSPACE environment variable, or the workspace selected
+// with 'jumppad workspace select'
+func Workspace() string {
+	if ws := os.Getenv(WorkspaceEnvVar); ws != "" {
+		return ws
+	}
+
+	d, err := os.ReadFile(WorkspaceSelectionPath())
+	if err == nil {
+		if ws := strings.TrimSpace(string(d)); ws != "" {
+			return ws
+		}
+	}
+
+	return DefaultWorkspace
+}
+
+// WorkspaceSelectionPath returns the path of the file that stores the
+// selected workspace
+func WorkspaceSelectionPath() string {
+	return filepath.Join(JumppadHome(), "/workspace")
+}
+
+// WorkspacesDir returns the folder containing the non default workspaces,
+// usually $HOME/.jumppad/workspaces
+func WorkspacesDir() string {
+	return filepath.Join(JumppadHome(), "/workspaces")
+}
+
+// WorkspaceFolder returns the folder that contains the state and data
+// for the given workspace
+func WorkspaceFolder(name string) string {
+	if name == DefaultWorkspace {
+		return JumppadHome()
+	}
+
+	return filepath.Join(WorkspacesDir(), name)
+}
+
+// WorkspaceHome returns the folder that contains the state and data
+// for the current workspace
+func WorkspaceHome() string {
+	return WorkspaceFolder(Workspace())
+}
+
+// WorkspaceExists returns true if the workspace with the given name
+// has been created
+func WorkspaceExists(name string) bool {
+	if name == DefaultWorkspace {
+		return true
+	}
+
+	s, err := os.Stat(WorkspaceFolder(name))
+	return err == nil && s.IsDir()
+}
+
+// Workspaces returns the names of all workspaces including the default
+func Workspaces() ([]string, error) {
+	ws := []string{DefaultWorkspace}
+
+	entries, err := os.ReadDir(WorkspacesDir())
+	if err != nil {
+		if os.IsNotExist(err) {
+			return ws, nil
+		}
+
+		return nil, fmt.Errorf("unable to read workspaces from '%s': %s", WorkspacesDir(), err)
+	}
+
+	names := []string{}
+	for _, e := range entries {
+		if e.IsDir() && ValidateWorkspaceName(e.Name()) == nil && e.Name() != DefaultWorkspace {
+			names = append(names, e.Name())
+		}
+	}
+
+	sort.Strings(names)
+
+	return append(ws, names...), nil
+}
@@ -0,0 +1,118 @@
+package utils
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func setupWorkspaceHome(t *testing.T) string {
+	tmp := t.TempDir()
+	t.Setenv(HomeEnvName(), tmp)
+	t.Setenv(WorkspaceEnvVar, "")
+
+	return tmp
+}
+
+func TestWorkspaceReturnsDefaultWhenNotSelected(t *testing.T) {
+	setupWorkspaceHome(t)
+
+	require.Equal(t, DefaultWorkspace, Workspace())
+}
+
+func TestWorkspaceReturnsSelectedWorkspace(t *testing.T) {
+	setupWorkspaceHome(t)
+
+	os.MkdirAll(JumppadHome(), os.ModePerm)
+	err := os.WriteFile(WorkspaceSelectionPath(), []byte("dev\n"), 0644)
+	require.NoError(t, err)
+
+	require.Equal(t, "dev", Workspace())
+}
+
+func TestWorkspaceEnvOverridesSelectedWorkspace(t *testing.T) {
+	setupWorkspaceHome(t)
+
+	os.MkdirAll(JumppadHome(), os.ModePerm)
+	err := os.WriteFile(WorkspaceSelectionPath(), []byte("dev"), 0644)
+	require.NoError(t, err)
+
+	t.Setenv(WorkspaceEnvVar, "test")
+
+	require.Equal(t, "test", Workspace())
+}
+
+func TestWorkspaceFoldersAreIsolated(t *testing.T) {
+	tmp := setupWorkspaceHome(t)
+
+	require.Equal(t, filepath.Join(tmp, ".jumppad", "state"), StateDir())
+
+	t.Setenv(WorkspaceEnvVar, "dev")
+
+	require.Equal(t, filepath.Join(tmp, ".jumppad", "workspaces", "dev", "state"), StateDir())
+	require.Equal(t, filepath.Join(tmp, ".jumppad", "workspaces", "dev", "data", "test"), DataFolder("test", 0775))
+}
+
+func TestFQDNAddsWorkspace(t *testing.T) {
+	setupWorkspaceHome(t)
+
+	require.Equal(t, "consul.container.local.jmpd.in", FQDN("consul", "", "container"))
+
+	t.Setenv(WorkspaceEnvVar, "dev")
+	t.Setenv("IMAGE_CACHE_ADDR", "")
+
+	require.Equal(t, "consul.container.dev.local.jmpd.in", FQDN("consul", "", "container"))
+	require.Equal(t, "consul.mod.container.dev.local.jmpd.in", FQDN("consul", "mod", "container"))
+	require.Equal(t, "http://default.image-cache.dev.local.jmpd.in:3128", ImageCacheAddress())
+}
+
+func TestDockerNamesAndCertsAreScopedByWorkspace(t *testing.T) {
+	tmp := setupWorkspaceHome(t)
+
+	require.Equal(t, "main", NetworkName("main"))
+	require.Equal(t, "images.volume.jmpd.in", FQDNVolumeName("images"))
+	require.Equal(t, filepath.Join(tmp, ".jumppad", "certs", "k8s"), CertsDir("k8s"))
+
+	t.Setenv(WorkspaceEnvVar, "dev")
+
+	require.Equal(t, "main.dev", NetworkName("main"))
+	require.Equal(t, "images.volume.dev.jmpd.in", FQDNVolumeName("images"))
+	require.Equal(t, filepath.Join(tmp, ".jumppad", "workspaces", "dev", "certs", "k8s"), CertsDir("k8s"))
+
+	// the root certificates are shared with the connector
+	require.Equal(t, filepath.Join(tmp, ".jumppad", "certs"), CertsDir(""))
+}
+
+func TestEnvironmentIgnoresUserHomeAndWorkspace(t *testing.T) {
+	setupWorkspaceHome(t)
+	t.Setenv(WorkspaceEnvVar, "prod")
+
+	home := t.TempDir()
+	env := Environment{Home: home, Workspace: "dev"}
+
+	require.Equal(t, "consul.container.dev.local.jmpd.in", env.FQDN("consul", "", "container"))
+	require.Equal(t, "main.dev", env.NetworkName("main"))
+	require.Equal(t, filepath.Join(home, "workspaces", "dev", "certs", "k8s"), env.CertsDir("k8s"))
+	require.Equal(t, filepath.Join(home, "workspaces", "dev", "data", "test"), env.DataFolder("test", os.ModePerm))
+	require.Equal(t, filepath.Join(home, "logs"), env.LogsDir())
+}
+
+func TestWorkspacesReturnsDefaultAndCreatedWorkspaces(t *testing.T) {
+	setupWorkspaceHome(t)
+
+	os.MkdirAll(WorkspaceFolder("prod"), os.ModePerm)
+	os.MkdirAll(WorkspaceFolder("dev"), os.ModePerm)
+
+	ws, err := Workspaces()
+	require.NoError(t, err)
+	require.Equal(t, []string{"default", "dev", "prod"}, ws)
+}
+
+func TestValidateWorkspaceName(t *testing.T) {
+	require.NoError(t, ValidateWorkspaceName("dev-1"))
+	require.Error(t, ValidateWorkspaceName("Dev"))
+	require.Error(t, ValidateWorkspaceName("dev.1"))
+	require.Error(t, ValidateWorkspaceName("-dev"))
+}